@@ -0,0 +1,100 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+)
+
+// Audit writes a critical record and blocks until the processor confirms it reached the log file
+// Audit records bypass level filtering but honor the group, bound fields, and field limits
+// With AuditVerify enabled, the record is read back from the file before it is confirmed, catching silent
+// short writes on unreliable network filesystems
+// Returns an error if the record could not be queued, written, or verified, or ctx is done before confirmation
+func (l *Logger) Audit(ctx context.Context, level int64, args ...any) error {
+	if !l.state.IsInitialized.Load() || !l.state.Started.Load() {
+		return fmtErrorf("logger not started")
+	}
+
+	cfg := l.getConfig()
+	flags, args := l.applyGroup(l.getFlags(), args)
+	if len(l.fields) > 0 {
+		args = l.bindFields(flags, args)
+	}
+	var keep bool
+	if args, keep = l.applyFieldLimits(cfg, flags, args); !keep {
+		return fmtErrorf("audit record rejected by field limits")
+	}
+
+	ack := make(chan error, 1)
+	record := logRecord{
+		Flags:     flags,
+		TimeStamp: time.Now(),
+		Level:     level,
+		Args:      args,
+		Labels:    l.labels,
+		ack:       ack,
+	}
+	if err := l.sendAuditRecord(ctx, record); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-ack:
+		if err != nil {
+			return fmtErrorf("audit record not written: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmtErrorf("audit record not confirmed: %w", ctx.Err())
+	}
+}
+
+// sendAuditRecord queues an audit record, waiting for channel space instead of dropping the record
+func (l *Logger) sendAuditRecord(ctx context.Context, record logRecord) error {
+	target := l
+	if set := l.getShards(); set != nil {
+		target = set.pick()
+	}
+
+	if target.state.ShutdownCalled.Load() || target.state.LoggerDisabled.Load() || !target.state.Started.Load() {
+		return fmtErrorf("logger not running")
+	}
+	if err := target.getQueue().sendWait(ctx, record); err != nil {
+		return fmtErrorf("audit record not queued: %w", err)
+	}
+	return nil
+}
+
+// verifyWrite syncs the log file and compares its tail with the data just appended
+// The file is reopened for reading, the active handle is write-only
+func verifyWrite(file *os.File, data []byte) error {
+	if err := file.Sync(); err != nil {
+		return fmtErrorf("failed to sync log file for verification: %w", err)
+	}
+
+	reader, err := os.Open(file.Name())
+	if err != nil {
+		return fmtErrorf("failed to open log file for verification: %w", err)
+	}
+	defer reader.Close()
+
+	info, err := reader.Stat()
+	if err != nil {
+		return fmtErrorf("failed to stat log file for verification: %w", err)
+	}
+	size := info.Size()
+	if size < int64(len(data)) {
+		return fmtErrorf("log file size %d is smaller than the %d byte record", size, len(data))
+	}
+
+	tail := make([]byte, len(data))
+	if _, err := reader.ReadAt(tail, size-int64(len(data))); err != nil {
+		return fmtErrorf("failed to read back log file: %w", err)
+	}
+	if !bytes.Equal(tail, data) {
+		return fmtErrorf("log file tail does not match the written record")
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAudit verifies audit records are confirmed after reaching the file, with and without read-back
+func TestAudit(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Confirmed without a flush, below the configured level
+	require.NoError(t, logger.Audit(ctx, LevelDebug, "unverified audit"))
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "unverified audit")
+
+	require.NoError(t, logger.ApplyConfigString("audit_verify=true"))
+	require.NoError(t, logger.Audit(ctx, LevelInfo, "verified audit", "amount", 42))
+	content, err = os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "verified audit")
+
+	// Keys of grouped loggers are qualified as for other records
+	require.NoError(t, logger.WithGroup("db").Audit(ctx, LevelInfo, "grouped audit", "rows", 2))
+	content, err = os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "grouped audit db.rows 2")
+
+	// Shard loggers confirm the records they write
+	require.NoError(t, logger.ApplyConfigString("shards=2"))
+	require.NoError(t, logger.Audit(ctx, LevelInfo, "sharded audit"))
+}
+
+// TestAuditFailures verifies audit errors are surfaced to the caller
+func TestAuditFailures(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+
+	require.NoError(t, logger.ApplyConfigString("max_fields_per_record=1", "field_limit_policy=reject"))
+	assert.Error(t, logger.Audit(context.Background(), LevelInfo, "too", "many"))
+
+	require.NoError(t, logger.Shutdown())
+	assert.Error(t, logger.Audit(context.Background(), LevelInfo, "after shutdown"))
+
+	// Read-back detects a tail that differs from the written record
+	path := filepath.Join(tmpDir, "verify.log")
+	require.NoError(t, os.WriteFile(path, []byte("first line\n"), 0644))
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	defer file.Close()
+	assert.NoError(t, verifyWrite(file, []byte("line\n")))
+	assert.Error(t, verifyWrite(file, []byte("other\n")))
+	assert.Error(t, verifyWrite(file, make([]byte, 64)))
+}
\ No newline at end of file
@@ -2,6 +2,7 @@ package log
 
 import (
 	"testing"
+	"time"
 )
 
 // BenchmarkLoggerInfo benchmarks the performance of standard Info logging
@@ -64,4 +65,39 @@ func BenchmarkConcurrentLogging(b *testing.B) {
 			i++
 		}
 	})
+}
+
+// BenchmarkProcessBatch compares writing queued records in batches under one configuration snapshot against
+// taking the snapshot for every received record
+func BenchmarkProcessBatch(b *testing.B) {
+	for _, bc := range []struct {
+		name  string
+		limit int
+	}{
+		{"single", 1},
+		{"batched", maxBatchSize},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			logger, _ := createTestLogger(&testing.T{})
+			defer logger.Shutdown()
+
+			ch := make(chan logRecord, maxBatchSize)
+			record := logRecord{
+				Flags:     FlagDefault,
+				TimeStamp: time.Now(),
+				Level:     LevelInfo,
+				Args:      []any{"benchmark message", 1},
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i += maxBatchSize {
+				for range min(maxBatchSize, b.N-i) {
+					ch <- record
+				}
+				for len(ch) > 0 {
+					logger.processBatch(<-ch, ch, bc.limit)
+				}
+			}
+		})
+	}
 }
\ No newline at end of file
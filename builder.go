@@ -1,14 +1,18 @@
 package log
 
 import (
+	"cmp"
+
+	"github.com/lixenwraith/log/formatter"
 	"github.com/lixenwraith/log/sanitizer"
 )
 
 // Builder provides a fluent API for building logger configurations
 // It wraps a Config instance and provides chainable methods for setting values
 type Builder struct {
-	cfg *Config
-	err error // Accumulate errors for deferred handling
+	cfg       *Config
+	syncGroup *SyncGroup
+	err       error // Accumulate errors for deferred handling
 }
 
 // NewBuilder creates a new configuration builder with default values
@@ -24,6 +28,9 @@ func (b *Builder) Build() (*Logger, error) {
 		return nil, b.err
 	}
 
+	// Attribute the values set through the builder before they reach the logger
+	b.cfg.recordChanged(SourceBuilder)
+
 	// Create a new logger
 	logger := NewLogger()
 
@@ -31,6 +38,9 @@ func (b *Builder) Build() (*Logger, error) {
 	if err := logger.ApplyConfig(b.cfg); err != nil {
 		return nil, err
 	}
+	if b.syncGroup != nil {
+		logger.JoinSyncGroup(b.syncGroup)
+	}
 
 	return logger, nil
 }
@@ -55,6 +65,33 @@ func (b *Builder) LevelString(level string) *Builder {
 	return b
 }
 
+// ConsoleLevel sets the minimum level of records written to the console
+func (b *Builder) ConsoleLevel(level int64) *Builder {
+	b.cfg.ConsoleLevel = level
+	return b
+}
+
+// FileLevel sets the minimum level of records written to the log file
+func (b *Builder) FileLevel(level int64) *Builder {
+	b.cfg.FileLevel = level
+	return b
+}
+
+// LevelOverride sets the level for records carrying a matching field, e.g. LevelOverride("component=db.*", "debug")
+func (b *Builder) LevelOverride(rule, level string) *Builder {
+	if b.cfg.LevelOverridesByField == nil {
+		b.cfg.LevelOverridesByField = make(map[string]string)
+	}
+	b.cfg.LevelOverridesByField[rule] = level
+	return b
+}
+
+// SuppressionSummaryIntervalS sets the interval of records counting the records each rule suppressed (0 = disabled)
+func (b *Builder) SuppressionSummaryIntervalS(interval int64) *Builder {
+	b.cfg.SuppressionSummaryIntervalS = interval
+	return b
+}
+
 // Name sets the log level
 func (b *Builder) Name(name string) *Builder {
 	b.cfg.Name = name
@@ -67,30 +104,269 @@ func (b *Builder) Directory(dir string) *Builder {
 	return b
 }
 
+// MirrorDirectory sets a second directory receiving a copy of every record written to the log file
+func (b *Builder) MirrorDirectory(dir string) *Builder {
+	b.cfg.MirrorDirectory = dir
+	return b
+}
+
+// AutoRecreateDir sets whether the log directory and file are recreated if removed at runtime
+func (b *Builder) AutoRecreateDir(enable bool) *Builder {
+	b.cfg.AutoRecreateDir = enable
+	return b
+}
+
+// FileHeader sets whether a metadata header line is written to each new log file
+func (b *Builder) FileHeader(enable bool) *Builder {
+	b.cfg.FileHeader = enable
+	return b
+}
+
+// RotationJournal sets whether rotations are recorded as JSON lines in {name}.rotations
+func (b *Builder) RotationJournal(enable bool) *Builder {
+	b.cfg.RotationJournal = enable
+	return b
+}
+
+// FadviseDontNeed sets whether rotated files are dropped from the page cache (Linux only)
+func (b *Builder) FadviseDontNeed(enable bool) *Builder {
+	b.cfg.FadviseDontNeed = enable
+	return b
+}
+
+// OpenDSync sets whether log files are opened with O_DSYNC so each write reaches the disk
+func (b *Builder) OpenDSync(enable bool) *Builder {
+	b.cfg.OpenDSync = enable
+	return b
+}
+
+// GzipActive sets whether the active log file is written gzip-compressed as {name}.{ext}.gz
+func (b *Builder) GzipActive(enable bool) *Builder {
+	b.cfg.GzipActive = enable
+	return b
+}
+
+// AuditVerify sets whether audit records are read back from the log file before they are confirmed
+func (b *Builder) AuditVerify(enable bool) *Builder {
+	b.cfg.AuditVerify = enable
+	return b
+}
+
 // Format sets the output format
 func (b *Builder) Format(format string) *Builder {
 	b.cfg.Format = format
 	return b
 }
 
+// ConsoleFormat sets the console output format, overriding Format for the console
+func (b *Builder) ConsoleFormat(format string) *Builder {
+	b.cfg.ConsoleFormat = format
+	return b
+}
+
+// FileFormat sets the file output format, overriding Format for the log file and registered sinks
+func (b *Builder) FileFormat(format string) *Builder {
+	b.cfg.FileFormat = format
+	return b
+}
+
 // Sanitization sets the sanitization mode
 func (b *Builder) Sanitization(policy sanitizer.PolicyPreset) *Builder {
 	b.cfg.Sanitization = policy
 	return b
 }
 
+// ConsoleSanitization sets the sanitization policy of console output, empty uses Sanitization
+func (b *Builder) ConsoleSanitization(policy sanitizer.PolicyPreset) *Builder {
+	b.cfg.ConsoleSanitization = policy
+	return b
+}
+
+// FileSanitization sets the sanitization policy of file output and registered sinks, empty uses Sanitization
+func (b *Builder) FileSanitization(policy sanitizer.PolicyPreset) *Builder {
+	b.cfg.FileSanitization = policy
+	return b
+}
+
+// NetworkSanitization sets the sanitization policy of syslog, journald, and GELF output, empty uses Sanitization
+func (b *Builder) NetworkSanitization(policy sanitizer.PolicyPreset) *Builder {
+	b.cfg.NetworkSanitization = policy
+	return b
+}
+
+// FormatSanitization sets the sanitization policy of outputs writing format, e.g. FormatSanitization("json", "json")
+func (b *Builder) FormatSanitization(format string, policy sanitizer.PolicyPreset) *Builder {
+	if b.cfg.SanitizationByFormat == nil {
+		b.cfg.SanitizationByFormat = make(map[string]string)
+	}
+	b.cfg.SanitizationByFormat[format] = string(policy)
+	return b
+}
+
+// EagerStringify sets whether Stringer and error arguments are converted to strings at call time
+func (b *Builder) EagerStringify(enable bool) *Builder {
+	b.cfg.EagerStringify = enable
+	return b
+}
+
+// AutoKV sets whether JSON output flattens key-value args into top-level fields
+func (b *Builder) AutoKV(enable bool) *Builder {
+	b.cfg.AutoKV = enable
+	return b
+}
+
+// JSONFields sets how JSON output writes args not flattened by AutoKV: "array" or "object"
+func (b *Builder) JSONFields(mode string) *Builder {
+	b.cfg.JSONFields = mode
+	return b
+}
+
+// JSONKeys renames the keys json records write their own members under, empty names keep the current ones
+func (b *Builder) JSONKeys(keys formatter.JSONKeyNames) *Builder {
+	b.cfg.FieldTimeKey = cmp.Or(keys.Time, b.cfg.FieldTimeKey)
+	b.cfg.FieldLevelKey = cmp.Or(keys.Level, b.cfg.FieldLevelKey)
+	b.cfg.FieldTraceKey = cmp.Or(keys.Trace, b.cfg.FieldTraceKey)
+	b.cfg.FieldFieldsKey = cmp.Or(keys.Fields, b.cfg.FieldFieldsKey)
+	b.cfg.FieldMessageKey = cmp.Or(keys.Message, b.cfg.FieldMessageKey)
+	return b
+}
+
+// EnrichHost sets whether every record gets the machine hostname as "host"
+func (b *Builder) EnrichHost(enable bool) *Builder {
+	b.cfg.EnrichHost = enable
+	return b
+}
+
+// EnrichPID sets whether every record gets the process ID as "pid"
+func (b *Builder) EnrichPID(enable bool) *Builder {
+	b.cfg.EnrichPID = enable
+	return b
+}
+
+// App sets the application name and version added to every record as "app" and "version", empty values are omitted
+func (b *Builder) App(name, version string) *Builder {
+	b.cfg.AppName = name
+	b.cfg.AppVersion = version
+	return b
+}
+
+// NestedKeys sets whether JSON and ECS output expands dotted field keys into nested objects
+func (b *Builder) NestedKeys(enable bool) *Builder {
+	b.cfg.NestedKeys = enable
+	return b
+}
+
+// ReservedKeyPolicy sets how flattened keys colliding with record keys are written: "prefix", "drop", or "allow"
+func (b *Builder) ReservedKeyPolicy(policy string) *Builder {
+	b.cfg.ReservedKeyPolicy = policy
+	return b
+}
+
+// BytesEncoding sets how []byte arguments are rendered: "string", "hex", or "base64"
+func (b *Builder) BytesEncoding(encoding string) *Builder {
+	b.cfg.BytesEncoding = encoding
+	return b
+}
+
+// DurationFormat sets how time.Duration arguments are rendered: "string", "ns", or "ms"
+func (b *Builder) DurationFormat(format string) *Builder {
+	b.cfg.DurationFormat = format
+	return b
+}
+
+// TimeValueFormat sets how time.Time arguments are rendered: "timestamp", "rfc3339", "unix", or "unix_ms"
+func (b *Builder) TimeValueFormat(format string) *Builder {
+	b.cfg.TimeValueFormat = format
+	return b
+}
+
+// CSVColumns sets the comma-separated column order of csv records, from time, level, trace, message, extra, and labels
+func (b *Builder) CSVColumns(columns string) *Builder {
+	b.cfg.CSVColumns = columns
+	return b
+}
+
+// TxtLayout sets the placeholder layout of txt records, e.g. "{time} [{level}] {trace} {msg} {fields}"
+func (b *Builder) TxtLayout(layout string) *Builder {
+	b.cfg.TxtLayout = layout
+	return b
+}
+
+// CustomFormatter sets a formatter rendering records in place of the configured format, nil restores it
+func (b *Builder) CustomFormatter(f RecordFormatter) *Builder {
+	b.cfg.CustomFormatter = f
+	return b
+}
+
+// CEFVendor sets the Device Vendor header field of cef records
+func (b *Builder) CEFVendor(vendor string) *Builder {
+	b.cfg.CEFVendor = vendor
+	return b
+}
+
+// CEFProduct sets the Device Product header field of cef records, defaults to the log name
+func (b *Builder) CEFProduct(product string) *Builder {
+	b.cfg.CEFProduct = product
+	return b
+}
+
+// CEFProductVersion sets the Device Version header field of cef records
+func (b *Builder) CEFProductVersion(version string) *Builder {
+	b.cfg.CEFProductVersion = version
+	return b
+}
+
+// CEFExtension writes the argument key field as the CEF extension key, e.g. CEFExtension("client_ip", "src")
+func (b *Builder) CEFExtension(field, key string) *Builder {
+	if b.cfg.CEFExtensionMap == nil {
+		b.cfg.CEFExtensionMap = make(map[string]string)
+	}
+	b.cfg.CEFExtensionMap[field] = key
+	return b
+}
+
+// GCPProject sets the project ID completing trace_id fields of gcp records to "projects/<id>/traces/<trace_id>"
+func (b *Builder) GCPProject(project string) *Builder {
+	b.cfg.GCPProject = project
+	return b
+}
+
 // Extension sets the log level
 func (b *Builder) Extension(ext string) *Builder {
 	b.cfg.Extension = ext
 	return b
 }
 
+// ReadOnly sets whether the logger only inspects the existing files in its directory
+func (b *Builder) ReadOnly(readOnly bool) *Builder {
+	b.cfg.ReadOnly = readOnly
+	return b
+}
+
 // BufferSize sets the channel buffer size
 func (b *Builder) BufferSize(size int64) *Builder {
 	b.cfg.BufferSize = size
 	return b
 }
 
+// RecentRecords sets how many formatted records are kept in memory for DumpRecent (0 = disabled)
+func (b *Builder) RecentRecords(count int64) *Builder {
+	b.cfg.RecentRecords = count
+	return b
+}
+
+// CallLatencyStats sets whether logging calls are timed, reported in Stats and PROC heartbeats
+func (b *Builder) CallLatencyStats(enable bool) *Builder {
+	b.cfg.CallLatencyStats = enable
+	return b
+}
+
+// CallLatencyBudgetUs sets the logging call duration in microseconds after which the call path sheds work (0 = disabled)
+func (b *Builder) CallLatencyBudgetUs(budget int64) *Builder {
+	b.cfg.CallLatencyBudgetUs = budget
+	return b
+}
+
 // MaxSizeKB sets the maximum log file size in KB
 func (b *Builder) MaxSizeKB(size int64) *Builder {
 	b.cfg.MaxSizeKB = size
@@ -109,6 +385,126 @@ func (b *Builder) EnableFile(enable bool) *Builder {
 	return b
 }
 
+// EnableSyslog sets whether records are forwarded to a syslog daemon
+func (b *Builder) EnableSyslog(enable bool) *Builder {
+	b.cfg.EnableSyslog = enable
+	return b
+}
+
+// SyslogNetwork sets the syslog network: "udp", "tcp", "unix", "unixgram", or "" for the local daemon
+func (b *Builder) SyslogNetwork(network string) *Builder {
+	b.cfg.SyslogNetwork = network
+	return b
+}
+
+// SyslogAddress sets the syslog daemon address or socket path
+func (b *Builder) SyslogAddress(address string) *Builder {
+	b.cfg.SyslogAddress = address
+	return b
+}
+
+// SyslogFormat sets the syslog message format, "rfc3164" or "rfc5424"
+func (b *Builder) SyslogFormat(format string) *Builder {
+	b.cfg.SyslogFormat = format
+	return b
+}
+
+// SyslogFacility sets the syslog facility by name, e.g. "daemon" or "local0"
+func (b *Builder) SyslogFacility(facility string) *Builder {
+	b.cfg.SyslogFacility = facility
+	return b
+}
+
+// SyslogTag sets the syslog APP-NAME/TAG, defaults to the log name
+func (b *Builder) SyslogTag(tag string) *Builder {
+	b.cfg.SyslogTag = tag
+	return b
+}
+
+// SyslogSDID sets the SD-ID of the structured data element of rfc5424 records, "name@enterprise-number"
+func (b *Builder) SyslogSDID(sdID string) *Builder {
+	b.cfg.SyslogSDID = sdID
+	return b
+}
+
+// EnableGELF sets whether records are shipped to a Graylog GELF input
+func (b *Builder) EnableGELF(enable bool) *Builder {
+	b.cfg.EnableGELF = enable
+	return b
+}
+
+// GELFNetwork sets the GELF transport, "udp" or "tcp"
+func (b *Builder) GELFNetwork(network string) *Builder {
+	b.cfg.GELFNetwork = network
+	return b
+}
+
+// GELFAddress sets the GELF input address, e.g. "graylog.example.com:12201"
+func (b *Builder) GELFAddress(address string) *Builder {
+	b.cfg.GELFAddress = address
+	return b
+}
+
+// GELFHost sets the host field of GELF messages, defaults to the machine hostname
+func (b *Builder) GELFHost(host string) *Builder {
+	b.cfg.GELFHost = host
+	return b
+}
+
+// EnableJournal sets whether records are written to the systemd journal
+func (b *Builder) EnableJournal(enable bool) *Builder {
+	b.cfg.EnableJournal = enable
+	return b
+}
+
+// JournalSocket sets the journal socket path, defaults to the systemd socket
+func (b *Builder) JournalSocket(path string) *Builder {
+	b.cfg.JournalSocket = path
+	return b
+}
+
+// S3Upload sets whether rotated archives are uploaded to an S3-compatible bucket
+func (b *Builder) S3Upload(enable bool) *Builder {
+	b.cfg.S3Upload = enable
+	return b
+}
+
+// S3Endpoint sets the service URL archives are uploaded to
+func (b *Builder) S3Endpoint(endpoint string) *Builder {
+	b.cfg.S3Endpoint = endpoint
+	return b
+}
+
+// S3Bucket sets the bucket receiving uploaded archives
+func (b *Builder) S3Bucket(bucket string) *Builder {
+	b.cfg.S3Bucket = bucket
+	return b
+}
+
+// S3Region sets the region upload requests are signed for
+func (b *Builder) S3Region(region string) *Builder {
+	b.cfg.S3Region = region
+	return b
+}
+
+// S3Prefix sets the object key prefix, expanding {name}, {host}, {yyyy}, {mm}, {dd}, and {hh}
+func (b *Builder) S3Prefix(prefix string) *Builder {
+	b.cfg.S3Prefix = prefix
+	return b
+}
+
+// S3KeepLocal sets whether archives are kept locally after a successful upload
+func (b *Builder) S3KeepLocal(keep bool) *Builder {
+	b.cfg.S3KeepLocal = keep
+	return b
+}
+
+// SyncGroup sets a sync coordinator shared with other loggers, batching their file syncs into group commits
+func (b *Builder) SyncGroup(g *SyncGroup) *Builder {
+	b.syncGroup = g
+	return b
+}
+
 // HeartbeatLevel sets the heartbeat monitoring level
 func (b *Builder) HeartbeatLevel(level int64) *Builder {
 	b.cfg.HeartbeatLevel = level
@@ -121,6 +517,18 @@ func (b *Builder) HeartbeatIntervalS(interval int64) *Builder {
 	return b
 }
 
+// HeartbeatIncidentIntervalS sets the heartbeat interval used while records drop or disk status is not OK
+func (b *Builder) HeartbeatIncidentIntervalS(interval int64) *Builder {
+	b.cfg.HeartbeatIncidentIntervalS = interval
+	return b
+}
+
+// HeartbeatFormat sets the format of heartbeats in text files: "json", "logfmt", or empty to follow the file format
+func (b *Builder) HeartbeatFormat(format string) *Builder {
+	b.cfg.HeartbeatFormat = format
+	return b
+}
+
 // ShowTimestamp sets whether to show timestamps in logs
 func (b *Builder) ShowTimestamp(show bool) *Builder {
 	b.cfg.ShowTimestamp = show
@@ -157,12 +565,48 @@ func (b *Builder) MinDiskFreeKB(size int64) *Builder {
 	return b
 }
 
+// OnDiskFull sets how records bound for the file are handled while disk limits block it: "drop", "stderr", or "block"
+func (b *Builder) OnDiskFull(policy string) *Builder {
+	b.cfg.OnDiskFull = policy
+	return b
+}
+
 // MinDiskFreeMB sets the minimum required free disk space in MB
 func (b *Builder) MinDiskFreeMB(size int64) *Builder {
 	b.cfg.MinDiskFreeKB = size * sizeMultiplier
 	return b
 }
 
+// Shards sets the number of parallel shard files, each with its own writer (0 or 1 = single file)
+func (b *Builder) Shards(count int64) *Builder {
+	b.cfg.Shards = count
+	return b
+}
+
+// SplitErrorFile sets whether WARN and ERROR records are also written to {name}_error.{ext}
+func (b *Builder) SplitErrorFile(enable bool) *Builder {
+	b.cfg.SplitErrorFile = enable
+	return b
+}
+
+// MaxFieldsPerRecord sets the maximum number of args or structured fields per record (0 = unlimited)
+func (b *Builder) MaxFieldsPerRecord(count int64) *Builder {
+	b.cfg.MaxFieldsPerRecord = count
+	return b
+}
+
+// MaxFieldKeyLen sets the maximum field key length in bytes (0 = unlimited)
+func (b *Builder) MaxFieldKeyLen(length int64) *Builder {
+	b.cfg.MaxFieldKeyLen = length
+	return b
+}
+
+// FieldLimitPolicy sets how records exceeding field limits are handled: "truncate", "drop_extra", or "reject"
+func (b *Builder) FieldLimitPolicy(policy string) *Builder {
+	b.cfg.FieldLimitPolicy = policy
+	return b
+}
+
 // FlushIntervalMs sets the flush interval in milliseconds
 func (b *Builder) FlushIntervalMs(interval int64) *Builder {
 	b.cfg.FlushIntervalMs = interval
@@ -187,6 +631,12 @@ func (b *Builder) RetentionCheckMins(mins float64) *Builder {
 	return b
 }
 
+// RetentionDryRun sets whether retention and disk limit cleanups only log the files they would delete
+func (b *Builder) RetentionDryRun(enable bool) *Builder {
+	b.cfg.RetentionDryRun = enable
+	return b
+}
+
 // DiskCheckIntervalMs sets the disk check interval in milliseconds
 func (b *Builder) DiskCheckIntervalMs(interval int64) *Builder {
 	b.cfg.DiskCheckIntervalMs = interval
@@ -217,12 +667,85 @@ func (b *Builder) MaxCheckIntervalMs(interval int64) *Builder {
 	return b
 }
 
+// SizeReconcileIntervalS sets the seconds between directory scans correcting the archive size totals, 0 scans on every check
+func (b *Builder) SizeReconcileIntervalS(interval int64) *Builder {
+	b.cfg.SizeReconcileIntervalS = interval
+	return b
+}
+
 // ConsoleTarget sets the console output target ("stdout", "stderr", or "split")
 func (b *Builder) ConsoleTarget(target string) *Builder {
 	b.cfg.ConsoleTarget = target
 	return b
 }
 
+// ConsoleGlyphs sets whether console levels are rendered as colored glyphs, file output is unaffected
+func (b *Builder) ConsoleGlyphs(enable bool) *Builder {
+	b.cfg.ConsoleGlyphs = enable
+	return b
+}
+
+// ConsoleColor sets when console output is colored by level: "auto" (terminals, unless NO_COLOR is set), "always", or "never"
+func (b *Builder) ConsoleColor(mode string) *Builder {
+	b.cfg.ConsoleColor = mode
+	return b
+}
+
+// ConsoleColorLine sets whether whole console lines are colored by level instead of the level token
+func (b *Builder) ConsoleColorLine(enable bool) *Builder {
+	b.cfg.ConsoleColorLine = enable
+	return b
+}
+
+// ConsoleFileDrops sets whether WARN and above dropped from the file are mirrored to the console, tagged [file-drop]
+func (b *Builder) ConsoleFileDrops(enable bool) *Builder {
+	b.cfg.ConsoleFileDrops = enable
+	return b
+}
+
+// FlushOnExit sets whether to flush on SIGINT or SIGTERM when Shutdown was not called
+func (b *Builder) FlushOnExit(enable bool) *Builder {
+	b.cfg.FlushOnExit = enable
+	return b
+}
+
+// FlushOnExitReraise sets whether the exit signal is re-sent after the flush so the process terminates, for
+// applications that do not handle SIGINT or SIGTERM themselves
+func (b *Builder) FlushOnExitReraise(enable bool) *Builder {
+	b.cfg.FlushOnExitReraise = enable
+	return b
+}
+
+// OnErrorExec sets the command run when a record at or above the on_error_exec level is written
+func (b *Builder) OnErrorExec(command string) *Builder {
+	b.cfg.OnErrorExec = command
+	return b
+}
+
+// OnErrorExecLevel sets the minimum level of records triggering the on_error_exec command
+func (b *Builder) OnErrorExecLevel(level int64) *Builder {
+	b.cfg.OnErrorExecLevel = level
+	return b
+}
+
+// OnErrorExecIntervalS sets the minimum seconds between runs of the on_error_exec command
+func (b *Builder) OnErrorExecIntervalS(seconds int64) *Builder {
+	b.cfg.OnErrorExecIntervalS = seconds
+	return b
+}
+
+// InternalErrorsToLog sets whether output and retention errors are logged as WARN records
+func (b *Builder) InternalErrorsToLog(enable bool) *Builder {
+	b.cfg.InternalErrorsToLog = enable
+	return b
+}
+
+// InternalErrorsLogIntervalS sets the minimum seconds between internal error records of one source
+func (b *Builder) InternalErrorsLogIntervalS(seconds int64) *Builder {
+	b.cfg.InternalErrorsLogIntervalS = seconds
+	return b
+}
+
 // InternalErrorsToStderr sets whether to write internal errors to stderr
 func (b *Builder) InternalErrorsToStderr(enable bool) *Builder {
 	b.cfg.InternalErrorsToStderr = enable
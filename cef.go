@@ -0,0 +1,58 @@
+package log
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/lixenwraith/log/formatter"
+)
+
+// cefSettings holds the CEF header fields and extension keys in comparable form, so outputs can detect changes
+type cefSettings struct {
+	vendor  string
+	product string
+	version string
+	keys    string // Extension map as sorted "field=key" entries
+}
+
+// newCEFSettings extracts CEF settings from cfg, the product defaults to the log name
+func newCEFSettings(cfg *Config) cefSettings {
+	product := cfg.CEFProduct
+	if product == "" {
+		product = cfg.Name
+	}
+	entries := make([]string, 0, len(cfg.CEFExtensionMap))
+	for _, field := range slices.Sorted(maps.Keys(cfg.CEFExtensionMap)) {
+		entries = append(entries, field+"="+cfg.CEFExtensionMap[field])
+	}
+	return cefSettings{
+		vendor:  cfg.CEFVendor,
+		product: product,
+		version: cfg.CEFProductVersion,
+		keys:    strings.Join(entries, ","),
+	}
+}
+
+// apply sets the CEF header fields and extension keys of f
+func (s cefSettings) apply(f *formatter.Formatter) *formatter.Formatter {
+	keys, _ := parseCEFExtensionMap(s.keys)
+	return f.CEFDevice(s.vendor, s.product, s.version).CEFExtensionMap(keys)
+}
+
+// parseCEFExtensionMap parses "field=key,..." into a map of argument keys to CEF extension keys
+func parseCEFExtensionMap(value string) (map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		field, key, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmtErrorf("invalid cef_extension_map entry '%s' (use field=key)", entry)
+		}
+		keys[strings.TrimSpace(field)] = strings.TrimSpace(key)
+	}
+	return keys, nil
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"path/filepath"
+)
+
+// CloneWith creates an independent logger from a copy of this logger's configuration, modified by cfgMutator
+// Intended for short-lived jobs wanting isolated logs, e.g. one file per migration. The clone has its own
+// processor and files, keeps this logger's bound fields, labels, and group, and is started if this logger is started
+// With file output on both loggers the clone must use a different directory or a name whose files cannot
+// be mistaken for this logger's archives. The caller shuts the clone down when done
+func (l *Logger) CloneWith(cfgMutator func(*Config)) (*Logger, error) {
+	current := l.getConfig()
+	cfg := current.Clone()
+	if cfgMutator != nil {
+		cfgMutator(cfg)
+	}
+
+	if current.EnableFile && cfg.EnableFile && filesCollide(current, cfg) {
+		return nil, fmtErrorf("cloned logger must write to a separate file: '%s' in '%s' collides with the source logger's files",
+			cfg.Name, cfg.Directory)
+	}
+
+	clone := NewLogger()
+	if err := clone.ApplyConfig(cfg); err != nil {
+		return nil, fmtErrorf("failed to configure cloned logger: %w", err)
+	}
+	clone.fields = l.fields
+	clone.labels = l.labels
+	clone.group = l.group
+
+	if l.state.Started.Load() {
+		if err := clone.Start(); err != nil {
+			_ = clone.Shutdown()
+			return nil, fmtErrorf("failed to start cloned logger: %w", err)
+		}
+	}
+	return clone, nil
+}
+
+// filesCollide reports whether two configurations share a directory and either one's file matcher claims
+// the other's active file, which would make rotation and retention of one touch the other's files
+func filesCollide(a, b *Config) bool {
+	if resolvePath(absPath(a.Directory)) != resolvePath(absPath(b.Directory)) {
+		return false
+	}
+	matcherA := newLogFileMatcher(a.Name, a.fileExtension())
+	matcherB := newLogFileMatcher(b.Name, b.fileExtension())
+	return matcherA.matches(matcherB.activeName) || matcherB.matches(matcherA.activeName)
+}
+
+// absPath returns the absolute form of path, or the cleaned path if the working directory is unavailable
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
\ No newline at end of file
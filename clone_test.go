@@ -0,0 +1,77 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCloneWith verifies that a clone writes its own file with inherited settings, bound fields, and group
+func TestCloneWith(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+	bound := logger.withFields("service", "api").WithGroup("job")
+
+	clone, err := bound.CloneWith(func(cfg *Config) {
+		cfg.Name = "migration"
+		cfg.Format = "json"
+	})
+	require.NoError(t, err)
+	defer clone.Shutdown()
+
+	cloneCfg := clone.GetConfig()
+	assert.Equal(t, tmpDir, cloneCfg.Directory)
+	assert.Equal(t, int64(1000), cloneCfg.BufferSize, "Unmodified settings are inherited")
+	assert.True(t, clone.state.Started.Load(), "Clone of a started logger is started")
+
+	clone.Info("step 1 done", "rows", 2)
+	logger.Info("main record")
+	require.NoError(t, clone.Flush(time.Second))
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "migration.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"step 1 done","service","api","job.rows",2`)
+	assert.NotContains(t, string(content), "main record")
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "step 1 done")
+	assert.Equal(t, "raw", logger.GetConfig().Format, "Source configuration is unchanged")
+}
+
+// TestCloneWithCollision verifies that clones cannot share or shadow the source logger's files
+func TestCloneWithCollision(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+
+	tests := []struct {
+		name    string
+		mutator func(*Config)
+		wantErr bool
+	}{
+		{"nil mutator", nil, true},
+		{"same name", func(cfg *Config) { cfg.Level = LevelDebug }, true},
+		{"archive-like name", func(cfg *Config) { cfg.Name = "log_job" }, true},
+		{"other extension", func(cfg *Config) { cfg.Extension = "job" }, false},
+		{"other directory", func(cfg *Config) { cfg.Directory = t.TempDir() }, false},
+		{"file output disabled", func(cfg *Config) { cfg.EnableFile = false }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clone, err := logger.CloneWith(tt.mutator)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "separate file")
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, clone.Shutdown())
+		})
+	}
+}
\ No newline at end of file
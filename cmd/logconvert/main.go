@@ -0,0 +1,60 @@
+// Command logconvert converts binary journal log files to txt, json, or raw output
+//
+// Usage:
+//
+//	logconvert [-format txt|json|raw] [-timestamp layout] file...
+//
+// Reads standard input when no files are given and writes to standard output
+// Gzip-compressed input, such as files written with gzip_active, is decompressed
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lixenwraith/log/logreader"
+)
+
+func main() {
+	format := flag.String("format", "json", "output format: txt, json, gelf, or raw")
+	timestampFormat := flag.String("timestamp", time.RFC3339Nano, "timestamp layout (Go time format)")
+	flag.Parse()
+
+	if err := run(os.Stdout, flag.Args(), *format, *timestampFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "logconvert: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run converts each input file in order, or standard input if none are given
+func run(dst io.Writer, paths []string, format, timestampFormat string) error {
+	if len(paths) == 0 {
+		return convert(dst, os.Stdin, format, timestampFormat)
+	}
+
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		err = convert(dst, file, format, timestampFormat)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// convert decompresses src if needed and converts it
+func convert(dst io.Writer, src io.Reader, format, timestampFormat string) error {
+	r, err := logreader.Decompress(src)
+	if err != nil {
+		return err
+	}
+	_, err = logreader.Convert(dst, r, format, timestampFormat)
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,81 @@
+// Command logmerge merges log files, such as shards, split error files, and rotated archives, into one
+// chronologically ordered stream
+//
+// Usage:
+//
+//	logmerge [-input binary|json|txt|raw] [-format txt|json|gelf|raw] [-timestamp layout] file...
+//
+// Records are ordered by timestamp; records with equal timestamps keep their order within a file and the order
+// of the files on the command line, so archives should be listed oldest first
+// Binary journal input is re-rendered in -format (json by default); line input is merged as written, lines
+// without a timestamp staying with the record before them
+// Gzip-compressed input, such as files written with gzip_active, is decompressed; output goes to standard output
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lixenwraith/log/logreader"
+)
+
+func main() {
+	input := flag.String("input", "binary", "input format: binary, json, txt, or raw")
+	format := flag.String("format", "", "output format for binary input: txt, json, gelf, or raw (default json)")
+	timestampFormat := flag.String("timestamp", time.RFC3339Nano, "timestamp layout (Go time format) of txt and raw input and of output")
+	flag.Parse()
+
+	if err := run(os.Stdout, flag.Args(), *input, *format, *timestampFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "logmerge: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run opens every input file and merges them into dst
+func run(dst io.Writer, paths []string, input, format, timestampFormat string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no input files")
+	}
+
+	var timeOf logreader.TimeFunc
+	switch input {
+	case "binary":
+		if format == "" {
+			format = "json"
+		}
+	case "json":
+		timeOf = logreader.JSONTime
+	case "txt", "raw":
+		timeOf = logreader.TextTime(timestampFormat)
+	default:
+		return fmt.Errorf("unsupported input format '%s' (use binary, json, txt, or raw)", input)
+	}
+	if timeOf != nil && format != "" && format != input {
+		return fmt.Errorf("%s input is merged as written, -format only converts binary input", input)
+	}
+
+	srcs := make([]io.Reader, 0, len(paths))
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r, err := logreader.Decompress(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		srcs = append(srcs, r)
+	}
+
+	var err error
+	if timeOf != nil {
+		_, err = logreader.MergeLines(dst, timeOf, srcs...)
+	} else {
+		_, err = logreader.MergeConvert(dst, format, timestampFormat, srcs...)
+	}
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,157 @@
+package compat
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/lixenwraith/log"
+)
+
+// JSONBridge converts JSON log lines emitted by other logging libraries into lixenwraith/log records
+// It is an io.Writer set as the other library's output, so mixed-library applications converge into one file
+// without compat depending on those libraries; the zerologbridge and logrusbridge modules implement the libraries'
+// own LevelWriter, Hook, and Formatter interfaces and keep source timestamps
+//
+//	zl := zerolog.New(compat.NewZerologBridge(appLogger))
+//	logrus.SetFormatter(&logrus.JSONFormatter{})
+//	logrus.SetOutput(compat.NewLogrusBridge(appLogger))
+type JSONBridge struct {
+	adapterState
+	logger     *log.Logger
+	source     string // Value of the "source" field on bridged records
+	messageKey string // JSON key holding the message
+	levelKey   string // JSON key holding the level name
+	timeKey    string // JSON key holding the timestamp, dropped in favor of the logger's own
+	consoleTag string // Console-only component tag, empty when disabled
+}
+
+// BridgeOption allows customizing bridge behavior
+type BridgeOption func(*JSONBridge)
+
+// WithBridgeConsoleTag prefixes console output with a level-colored tag named after the source library
+func WithBridgeConsoleTag(enable bool) BridgeOption {
+	return func(b *JSONBridge) {
+		b.consoleTag = consoleTagFor(enable, b.source)
+	}
+}
+
+// WithBridgeKeys overrides the JSON keys for message, level, and time, for libraries configured with custom field names
+func WithBridgeKeys(messageKey, levelKey, timeKey string) BridgeOption {
+	return func(b *JSONBridge) {
+		b.messageKey = messageKey
+		b.levelKey = levelKey
+		b.timeKey = timeKey
+	}
+}
+
+// NewZerologBridge creates a bridge for zerolog's default JSON output
+// Use as the writer passed to zerolog.New or zerolog.MultiLevelWriter
+func NewZerologBridge(logger *log.Logger, opts ...BridgeOption) *JSONBridge {
+	return newJSONBridge(logger, "zerolog", "message", "level", "time", opts)
+}
+
+// NewLogrusBridge creates a bridge for logrus configured with logrus.JSONFormatter
+// Use as the writer passed to logrus.SetOutput or a Logger's Out field
+func NewLogrusBridge(logger *log.Logger, opts ...BridgeOption) *JSONBridge {
+	return newJSONBridge(logger, "logrus", "msg", "level", "time", opts)
+}
+
+// newJSONBridge applies options after defaults so option values take precedence
+func newJSONBridge(logger *log.Logger, source, messageKey, levelKey, timeKey string, opts []BridgeOption) *JSONBridge {
+	b := &JSONBridge{
+		logger:     logger,
+		source:     source,
+		messageKey: messageKey,
+		levelKey:   levelKey,
+		timeKey:    timeKey,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// Write parses each JSON line in p and logs it, lines that are not JSON objects are logged as INFO messages
+// Always reports the full length as written so the source library never sees a short write
+func (b *JSONBridge) Write(p []byte) (int, error) {
+	if b.isShutdown() {
+		return len(p), nil
+	}
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		b.writeLine(line)
+	}
+	return len(p), nil
+}
+
+// writeLine converts a single JSON object into a record with msg, source, and remaining fields in key order
+func (b *JSONBridge) writeLine(line []byte) {
+	var entry map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&entry); err != nil {
+		b.logger.LogWithConsoleTag(log.LevelInfo, b.consoleTag, "msg", string(line), "source", b.source)
+		return
+	}
+
+	levelName, _ := entry[b.levelKey].(string)
+	message, _ := entry[b.messageKey].(string)
+	delete(entry, b.levelKey)
+	delete(entry, b.messageKey)
+	delete(entry, b.timeKey)
+
+	keys := make([]string, 0, len(entry))
+	for k := range entry {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]any, 0, 4+len(keys)*2)
+	fields = append(fields, "msg", message, "source", b.source)
+	if levelName == "trace" || levelName == "fatal" || levelName == "panic" {
+		// Preserve levels without a direct equivalent
+		fields = append(fields, "level", levelName)
+	}
+	for _, k := range keys {
+		fields = append(fields, k, bridgeValue(entry[k]))
+	}
+
+	b.logger.LogWithConsoleTag(bridgeLevel(levelName), b.consoleTag, fields...)
+}
+
+// bridgeLevel maps zerolog and logrus level names to log levels
+// fatal and panic map to ERROR, the source library still handles exit or panic itself
+func bridgeLevel(name string) int64 {
+	switch strings.ToLower(name) {
+	case "trace", "debug":
+		return log.LevelDebug
+	case "warn", "warning":
+		return log.LevelWarn
+	case "error", "fatal", "panic":
+		return log.LevelError
+	default:
+		return log.LevelInfo
+	}
+}
+
+// bridgeValue converts JSON numbers to int64 or float64, other values are returned unchanged
+func bridgeValue(v any) any {
+	num, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := num.Int64(); err == nil {
+		return i
+	}
+	if f, err := num.Float64(); err == nil {
+		return f
+	}
+	return num.String()
+}
\ No newline at end of file
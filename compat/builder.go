@@ -2,6 +2,8 @@ package compat
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/lixenwraith/log"
 )
@@ -12,6 +14,9 @@ type Builder struct {
 	logger *log.Logger
 	logCfg *log.Config
 	err    error
+
+	mu       sync.Mutex
+	adapters []shutdowner // Adapters built so far, shut down by ShutdownAll
 }
 
 // NewBuilder creates a new adapter builder
@@ -75,7 +80,7 @@ func (b *Builder) BuildGnet(opts ...GnetOption) (*GnetAdapter, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewGnetAdapter(l, opts...), nil
+	return track(b, NewGnetAdapter(l, opts...)), nil
 }
 
 // BuildStructuredGnet creates a gnet adapter that attempts to extract structured
@@ -85,7 +90,9 @@ func (b *Builder) BuildStructuredGnet(opts ...GnetOption) (*StructuredGnetAdapte
 	if err != nil {
 		return nil, err
 	}
-	return NewStructuredGnetAdapter(l, opts...), nil
+	adapter := NewStructuredGnetAdapter(l, opts...)
+	track(b, adapter.GnetAdapter)
+	return adapter, nil
 }
 
 // BuildFastHTTP creates a fasthttp adapter
@@ -94,7 +101,7 @@ func (b *Builder) BuildFastHTTP(opts ...FastHTTPOption) (*FastHTTPAdapter, error
 	if err != nil {
 		return nil, err
 	}
-	return NewFastHTTPAdapter(l, opts...), nil
+	return track(b, NewFastHTTPAdapter(l, opts...)), nil
 }
 
 // BuildFiber creates a Fiber v2.54.x adapter
@@ -103,7 +110,73 @@ func (b *Builder) BuildFiber(opts ...FiberOption) (*FiberAdapter, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewFiberAdapter(l, opts...), nil
+	return track(b, NewFiberAdapter(l, opts...)), nil
+}
+
+// BuildHTTPMiddleware creates a net/http request logging middleware
+func (b *Builder) BuildHTTPMiddleware(opts ...HTTPOption) (*HTTPMiddleware, error) {
+	l, err := b.getLogger()
+	if err != nil {
+		return nil, err
+	}
+	return track(b, NewHTTPMiddleware(l, opts...)), nil
+}
+
+// BuildZerologBridge creates a writer converting zerolog JSON output into log records
+func (b *Builder) BuildZerologBridge(opts ...BridgeOption) (*JSONBridge, error) {
+	l, err := b.getLogger()
+	if err != nil {
+		return nil, err
+	}
+	return track(b, NewZerologBridge(l, opts...)), nil
+}
+
+// BuildLogrusBridge creates a writer converting logrus JSONFormatter output into log records
+func (b *Builder) BuildLogrusBridge(opts ...BridgeOption) (*JSONBridge, error) {
+	l, err := b.getLogger()
+	if err != nil {
+		return nil, err
+	}
+	return track(b, NewLogrusBridge(l, opts...)), nil
+}
+
+// track registers an adapter for ShutdownAll and returns it
+func track[T shutdowner](b *Builder, adapter T) T {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.adapters = append(b.adapters, adapter)
+	return adapter
+}
+
+// ShutdownAll shuts down every adapter built by this builder, then shuts down the underlying logger
+// Adapters are silenced first, so logging during framework teardown (e.g. fasthttp closing connections)
+// is discarded instead of reaching a stopped logger; records already logged are flushed by the logger's shutdown
+// The logger is shut down even if it was provided with WithLogger
+func (b *Builder) ShutdownAll(timeout time.Duration) error {
+	b.mu.Lock()
+	adapters := b.adapters
+	b.adapters = nil
+	b.mu.Unlock()
+
+	for _, adapter := range adapters {
+		adapter.shutdown()
+	}
+
+	if b.logger == nil {
+		return nil
+	}
+	if err := b.logger.Shutdown(timeout); err != nil {
+		return fmt.Errorf("log/compat: failed to shut down logger: %w", err)
+	}
+	return nil
+}
+
+// consoleTagFor returns the console tag for an adapter, or empty when tagging is disabled
+func consoleTagFor(enable bool, tag string) string {
+	if !enable {
+		return ""
+	}
+	return tag
 }
 
 // GetLogger returns the underlying *log.Logger instance
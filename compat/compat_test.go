@@ -3,6 +3,9 @@ package compat
 import (
 	"bufio"
 	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -346,4 +349,211 @@ func TestFiberBuilderIntegration(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, fiberAdapter)
 	assert.Equal(t, logger, fiberAdapter.logger)
+}
+
+// TestAdapterConsoleTag verifies that the console tag decorates console output only
+func TestAdapterConsoleTag(t *testing.T) {
+	// Redirect stdout before the logger captures its console writer
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	logger, err := log.NewBuilder().
+		Directory(tmpDir).
+		Format("json").
+		EnableFile(true).
+		EnableConsole(true).
+		ConsoleTarget("stdout").
+		ConsoleColor("always").
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, logger.Start())
+	defer logger.Shutdown()
+
+	builder := NewBuilder().WithLogger(logger)
+	fiberAdapter, err := builder.BuildFiber(WithFiberConsoleTag(true))
+	require.NoError(t, err)
+	gnetAdapter, err := builder.BuildGnet()
+	require.NoError(t, err)
+
+	fiberAdapter.Error("tagged message")
+	gnetAdapter.Infof("untagged message")
+
+	lines := readLogFile(t, tmpDir, 2)
+	require.NoError(t, logger.Flush(time.Second))
+	w.Close()
+
+	console, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(console), "\x1b[31m[fiber]\x1b[0m {")
+	assert.NotContains(t, string(console), "[gnet]")
+	for _, line := range lines {
+		assert.NotContains(t, line, "[fiber]", "File output must not carry the console tag")
+	}
+}
+
+// TestAdapterSource verifies the source override and static fields tell adapter instances apart
+func TestAdapterSource(t *testing.T) {
+	builder, logger, tmpDir := createTestCompatBuilder(t)
+	defer logger.Shutdown()
+
+	edge, err := builder.BuildFastHTTP(WithFastHTTPSource("edge-proxy"), WithFastHTTPStaticFields("region", "eu-1"))
+	require.NoError(t, err)
+	api, err := builder.BuildFiber(WithFiberSource("api"), WithFiberStaticFields("tier", "public", "dangling"))
+	require.NoError(t, err)
+	gnetAdapter, err := builder.BuildGnet()
+	require.NoError(t, err)
+
+	edge.Printf("proxied")
+	api.Infow("served", "status", 200)
+	gnetAdapter.Infof("accepted")
+	require.NoError(t, logger.Flush(time.Second))
+
+	lines := readLogFile(t, tmpDir, 3)
+	require.Len(t, lines, 3)
+	expected := [][]any{
+		{"msg", "proxied", "source", "edge-proxy", "region", "eu-1"},
+		{"msg", "served", "source", "api", "tier", "public", "status", 200.0},
+		{"msg", "accepted", "source", "gnet"},
+	}
+	for i, line := range lines {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, expected[i], entry["fields"])
+	}
+}
+
+// TestJSONBridges verifies zerolog and logrus JSON output is converted into log records
+func TestJSONBridges(t *testing.T) {
+	builder, logger, tmpDir := createTestCompatBuilder(t)
+	defer logger.Shutdown()
+
+	zerologBridge, err := builder.BuildZerologBridge()
+	require.NoError(t, err)
+	logrusBridge, err := builder.BuildLogrusBridge()
+	require.NoError(t, err)
+
+	// Output as produced by zerolog.New(w).Info().Int("port", 8080).Msg(...) and logrus.JSONFormatter
+	_, err = zerologBridge.Write([]byte(`{"level":"info","port":8080,"time":"2024-01-15T10:30:00Z","message":"server started"}` + "\n"))
+	require.NoError(t, err)
+	logrusOutput := []byte(`{"level":"warning","msg":"slow query","ms":12.5,"time":"2024-01-15T10:30:00Z"}` + "\n" + "not json\n")
+	n, err := logrusBridge.Write(logrusOutput)
+	require.NoError(t, err)
+	assert.Equal(t, len(logrusOutput), n, "Full length should be reported")
+
+	require.NoError(t, logger.Flush(time.Second))
+	lines := readLogFile(t, tmpDir, 3)
+
+	expected := []struct {
+		level  string
+		fields []any
+	}{
+		{"INFO", []any{"msg", "server started", "source", "zerolog", "port", 8080.0}},
+		{"WARN", []any{"msg", "slow query", "source", "logrus", "ms", 12.5}},
+		{"INFO", []any{"msg", "not json", "source", "logrus"}},
+	}
+	for i, line := range lines {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, expected[i].level, entry["level"])
+		assert.Equal(t, expected[i].fields, entry["fields"])
+	}
+}
+
+// TestHTTPMiddleware verifies request records, traceparent propagation, and the request-scoped logger
+func TestHTTPMiddleware(t *testing.T) {
+	builder, logger, tmpDir := createTestCompatBuilder(t)
+	defer logger.Shutdown()
+
+	middleware, err := builder.BuildHTTPMiddleware(WithHTTPSkip(func(r *http.Request) bool {
+		return r.URL.Path == "/healthz"
+	}))
+	require.NoError(t, err)
+
+	handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.FromContext(r.Context()).Info("handled", "path", r.URL.Path)
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/missing", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	require.NoError(t, logger.Flush(time.Second))
+	lines := readLogFile(t, tmpDir, 5)
+	require.Len(t, lines, 5, "Skipped requests log only from the handler")
+
+	entries := make([]map[string]any, len(lines))
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &entries[i]))
+	}
+
+	traced := entries[0]["fields"].([]any)
+	assert.Equal(t, []any{"handled", "trace_id", "4bf92f3577b34da6a3ce929d0e0e4736", "span_id", "00f067aa0ba902b7", "path", "/orders"}, traced)
+
+	request := entries[1]["fields"].([]any)
+	assert.Equal(t, "INFO", entries[1]["level"])
+	assert.Equal(t, []any{"msg", "http request", "trace_id", "4bf92f3577b34da6a3ce929d0e0e4736", "span_id", "00f067aa0ba902b7",
+		"source", "http", "method", "GET", "path", "/orders", "status", 200.0, "bytes", 2.0}, request[:16])
+
+	assert.Equal(t, []any{"handled", "path", "/missing"}, entries[2]["fields"], "No trace fields without a traceparent")
+	assert.Equal(t, "WARN", entries[3]["level"])
+	assert.Contains(t, entries[3]["fields"], 404.0)
+	assert.Equal(t, []any{"handled", "path", "/healthz"}, entries[4]["fields"])
+
+	for _, header := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		_, _, ok := parseTraceparent(header)
+		assert.False(t, ok, header)
+	}
+	_, _, ok := parseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future")
+	assert.True(t, ok, "Later versions may append fields")
+}
+
+// TestBuilderShutdownAll verifies adapters are silenced before the logger shuts down
+func TestBuilderShutdownAll(t *testing.T) {
+	builder, logger, tmpDir := createTestCompatBuilder(t)
+
+	fasthttpAdapter, err := builder.BuildFastHTTP()
+	require.NoError(t, err)
+	structuredAdapter, err := builder.BuildStructuredGnet()
+	require.NoError(t, err)
+	bridge, err := builder.BuildZerologBridge()
+	require.NoError(t, err)
+
+	fasthttpAdapter.Printf("before shutdown")
+	require.NoError(t, builder.ShutdownAll(time.Second))
+
+	// Late teardown logging must be discarded without panics
+	assert.NotPanics(t, func() {
+		fasthttpAdapter.Printf("connection closed")
+		structuredAdapter.Infof("client=%s disconnected", "10.0.0.1")
+		n, err := bridge.Write([]byte(`{"level":"info","message":"late"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, 33, n)
+	})
+	assert.Error(t, logger.Flush(100*time.Millisecond), "Logger should be shut down")
+
+	lines := readLogFile(t, tmpDir, 1)
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "before shutdown")
+
+	// Repeated calls are safe
+	assert.NoError(t, builder.ShutdownAll(time.Second))
 }
\ No newline at end of file
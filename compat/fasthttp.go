@@ -9,9 +9,12 @@ import (
 
 // FastHTTPAdapter wraps lixenwraith/log.Logger to implement fasthttp Logger interface
 type FastHTTPAdapter struct {
+	adapterState
 	logger        *log.Logger
 	defaultLevel  int64
 	levelDetector func(string) int64 // Function to detect log level from message
+	consoleTag    string             // Console-only component tag, empty when disabled
+	source        adapterSource      // Source and static fields of every record
 }
 
 // NewFastHTTPAdapter creates a new fasthttp-compatible logger adapter
@@ -20,11 +23,14 @@ func NewFastHTTPAdapter(logger *log.Logger, opts ...FastHTTPOption) *FastHTTPAda
 		logger:        logger,
 		defaultLevel:  log.LevelInfo,
 		levelDetector: DetectLogLevel, // Default level detection
+		source:        adapterSource{name: "fasthttp"},
 	}
 
 	for _, opt := range opts {
 		opt(adapter)
 	}
+	// The console tag names the instance like the source field
+	adapter.consoleTag = consoleTagFor(adapter.consoleTag != "", adapter.source.name)
 
 	return adapter
 }
@@ -46,8 +52,32 @@ func WithLevelDetector(detector func(string) int64) FastHTTPOption {
 	}
 }
 
+// WithFastHTTPConsoleTag prefixes console output with a level-colored "[fasthttp]" tag, file output is unaffected
+func WithFastHTTPConsoleTag(enable bool) FastHTTPOption {
+	return func(a *FastHTTPAdapter) {
+		a.consoleTag = consoleTagFor(enable, "fasthttp")
+	}
+}
+
+// WithFastHTTPSource replaces "fasthttp" as the value of the source field and console tag, to tell adapter instances apart
+func WithFastHTTPSource(source string) FastHTTPOption {
+	return func(a *FastHTTPAdapter) {
+		a.source.setSource(source)
+	}
+}
+
+// WithFastHTTPStaticFields adds key-value pairs to every record of the adapter, after the source field
+func WithFastHTTPStaticFields(keysAndValues ...any) FastHTTPOption {
+	return func(a *FastHTTPAdapter) {
+		a.source.addStatic(keysAndValues)
+	}
+}
+
 // Printf implements fasthttp's Logger interface
 func (a *FastHTTPAdapter) Printf(format string, args ...any) {
+	if a.isShutdown() {
+		return
+	}
 	msg := fmt.Sprintf(format, args...)
 
 	// Detect log level from message content
@@ -59,17 +89,13 @@ func (a *FastHTTPAdapter) Printf(format string, args ...any) {
 		}
 	}
 
-	// Log with appropriate level
+	// Log with appropriate level, unrecognized levels are logged as info
 	switch level {
-	case log.LevelDebug:
-		a.logger.Debug("msg", msg, "source", "fasthttp")
-	case log.LevelWarn:
-		a.logger.Warn("msg", msg, "source", "fasthttp")
-	case log.LevelError:
-		a.logger.Error("msg", msg, "source", "fasthttp")
+	case log.LevelDebug, log.LevelWarn, log.LevelError:
 	default:
-		a.logger.Info("msg", msg, "source", "fasthttp")
+		level = log.LevelInfo
 	}
+	a.logger.LogWithConsoleTag(level, a.consoleTag, a.source.fields(msg)...)
 }
 
 // DetectLogLevel attempts to detect log level from message content
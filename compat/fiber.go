@@ -11,9 +11,12 @@ import (
 // FiberAdapter wraps lixenwraith/log.Logger to implement Fiber's CommonLogger interface
 // This provides compatibility with Fiber v2.54.x logging requirements
 type FiberAdapter struct {
+	adapterState
 	logger       *log.Logger
 	fatalHandler func(msg string) // Customizable fatal behavior
 	panicHandler func(msg string) // Customizable panic behavior
+	consoleTag   string           // Console-only component tag, empty when disabled
+	source       adapterSource    // Source and static fields of every record
 }
 
 // NewFiberAdapter creates a new Fiber-compatible logger adapter
@@ -26,11 +29,14 @@ func NewFiberAdapter(logger *log.Logger, opts ...FiberOption) *FiberAdapter {
 		panicHandler: func(msg string) {
 			panic(msg) // Default behavior
 		},
+		source: adapterSource{name: "fiber"},
 	}
 
 	for _, opt := range opts {
 		opt(adapter)
 	}
+	// The console tag names the instance like the source field
+	adapter.consoleTag = consoleTagFor(adapter.consoleTag != "", adapter.source.name)
 
 	return adapter
 }
@@ -52,42 +58,71 @@ func WithFiberPanicHandler(handler func(string)) FiberOption {
 	}
 }
 
+// WithFiberConsoleTag prefixes console output with a level-colored "[fiber]" tag, file output is unaffected
+func WithFiberConsoleTag(enable bool) FiberOption {
+	return func(a *FiberAdapter) {
+		a.consoleTag = consoleTagFor(enable, "fiber")
+	}
+}
+
+// WithFiberSource replaces "fiber" as the value of the source field and console tag, to tell adapter instances apart
+func WithFiberSource(source string) FiberOption {
+	return func(a *FiberAdapter) {
+		a.source.setSource(source)
+	}
+}
+
+// WithFiberStaticFields adds key-value pairs to every record of the adapter, after the source field
+func WithFiberStaticFields(keysAndValues ...any) FiberOption {
+	return func(a *FiberAdapter) {
+		a.source.addStatic(keysAndValues)
+	}
+}
+
+// log sends a record to the underlying logger, applying the console tag if enabled
+func (a *FiberAdapter) log(level int64, fields ...any) {
+	if a.isShutdown() {
+		return
+	}
+	a.logger.LogWithConsoleTag(level, a.consoleTag, fields...)
+}
+
 // --- Logger interface implementation (7 methods) ---
 
 // Trace logs at trace/debug level
 func (a *FiberAdapter) Trace(v ...any) {
 	msg := fmt.Sprint(v...)
-	a.logger.Debug("msg", msg, "source", "fiber", "level", "trace")
+	a.log(log.LevelDebug, a.source.fields(msg, "level", "trace")...)
 }
 
 // Debug logs at debug level
 func (a *FiberAdapter) Debug(v ...any) {
 	msg := fmt.Sprint(v...)
-	a.logger.Debug("msg", msg, "source", "fiber")
+	a.log(log.LevelDebug, a.source.fields(msg)...)
 }
 
 // Info logs at info level
 func (a *FiberAdapter) Info(v ...any) {
 	msg := fmt.Sprint(v...)
-	a.logger.Info("msg", msg, "source", "fiber")
+	a.log(log.LevelInfo, a.source.fields(msg)...)
 }
 
 // Warn logs at warn level
 func (a *FiberAdapter) Warn(v ...any) {
 	msg := fmt.Sprint(v...)
-	a.logger.Warn("msg", msg, "source", "fiber")
+	a.log(log.LevelWarn, a.source.fields(msg)...)
 }
 
 // Error logs at error level
 func (a *FiberAdapter) Error(v ...any) {
 	msg := fmt.Sprint(v...)
-	a.logger.Error("msg", msg, "source", "fiber")
+	a.log(log.LevelError, a.source.fields(msg)...)
 }
 
 // Fatal logs at error level and triggers fatal handler
 func (a *FiberAdapter) Fatal(v ...any) {
 	msg := fmt.Sprint(v...)
-	a.logger.Error("msg", msg, "source", "fiber", "fatal", true)
+	a.log(log.LevelError, a.source.fields(msg, "fatal", true)...)
 
 	// Ensure log is flushed before exit
 	_ = a.logger.Flush(100 * time.Millisecond)
@@ -100,7 +135,7 @@ func (a *FiberAdapter) Fatal(v ...any) {
 // Panic logs at error level and triggers panic handler
 func (a *FiberAdapter) Panic(v ...any) {
 	msg := fmt.Sprint(v...)
-	a.logger.Error("msg", msg, "source", "fiber", "panic", true)
+	a.log(log.LevelError, a.source.fields(msg, "panic", true)...)
 
 	// Ensure log is flushed before panic
 	_ = a.logger.Flush(100 * time.Millisecond)
@@ -118,7 +153,7 @@ func (a *FiberAdapter) Write(p []byte) (n int, err error) {
 	if len(msg) > 0 && msg[len(msg)-1] == '\n' {
 		msg = msg[:len(msg)-1]
 	}
-	a.logger.Info("msg", msg, "source", "fiber")
+	a.log(log.LevelInfo, a.source.fields(msg)...)
 	return len(p), nil
 }
 
@@ -127,37 +162,37 @@ func (a *FiberAdapter) Write(p []byte) (n int, err error) {
 // Tracef logs at trace/debug level with printf-style formatting
 func (a *FiberAdapter) Tracef(format string, v ...any) {
 	msg := fmt.Sprintf(format, v...)
-	a.logger.Debug("msg", msg, "source", "fiber", "level", "trace")
+	a.log(log.LevelDebug, a.source.fields(msg, "level", "trace")...)
 }
 
 // Debugf logs at debug level with printf-style formatting
 func (a *FiberAdapter) Debugf(format string, v ...any) {
 	msg := fmt.Sprintf(format, v...)
-	a.logger.Debug("msg", msg, "source", "fiber")
+	a.log(log.LevelDebug, a.source.fields(msg)...)
 }
 
 // Infof logs at info level with printf-style formatting
 func (a *FiberAdapter) Infof(format string, v ...any) {
 	msg := fmt.Sprintf(format, v...)
-	a.logger.Info("msg", msg, "source", "fiber")
+	a.log(log.LevelInfo, a.source.fields(msg)...)
 }
 
 // Warnf logs at warn level with printf-style formatting
 func (a *FiberAdapter) Warnf(format string, v ...any) {
 	msg := fmt.Sprintf(format, v...)
-	a.logger.Warn("msg", msg, "source", "fiber")
+	a.log(log.LevelWarn, a.source.fields(msg)...)
 }
 
 // Errorf logs at error level with printf-style formatting
 func (a *FiberAdapter) Errorf(format string, v ...any) {
 	msg := fmt.Sprintf(format, v...)
-	a.logger.Error("msg", msg, "source", "fiber")
+	a.log(log.LevelError, a.source.fields(msg)...)
 }
 
 // Fatalf logs at error level and triggers fatal handler
 func (a *FiberAdapter) Fatalf(format string, v ...any) {
 	msg := fmt.Sprintf(format, v...)
-	a.logger.Error("msg", msg, "source", "fiber", "fatal", true)
+	a.log(log.LevelError, a.source.fields(msg, "fatal", true)...)
 
 	// Ensure log is flushed before exit
 	_ = a.logger.Flush(100 * time.Millisecond)
@@ -170,7 +205,7 @@ func (a *FiberAdapter) Fatalf(format string, v ...any) {
 // Panicf logs at error level and triggers panic handler
 func (a *FiberAdapter) Panicf(format string, v ...any) {
 	msg := fmt.Sprintf(format, v...)
-	a.logger.Error("msg", msg, "source", "fiber", "panic", true)
+	a.log(log.LevelError, a.source.fields(msg, "panic", true)...)
 
 	// Ensure log is flushed before panic
 	_ = a.logger.Flush(100 * time.Millisecond)
@@ -184,50 +219,38 @@ func (a *FiberAdapter) Panicf(format string, v ...any) {
 
 // Tracew logs at trace/debug level with structured key-value pairs
 func (a *FiberAdapter) Tracew(msg string, keysAndValues ...any) {
-	fields := make([]any, 0, len(keysAndValues)+6)
-	fields = append(fields, "msg", msg, "source", "fiber", "level", "trace")
-	fields = append(fields, keysAndValues...)
-	a.logger.Debug(fields...)
+	fields := append(a.source.fields(msg, "level", "trace"), keysAndValues...)
+	a.log(log.LevelDebug, fields...)
 }
 
 // Debugw logs at debug level with structured key-value pairs
 func (a *FiberAdapter) Debugw(msg string, keysAndValues ...any) {
-	fields := make([]any, 0, len(keysAndValues)+4)
-	fields = append(fields, "msg", msg, "source", "fiber")
-	fields = append(fields, keysAndValues...)
-	a.logger.Debug(fields...)
+	fields := append(a.source.fields(msg), keysAndValues...)
+	a.log(log.LevelDebug, fields...)
 }
 
 // Infow logs at info level with structured key-value pairs
 func (a *FiberAdapter) Infow(msg string, keysAndValues ...any) {
-	fields := make([]any, 0, len(keysAndValues)+4)
-	fields = append(fields, "msg", msg, "source", "fiber")
-	fields = append(fields, keysAndValues...)
-	a.logger.Info(fields...)
+	fields := append(a.source.fields(msg), keysAndValues...)
+	a.log(log.LevelInfo, fields...)
 }
 
 // Warnw logs at warn level with structured key-value pairs
 func (a *FiberAdapter) Warnw(msg string, keysAndValues ...any) {
-	fields := make([]any, 0, len(keysAndValues)+4)
-	fields = append(fields, "msg", msg, "source", "fiber")
-	fields = append(fields, keysAndValues...)
-	a.logger.Warn(fields...)
+	fields := append(a.source.fields(msg), keysAndValues...)
+	a.log(log.LevelWarn, fields...)
 }
 
 // Errorw logs at error level with structured key-value pairs
 func (a *FiberAdapter) Errorw(msg string, keysAndValues ...any) {
-	fields := make([]any, 0, len(keysAndValues)+4)
-	fields = append(fields, "msg", msg, "source", "fiber")
-	fields = append(fields, keysAndValues...)
-	a.logger.Error(fields...)
+	fields := append(a.source.fields(msg), keysAndValues...)
+	a.log(log.LevelError, fields...)
 }
 
 // Fatalw logs at error level with structured key-value pairs and triggers fatal handler
 func (a *FiberAdapter) Fatalw(msg string, keysAndValues ...any) {
-	fields := make([]any, 0, len(keysAndValues)+6)
-	fields = append(fields, "msg", msg, "source", "fiber", "fatal", true)
-	fields = append(fields, keysAndValues...)
-	a.logger.Error(fields...)
+	fields := append(a.source.fields(msg, "fatal", true), keysAndValues...)
+	a.log(log.LevelError, fields...)
 
 	// Ensure log is flushed before exit
 	_ = a.logger.Flush(100 * time.Millisecond)
@@ -239,10 +262,8 @@ func (a *FiberAdapter) Fatalw(msg string, keysAndValues ...any) {
 
 // Panicw logs at error level with structured key-value pairs and triggers panic handler
 func (a *FiberAdapter) Panicw(msg string, keysAndValues ...any) {
-	fields := make([]any, 0, len(keysAndValues)+6)
-	fields = append(fields, "msg", msg, "source", "fiber", "panic", true)
-	fields = append(fields, keysAndValues...)
-	a.logger.Error(fields...)
+	fields := append(a.source.fields(msg, "panic", true), keysAndValues...)
+	a.log(log.LevelError, fields...)
 
 	// Ensure log is flushed before panic
 	_ = a.logger.Flush(100 * time.Millisecond)
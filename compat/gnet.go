@@ -10,8 +10,11 @@ import (
 
 // GnetAdapter wraps lixenwraith/log.Logger to implement gnet logging.Logger interface
 type GnetAdapter struct {
+	adapterState
 	logger       *log.Logger
 	fatalHandler func(msg string) // Customizable fatal behavior
+	consoleTag   string           // Console-only component tag, empty when disabled
+	source       adapterSource    // Source and static fields of every record
 }
 
 // NewGnetAdapter creates a new gnet-compatible logger adapter
@@ -21,11 +24,14 @@ func NewGnetAdapter(logger *log.Logger, opts ...GnetOption) *GnetAdapter {
 		fatalHandler: func(msg string) {
 			os.Exit(1) // Default behavior matches gnet expectations
 		},
+		source: adapterSource{name: "gnet"},
 	}
 
 	for _, opt := range opts {
 		opt(adapter)
 	}
+	// The console tag names the instance like the source field
+	adapter.consoleTag = consoleTagFor(adapter.consoleTag != "", adapter.source.name)
 
 	return adapter
 }
@@ -40,34 +46,63 @@ func WithFatalHandler(handler func(string)) GnetOption {
 	}
 }
 
+// WithGnetConsoleTag prefixes console output with a level-colored "[gnet]" tag, file output is unaffected
+func WithGnetConsoleTag(enable bool) GnetOption {
+	return func(a *GnetAdapter) {
+		a.consoleTag = consoleTagFor(enable, "gnet")
+	}
+}
+
+// WithGnetSource replaces "gnet" as the value of the source field and console tag, to tell adapter instances apart
+func WithGnetSource(source string) GnetOption {
+	return func(a *GnetAdapter) {
+		a.source.setSource(source)
+	}
+}
+
+// WithGnetStaticFields adds key-value pairs to every record of the adapter, after the source field
+func WithGnetStaticFields(keysAndValues ...any) GnetOption {
+	return func(a *GnetAdapter) {
+		a.source.addStatic(keysAndValues)
+	}
+}
+
+// log sends a record to the underlying logger, applying the console tag if enabled
+func (a *GnetAdapter) log(level int64, fields ...any) {
+	if a.isShutdown() {
+		return
+	}
+	a.logger.LogWithConsoleTag(level, a.consoleTag, fields...)
+}
+
 // Debugf logs at debug level with printf-style formatting
 func (a *GnetAdapter) Debugf(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
-	a.logger.Debug("msg", msg, "source", "gnet")
+	a.log(log.LevelDebug, a.source.fields(msg)...)
 }
 
 // Infof logs at info level with printf-style formatting
 func (a *GnetAdapter) Infof(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
-	a.logger.Info("msg", msg, "source", "gnet")
+	a.log(log.LevelInfo, a.source.fields(msg)...)
 }
 
 // Warnf logs at warn level with printf-style formatting
 func (a *GnetAdapter) Warnf(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
-	a.logger.Warn("msg", msg, "source", "gnet")
+	a.log(log.LevelWarn, a.source.fields(msg)...)
 }
 
 // Errorf logs at error level with printf-style formatting
 func (a *GnetAdapter) Errorf(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
-	a.logger.Error("msg", msg, "source", "gnet")
+	a.log(log.LevelError, a.source.fields(msg)...)
 }
 
 // Fatalf logs at error level and triggers fatal handler
 func (a *GnetAdapter) Fatalf(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
-	a.logger.Error("msg", msg, "source", "gnet", "fatal", true)
+	a.log(log.LevelError, a.source.fields(msg, "fatal", true)...)
 
 	// Ensure log is flushed before exit
 	_ = a.logger.Flush(100 * time.Millisecond)
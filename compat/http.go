@@ -0,0 +1,179 @@
+package compat
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/lixenwraith/log"
+)
+
+// HTTPMiddleware logs net/http requests with structured fields and attaches a request-scoped logger to the
+// request context, retrieved by handlers with log.FromContext(r.Context())
+// Handler matches the func(http.Handler) http.Handler middleware signature used by chi and similar routers
+type HTTPMiddleware struct {
+	adapterState
+	logger     *log.Logger
+	skip       func(r *http.Request) bool // Requests not logged, e.g. health checks
+	consoleTag string                     // Console-only component tag, empty when disabled
+}
+
+// NewHTTPMiddleware creates a net/http request logging middleware
+func NewHTTPMiddleware(logger *log.Logger, opts ...HTTPOption) *HTTPMiddleware {
+	m := &HTTPMiddleware{logger: logger}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// HTTPOption allows customizing middleware behavior
+type HTTPOption func(*HTTPMiddleware)
+
+// WithHTTPSkip excludes requests from request logging, they still receive the request-scoped logger
+func WithHTTPSkip(skip func(r *http.Request) bool) HTTPOption {
+	return func(m *HTTPMiddleware) {
+		m.skip = skip
+	}
+}
+
+// WithHTTPConsoleTag prefixes console output with a level-colored "[http]" tag, file output is unaffected
+func WithHTTPConsoleTag(enable bool) HTTPOption {
+	return func(m *HTTPMiddleware) {
+		m.consoleTag = consoleTagFor(enable, "http")
+	}
+}
+
+// Handler wraps next, logging each request after it completes
+// A valid W3C traceparent header binds trace_id and span_id to the request-scoped logger, so records logged
+// by handlers and the request record share them; the request context carries them for the *Context methods. Requests answered with 5xx are logged at ERROR, 4xx at WARN
+func (m *HTTPMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		reqLogger := m.logger
+		ctx := r.Context()
+		if traceID, spanID, ok := parseTraceparent(r.Header.Get("traceparent")); ok {
+			reqLogger = reqLogger.With("trace_id", traceID, "span_id", spanID)
+			ctx = log.WithTraceContext(ctx, traceID, spanID)
+		}
+		r = r.WithContext(log.NewContext(ctx, reqLogger))
+
+		if m.isShutdown() || (m.skip != nil && m.skip(r)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			if p := recover(); p != nil {
+				m.logRequest(reqLogger, r, http.StatusInternalServerError, rec.bytes, start, "panic", p)
+				panic(p)
+			}
+			m.logRequest(reqLogger, r, rec.status, rec.bytes, start)
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// logRequest writes the request record, extra holds additional key-value pairs
+func (m *HTTPMiddleware) logRequest(l *log.Logger, r *http.Request, status int, bytes int64, start time.Time, extra ...any) {
+	if m.isShutdown() {
+		return
+	}
+
+	level := log.LevelInfo
+	switch {
+	case status >= 500:
+		level = log.LevelError
+	case status >= 400:
+		level = log.LevelWarn
+	}
+
+	fields := []any{
+		"msg", "http request",
+		"source", "http",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"status", status,
+		"bytes", bytes,
+		"duration_ms", float64(time.Since(start).Microseconds()) / 1000,
+		"remote_addr", r.RemoteAddr,
+	}
+	if ua := r.UserAgent(); ua != "" {
+		fields = append(fields, "user_agent", ua)
+	}
+	fields = append(fields, extra...)
+	l.LogWithConsoleTag(level, m.consoleTag, fields...)
+}
+
+// responseRecorder captures the status code and body size written by a handler
+// Unwrap exposes the original writer to http.ResponseController for flushing and hijacking
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+// WriteHeader records the first status code
+func (rw *responseRecorder) WriteHeader(status int) {
+	if !rw.wroteHeader {
+		rw.status = status
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+// Write counts body bytes, an implicit 200 is recorded by the default status
+func (rw *responseRecorder) Write(p []byte) (int, error) {
+	rw.wroteHeader = true
+	n, err := rw.ResponseWriter.Write(p)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap returns the wrapped ResponseWriter
+func (rw *responseRecorder) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// parseTraceparent extracts the trace and parent span IDs from a W3C traceparent header
+// Format: version "-" trace-id (32 hex) "-" parent-id (16 hex) "-" flags (2 hex); all-zero IDs are invalid
+func parseTraceparent(header string) (traceID, spanID string, ok bool) {
+	// Future versions may append fields, version 00 has exactly four
+	if len(header) < 55 || (len(header) > 55 && header[55] != '-') {
+		return "", "", false
+	}
+	if header[2] != '-' || header[35] != '-' || header[52] != '-' {
+		return "", "", false
+	}
+	version, traceID, spanID, flags := header[0:2], header[3:35], header[36:52], header[53:55]
+	if !isLowerHex(version) || version == "ff" || (version == "00" && len(header) != 55) {
+		return "", "", false
+	}
+	if !isLowerHex(traceID) || !isLowerHex(spanID) || !isLowerHex(flags) || isZeroHex(traceID) || isZeroHex(spanID) {
+		return "", "", false
+	}
+	return traceID, spanID, true
+}
+
+// isLowerHex reports whether s consists of lowercase hex digits only
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// isZeroHex reports whether a hex string is all zeros
+func isZeroHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '0' {
+			return false
+		}
+	}
+	return true
+}
\ No newline at end of file
@@ -0,0 +1,25 @@
+package compat
+
+import (
+	"sync/atomic"
+)
+
+// adapterState marks an adapter as shut down so late calls become no-ops instead of reaching a stopped logger
+type adapterState struct {
+	closed atomic.Bool
+}
+
+// shutdown silences the adapter, it cannot be reopened
+func (s *adapterState) shutdown() {
+	s.closed.Store(true)
+}
+
+// isShutdown reports whether the adapter was shut down
+func (s *adapterState) isShutdown() bool {
+	return s.closed.Load()
+}
+
+// shutdowner is implemented by every adapter the Builder tracks for ShutdownAll
+type shutdowner interface {
+	shutdown()
+}
\ No newline at end of file
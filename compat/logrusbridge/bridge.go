@@ -0,0 +1,123 @@
+// Package logrusbridge writes logrus entries into a lixenwraith/log Logger
+// It lives in its own module so the core log package stays free of logrus dependencies
+package logrusbridge
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lixenwraith/log"
+	"github.com/sirupsen/logrus"
+)
+
+// flushTimeout bounds the flush before logrus exits or panics on a fatal or panic entry
+const flushTimeout = time.Second
+
+// Hook is a logrus.Hook converting each entry into a log record, leaving logrus' own output and formatter untouched
+// Applications migrating from logrus route it into the same files before every call site is converted
+//
+//	logrus.AddHook(logrusbridge.NewHook(appLogger))
+type Hook struct {
+	logger *log.Logger
+}
+
+var _ logrus.Hook = (*Hook)(nil)
+
+// NewHook creates a hook backed by the given logger
+func NewHook(logger *log.Logger) *Hook {
+	return &Hook{logger: logger}
+}
+
+// Levels reports every level, the logger's own level filter selects the records kept
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire logs the entry, flushing fatal and panic entries as logrus exits or panics right after
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	ingest(h.logger, entry)
+	return nil
+}
+
+// Formatter is a logrus.Formatter converting each entry into a log record, then formatting it with the wrapped
+// formatter; it replaces a logger's formatter where hooks are not an option
+//
+//	logrus.SetFormatter(logrusbridge.NewFormatter(appLogger, &logrus.TextFormatter{}))
+type Formatter struct {
+	logger *log.Logger
+	next   logrus.Formatter // Formats logrus' own output, nil writes nothing
+}
+
+var _ logrus.Formatter = (*Formatter)(nil)
+
+// NewFormatter creates a formatter backed by the given logger, next formats logrus' own output
+func NewFormatter(logger *log.Logger, next logrus.Formatter) *Formatter {
+	return &Formatter{logger: logger, next: next}
+}
+
+// Format logs the entry and returns the wrapped formatter's output
+func (f *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	ingest(f.logger, entry)
+	if f.next == nil {
+		return nil, nil
+	}
+	return f.next.Format(entry)
+}
+
+// ingest converts an entry into a record with msg, source, caller, and the entry's data in key order
+// The entry's timestamp is kept
+func ingest(logger *log.Logger, entry *logrus.Entry) {
+	level := logLevel(entry.Level)
+	if !logger.Enabled(level) {
+		return
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	args := make([]any, 0, 10+len(keys)*2)
+	args = append(args, "msg", entry.Message, "source", "logrus")
+	if entry.Level == logrus.TraceLevel || entry.Level == logrus.FatalLevel || entry.Level == logrus.PanicLevel {
+		// Preserve levels without a direct equivalent
+		args = append(args, "level", entry.Level.String())
+	}
+	if entry.HasCaller() {
+		args = append(args, logrus.FieldKeyFunc, entry.Caller.Function,
+			logrus.FieldKeyFile, fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line))
+	}
+	for _, k := range keys {
+		args = append(args, k, value(entry.Data[k]))
+	}
+
+	logger.Ingest(log.Record{Time: entry.Time, Level: level, Args: args})
+	if entry.Level <= logrus.FatalLevel {
+		_ = logger.Flush(flushTimeout)
+	}
+}
+
+// logLevel maps logrus levels to log levels
+// Fatal and panic map to ERROR, logrus still handles exit or panic itself
+func logLevel(level logrus.Level) int64 {
+	switch level {
+	case logrus.TraceLevel, logrus.DebugLevel:
+		return log.LevelDebug
+	case logrus.WarnLevel:
+		return log.LevelWarn
+	case logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel:
+		return log.LevelError
+	default:
+		return log.LevelInfo
+	}
+}
+
+// value logs errors by their message, as logrus' JSON formatter does; other values are returned unchanged
+func value(v any) any {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+	return v
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package logrusbridge
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lixenwraith/log"
+	"github.com/lixenwraith/log/logtest"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHook(t *testing.T) {
+	logger, capture := logtest.NewTestLogger(t)
+	var out bytes.Buffer
+	lr := logrus.New()
+	lr.SetOutput(&out)
+	lr.SetFormatter(&logrus.TextFormatter{DisableColors: true})
+	lr.SetLevel(logrus.TraceLevel)
+	lr.AddHook(NewHook(logger))
+
+	stamp := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	lr.WithTime(stamp).WithFields(logrus.Fields{"port": 8080, "error": errors.New("busy")}).Warn("retrying")
+	lr.Debug("dropped")
+	lr.Trace("dropped too")
+
+	require.NoError(t, logger.Flush(time.Second))
+	records := capture.Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, log.LevelWarn, records[0].Level)
+	assert.Equal(t, []any{"msg", "retrying", "source", "logrus", "error", "busy", "port", 8080}, records[0].Args)
+	assert.True(t, stamp.Equal(records[0].Time), "the entry's timestamp is kept")
+
+	// logrus keeps its own text output
+	assert.Contains(t, out.String(), `level=warning msg=retrying error=busy port=8080`)
+	assert.Contains(t, out.String(), "level=trace")
+}
+
+func TestFormatter(t *testing.T) {
+	logger, capture := logtest.NewTestLogger(t)
+	var out bytes.Buffer
+	lr := logrus.New()
+	lr.SetOutput(&out)
+	lr.SetReportCaller(true)
+	lr.SetFormatter(NewFormatter(logger, nil))
+
+	lr.WithField("attempt", 2).Error("payment failed")
+
+	require.NoError(t, logger.Flush(time.Second))
+	records := capture.Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, log.LevelError, records[0].Level)
+	fields := records[0].Args
+	require.Len(t, fields, 10)
+	assert.Equal(t, []any{"msg", "payment failed", "source", "logrus", "func"}, fields[:5])
+	assert.Contains(t, fields[5], "TestFormatter")
+	assert.Equal(t, "file", fields[6])
+	assert.Contains(t, fields[7], "bridge_test.go:")
+	assert.Equal(t, []any{"attempt", 2}, fields[8:])
+	assert.Empty(t, out.String(), "a nil wrapped formatter writes nothing")
+
+	lr.SetFormatter(NewFormatter(logger, &logrus.JSONFormatter{}))
+	lr.Info("both")
+	assert.Contains(t, out.String(), `"msg":"both"`)
+}
\ No newline at end of file
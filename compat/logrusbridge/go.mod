@@ -0,0 +1,18 @@
+module github.com/lixenwraith/log/compat/logrusbridge
+
+go 1.26.0
+
+require (
+	github.com/lixenwraith/log v0.0.0-20261016171709-66f395c1eedb
+	github.com/sirupsen/logrus v1.9.4
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/lixenwraith/log => ../..
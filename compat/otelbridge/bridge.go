@@ -0,0 +1,155 @@
+// Package otelbridge implements the OpenTelemetry Logs Bridge API on top of a lixenwraith/log Logger
+// It lives in its own module so the core log package stays free of OpenTelemetry dependencies
+package otelbridge
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/lixenwraith/log"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LoggerProvider is an OpenTelemetry log.LoggerProvider writing every emitted record through a Logger
+// Instrumentation using the OTel Logs API, directly or through an OTel bridge such as otelslog, persists to local files
+//
+//	provider := otelbridge.NewLoggerProvider(appLogger)
+//	global.SetLoggerProvider(provider)
+type LoggerProvider struct {
+	embedded.LoggerProvider
+	logger *log.Logger
+}
+
+// NewLoggerProvider creates a provider backed by the given logger
+func NewLoggerProvider(logger *log.Logger) *LoggerProvider {
+	return &LoggerProvider{logger: logger}
+}
+
+// Logger returns an OTel logger whose records carry the instrumentation scope name and version
+func (p *LoggerProvider) Logger(name string, options ...otellog.LoggerOption) otellog.Logger {
+	cfg := otellog.NewLoggerConfig(options...)
+	return &Logger{
+		logger:  p.logger,
+		scope:   name,
+		version: cfg.InstrumentationVersion(),
+	}
+}
+
+// Logger is an OpenTelemetry log.Logger for a single instrumentation scope
+type Logger struct {
+	embedded.Logger
+	logger  *log.Logger
+	scope   string // Instrumentation scope name, the "scope" field
+	version string // Instrumentation scope version, the "scope_version" field when set
+}
+
+// Emit converts an OTel record into a log record at the mapped level
+// Fields are msg, scope, trace context, event name, error, then the record's attributes in emit order
+// The record's timestamp is dropped in favor of the logger's own
+func (l *Logger) Emit(ctx context.Context, record otellog.Record) {
+	fields := make([]any, 0, 4+record.AttributesLen()*2)
+	fields = append(fields, "msg", bodyValue(record.Body()))
+	if l.scope != "" {
+		fields = append(fields, "scope", l.scope)
+	}
+	if l.version != "" {
+		fields = append(fields, "scope_version", l.version)
+	}
+
+	severity := record.Severity()
+	if severity != otellog.SeverityUndefined && (severity < otellog.SeverityDebug1 || severity >= otellog.SeverityFatal1) {
+		// Preserve severities without a direct equivalent
+		fields = append(fields, "severity", severityName(record))
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields, "trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+	}
+	if name := record.EventName(); name != "" {
+		fields = append(fields, "event", name)
+	}
+	if err := record.Err(); err != nil {
+		fields = append(fields, "error", err.Error())
+	}
+
+	record.WalkAttributes(func(kv otellog.KeyValue) bool {
+		fields = append(fields, kv.Key, convertValue(kv.Value))
+		return true
+	})
+
+	l.logger.LogWithConsoleTag(severityLevel(severity), "", fields...)
+}
+
+// Enabled reports whether a record of the given severity can pass the logger's level filter, including
+// level_overrides_by_field rules lowering it
+func (l *Logger) Enabled(_ context.Context, param otellog.EnabledParameters) bool {
+	return l.logger.Enabled(severityLevel(param.Severity))
+}
+
+// severityLevel maps OTel severity ranges to log levels
+// TRACE maps to DEBUG and FATAL to ERROR; undefined severity is treated as INFO
+func severityLevel(severity otellog.Severity) int64 {
+	switch {
+	case severity == otellog.SeverityUndefined:
+		return log.LevelInfo
+	case severity < otellog.SeverityInfo1:
+		return log.LevelDebug
+	case severity < otellog.SeverityWarn1:
+		return log.LevelInfo
+	case severity < otellog.SeverityError1:
+		return log.LevelWarn
+	default:
+		return log.LevelError
+	}
+}
+
+// severityName returns the record's severity text, falling back to the severity's canonical name
+func severityName(record otellog.Record) string {
+	if text := record.SeverityText(); text != "" {
+		return text
+	}
+	return record.Severity().String()
+}
+
+// bodyValue returns string bodies unchanged and converts structured bodies like attribute values
+func bodyValue(v otellog.Value) any {
+	if v.Kind() == otellog.KindString {
+		return v.AsString()
+	}
+	return convertValue(v)
+}
+
+// convertValue maps an OTel value to the closest native type
+// Bytes are base64 encoded, maps become map[string]any, and empty values become nil
+func convertValue(v otellog.Value) any {
+	switch v.Kind() {
+	case otellog.KindBool:
+		return v.AsBool()
+	case otellog.KindInt64:
+		return v.AsInt64()
+	case otellog.KindFloat64:
+		return v.AsFloat64()
+	case otellog.KindString:
+		return v.AsString()
+	case otellog.KindBytes:
+		return base64.StdEncoding.EncodeToString(v.AsBytes())
+	case otellog.KindSlice:
+		values := v.AsSlice()
+		converted := make([]any, len(values))
+		for i, elem := range values {
+			converted[i] = convertValue(elem)
+		}
+		return converted
+	case otellog.KindMap:
+		kvs := v.AsMap()
+		converted := make(map[string]any, len(kvs))
+		for _, kv := range kvs {
+			converted[kv.Key] = convertValue(kv.Value)
+		}
+		return converted
+	default:
+		return nil
+	}
+}
\ No newline at end of file
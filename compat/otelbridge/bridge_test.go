@@ -0,0 +1,96 @@
+package otelbridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lixenwraith/log"
+	"github.com/lixenwraith/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLoggerProvider(t *testing.T) {
+	logger, capture := logtest.NewTestLogger(t)
+	provider := NewLoggerProvider(logger)
+	otelLogger := provider.Logger("checkout", otellog.WithInstrumentationVersion("1.2.0"))
+
+	t.Run("enabled", func(t *testing.T) {
+		ctx := context.Background()
+		assert.False(t, otelLogger.Enabled(ctx, otellog.EnabledParameters{Severity: otellog.SeverityDebug}))
+		assert.True(t, otelLogger.Enabled(ctx, otellog.EnabledParameters{Severity: otellog.SeverityInfo}))
+		assert.True(t, otelLogger.Enabled(ctx, otellog.EnabledParameters{Severity: otellog.SeverityFatal}))
+
+		// Field rules lowering the level let DEBUG records through to be matched
+		cfg := logger.GetConfig()
+		cfg.LevelOverridesByField = map[string]string{"scope=checkout": "debug"}
+		require.NoError(t, logger.ApplyConfig(cfg))
+		defer func() {
+			cfg.LevelOverridesByField = nil
+			require.NoError(t, logger.ApplyConfig(cfg))
+		}()
+		assert.True(t, otelLogger.Enabled(ctx, otellog.EnabledParameters{Severity: otellog.SeverityDebug}))
+	})
+
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	var record otellog.Record
+	record.SetSeverity(otellog.SeverityWarn2)
+	record.SetBody(otellog.StringValue("payment retried"))
+	record.SetEventName("payment.retry")
+	record.SetErr(errors.New("gateway timeout"))
+	record.AddAttributes(
+		otellog.Int("attempt", 2),
+		otellog.Map("card", otellog.String("brand", "visa"), otellog.Bool("saved", true)),
+	)
+	otelLogger.Emit(ctx, record)
+
+	var fatal otellog.Record
+	fatal.SetSeverity(otellog.SeverityFatal)
+	fatal.SetBody(otellog.StringValue("shutting down"))
+	otelLogger.Emit(context.Background(), fatal)
+
+	require.NoError(t, logger.Flush(time.Second))
+	records := capture.Records()
+	require.Len(t, records, 2)
+
+	assert.Equal(t, log.LevelWarn, records[0].Level)
+	assert.Equal(t, []any{
+		"msg", "payment retried",
+		"scope", "checkout",
+		"scope_version", "1.2.0",
+		"trace_id", "0102030405060708090a0b0c0d0e0f10",
+		"span_id", "0102030405060708",
+		"event", "payment.retry",
+		"error", "gateway timeout",
+		"attempt", int64(2),
+		"card", map[string]any{"brand": "visa", "saved": true},
+	}, records[0].Args)
+
+	assert.Equal(t, log.LevelError, records[1].Level)
+	assert.Equal(t, []any{
+		"msg", "shutting down",
+		"scope", "checkout",
+		"scope_version", "1.2.0",
+		"severity", "FATAL",
+	}, records[1].Args)
+}
+
+func TestSeverityLevel(t *testing.T) {
+	assert.Equal(t, log.LevelInfo, severityLevel(otellog.SeverityUndefined))
+	assert.Equal(t, log.LevelDebug, severityLevel(otellog.SeverityTrace3))
+	assert.Equal(t, log.LevelDebug, severityLevel(otellog.SeverityDebug4))
+	assert.Equal(t, log.LevelInfo, severityLevel(otellog.SeverityInfo2))
+	assert.Equal(t, log.LevelWarn, severityLevel(otellog.SeverityWarn4))
+	assert.Equal(t, log.LevelError, severityLevel(otellog.SeverityError1))
+	assert.Equal(t, log.LevelError, severityLevel(otellog.SeverityFatal4))
+}
\ No newline at end of file
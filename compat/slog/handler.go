@@ -0,0 +1,123 @@
+// Package slog provides a log/slog Handler writing records through a lixenwraith/log Logger, so applications and
+// libraries using the standard library structured logger share its outputs
+package slog
+
+import (
+	"context"
+	stdslog "log/slog"
+	"runtime"
+	"strconv"
+
+	"github.com/lixenwraith/log"
+)
+
+// HandlerOptions customizes a Handler
+type HandlerOptions struct {
+	// Level is the minimum slog level handled, in addition to the logger's own level; nil handles every level the
+	// logger accepts
+	Level stdslog.Leveler
+	// AddSource adds the "source" field with the file and line of the logging call
+	AddSource bool
+}
+
+// Handler is a slog.Handler backed by a Logger
+// Records keep their time, are written at the mapped level, and carry "msg" followed by their attributes. Attributes
+// added with WithAttrs are bound with Logger.With, groups opened with WithGroup use Logger.WithGroup, so their
+// keys nest in JSON output. Groups inside a record's attributes become dotted keys
+//
+//	slog.SetDefault(slog.New(logslog.NewHandler(appLogger, nil)))
+type Handler struct {
+	logger *log.Logger
+	opts   HandlerOptions
+}
+
+// NewHandler creates a handler writing through logger, nil opts uses the defaults
+func NewHandler(logger *log.Logger, opts *HandlerOptions) *Handler {
+	h := &Handler{logger: logger}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled reports whether records at level pass both the handler's minimum level and the logger's level filter
+func (h *Handler) Enabled(_ context.Context, level stdslog.Level) bool {
+	if h.opts.Level != nil && level < h.opts.Level.Level() {
+		return false
+	}
+	return h.logger.Enabled(mapLevel(level))
+}
+
+// Handle writes a record through the logger with the trace IDs and fields carried by ctx, see log.ContextFields
+// A zero record time is replaced by now
+func (h *Handler) Handle(ctx context.Context, r stdslog.Record) error {
+	contextFields := log.ContextFields(ctx)
+	args := make([]any, 0, 4+len(contextFields)+r.NumAttrs()*2)
+	args = append(args, "msg", r.Message)
+	args = append(args, contextFields...)
+	if h.opts.AddSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		args = append(args, "source", frame.File+":"+strconv.Itoa(frame.Line))
+	}
+	r.Attrs(func(attr stdslog.Attr) bool {
+		args = appendAttr(args, "", attr)
+		return true
+	})
+
+	h.logger.Ingest(log.Record{Time: r.Time, Level: mapLevel(r.Level), Args: args})
+	return nil
+}
+
+// WithAttrs returns a handler whose logger has the attributes bound as fields
+func (h *Handler) WithAttrs(attrs []stdslog.Attr) stdslog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	fields := make([]any, 0, len(attrs)*2)
+	for _, attr := range attrs {
+		fields = appendAttr(fields, "", attr)
+	}
+	return &Handler{logger: h.logger.With(fields...), opts: h.opts}
+}
+
+// WithGroup returns a handler whose later attributes are nested under name, an empty name returns h
+func (h *Handler) WithGroup(name string) stdslog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{logger: h.logger.WithGroup(name), opts: h.opts}
+}
+
+// appendAttr appends an attribute as key-value pairs with keys prefixed by prefix
+// LogValuers are resolved, empty attributes and empty groups are skipped, groups without a key are inlined
+func appendAttr(args []any, prefix string, attr stdslog.Attr) []any {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(stdslog.Attr{}) {
+		return args
+	}
+	if attr.Value.Kind() == stdslog.KindGroup {
+		group := prefix
+		if attr.Key != "" {
+			group = prefix + attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			args = appendAttr(args, group, member)
+		}
+		return args
+	}
+	return append(args, prefix+attr.Key, attr.Value.Any())
+}
+
+// mapLevel maps slog levels to log levels: below INFO is DEBUG, then INFO, WARN, and ERROR from their slog values on
+func mapLevel(level stdslog.Level) int64 {
+	switch {
+	case level < stdslog.LevelInfo:
+		return log.LevelDebug
+	case level < stdslog.LevelWarn:
+		return log.LevelInfo
+	case level < stdslog.LevelError:
+		return log.LevelWarn
+	default:
+		return log.LevelError
+	}
+}
\ No newline at end of file
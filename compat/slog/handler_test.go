@@ -0,0 +1,117 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	stdslog "log/slog"
+	"testing"
+	"time"
+
+	"github.com/lixenwraith/log"
+	"github.com/lixenwraith/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonKV has the test logger write JSON with flattened key-value pairs
+var jsonKV = []logtest.Option{logtest.Format("json"), func(b *log.Builder) { b.AutoKV(true) }}
+
+func TestHandler(t *testing.T) {
+	logger, capture := logtest.NewTestLogger(t, jsonKV...)
+	slogger := stdslog.New(NewHandler(logger, nil))
+
+	slogger.Debug("filtered by the logger level")
+	slogger.Info("request served", "status", 200, "took", 1500*time.Millisecond)
+	slogger.With("request_id", "r-1").WithGroup("http").With("method", "GET").
+		Warn("slow response", stdslog.Group("client", "ip", "10.0.0.1"), stdslog.Attr{})
+	slogger.ErrorContext(log.WithTraceContext(context.Background(), "4bf9", "00f0"), "payment failed", "err", errors.New("card declined"))
+	slogger.Log(context.Background(), stdslog.LevelError+4, "beyond error")
+
+	require.NoError(t, logger.Flush(time.Second))
+	entries := capture.JSON(t)
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, "INFO", entries[0]["level"])
+	assert.Equal(t, "request served", entries[0]["msg"])
+	assert.Equal(t, float64(200), entries[0]["status"])
+	assert.Equal(t, "1.5s", entries[0]["took"])
+
+	assert.Equal(t, "WARN", entries[1]["level"])
+	assert.Equal(t, "r-1", entries[1]["request_id"])
+	assert.Equal(t, map[string]any{"method": "GET", "client": map[string]any{"ip": "10.0.0.1"}}, entries[1]["http"],
+		"Groups nest attributes bound after them and the record's own")
+
+	assert.Equal(t, "ERROR", entries[2]["level"])
+	assert.Equal(t, "card declined", entries[2]["err"])
+	assert.Equal(t, "4bf9", entries[2]["trace_id"], "Context fields are added")
+	assert.Equal(t, "ERROR", entries[3]["level"], "Levels above ERROR map to ERROR")
+}
+
+// TestHandlerFormats verifies formats telling the message apart from the fields write the slog message as their message
+func TestHandlerFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		config []string
+		check  func(t *testing.T, entry map[string]any)
+	}{
+		{
+			name:   "json fields object",
+			config: []string{"auto_kv=false", "json_fields=object"},
+			check: func(t *testing.T, entry map[string]any) {
+				assert.Equal(t, "slog msg", entry["message"])
+				assert.Equal(t, map[string]any{"request_id": "r-1", "trace_id": "4bf9", "user": 7.0}, entry["fields"])
+			},
+		},
+		{
+			name:   "ecs",
+			config: []string{"format=ecs"},
+			check: func(t *testing.T, entry map[string]any) {
+				assert.Equal(t, "slog msg", entry["message"])
+				assert.Equal(t, "4bf9", entry["trace.id"])
+				assert.Equal(t, "r-1", entry["request_id"])
+				assert.NotContains(t, entry, "msg")
+			},
+		},
+		{
+			name:   "gcp",
+			config: []string{"format=gcp"},
+			check: func(t *testing.T, entry map[string]any) {
+				assert.Equal(t, "slog msg", entry["message"])
+				assert.Equal(t, "4bf9", entry["logging.googleapis.com/trace"])
+				assert.Equal(t, map[string]any{"request_id": "r-1", "user": 7.0}, entry["jsonPayload"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, capture := logtest.NewTestLogger(t, jsonKV...)
+			require.NoError(t, logger.ApplyConfigString(tt.config...))
+			slogger := stdslog.New(NewHandler(logger, nil)).With("request_id", "r-1")
+			slogger.InfoContext(log.WithTraceContext(context.Background(), "4bf9", ""), "slog msg", "user", 7)
+
+			require.NoError(t, logger.Flush(time.Second))
+			entries := capture.JSON(t)
+			require.Len(t, entries, 1)
+			tt.check(t, entries[0])
+		})
+	}
+}
+
+func TestHandlerOptions(t *testing.T) {
+	logger, capture := logtest.NewTestLogger(t, jsonKV...)
+	handler := NewHandler(logger, &HandlerOptions{Level: stdslog.LevelWarn, AddSource: true})
+	assert.False(t, handler.Enabled(context.Background(), stdslog.LevelInfo))
+	assert.True(t, handler.Enabled(context.Background(), stdslog.LevelWarn))
+	assert.Same(t, handler, handler.WithGroup(""))
+
+	slogger := stdslog.New(handler)
+	slogger.Info("below the handler level")
+	slogger.Warn("disk almost full")
+
+	require.NoError(t, logger.Flush(time.Second))
+	entries := capture.JSON(t)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "disk almost full", entries[0]["msg"])
+	assert.Contains(t, entries[0]["source"], "handler_test.go:")
+}
\ No newline at end of file
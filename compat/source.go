@@ -0,0 +1,33 @@
+package compat
+
+// adapterSource holds the "source" field value and the static fields an adapter adds to every record
+type adapterSource struct {
+	name   string
+	static []any // Key-value pairs following the source field
+}
+
+// fields returns the arguments of a record: msg, source, static fields, then extra
+func (s *adapterSource) fields(msg string, extra ...any) []any {
+	fields := make([]any, 0, 4+len(s.static)+len(extra))
+	fields = append(fields, "msg", msg)
+	fields = s.appendTo(fields)
+	return append(fields, extra...)
+}
+
+// appendTo appends the source and static fields to fields
+func (s *adapterSource) appendTo(fields []any) []any {
+	fields = append(fields, "source", s.name)
+	return append(fields, s.static...)
+}
+
+// setSource overrides the source value, empty keeps the adapter's default
+func (s *adapterSource) setSource(name string) {
+	if name != "" {
+		s.name = name
+	}
+}
+
+// addStatic appends key-value pairs added to every record, a trailing key without a value is ignored
+func (s *adapterSource) addStatic(keysAndValues []any) {
+	s.static = append(s.static, keysAndValues[:len(keysAndValues)&^1]...)
+}
\ No newline at end of file
@@ -93,7 +93,7 @@ func NewStructuredGnetAdapter(logger *log.Logger, opts ...GnetOption) *Structure
 func (a *StructuredGnetAdapter) Debugf(format string, args ...any) {
 	if a.extractFields {
 		fields := parseFormat(format, args)
-		a.logger.Debug(append(fields, "source", "gnet")...)
+		a.log(log.LevelDebug, a.source.appendTo(fields)...)
 	} else {
 		a.GnetAdapter.Debugf(format, args...)
 	}
@@ -103,7 +103,7 @@ func (a *StructuredGnetAdapter) Debugf(format string, args ...any) {
 func (a *StructuredGnetAdapter) Infof(format string, args ...any) {
 	if a.extractFields {
 		fields := parseFormat(format, args)
-		a.logger.Info(append(fields, "source", "gnet")...)
+		a.log(log.LevelInfo, a.source.appendTo(fields)...)
 	} else {
 		a.GnetAdapter.Infof(format, args...)
 	}
@@ -113,7 +113,7 @@ func (a *StructuredGnetAdapter) Infof(format string, args ...any) {
 func (a *StructuredGnetAdapter) Warnf(format string, args ...any) {
 	if a.extractFields {
 		fields := parseFormat(format, args)
-		a.logger.Warn(append(fields, "source", "gnet")...)
+		a.log(log.LevelWarn, a.source.appendTo(fields)...)
 	} else {
 		a.GnetAdapter.Warnf(format, args...)
 	}
@@ -123,7 +123,7 @@ func (a *StructuredGnetAdapter) Warnf(format string, args ...any) {
 func (a *StructuredGnetAdapter) Errorf(format string, args ...any) {
 	if a.extractFields {
 		fields := parseFormat(format, args)
-		a.logger.Error(append(fields, "source", "gnet")...)
+		a.log(log.LevelError, a.source.appendTo(fields)...)
 	} else {
 		a.GnetAdapter.Errorf(format, args...)
 	}
@@ -0,0 +1,166 @@
+// Package zerologbridge writes zerolog events into a lixenwraith/log Logger
+// It lives in its own module so the core log package stays free of zerolog dependencies
+package zerologbridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/lixenwraith/log"
+	"github.com/rs/zerolog"
+)
+
+// flushTimeout bounds the flush before zerolog exits or panics on a fatal or panic event
+const flushTimeout = time.Second
+
+// Writer is a zerolog.LevelWriter converting each event into a log record
+// Applications migrating from zerolog route it into the same files before every call site is converted
+//
+//	zl := zerolog.New(zerologbridge.New(appLogger)).With().Timestamp().Logger()
+type Writer struct {
+	logger *log.Logger
+}
+
+var _ zerolog.LevelWriter = (*Writer)(nil)
+
+// New creates a writer backed by the given logger
+func New(logger *log.Logger) *Writer {
+	return &Writer{logger: logger}
+}
+
+// Write logs an event whose level is read from its zerolog.LevelFieldName field
+// Always reports the full length as written so zerolog never sees a short write
+func (w *Writer) Write(p []byte) (int, error) {
+	level := zerolog.NoLevel
+	fields := decode(p)
+	if name, ok := fields[zerolog.LevelFieldName].(string); ok {
+		if parsed, err := zerolog.ParseLevel(name); err == nil {
+			level = parsed
+		}
+	}
+	w.ingest(level, fields, p)
+	return len(p), nil
+}
+
+// WriteLevel logs an event at the level zerolog reports, skipping the JSON decoding of events the logger drops
+// Fatal and panic events are flushed before returning, as zerolog exits or panics right after
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level == zerolog.Disabled || !w.logger.Enabled(logLevel(level)) {
+		return len(p), nil
+	}
+	fields := decode(p)
+	w.ingest(level, fields, p)
+	return len(p), nil
+}
+
+// ingest converts a decoded event into a record with msg, source, and the remaining fields in key order
+// The event's timestamp is kept; events that are not JSON objects are logged as their raw line
+func (w *Writer) ingest(level zerolog.Level, fields map[string]any, p []byte) {
+	record := log.Record{Level: logLevel(level)}
+	if fields == nil {
+		record.Args = []any{"msg", string(bytes.TrimSpace(p)), "source", "zerolog"}
+	} else {
+		message, _ := fields[zerolog.MessageFieldName].(string)
+		record.Time, _ = eventTime(fields[zerolog.TimestampFieldName])
+		delete(fields, zerolog.MessageFieldName)
+		delete(fields, zerolog.LevelFieldName)
+		delete(fields, zerolog.TimestampFieldName)
+
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		args := make([]any, 0, 6+len(keys)*2)
+		args = append(args, "msg", message, "source", "zerolog")
+		if level == zerolog.TraceLevel || level == zerolog.FatalLevel || level == zerolog.PanicLevel {
+			// Preserve levels without a direct equivalent
+			args = append(args, "level", level.String())
+		}
+		for _, k := range keys {
+			args = append(args, k, value(fields[k]))
+		}
+		record.Args = args
+	}
+
+	w.logger.Ingest(record)
+	if level == zerolog.FatalLevel || level == zerolog.PanicLevel {
+		_ = w.logger.Flush(flushTimeout)
+	}
+}
+
+// decode parses one JSON object keeping numbers as json.Number, nil when p is not an object
+func decode(p []byte) map[string]any {
+	var fields map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// eventTime parses a timestamp written with zerolog.TimeFieldFormat, reporting false when it is absent or unreadable
+func eventTime(v any) (time.Time, bool) {
+	switch zerolog.TimeFieldFormat {
+	case zerolog.TimeFormatUnix, zerolog.TimeFormatUnixMs, zerolog.TimeFormatUnixMicro, zerolog.TimeFormatUnixNano:
+		num, ok := v.(json.Number)
+		if !ok {
+			return time.Time{}, false
+		}
+		n, err := num.Int64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		switch zerolog.TimeFieldFormat {
+		case zerolog.TimeFormatUnixMs:
+			return time.UnixMilli(n), true
+		case zerolog.TimeFormatUnixMicro:
+			return time.UnixMicro(n), true
+		case zerolog.TimeFormatUnixNano:
+			return time.Unix(0, n), true
+		default:
+			return time.Unix(n, 0), true
+		}
+	default:
+		s, ok := v.(string)
+		if !ok {
+			return time.Time{}, false
+		}
+		t, err := time.Parse(zerolog.TimeFieldFormat, s)
+		return t, err == nil
+	}
+}
+
+// logLevel maps zerolog levels to log levels
+// Fatal and panic map to ERROR, zerolog still handles exit or panic itself; events without a level are INFO
+func logLevel(level zerolog.Level) int64 {
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return log.LevelDebug
+	case zerolog.WarnLevel:
+		return log.LevelWarn
+	case zerolog.ErrorLevel, zerolog.FatalLevel, zerolog.PanicLevel:
+		return log.LevelError
+	default:
+		return log.LevelInfo
+	}
+}
+
+// value converts JSON numbers to int64 or float64, other values are returned unchanged
+func value(v any) any {
+	num, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := num.Int64(); err == nil {
+		return i
+	}
+	if f, err := num.Float64(); err == nil {
+		return f
+	}
+	return num.String()
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+package zerologbridge
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lixenwraith/log"
+	"github.com/lixenwraith/log/logtest"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	logger, capture := logtest.NewTestLogger(t)
+	stamp := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	zl := zerolog.New(New(logger)).With().Timestamp().Logger()
+	zerolog.TimestampFunc = func() time.Time { return stamp }
+	t.Cleanup(func() { zerolog.TimestampFunc = time.Now })
+
+	zl.Debug().Msg("dropped")
+	zl.Info().Int("port", 8080).Str("addr", "0.0.0.0").Msg("server started")
+	zl.WithLevel(zerolog.FatalLevel).Msg("shutting down")
+	_, err := New(logger).Write([]byte(`{"level":"warn","message":"disk low"}` + "\n"))
+	require.NoError(t, err)
+	_, err = New(logger).Write([]byte("plain line\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, logger.Flush(time.Second))
+	records := capture.Records()
+	require.Len(t, records, 4)
+
+	assert.Equal(t, log.LevelInfo, records[0].Level)
+	assert.Equal(t, []any{"msg", "server started", "source", "zerolog", "addr", "0.0.0.0", "port", int64(8080)}, records[0].Args)
+	assert.True(t, stamp.Equal(records[0].Time), "the event's timestamp is kept")
+
+	assert.Equal(t, log.LevelError, records[1].Level)
+	assert.Equal(t, []any{"msg", "shutting down", "source", "zerolog", "level", "fatal"}, records[1].Args)
+
+	assert.Equal(t, log.LevelWarn, records[2].Level)
+	assert.Equal(t, []any{"msg", "disk low", "source", "zerolog"}, records[2].Args)
+
+	assert.Equal(t, log.LevelInfo, records[3].Level)
+	assert.Equal(t, []any{"msg", "plain line", "source", "zerolog"}, records[3].Args)
+}
+
+func TestEventTime(t *testing.T) {
+	defer func(format string) { zerolog.TimeFieldFormat = format }(zerolog.TimeFieldFormat)
+
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnixMs
+	ts, ok := eventTime(json.Number("1705314600123"))
+	require.True(t, ok)
+	assert.Equal(t, int64(1705314600123), ts.UnixMilli())
+
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	ts, ok = eventTime(json.Number("1705314600"))
+	require.True(t, ok)
+	assert.Equal(t, int64(1705314600), ts.Unix())
+
+	zerolog.TimeFieldFormat = time.RFC3339
+	_, ok = eventTime("yesterday")
+	assert.False(t, ok)
+	_, ok = eventTime(nil)
+	assert.False(t, ok)
+}
\ No newline at end of file
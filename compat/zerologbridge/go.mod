@@ -0,0 +1,20 @@
+module github.com/lixenwraith/log/compat/zerologbridge
+
+go 1.26.0
+
+require (
+	github.com/lixenwraith/log v0.0.0-20261016171709-66f395c1eedb
+	github.com/rs/zerolog v1.35.1
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/lixenwraith/log => ../..
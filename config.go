@@ -2,91 +2,324 @@ package log
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/lixenwraith/log/formatter"
 	"github.com/lixenwraith/log/sanitizer"
 )
 
 // Config holds all logger configuration values
 type Config struct {
 	// File and Console output settings
-	EnableConsole bool   `toml:"enable_console"` // Enable console output (stdout/stderr)
-	ConsoleTarget string `toml:"console_target"` // "stdout", "stderr", or "split"
-	EnableFile    bool   `toml:"enable_file"`    // Enable file output
+	EnableConsole    bool   `toml:"enable_console"`     // Enable console output (stdout/stderr)
+	ConsoleTarget    string `toml:"console_target"`     // "stdout", "stderr", or "split"
+	ConsoleGlyphs    bool   `toml:"console_glyphs"`     // Render console levels as colored glyphs instead of names
+	ConsoleFileDrops bool   `toml:"console_file_drops"` // Mirror WARN and above dropped from the file to the console
+	ConsoleColor     string `toml:"console_color"`      // "auto" colors terminals unless NO_COLOR is set, "always", or "never"
+	ConsoleColorLine bool   `toml:"console_color_line"` // Color whole console lines by level instead of the level token
+	EnableFile       bool   `toml:"enable_file"`        // Enable file output
+
+	// Syslog output
+	EnableSyslog   bool   `toml:"enable_syslog"`   // Forward records to a syslog daemon
+	SyslogNetwork  string `toml:"syslog_network"`  // "udp", "tcp", "unix", "unixgram", or "" for the local daemon
+	SyslogAddress  string `toml:"syslog_address"`  // Daemon address, e.g. "logs.example.com:514" or a socket path
+	SyslogFormat   string `toml:"syslog_format"`   // "rfc3164" or "rfc5424"
+	SyslogFacility string `toml:"syslog_facility"` // Facility name: "user", "daemon", "local0"-"local7", ...
+	SyslogTag      string `toml:"syslog_tag"`      // APP-NAME/TAG of messages, defaults to Name
+	SyslogSDID     string `toml:"syslog_sd_id"`    // SD-ID of the structured data element of rfc5424 records, "name@enterprise"
+
+	// GELF output
+	EnableGELF  bool   `toml:"enable_gelf"`  // Ship records to a Graylog GELF input
+	GELFNetwork string `toml:"gelf_network"` // "udp" (chunked datagrams) or "tcp" (null-byte delimited)
+	GELFAddress string `toml:"gelf_address"` // GELF input address, e.g. "graylog.example.com:12201"
+	GELFHost    string `toml:"gelf_host"`    // Host field of GELF messages, defaults to the machine hostname
+
+	// Journald output
+	EnableJournal bool   `toml:"enable_journal"` // Write records to the systemd journal
+	JournalSocket string `toml:"journal_socket"` // Journal socket path, empty for the systemd default
+
+	// S3 archive upload
+	S3Upload    bool   `toml:"s3_upload"`     // Upload rotated archives to an S3-compatible bucket
+	S3Endpoint  string `toml:"s3_endpoint"`   // Service URL, e.g. "https://s3.us-east-1.amazonaws.com"
+	S3Bucket    string `toml:"s3_bucket"`     // Bucket receiving the archives
+	S3Region    string `toml:"s3_region"`     // Region requests are signed for
+	S3Prefix    string `toml:"s3_prefix"`     // Key prefix, expands {name}, {host}, {yyyy}, {mm}, {dd}, {hh}
+	S3KeepLocal bool   `toml:"s3_keep_local"` // Keep the local archive after a successful upload
 
 	// Basic settings
-	Level     int64  `toml:"level"`     // Log records at or above this Level will be logged
-	Name      string `toml:"name"`      // Base name for log files
-	Directory string `toml:"directory"` // Directory for log files
-	Extension string `toml:"extension"` // Log file extension
+	Level           int64  `toml:"level"`            // Log records at or above this Level will be logged
+	Name            string `toml:"name"`             // Base name for log files
+	Directory       string `toml:"directory"`        // Directory for log files
+	MirrorDirectory string `toml:"mirror_directory"` // Second directory receiving a copy of every file record (""=disabled)
+	Extension       string `toml:"extension"`        // Log file extension
+	ReadOnly        bool   `toml:"read_only"`        // Inspect the files in Directory without creating, rotating, or deleting any
+
+	// Per-output minimum levels, applied after Level
+	ConsoleLevel int64 `toml:"console_level"` // Console output only receives records at or above this level
+	FileLevel    int64 `toml:"file_level"`    // File output only receives records at or above this level
+
+	// Field-based level routing, e.g. {"source=gnet": "warn", "component=db.*": "debug"}
+	LevelOverridesByField       map[string]string `toml:"level_overrides_by_field"`       // "field=value" -> level, '*' wildcards values
+	SuppressionSummaryIntervalS int64             `toml:"suppression_summary_interval_s"` // Interval of records counting suppressed records per rule (0=disabled)
+
+	// Directory recovery
+	AutoRecreateDir bool `toml:"auto_recreate_dir"` // Recreate log directory and file if removed at runtime
+	FileHeader      bool `toml:"file_header"`       // Write a metadata header line to each new log file
+	RotationJournal bool `toml:"rotation_journal"`  // Record rotations as JSON lines in {name}.rotations
+
+	// File I/O tuning
+	FadviseDontNeed bool `toml:"fadvise_dontneed"` // Drop rotated files from the page cache (Linux only)
+	OpenDSync       bool `toml:"open_dsync"`       // Open log files with O_DSYNC, each write reaches the disk
+	GzipActive      bool `toml:"gzip_active"`      // Write the active log file gzip-compressed as {name}.{ext}.gz
+	AuditVerify     bool `toml:"audit_verify"`     // Read back audit records from the file before confirming them
 
 	// Formatting
-	Format          string                 `toml:"format"`           // "txt", "raw", or "json"
-	ShowTimestamp   bool                   `toml:"show_timestamp"`   // Add timestamp to log records
-	ShowLevel       bool                   `toml:"show_level"`       // Add level to log record
-	TimestampFormat string                 `toml:"timestamp_format"` // Time format for log timestamps
-	Sanitization    sanitizer.PolicyPreset `toml:"sanitization"`     // "raw", "json", "txt", "shell"
+	Format            string                 `toml:"format"`              // "txt", "pretty", "raw", "json", "ecs", "gcp", "gelf", "binary", "msgpack", "csv", "cef", or "rfc5424"
+	ConsoleFormat     string                 `toml:"console_format"`      // Console output format, empty uses format
+	FileFormat        string                 `toml:"file_format"`         // File output format, empty uses format
+	ShowTimestamp     bool                   `toml:"show_timestamp"`      // Add timestamp to log records
+	ShowLevel         bool                   `toml:"show_level"`          // Add level to log record
+	TimestampFormat   string                 `toml:"timestamp_format"`    // Time format for log timestamps
+	Sanitization      sanitizer.PolicyPreset `toml:"sanitization"`        // "raw", "json", "txt", "shell"
+	EagerStringify    bool                   `toml:"eager_stringify"`     // Snapshot Stringer/error args at call time
+	AutoKV            bool                   `toml:"auto_kv"`             // Flatten key-value args into top-level JSON fields
+	JSONFields        string                 `toml:"json_fields"`         // JSON args not flattened: "array" or "object" after the message
+	NestedKeys        bool                   `toml:"nested_keys"`         // Expand dotted field keys into nested JSON and ECS objects
+	ReservedKeyPolicy string                 `toml:"reserved_key_policy"` // Flattened keys colliding with record keys: "prefix", "drop", or "allow"
+	BytesEncoding     string                 `toml:"bytes_encoding"`      // []byte rendering: "string", "hex", or "base64"
+	DurationFormat    string                 `toml:"duration_format"`     // time.Duration rendering: "string", "ns", or "ms"
+	TimeValueFormat   string                 `toml:"time_value_format"`   // time.Time rendering: "timestamp", "rfc3339", "unix", or "unix_ms"
+	CSVColumns        string                 `toml:"csv_columns"`         // Comma-separated column order of csv records
+	TxtLayout         string                 `toml:"txt_layout"`          // Placeholder layout of txt records, e.g. "{time} [{level}] {msg} {fields}" (""=built-in)
+	CustomFormatter   RecordFormatter        `toml:"-"`                   // Formats records in place of format when set, in code only
+
+	// JSON record keys
+	FieldTimeKey    string `toml:"field_time_key"`    // Key of the timestamp in json records
+	FieldLevelKey   string `toml:"field_level_key"`   // Key of the level in json records
+	FieldTraceKey   string `toml:"field_trace_key"`   // Key of the function trace in json records
+	FieldFieldsKey  string `toml:"field_fields_key"`  // Key of the fields array or object in json records
+	FieldMessageKey string `toml:"field_message_key"` // Key of the message in structured and json_fields=object records
+
+	// Record enrichment, appended to every record by the processor
+	EnrichHost bool   `toml:"enrich_host"` // Add the machine hostname as "host"
+	EnrichPID  bool   `toml:"enrich_pid"`  // Add the process ID as "pid"
+	AppName    string `toml:"app_name"`    // Add the application name as "app" (""=omitted)
+	AppVersion string `toml:"app_version"` // Add the application version as "version" (""=omitted)
+
+	// Per-output sanitization, empty falls back to sanitization_by_format, then sanitization
+	ConsoleSanitization  sanitizer.PolicyPreset `toml:"console_sanitization"`   // Console output policy
+	FileSanitization     sanitizer.PolicyPreset `toml:"file_sanitization"`      // File output and registered sink policy
+	NetworkSanitization  sanitizer.PolicyPreset `toml:"network_sanitization"`   // Syslog, journald, and GELF output policy
+	SanitizationByFormat map[string]string      `toml:"sanitization_by_format"` // Format -> policy of outputs writing that format
+
+	// CEF records
+	CEFVendor         string            `toml:"cef_vendor"`          // Device Vendor header field
+	CEFProduct        string            `toml:"cef_product"`         // Device Product header field, defaults to Name
+	CEFProductVersion string            `toml:"cef_product_version"` // Device Version header field
+	CEFExtensionMap   map[string]string `toml:"cef_extension_map"`   // Argument key -> CEF extension key, e.g. "client_ip" -> "src"
+
+	// GCP records
+	GCPProject string `toml:"gcp_project"` // Project ID completing trace_id fields to "projects/<id>/traces/<trace_id>"
 
 	// Buffer and size limits
-	BufferSize     int64 `toml:"buffer_size"`       // Channel buffer size
-	MaxSizeKB      int64 `toml:"max_size_kb"`       // Max size per log file
-	MaxTotalSizeKB int64 `toml:"max_total_size_kb"` // Max total size of all logs in dir
-	MinDiskFreeKB  int64 `toml:"min_disk_free_kb"`  // Minimum free disk space required
+	BufferSize    int64 `toml:"buffer_size"`    // Channel buffer size
+	RecentRecords int64 `toml:"recent_records"` // Formatted records kept in memory for DumpRecent (0=disabled)
+
+	// Call path latency
+	CallLatencyStats    bool   `toml:"call_latency_stats"`     // Time logging calls and report percentiles in Stats and PROC heartbeats
+	CallLatencyBudgetUs int64  `toml:"call_latency_budget_us"` // Call duration after which the call path sheds work (0=disabled)
+	MaxSizeKB           int64  `toml:"max_size_kb"`            // Max size per log file
+	MaxTotalSizeKB      int64  `toml:"max_total_size_kb"`      // Max total size of all logs in dir
+	MinDiskFreeKB       int64  `toml:"min_disk_free_kb"`       // Minimum free disk space required
+	OnDiskFull          string `toml:"on_disk_full"`           // Records bound for the file while disk limits block it: "drop", "stderr", or "block"
+	Shards              int64  `toml:"shards"`                 // Parallel shard files with own writers (0 or 1 = single file)
+	SplitErrorFile      bool   `toml:"split_error_file"`       // Duplicate WARN and ERROR records into {name}_error.{ext}
+
+	// Record field limits
+	MaxFieldsPerRecord int64  `toml:"max_fields_per_record"` // Max args (or structured fields) per record (0=unlimited)
+	MaxFieldKeyLen     int64  `toml:"max_field_key_len"`     // Max field key length in bytes (0=unlimited)
+	FieldLimitPolicy   string `toml:"field_limit_policy"`    // "truncate", "drop_extra", or "reject"
 
 	// Timers
 	FlushIntervalMs    int64   `toml:"flush_interval_ms"`    // Interval for flushing file buffer
 	TraceDepth         int64   `toml:"trace_depth"`          // Default trace depth (0-10)
 	RetentionPeriodHrs float64 `toml:"retention_period_hrs"` // Hours to keep logs (0=disabled)
 	RetentionCheckMins float64 `toml:"retention_check_mins"` // How often to check retention
+	RetentionDryRun    bool    `toml:"retention_dry_run"`    // Log deletions by retention and disk limits instead of deleting
 
 	// Disk check settings
-	DiskCheckIntervalMs    int64 `toml:"disk_check_interval_ms"`   // Base interval for disk checks
-	EnableAdaptiveInterval bool  `toml:"enable_adaptive_interval"` // Adjust interval based on log rate
-	EnablePeriodicSync     bool  `toml:"enable_periodic_sync"`     // Periodic sync with disk
-	MinCheckIntervalMs     int64 `toml:"min_check_interval_ms"`    // Minimum adaptive interval
-	MaxCheckIntervalMs     int64 `toml:"max_check_interval_ms"`    // Maximum adaptive interval
+	DiskCheckIntervalMs    int64 `toml:"disk_check_interval_ms"`    // Base interval for disk checks
+	EnableAdaptiveInterval bool  `toml:"enable_adaptive_interval"`  // Adjust interval based on log rate
+	EnablePeriodicSync     bool  `toml:"enable_periodic_sync"`      // Periodic sync with disk
+	MinCheckIntervalMs     int64 `toml:"min_check_interval_ms"`     // Minimum adaptive interval
+	MaxCheckIntervalMs     int64 `toml:"max_check_interval_ms"`     // Maximum adaptive interval
+	SizeReconcileIntervalS int64 `toml:"size_reconcile_interval_s"` // Seconds between directory scans correcting the archive size totals (0=scan on every check)
 
 	// Heartbeat configuration
 	HeartbeatLevel     int64 `toml:"heartbeat_level"`      // 0=disabled, 1=proc only, 2=proc+disk, 3=proc+disk+sys
 	HeartbeatIntervalS int64 `toml:"heartbeat_interval_s"` // Interval seconds for heartbeat
 
+	HeartbeatIncidentIntervalS int64  `toml:"heartbeat_incident_interval_s"` // Interval seconds while records drop or disk status is not OK (0=disabled)
+	HeartbeatFormat            string `toml:"heartbeat_format"`              // "json" or "logfmt" heartbeats in text files, empty follows the file format
+
+	// Process exit
+	FlushOnExit        bool `toml:"flush_on_exit"`         // Best-effort flush when SIGINT or SIGTERM ends the process
+	FlushOnExitReraise bool `toml:"flush_on_exit_reraise"` // Re-send the signal after the flush, for processes not handling it
+
+	// Exec hook
+	OnErrorExec          string `toml:"on_error_exec"`            // Command run when a record at or above on_error_exec_level is written (""=disabled)
+	OnErrorExecLevel     int64  `toml:"on_error_exec_level"`      // Minimum level of records triggering the command
+	OnErrorExecIntervalS int64  `toml:"on_error_exec_interval_s"` // Minimum seconds between runs, records in between are counted (0=no limit)
+
 	// Internal error handling
-	InternalErrorsToStderr bool `toml:"internal_errors_to_stderr"` // Write internal errors to stderr
+	InternalErrorsToStderr     bool  `toml:"internal_errors_to_stderr"`      // Write internal errors to stderr
+	InternalErrorsToLog        bool  `toml:"internal_errors_to_log"`         // Log output and retention errors as WARN records while the file is healthy
+	InternalErrorsLogIntervalS int64 `toml:"internal_errors_log_interval_s"` // Minimum seconds between records of one source, errors in between are counted
+
+	provenance map[string]provenanceEntry // Recorded source of fields set through Set, ApplyConfigString, or Builder
 }
 
 // defaultConfig is the single source for all configurable default values
 var defaultConfig = Config{
 	// Output settings
-	EnableConsole: true,
-	ConsoleTarget: "stderr",
-	EnableFile:    false,
+	EnableConsole:    true,
+	ConsoleTarget:    "stderr",
+	ConsoleGlyphs:    false,
+	ConsoleFileDrops: false,
+	ConsoleColor:     "auto",
+	ConsoleColorLine: false,
+	EnableFile:       false,
+
+	// Syslog output
+	EnableSyslog:   false,
+	SyslogNetwork:  "",
+	SyslogAddress:  "",
+	SyslogFormat:   SyslogRFC5424,
+	SyslogFacility: "user",
+	SyslogTag:      "",
+	SyslogSDID:     "fields@32473",
+
+	// GELF output
+	EnableGELF:  false,
+	GELFNetwork: "udp",
+	GELFAddress: "",
+	GELFHost:    "",
+
+	// Journald output
+	EnableJournal: false,
+	JournalSocket: "",
+
+	// S3 archive upload
+	S3Upload:    false,
+	S3Endpoint:  "",
+	S3Bucket:    "",
+	S3Region:    "us-east-1",
+	S3Prefix:    "{name}/{yyyy}/{mm}/{dd}/",
+	S3KeepLocal: true,
 
 	// File settings
-	Level:     LevelInfo,
-	Name:      "log",
-	Directory: "./log",
-	Extension: "log",
+	Level:           LevelInfo,
+	Name:            "log",
+	Directory:       "./log",
+	MirrorDirectory: "",
+	Extension:       "log",
+	ReadOnly:        false,
+
+	// Per-output minimum levels
+	ConsoleLevel: LevelDebug,
+	FileLevel:    LevelDebug,
+
+	// Field-based level routing
+	LevelOverridesByField:       nil,
+	SuppressionSummaryIntervalS: 0,
+
+	// Directory recovery
+	AutoRecreateDir: false,
+	FileHeader:      false,
+	RotationJournal: false,
+
+	// File I/O tuning
+	FadviseDontNeed: false,
+	OpenDSync:       false,
+	GzipActive:      false,
+	AuditVerify:     false,
 
 	// Formatting
-	Format:          "raw",
-	ShowTimestamp:   true,
-	ShowLevel:       true,
-	TimestampFormat: time.RFC3339Nano,
-	Sanitization:    PolicyRaw,
+	Format:            "raw",
+	ConsoleFormat:     "",
+	FileFormat:        "",
+	ShowTimestamp:     true,
+	ShowLevel:         true,
+	TimestampFormat:   time.RFC3339Nano,
+	Sanitization:      PolicyRaw,
+	EagerStringify:    false,
+	AutoKV:            false,
+	JSONFields:        "array",
+	NestedKeys:        false,
+	ReservedKeyPolicy: "prefix",
+	BytesEncoding:     "string",
+	DurationFormat:    "string",
+	TimeValueFormat:   "timestamp",
+	CSVColumns:        "time,level,trace,message,extra",
+	TxtLayout:         "",
+
+	// JSON record keys
+	FieldTimeKey:    "time",
+	FieldLevelKey:   "level",
+	FieldTraceKey:   "trace",
+	FieldFieldsKey:  "fields",
+	FieldMessageKey: "message",
+
+	// Record enrichment
+	EnrichHost: false,
+	EnrichPID:  false,
+	AppName:    "",
+	AppVersion: "",
+
+	// Per-output sanitization
+	ConsoleSanitization:  "",
+	FileSanitization:     "",
+	NetworkSanitization:  "",
+	SanitizationByFormat: nil,
+
+	// CEF records
+	CEFVendor:         "lixenwraith",
+	CEFProduct:        "",
+	CEFProductVersion: "",
+	CEFExtensionMap:   nil,
+
+	// GCP records
+	GCPProject: "",
 
 	// Buffer and size limits
-	BufferSize:     1024,
-	MaxSizeKB:      1000,
-	MaxTotalSizeKB: 5000,
-	MinDiskFreeKB:  10000,
+	BufferSize:    1024,
+	RecentRecords: 0,
+
+	// Call path latency
+	CallLatencyStats:    false,
+	CallLatencyBudgetUs: 0,
+	MaxSizeKB:           1000,
+	MaxTotalSizeKB:      5000,
+	MinDiskFreeKB:       10000,
+	OnDiskFull:          "drop",
+	Shards:              0,
+	SplitErrorFile:      false,
+
+	// Record field limits
+	MaxFieldsPerRecord: 0,
+	MaxFieldKeyLen:     0,
+	FieldLimitPolicy:   "truncate",
 
 	// Timers
 	FlushIntervalMs:    100,
 	TraceDepth:         0,
 	RetentionPeriodHrs: 0.0,
 	RetentionCheckMins: 60.0,
+	RetentionDryRun:    false,
 
 	// Disk check settings
 	DiskCheckIntervalMs:    5000,
@@ -94,13 +327,28 @@ var defaultConfig = Config{
 	EnablePeriodicSync:     true,
 	MinCheckIntervalMs:     100,
 	MaxCheckIntervalMs:     60000,
+	SizeReconcileIntervalS: 300,
 
 	// Heartbeat settings
 	HeartbeatLevel:     0,
 	HeartbeatIntervalS: 60,
 
+	HeartbeatIncidentIntervalS: 5,
+	HeartbeatFormat:            "",
+
+	// Process exit
+	FlushOnExit:        false,
+	FlushOnExitReraise: false,
+
+	// Exec hook
+	OnErrorExec:          "",
+	OnErrorExecLevel:     LevelError,
+	OnErrorExecIntervalS: 60,
+
 	// Internal error handling
-	InternalErrorsToStderr: false,
+	InternalErrorsToStderr:     false,
+	InternalErrorsToLog:        false,
+	InternalErrorsLogIntervalS: 60,
 }
 
 // DefaultConfig returns a copy of the default configuration
@@ -112,6 +360,10 @@ func DefaultConfig() *Config {
 // Clone creates a deep copy of the configuration
 func (c *Config) Clone() *Config {
 	copiedConfig := *c
+	copiedConfig.LevelOverridesByField = maps.Clone(c.LevelOverridesByField)
+	copiedConfig.CEFExtensionMap = maps.Clone(c.CEFExtensionMap)
+	copiedConfig.SanitizationByFormat = maps.Clone(c.SanitizationByFormat)
+	copiedConfig.provenance = maps.Clone(c.provenance)
 	return &copiedConfig
 }
 
@@ -122,16 +374,130 @@ func (c *Config) Validate() error {
 		return fmtErrorf("log name cannot be empty")
 	}
 
-	if c.Format != "txt" && c.Format != "json" && c.Format != "raw" {
-		return fmtErrorf("invalid format: '%s' (use txt, json, or raw)", c.Format)
+	if c.MirrorDirectory != "" && sameDirectory(c.MirrorDirectory, c.Directory) {
+		return fmtErrorf("mirror_directory must differ from directory: '%s'", c.MirrorDirectory)
 	}
 
-	switch c.Sanitization {
-	case PolicyRaw, PolicyJSON, PolicyTxt, PolicyShell:
-		// valid policy
+	switch c.Format {
+	case "txt", "pretty", "json", "ecs", "gcp", "gelf", "raw", "binary", "msgpack", "csv", "cef", "rfc5424":
+		// valid format
+	default:
+		return fmtErrorf("invalid format: '%s' (use txt, pretty, json, ecs, gcp, gelf, raw, binary, msgpack, csv, cef, or rfc5424)", c.Format)
+	}
+
+	// Per-output formats are optional, empty falls back to format
+	switch c.ConsoleFormat {
+	case "", "txt", "pretty", "json", "ecs", "gcp", "gelf", "raw", "binary", "msgpack", "csv", "cef", "rfc5424":
+		// valid format
 	default:
+		return fmtErrorf("invalid console_format: '%s' (use txt, pretty, json, ecs, gcp, gelf, raw, binary, msgpack, csv, cef, or rfc5424)", c.ConsoleFormat)
+	}
+	// Pretty output is meant for people, files and sinks keep a machine-readable format
+	switch c.FileFormat {
+	case "", "txt", "json", "ecs", "gcp", "gelf", "raw", "binary", "msgpack", "csv", "cef", "rfc5424":
+		// valid format
+	default:
+		return fmtErrorf("invalid file_format: '%s' (use txt, json, ecs, gcp, gelf, raw, binary, msgpack, csv, cef, or rfc5424)", c.FileFormat)
+	}
+
+	if !validSanitization(c.Sanitization) {
 		return fmtErrorf("invalid sanitization policy: '%s' (use raw, json, txt, or shell)", c.Sanitization)
 	}
+	if c.ConsoleSanitization != "" && !validSanitization(c.ConsoleSanitization) {
+		return fmtErrorf("invalid console_sanitization policy: '%s' (use raw, json, txt, or shell)", c.ConsoleSanitization)
+	}
+	if c.FileSanitization != "" && !validSanitization(c.FileSanitization) {
+		return fmtErrorf("invalid file_sanitization policy: '%s' (use raw, json, txt, or shell)", c.FileSanitization)
+	}
+	if c.NetworkSanitization != "" && !validSanitization(c.NetworkSanitization) {
+		return fmtErrorf("invalid network_sanitization policy: '%s' (use raw, json, txt, or shell)", c.NetworkSanitization)
+	}
+	for format, policy := range c.SanitizationByFormat {
+		switch format {
+		case "txt", "pretty", "logfmt", "json", "ecs", "gcp", "gelf", "raw", "binary", "msgpack", "csv", "cef", "rfc5424":
+			// valid format
+		default:
+			return fmtErrorf("invalid sanitization_by_format format: '%s' (use txt, pretty, logfmt, json, ecs, gcp, gelf, raw, binary, msgpack, csv, cef, or rfc5424)", format)
+		}
+		if !validSanitization(sanitizer.PolicyPreset(policy)) {
+			return fmtErrorf("invalid sanitization_by_format policy for '%s': '%s' (use raw, json, txt, or shell)", format, policy)
+		}
+	}
+
+	switch c.FieldLimitPolicy {
+	case "truncate", "drop_extra", "reject":
+		// valid policy
+	default:
+		return fmtErrorf("invalid field_limit_policy: '%s' (use truncate, drop_extra, or reject)", c.FieldLimitPolicy)
+	}
+
+	switch c.BytesEncoding {
+	case "string", "hex", "base64":
+		// valid encoding
+	default:
+		return fmtErrorf("invalid bytes_encoding: '%s' (use string, hex, or base64)", c.BytesEncoding)
+	}
+
+	if !slices.Contains(formatter.DurationFormats, c.DurationFormat) {
+		return fmtErrorf("invalid duration_format: '%s' (use %s)", c.DurationFormat, strings.Join(formatter.DurationFormats, ", "))
+	}
+
+	if !slices.Contains(formatter.TimeValueFormats, c.TimeValueFormat) {
+		return fmtErrorf("invalid time_value_format: '%s' (use %s)", c.TimeValueFormat, strings.Join(formatter.TimeValueFormats, ", "))
+	}
+
+	keys := c.jsonKeys()
+	names := []string{keys.Time, keys.Level, keys.Trace, keys.Fields, keys.Message}
+	for i, key := range []string{"field_time_key", "field_level_key", "field_trace_key", "field_fields_key", "field_message_key"} {
+		if names[i] == "" {
+			return fmtErrorf("%s cannot be empty", key)
+		}
+		if names[i] == "labels" || slices.Contains(names[:i], names[i]) {
+			return fmtErrorf("%s '%s' is already used by another json record key", key, names[i])
+		}
+	}
+
+	switch c.JSONFields {
+	case "array", "object":
+		// valid mode
+	default:
+		return fmtErrorf("invalid json_fields: '%s' (use array or object)", c.JSONFields)
+	}
+
+	switch c.ReservedKeyPolicy {
+	case "prefix", "drop", "allow":
+		// valid policy
+	default:
+		return fmtErrorf("invalid reserved_key_policy: '%s' (use prefix, drop, or allow)", c.ReservedKeyPolicy)
+	}
+
+	if err := formatter.ValidateTxtLayout(c.TxtLayout); err != nil {
+		return fmtErrorf("invalid txt_layout: %w", err)
+	}
+
+	columns := c.csvColumns()
+	if len(columns) == 0 {
+		return fmtErrorf("csv_columns cannot be empty")
+	}
+	for i, column := range columns {
+		if !slices.Contains(formatter.CSVColumnNames, column) {
+			return fmtErrorf("invalid csv_columns entry: '%s' (use %s)", column, strings.Join(formatter.CSVColumnNames, ", "))
+		}
+		if slices.Contains(columns[:i], column) {
+			return fmtErrorf("duplicate csv_columns entry: '%s'", column)
+		}
+	}
+
+	for field, key := range c.CEFExtensionMap {
+		if field == "" {
+			return fmtErrorf("cef_extension_map keys cannot be empty")
+		}
+		if key == "" || strings.IndexFunc(key, func(r rune) bool {
+			return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+		}) >= 0 {
+			return fmtErrorf("invalid cef_extension_map key for '%s': '%s' (use letters and digits)", field, key)
+		}
+	}
 
 	if strings.HasPrefix(c.Extension, ".") {
 		return fmtErrorf("extension should not start with dot: %s", c.Extension)
@@ -145,20 +511,74 @@ func (c *Config) Validate() error {
 		return fmtErrorf("invalid console_target: '%s' (use stdout, stderr, or split)", c.ConsoleTarget)
 	}
 
+	switch c.ConsoleColor {
+	case "auto", "always", "never":
+		// valid mode
+	default:
+		return fmtErrorf("invalid console_color: '%s' (use auto, always, or never)", c.ConsoleColor)
+	}
+
+	if _, err := compileLevelRoutes(c.LevelOverridesByField, c.Level); err != nil {
+		return err
+	}
+
+	if err := validateSyslog(c); err != nil {
+		return err
+	}
+
+	if err := validateGELF(c); err != nil {
+		return err
+	}
+
+	if err := validateS3Upload(c); err != nil {
+		return err
+	}
+
 	// Numeric validations
 	if c.BufferSize <= 0 {
 		return fmtErrorf("buffer_size must be positive: %d", c.BufferSize)
 	}
 
+	if c.RecentRecords < 0 {
+		return fmtErrorf("recent_records cannot be negative: %d", c.RecentRecords)
+	}
+
+	if c.SuppressionSummaryIntervalS < 0 {
+		return fmtErrorf("suppression_summary_interval_s cannot be negative: %d", c.SuppressionSummaryIntervalS)
+	}
+
+	if c.CallLatencyBudgetUs < 0 {
+		return fmtErrorf("call_latency_budget_us cannot be negative: %d", c.CallLatencyBudgetUs)
+	}
+
 	if c.MaxSizeKB < 0 || c.MaxTotalSizeKB < 0 || c.MinDiskFreeKB < 0 {
 		return fmtErrorf("size limits cannot be negative")
 	}
 
+	switch c.OnDiskFull {
+	case "drop", "stderr", "block":
+		// valid policy
+	default:
+		return fmtErrorf("invalid on_disk_full: '%s' (use drop, stderr, or block)", c.OnDiskFull)
+	}
+
+	if c.Shards < 0 || c.Shards > maxShards {
+		return fmtErrorf("shards must be between 0 and %d: %d", maxShards, c.Shards)
+	}
+
+	if c.MaxFieldsPerRecord < 0 || c.MaxFieldKeyLen < 0 {
+		return fmtErrorf("field limits cannot be negative")
+	}
+
 	if c.FlushIntervalMs <= 0 || c.DiskCheckIntervalMs <= 0 ||
 		c.MinCheckIntervalMs <= 0 || c.MaxCheckIntervalMs <= 0 {
 		return fmtErrorf("interval settings must be positive")
 	}
 
+	if c.SizeReconcileIntervalS < 0 {
+		return fmtErrorf("size_reconcile_interval_s cannot be negative: %d", c.SizeReconcileIntervalS)
+	}
+
 	if c.TraceDepth < 0 || c.TraceDepth > 10 {
 		return fmtErrorf("trace_depth must be between 0 and 10: %d", c.TraceDepth)
 	}
@@ -171,6 +591,33 @@ func (c *Config) Validate() error {
 		return fmtErrorf("heartbeat_level must be between 0 and 3: %d", c.HeartbeatLevel)
 	}
 
+	switch c.HeartbeatFormat {
+	case "":
+		// follows the file format
+	case "json", "logfmt":
+		// Structured heartbeats are lines of text, binary, MessagePack, GELF, CSV, CEF, and RFC 5424 files cannot interleave them
+		switch format := c.fileFormat(); format {
+		case "binary", "msgpack", "gelf", "csv", "cef", "rfc5424":
+			return fmtErrorf("heartbeat_format cannot be used with file format '%s'", format)
+		}
+	default:
+		return fmtErrorf("invalid heartbeat_format: '%s' (use json, logfmt, or empty)", c.HeartbeatFormat)
+	}
+
+	if c.OnErrorExec != "" && strings.TrimSpace(c.OnErrorExec) == "" {
+		return fmtErrorf("on_error_exec cannot be blank")
+	}
+	if c.OnErrorExecIntervalS < 0 {
+		return fmtErrorf("on_error_exec_interval_s cannot be negative: %d", c.OnErrorExecIntervalS)
+	}
+	if c.InternalErrorsLogIntervalS < 0 {
+		return fmtErrorf("internal_errors_log_interval_s cannot be negative: %d", c.InternalErrorsLogIntervalS)
+	}
+
+	if c.HeartbeatIncidentIntervalS < 0 {
+		return fmtErrorf("heartbeat_incident_interval_s cannot be negative: %d", c.HeartbeatIncidentIntervalS)
+	}
+
 	// Cross-field validations
 	if c.MinCheckIntervalMs > c.MaxCheckIntervalMs {
 		return fmtErrorf("min_check_interval_ms (%d) cannot be greater than max_check_interval_ms (%d)",
@@ -202,16 +649,94 @@ func applyConfigField(cfg *Config, key, value string) error {
 			}
 			cfg.Level = levelVal
 		}
+	case "console_level":
+		levelVal, err := parseLevelValue(value)
+		if err != nil {
+			return fmtErrorf("invalid console_level value '%s': %w", value, err)
+		}
+		cfg.ConsoleLevel = levelVal
+	case "file_level":
+		levelVal, err := parseLevelValue(value)
+		if err != nil {
+			return fmtErrorf("invalid file_level value '%s': %w", value, err)
+		}
+		cfg.FileLevel = levelVal
+	case "level_overrides_by_field":
+		overrides, err := parseLevelOverrides(value)
+		if err != nil {
+			return err
+		}
+		cfg.LevelOverridesByField = overrides
+	case "suppression_summary_interval_s":
+		intVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmtErrorf("invalid integer value for suppression_summary_interval_s '%s': %w", value, err)
+		}
+		cfg.SuppressionSummaryIntervalS = intVal
 	case "name":
 		cfg.Name = value
 	case "directory":
 		cfg.Directory = value
+	case "mirror_directory":
+		cfg.MirrorDirectory = value
 	case "extension":
 		cfg.Extension = value
+	case "read_only":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for read_only '%s': %w", value, err)
+		}
+		cfg.ReadOnly = boolVal
+	case "auto_recreate_dir":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for auto_recreate_dir '%s': %w", value, err)
+		}
+		cfg.AutoRecreateDir = boolVal
+	case "file_header":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for file_header '%s': %w", value, err)
+		}
+		cfg.FileHeader = boolVal
+	case "rotation_journal":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for rotation_journal '%s': %w", value, err)
+		}
+		cfg.RotationJournal = boolVal
+	case "fadvise_dontneed":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for fadvise_dontneed '%s': %w", value, err)
+		}
+		cfg.FadviseDontNeed = boolVal
+	case "open_dsync":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for open_dsync '%s': %w", value, err)
+		}
+		cfg.OpenDSync = boolVal
+	case "gzip_active":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for gzip_active '%s': %w", value, err)
+		}
+		cfg.GzipActive = boolVal
+	case "audit_verify":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for audit_verify '%s': %w", value, err)
+		}
+		cfg.AuditVerify = boolVal
 
 	// Formatting
 	case "format":
 		cfg.Format = value
+	case "console_format":
+		cfg.ConsoleFormat = value
+	case "file_format":
+		cfg.FileFormat = value
 	case "show_timestamp":
 		boolVal, err := strconv.ParseBool(value)
 		if err != nil {
@@ -228,6 +753,94 @@ func applyConfigField(cfg *Config, key, value string) error {
 		cfg.TimestampFormat = value
 	case "sanitization":
 		cfg.Sanitization = sanitizer.PolicyPreset(value)
+	case "field_time_key":
+		cfg.FieldTimeKey = value
+	case "field_level_key":
+		cfg.FieldLevelKey = value
+	case "field_trace_key":
+		cfg.FieldTraceKey = value
+	case "field_fields_key":
+		cfg.FieldFieldsKey = value
+	case "field_message_key":
+		cfg.FieldMessageKey = value
+	case "enrich_host":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for enrich_host '%s': %w", value, err)
+		}
+		cfg.EnrichHost = boolVal
+	case "enrich_pid":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for enrich_pid '%s': %w", value, err)
+		}
+		cfg.EnrichPID = boolVal
+	case "app_name":
+		cfg.AppName = value
+	case "app_version":
+		cfg.AppVersion = value
+	case "console_sanitization":
+		cfg.ConsoleSanitization = sanitizer.PolicyPreset(value)
+	case "file_sanitization":
+		cfg.FileSanitization = sanitizer.PolicyPreset(value)
+	case "network_sanitization":
+		cfg.NetworkSanitization = sanitizer.PolicyPreset(value)
+	case "sanitization_by_format":
+		policies, err := parseSanitizationByFormat(value)
+		if err != nil {
+			return err
+		}
+		cfg.SanitizationByFormat = policies
+	case "eager_stringify":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for eager_stringify '%s': %w", value, err)
+		}
+		cfg.EagerStringify = boolVal
+	case "auto_kv":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for auto_kv '%s': %w", value, err)
+		}
+		cfg.AutoKV = boolVal
+	case "json_fields":
+		cfg.JSONFields = value
+	case "nested_keys":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for nested_keys '%s': %w", value, err)
+		}
+		cfg.NestedKeys = boolVal
+	case "reserved_key_policy":
+		cfg.ReservedKeyPolicy = value
+	case "bytes_encoding":
+		cfg.BytesEncoding = value
+	case "duration_format":
+		cfg.DurationFormat = value
+	case "time_value_format":
+		cfg.TimeValueFormat = value
+	case "csv_columns":
+		cfg.CSVColumns = value
+	case "txt_layout":
+		cfg.TxtLayout = value
+
+	// CEF records
+	case "cef_vendor":
+		cfg.CEFVendor = value
+	case "cef_product":
+		cfg.CEFProduct = value
+	case "cef_product_version":
+		cfg.CEFProductVersion = value
+	case "cef_extension_map":
+		keys, err := parseCEFExtensionMap(value)
+		if err != nil {
+			return err
+		}
+		cfg.CEFExtensionMap = keys
+
+	// GCP records
+	case "gcp_project":
+		cfg.GCPProject = value
 
 	// Buffer and size limits
 	case "buffer_size":
@@ -236,6 +849,26 @@ func applyConfigField(cfg *Config, key, value string) error {
 			return fmtErrorf("invalid integer value for buffer_size '%s': %w", value, err)
 		}
 		cfg.BufferSize = intVal
+	case "recent_records":
+		intVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmtErrorf("invalid integer value for recent_records '%s': %w", value, err)
+		}
+		cfg.RecentRecords = intVal
+
+	// Call path latency
+	case "call_latency_stats":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for call_latency_stats '%s': %w", value, err)
+		}
+		cfg.CallLatencyStats = boolVal
+	case "call_latency_budget_us":
+		intVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmtErrorf("invalid integer value for call_latency_budget_us '%s': %w", value, err)
+		}
+		cfg.CallLatencyBudgetUs = intVal
 	case "max_size_kb":
 		intVal, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
@@ -254,6 +887,36 @@ func applyConfigField(cfg *Config, key, value string) error {
 			return fmtErrorf("invalid integer value for min_disk_free_kb '%s': %w", value, err)
 		}
 		cfg.MinDiskFreeKB = intVal
+	case "on_disk_full":
+		cfg.OnDiskFull = value
+	case "shards":
+		intVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmtErrorf("invalid integer value for shards '%s': %w", value, err)
+		}
+		cfg.Shards = intVal
+	case "split_error_file":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for split_error_file '%s': %w", value, err)
+		}
+		cfg.SplitErrorFile = boolVal
+
+	// Record field limits
+	case "max_fields_per_record":
+		intVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmtErrorf("invalid integer value for max_fields_per_record '%s': %w", value, err)
+		}
+		cfg.MaxFieldsPerRecord = intVal
+	case "max_field_key_len":
+		intVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmtErrorf("invalid integer value for max_field_key_len '%s': %w", value, err)
+		}
+		cfg.MaxFieldKeyLen = intVal
+	case "field_limit_policy":
+		cfg.FieldLimitPolicy = value
 
 	// Timers
 	case "flush_interval_ms":
@@ -280,6 +943,12 @@ func applyConfigField(cfg *Config, key, value string) error {
 			return fmtErrorf("invalid float value for retention_check_mins '%s': %w", value, err)
 		}
 		cfg.RetentionCheckMins = floatVal
+	case "retention_dry_run":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for retention_dry_run '%s': %w", value, err)
+		}
+		cfg.RetentionDryRun = boolVal
 
 	// Disk check settings
 	case "disk_check_interval_ms":
@@ -312,6 +981,12 @@ func applyConfigField(cfg *Config, key, value string) error {
 			return fmtErrorf("invalid integer value for max_check_interval_ms '%s': %w", value, err)
 		}
 		cfg.MaxCheckIntervalMs = intVal
+	case "size_reconcile_interval_s":
+		intVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmtErrorf("invalid integer value for size_reconcile_interval_s '%s': %w", value, err)
+		}
+		cfg.SizeReconcileIntervalS = intVal
 
 	// Heartbeat configuration
 	case "heartbeat_level":
@@ -326,6 +1001,14 @@ func applyConfigField(cfg *Config, key, value string) error {
 			return fmtErrorf("invalid integer value for heartbeat_interval_s '%s': %w", value, err)
 		}
 		cfg.HeartbeatIntervalS = intVal
+	case "heartbeat_incident_interval_s":
+		intVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmtErrorf("invalid integer value for heartbeat_incident_interval_s '%s': %w", value, err)
+		}
+		cfg.HeartbeatIncidentIntervalS = intVal
+	case "heartbeat_format":
+		cfg.HeartbeatFormat = value
 
 	// Console output settings
 	case "enable_console":
@@ -336,6 +1019,26 @@ func applyConfigField(cfg *Config, key, value string) error {
 		cfg.EnableConsole = boolVal
 	case "console_target":
 		cfg.ConsoleTarget = value
+	case "console_glyphs":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for console_glyphs '%s': %w", value, err)
+		}
+		cfg.ConsoleGlyphs = boolVal
+	case "console_file_drops":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for console_file_drops '%s': %w", value, err)
+		}
+		cfg.ConsoleFileDrops = boolVal
+	case "console_color":
+		cfg.ConsoleColor = value
+	case "console_color_line":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for console_color_line '%s': %w", value, err)
+		}
+		cfg.ConsoleColorLine = boolVal
 	case "enable_file":
 		boolVal, err := strconv.ParseBool(value)
 		if err != nil {
@@ -343,6 +1046,102 @@ func applyConfigField(cfg *Config, key, value string) error {
 		}
 		cfg.EnableFile = boolVal
 
+	// Syslog output
+	case "enable_syslog":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for enable_syslog '%s': %w", value, err)
+		}
+		cfg.EnableSyslog = boolVal
+	case "syslog_network":
+		cfg.SyslogNetwork = value
+	case "syslog_address":
+		cfg.SyslogAddress = value
+	case "syslog_format":
+		cfg.SyslogFormat = value
+	case "syslog_facility":
+		cfg.SyslogFacility = value
+	case "syslog_tag":
+		cfg.SyslogTag = value
+	case "syslog_sd_id":
+		cfg.SyslogSDID = value
+
+	// GELF output
+	case "enable_gelf":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for enable_gelf '%s': %w", value, err)
+		}
+		cfg.EnableGELF = boolVal
+	case "gelf_network":
+		cfg.GELFNetwork = value
+	case "gelf_address":
+		cfg.GELFAddress = value
+	case "gelf_host":
+		cfg.GELFHost = value
+
+	// Journald output
+	case "enable_journal":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for enable_journal '%s': %w", value, err)
+		}
+		cfg.EnableJournal = boolVal
+	case "journal_socket":
+		cfg.JournalSocket = value
+
+	// S3 archive upload
+	case "s3_upload":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for s3_upload '%s': %w", value, err)
+		}
+		cfg.S3Upload = boolVal
+	case "s3_endpoint":
+		cfg.S3Endpoint = value
+	case "s3_bucket":
+		cfg.S3Bucket = value
+	case "s3_region":
+		cfg.S3Region = value
+	case "s3_prefix":
+		cfg.S3Prefix = value
+	case "s3_keep_local":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for s3_keep_local '%s': %w", value, err)
+		}
+		cfg.S3KeepLocal = boolVal
+
+	// Process exit
+	case "flush_on_exit":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for flush_on_exit '%s': %w", value, err)
+		}
+		cfg.FlushOnExit = boolVal
+	case "flush_on_exit_reraise":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for flush_on_exit_reraise '%s': %w", value, err)
+		}
+		cfg.FlushOnExitReraise = boolVal
+
+	// Exec hook
+	case "on_error_exec":
+		cfg.OnErrorExec = value
+	case "on_error_exec_level":
+		levelVal, err := parseLevelValue(value)
+		if err != nil {
+			return fmtErrorf("invalid on_error_exec_level value '%s': %w", value, err)
+		}
+		cfg.OnErrorExecLevel = levelVal
+	case "on_error_exec_interval_s":
+		intVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmtErrorf("invalid integer value for on_error_exec_interval_s '%s': %w", value, err)
+		}
+		cfg.OnErrorExecIntervalS = intVal
+
 	// Internal error handling
 	case "internal_errors_to_stderr":
 		boolVal, err := strconv.ParseBool(value)
@@ -350,6 +1149,18 @@ func applyConfigField(cfg *Config, key, value string) error {
 			return fmtErrorf("invalid boolean value for internal_errors_to_stderr '%s': %w", value, err)
 		}
 		cfg.InternalErrorsToStderr = boolVal
+	case "internal_errors_to_log":
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmtErrorf("invalid boolean value for internal_errors_to_log '%s': %w", value, err)
+		}
+		cfg.InternalErrorsToLog = boolVal
+	case "internal_errors_log_interval_s":
+		intVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmtErrorf("invalid integer value for internal_errors_log_interval_s '%s': %w", value, err)
+		}
+		cfg.InternalErrorsLogIntervalS = intVal
 
 	default:
 		return fmtErrorf("unknown configuration key '%s'", key)
@@ -358,6 +1169,108 @@ func applyConfigField(cfg *Config, key, value string) error {
 	return nil
 }
 
+// fileOutput reports whether the logger writes its own log file
+// Sharded loggers delegate file output to their shard loggers
+func (c *Config) fileOutput() bool {
+	return c.EnableFile && c.Shards <= 1 && !c.ReadOnly
+}
+
+// consoleFormat returns the format of console output
+func (c *Config) consoleFormat() string {
+	if c.ConsoleFormat != "" {
+		return c.ConsoleFormat
+	}
+	return c.Format
+}
+
+// fileFormat returns the format of file output and registered sinks, format=pretty writes txt
+func (c *Config) fileFormat() string {
+	if c.FileFormat != "" {
+		return c.FileFormat
+	}
+	return c.bodyFormat()
+}
+
+// jsonKeys returns the keys json records write their own members under
+func (c *Config) jsonKeys() formatter.JSONKeyNames {
+	return formatter.JSONKeyNames{
+		Time:    c.FieldTimeKey,
+		Level:   c.FieldLevelKey,
+		Trace:   c.FieldTraceKey,
+		Fields:  c.FieldFieldsKey,
+		Message: c.FieldMessageKey,
+	}
+}
+
+// sanitization returns the policy of an output writing format, override taking precedence over
+// sanitization_by_format and sanitization
+func (c *Config) sanitization(override sanitizer.PolicyPreset, format string) sanitizer.PolicyPreset {
+	if override != "" {
+		return override
+	}
+	if policy, ok := c.SanitizationByFormat[format]; ok {
+		return sanitizer.PolicyPreset(policy)
+	}
+	return c.Sanitization
+}
+
+// validSanitization reports whether policy is a sanitization preset
+func validSanitization(policy sanitizer.PolicyPreset) bool {
+	switch policy {
+	case PolicyRaw, PolicyJSON, PolicyTxt, PolicyShell:
+		return true
+	}
+	return false
+}
+
+// parseSanitizationByFormat parses "format=policy,..." into a map, empty clears it
+func parseSanitizationByFormat(value string) (map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	policies := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		format, policy, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmtErrorf("invalid sanitization_by_format entry '%s' (use format=policy)", entry)
+		}
+		policies[strings.TrimSpace(format)] = strings.TrimSpace(policy)
+	}
+	return policies, nil
+}
+
+// bodyFormat returns format for outputs that need machine-readable text, pretty falls back to txt
+func (c *Config) bodyFormat() string {
+	if c.Format == "pretty" {
+		return "txt"
+	}
+	return c.Format
+}
+
+// csvColumns returns the column names listed in csv_columns, with surrounding spaces removed
+func (c *Config) csvColumns() []string {
+	if strings.TrimSpace(c.CSVColumns) == "" {
+		return nil
+	}
+	columns := strings.Split(c.CSVColumns, ",")
+	for i, column := range columns {
+		columns[i] = strings.TrimSpace(column)
+	}
+	return columns
+}
+
+// fileExtension returns the extension of log files on disk, with ".gz" appended when gzip_active is enabled
+func (c *Config) fileExtension() string {
+	if !c.GzipActive {
+		return c.Extension
+	}
+	if c.Extension == "" {
+		return "gz"
+	}
+	return c.Extension + ".gz"
+}
+
 // configRequiresRestart checks if config changes require processor restart
 func configRequiresRestart(oldCfg, newCfg *Config) bool {
 	// Channel size change requires restart
@@ -373,7 +1286,8 @@ func configRequiresRestart(oldCfg, newCfg *Config) bool {
 	// Directory or file naming changes require restart
 	if oldCfg.Directory != newCfg.Directory ||
 		oldCfg.Name != newCfg.Name ||
-		oldCfg.Extension != newCfg.Extension {
+		oldCfg.Extension != newCfg.Extension ||
+		oldCfg.GzipActive != newCfg.GzipActive {
 		return true
 	}
 
@@ -382,7 +1296,9 @@ func configRequiresRestart(oldCfg, newCfg *Config) bool {
 		oldCfg.DiskCheckIntervalMs != newCfg.DiskCheckIntervalMs ||
 		oldCfg.EnableAdaptiveInterval != newCfg.EnableAdaptiveInterval ||
 		oldCfg.HeartbeatIntervalS != newCfg.HeartbeatIntervalS ||
+		oldCfg.HeartbeatIncidentIntervalS != newCfg.HeartbeatIncidentIntervalS ||
 		oldCfg.HeartbeatLevel != newCfg.HeartbeatLevel ||
+		oldCfg.SuppressionSummaryIntervalS != newCfg.SuppressionSummaryIntervalS ||
 		oldCfg.RetentionCheckMins != newCfg.RetentionCheckMins ||
 		oldCfg.RetentionPeriodHrs != newCfg.RetentionPeriodHrs {
 		return true
@@ -0,0 +1,172 @@
+package log
+
+import (
+	"bytes"
+	"os"
+)
+
+// ANSI escape sequences used for console coloring
+const (
+	ansiReset   = "\x1b[0m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// levelColor returns the ANSI color sequence associated with a log level
+func levelColor(level int64) string {
+	switch {
+	case level >= LevelProc:
+		return ansiMagenta
+	case level >= LevelError:
+		return ansiRed
+	case level >= LevelWarn:
+		return ansiYellow
+	case level >= LevelInfo:
+		return ansiGreen
+	default:
+		return ansiCyan
+	}
+}
+
+// levelGlyph returns the compact console glyph for a log level
+func levelGlyph(level int64) string {
+	switch {
+	case level >= LevelProc:
+		return "♥"
+	case level >= LevelError:
+		return "✗"
+	case level >= LevelWarn:
+		return "⚠"
+	case level >= LevelInfo:
+		return "✓"
+	default:
+		return "•"
+	}
+}
+
+// appendColored appends s wrapped in color, or plain when color is empty
+func appendColored(buf []byte, color string, s string) []byte {
+	if color == "" {
+		return append(buf, s...)
+	}
+	buf = append(buf, color...)
+	buf = append(buf, s...)
+	return append(buf, ansiReset...)
+}
+
+// appendLevelGlyph prepends a glyph in color and a space to formatted console data
+func appendLevelGlyph(buf []byte, level int64, color string, data []byte) []byte {
+	buf = appendColored(buf, color, levelGlyph(level))
+	buf = append(buf, ' ')
+	return append(buf, data...)
+}
+
+// appendColorLine appends formatted console data colored up to its trailing newline
+func appendColorLine(buf []byte, color string, data []byte) []byte {
+	line, found := bytes.CutSuffix(data, []byte{'\n'})
+	buf = append(buf, color...)
+	buf = append(buf, line...)
+	buf = append(buf, ansiReset...)
+	if found {
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+// colorEnabled resolves console_color for a stream: "auto" colors terminals unless NO_COLOR is set or TERM is dumb
+func colorEnabled(mode string, stream *os.File) bool {
+	switch mode {
+	case "always":
+		return true
+	case "auto":
+		return os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && isTerminal(stream)
+	default:
+		return false
+	}
+}
+
+// newConsoleSink creates the console output for cfg, resolving console_color for its streams
+func newConsoleSink(l *Logger, cfg *Config) *consoleSink {
+	s := &consoleSink{l: l, target: cfg.ConsoleTarget, colorLine: cfg.ConsoleColorLine}
+	if cfg.ConsoleTarget != "stderr" {
+		s.colorStdout = colorEnabled(cfg.ConsoleColor, os.Stdout)
+	}
+	if cfg.ConsoleTarget != "stdout" {
+		s.colorStderr = colorEnabled(cfg.ConsoleColor, os.Stderr)
+	}
+	return s
+}
+
+// color returns the color of records at level on their stream, "" when the stream is not colored
+func (s *consoleSink) color(level int64) string {
+	colored := s.colorStdout
+	if s.writesStderr(level) {
+		colored = s.colorStderr
+	}
+	if !colored {
+		return ""
+	}
+	return levelColor(level)
+}
+
+// colorsLevel reports whether the console formatter colors the level token, only txt and pretty leave room for it
+func (s *consoleSink) colorsLevel(cfg *Config) bool {
+	format := cfg.consoleFormat()
+	return (s.colorStdout || s.colorStderr) && !s.colorLine && !cfg.ConsoleGlyphs && (format == "txt" || format == "pretty")
+}
+
+// fileDropTag marks console records whose file copy was dropped, see console_file_drops
+const fileDropTag = "file-drop"
+
+// appendConsoleTag prepends a "[tag] " prefix in color to formatted console data
+func appendConsoleTag(buf []byte, tag string, color string, data []byte) []byte {
+	buf = appendColored(buf, color, "["+tag+"]")
+	buf = append(buf, ' ')
+	return append(buf, data...)
+}
+
+// writesStderr reports whether records at level are written to stderr by the console target
+func (s *consoleSink) writesStderr(level int64) bool {
+	return s.target == "stderr" || (s.target == "split" && level >= LevelWarn)
+}
+
+// Write writes a formatted record to the console target, split mode sends WARN and above to stderr
+func (s *consoleSink) Write(data []byte, record Record) error {
+	w := s.l.state.StdoutWriter.Load()
+	if w == nil {
+		return nil
+	}
+	sinkWrapper, ok := w.(*sink)
+	if !ok || sinkWrapper == nil {
+		return nil
+	}
+
+	// Component tags are console-only decoration, file output is never tagged
+	if record.consoleTag != "" {
+		data = appendConsoleTag(make([]byte, 0, len(data)+len(record.consoleTag)+16), record.consoleTag, s.color(record.Level), data)
+	}
+
+	// Handle split mode
+	var err error
+	if s.target == "split" && record.Level >= LevelWarn {
+		// Write WARN and ERROR to stderr
+		_, err = os.Stderr.Write(data)
+	} else {
+		// Write to the configured target, stdout for INFO and DEBUG in split mode
+		_, err = sinkWrapper.w.Write(data)
+	}
+	return err
+}
+
+// Flush is a no-op, console writes are unbuffered
+func (s *consoleSink) Flush() error {
+	return nil
+}
+
+// Close is a no-op, the standard streams stay open
+func (s *consoleSink) Close() error {
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,435 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConsoleGlyphs verifies that glyphs replace level names on the console while file output keeps them
+func TestConsoleGlyphs(t *testing.T) {
+	// Redirect stdout before the logger captures its console writer
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	logger, err := NewBuilder().
+		Directory(tmpDir).
+		Format("txt").
+		EnableFile(true).
+		EnableConsole(true).
+		ConsoleTarget("stdout").
+		ConsoleColor("always").
+		ConsoleGlyphs(true).
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, logger.Start())
+	defer logger.Shutdown()
+
+	logger.Info("started")
+	logger.Warn("slow")
+	logger.Error("failed")
+	logger.Write("raw")
+	require.NoError(t, logger.Flush(time.Second))
+	w.Close()
+
+	console, err := io.ReadAll(r)
+	require.NoError(t, err)
+	consoleLines := strings.Split(strings.TrimSpace(string(console)), "\n")
+	require.Len(t, consoleLines, 4)
+
+	assert.True(t, strings.HasPrefix(consoleLines[0], ansiGreen+"✓"+ansiReset+" "))
+	assert.True(t, strings.HasPrefix(consoleLines[1], ansiYellow+"⚠"+ansiReset+" "))
+	assert.True(t, strings.HasPrefix(consoleLines[2], ansiRed+"✗"+ansiReset+" "))
+	assert.Equal(t, "raw", consoleLines[3], "Raw writes are not decorated")
+	assert.NotContains(t, string(console), "INFO")
+	assert.NotContains(t, string(console), "ERROR")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "INFO started")
+	assert.Contains(t, string(content), "ERROR failed")
+	assert.NotContains(t, string(content), "✓", "File output must not carry glyphs")
+}
+
+// TestConsoleColor verifies level tokens and whole lines are colored per console_color, leaving the file plain
+func TestConsoleColor(t *testing.T) {
+	capture := func(t *testing.T, mode string, line bool, format string) string {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		origStdout := os.Stdout
+		os.Stdout = w
+		defer func() { os.Stdout = origStdout }()
+
+		tmpDir := t.TempDir()
+		logger, err := NewBuilder().
+			Directory(tmpDir).
+			Format(format).
+			EnableFile(true).
+			EnableConsole(true).
+			ConsoleTarget("stdout").
+			ConsoleColor(mode).
+			ConsoleColorLine(line).
+			Build()
+		require.NoError(t, err)
+		require.NoError(t, logger.Start())
+		defer logger.Shutdown()
+
+		logger.Info("started")
+		logger.Error("failed")
+		logger.Write("raw")
+		require.NoError(t, logger.Flush(time.Second))
+		w.Close()
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "\x1b", "File output must not be colored")
+
+		console, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return string(console)
+	}
+
+	t.Run("level token", func(t *testing.T) {
+		console := capture(t, "always", false, "txt")
+		assert.Contains(t, console, " "+ansiGreen+"INFO"+ansiReset+" started\n")
+		assert.Contains(t, console, " "+ansiRed+"ERROR"+ansiReset+" failed\n")
+		assert.Contains(t, console, "\nraw", "Raw writes are not colored")
+	})
+
+	t.Run("whole line", func(t *testing.T) {
+		console := capture(t, "always", true, "json")
+		lines := strings.Split(strings.TrimSpace(console), "\n")
+		require.Len(t, lines, 3)
+		assert.True(t, strings.HasPrefix(lines[0], ansiGreen+"{"))
+		assert.True(t, strings.HasSuffix(lines[1], "}"+ansiReset))
+		assert.Equal(t, "raw", lines[2])
+	})
+
+	t.Run("json level token", func(t *testing.T) {
+		console := capture(t, "always", false, "json")
+		assert.NotContains(t, console, "\x1b", "Coloring the level token would break json")
+	})
+
+	t.Run("never", func(t *testing.T) {
+		assert.NotContains(t, capture(t, "never", false, "txt"), "\x1b")
+	})
+
+	t.Run("auto", func(t *testing.T) {
+		assert.NotContains(t, capture(t, "auto", false, "txt"), "\x1b", "A pipe is not a terminal")
+	})
+
+	// A regular file is never a terminal, NO_COLOR only affects auto
+	file, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	require.NoError(t, err)
+	defer file.Close()
+	assert.False(t, colorEnabled("auto", file))
+	assert.True(t, colorEnabled("always", file))
+	t.Setenv("NO_COLOR", "1")
+	assert.True(t, colorEnabled("always", file))
+
+	_, err = NewBuilder().ConsoleColor("sometimes").Build()
+	assert.Error(t, err)
+}
+
+// TestConsoleFileDrops verifies WARN and above dropped from the file still reach the console with a marker
+func TestConsoleFileDrops(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	logger, err := NewBuilder().
+		Directory(tmpDir).
+		Format("txt").
+		EnableFile(true).
+		EnableConsole(true).
+		ConsoleTarget("stdout").
+		ConsoleColor("always").
+		ConsoleFileDrops(true).
+		MinDiskFreeKB(9999999999).
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, logger.Start())
+	defer logger.Shutdown()
+	require.False(t, logger.performDiskCheck(true))
+
+	logger.Info("lost")
+	logger.Warn("slow", "disk", "full")
+	require.NoError(t, logger.Flush(time.Second))
+	w.Close()
+
+	console, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(console), ansiYellow+"[file-drop]"+ansiReset+" ")
+	assert.Contains(t, string(console), ansiYellow+"WARN"+ansiReset+" slow disk full")
+	assert.NotContains(t, string(console), "lost", "INFO records are dropped silently")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "slow")
+}
+
+// TestOnDiskFullStderr verifies on_disk_full=stderr writes records the file cannot take to stderr instead of dropping them
+func TestOnDiskFullStderr(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origStderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = origStderr }()
+
+	tmpDir := t.TempDir()
+	logger, err := NewBuilder().
+		Directory(tmpDir).
+		Format("txt").
+		EnableFile(true).
+		EnableConsole(false).
+		OnDiskFull("stderr").
+		MinDiskFreeKB(9999999999).
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, logger.Start())
+	defer logger.Shutdown()
+	require.False(t, logger.performDiskCheck(true))
+
+	logger.Info("kept")
+	logger.Error("failed", "disk", "full")
+	require.NoError(t, logger.Flush(time.Second))
+	w.Close()
+
+	output, err := io.ReadAll(r)
+	require.NoError(t, err)
+	// The pipe is not a terminal, so console_color=auto leaves the prefix plain
+	assert.Contains(t, string(output), "[file-drop] ")
+	assert.NotContains(t, string(output), ansiReset)
+	assert.Contains(t, string(output), "INFO kept")
+	assert.Contains(t, string(output), "ERROR failed disk full")
+	assert.Contains(t, string(output), "Log directory full")
+
+	// The disk full error reported by the check takes the same path
+	stats := logger.Stats()
+	assert.Equal(t, uint64(3), stats.StderrFallback)
+	assert.Zero(t, stats.DroppedLogs)
+}
+
+// TestOnDiskFullBlock verifies on_disk_full=block holds records until disk limits are met and drops them on shutdown
+func TestOnDiskFullBlock(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	cfg := logger.GetConfig()
+	cfg.OnDiskFull = "block"
+	require.NoError(t, logger.ApplyConfig(cfg))
+
+	// The next disk check passes, the held record is written instead of dropped
+	logger.state.DiskStatusOK.Store(false)
+	logger.Info("held")
+	require.NoError(t, logger.Flush(time.Second))
+	assert.True(t, logger.state.DiskStatusOK.Load())
+	assert.Zero(t, logger.Stats().DroppedLogs)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "held")
+
+	// Limits that are never met hold the processor until shutdown
+	cfg.MinDiskFreeKB = 9999999999
+	require.NoError(t, logger.ApplyConfig(cfg))
+	require.False(t, logger.lockedDiskCheck(true))
+	logger.Info("lost")
+	time.Sleep(50 * time.Millisecond)
+	assert.Error(t, logger.Flush(100*time.Millisecond))
+
+	// A flush waiting behind the held record is answered when the logger shuts down
+	flushed := make(chan error, 1)
+	go func() { flushed <- logger.Flush(10 * time.Second) }()
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, logger.Shutdown(time.Second))
+	assert.Less(t, time.Since(start), time.Second)
+	select {
+	case err := <-flushed:
+		assert.ErrorIs(t, err, errFlushStopped)
+	case <-time.After(time.Second):
+		t.Fatal("Flush not answered on shutdown")
+	}
+	// The held record and the disk full error reported by the check
+	assert.Equal(t, uint64(2), logger.Stats().DroppedLogs)
+
+	_, err = NewBuilder().OnDiskFull("wait").Build()
+	assert.Error(t, err)
+}
+
+// TestOnDiskFullBlockApplyConfig verifies a held record leaves the logger reconfigurable, so limits can be relaxed
+func TestOnDiskFullBlockApplyConfig(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	cfg := logger.GetConfig()
+	cfg.OnDiskFull = "block"
+	cfg.MinDiskFreeKB = 9999999999
+	require.NoError(t, logger.ApplyConfig(cfg))
+	require.False(t, logger.lockedDiskCheck(true))
+
+	logger.Info("held")
+	logger.Info("behind")
+	time.Sleep(50 * time.Millisecond)
+	assert.Error(t, logger.Flush(100*time.Millisecond))
+
+	// ApplyConfig does not wait for the held record
+	done := make(chan error, 1)
+	go func() {
+		cfg.MinDiskFreeKB = 0
+		done <- logger.ApplyConfig(cfg)
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("ApplyConfig blocked by the held record")
+	}
+
+	// The next retry passes the disk check and writes both records in order
+	require.NoError(t, logger.Flush(time.Second))
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	held := strings.Index(string(content), "held")
+	require.GreaterOrEqual(t, held, 0)
+	assert.Greater(t, strings.Index(string(content), "behind"), held)
+}
+
+// TestOutputLevels verifies console_level and file_level filter each output independently
+func TestOutputLevels(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	logger, err := NewBuilder().
+		Directory(tmpDir).
+		Format("txt").
+		LevelString("debug").
+		EnableFile(true).
+		EnableConsole(true).
+		ConsoleTarget("stdout").
+		ConsoleLevel(LevelWarn).
+		FileLevel(LevelInfo).
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, logger.Start())
+	defer logger.Shutdown()
+
+	logger.Debug("debug record")
+	logger.Info("info record")
+	logger.Warn("warn record")
+	require.NoError(t, logger.Flush(time.Second))
+	w.Close()
+
+	console, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.NotContains(t, string(console), "info record")
+	assert.Contains(t, string(console), "warn record")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "debug record")
+	assert.Contains(t, string(content), "info record")
+	assert.Contains(t, string(content), "warn record")
+
+	require.NoError(t, logger.ApplyConfigString("file_level=error"))
+	assert.Equal(t, LevelError, logger.GetConfig().FileLevel)
+	assert.Error(t, logger.ApplyConfigString("console_level=loud"))
+}
+
+// TestOutputFormats verifies console_format and file_format override format per output
+func TestOutputFormats(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	logger, err := NewBuilder().
+		Directory(tmpDir).
+		Format("raw").
+		ConsoleFormat("txt").
+		FileFormat("json").
+		EnableFile(true).
+		EnableConsole(true).
+		ConsoleTarget("stdout").
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, logger.Start())
+	defer logger.Shutdown()
+
+	logger.Info("formatted", "key", "value")
+	require.NoError(t, logger.Flush(time.Second))
+	w.Close()
+
+	console, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(console), "INFO formatted")
+	assert.NotContains(t, string(console), "{")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(content, &entry))
+	assert.Equal(t, "INFO", entry["level"])
+
+	assert.Error(t, logger.ApplyConfigString("console_format=yaml"))
+	require.NoError(t, logger.ApplyConfigString("file_format="))
+	assert.Equal(t, "", logger.GetConfig().FileFormat)
+}
+
+// TestPrettyFormat verifies format=pretty renders the console for people while the file is written as txt
+func TestPrettyFormat(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+
+	tmpDir := t.TempDir()
+	logger, err := NewBuilder().
+		Directory(tmpDir).
+		Format("pretty").
+		EnableFile(true).
+		EnableConsole(true).
+		ConsoleTarget("stdout").
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, logger.Start())
+	defer logger.Shutdown()
+
+	logger.With("request_id", "r1").Warn("slow query", "sql", "SELECT 1\nFROM t")
+	require.NoError(t, logger.Flush(time.Second))
+	w.Close()
+
+	console, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(console), " WARN  slow query\n    request_id=r1\n    sql=SELECT 1\n        FROM t\n")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `WARN "slow query" request_id r1 sql`, "The file keeps the txt format")
+
+	assert.Error(t, logger.ApplyConfigString("file_format=pretty"))
+	require.NoError(t, logger.ApplyConfigString("format=json", "console_format=pretty"))
+}
\ No newline at end of file
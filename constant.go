@@ -28,6 +28,7 @@ const (
 	FlagShowTimestamp  = formatter.FlagShowTimestamp
 	FlagShowLevel      = formatter.FlagShowLevel
 	FlagStructuredJSON = formatter.FlagStructuredJSON
+	FlagNestedKeys     = formatter.FlagNestedKeys // Set on records of loggers derived with WithGroup
 	FlagDefault        = formatter.FlagDefault
 )
 
@@ -45,6 +46,32 @@ const (
 	reactiveCheckThresholdBytes int64 = 10 * 1024 * 1024
 	// Size multiplier for KB, MB
 	sizeMultiplier = 1000
+	// Upper bound for Config.Shards
+	maxShards = 256
+	// Version of the metadata header written to new log files
+	fileHeaderSchemaVersion = 1
+	// Extra age required beyond the retention period before a file is deleted
+	retentionClockMargin = time.Minute
+	// Rotation journal size that triggers trimming to its newest half
+	rotationJournalMaxBytes = 256 * 1024
+	// Minimum width of the zero-padded rotation generation in archive names
+	archiveGenerationDigits = 8
+	// Files modified this long before the logger started are assumed to carry a broken timestamp
+	retentionSanityBound = 10 * 365 * 24 * time.Hour
+)
+
+// Processing
+const (
+	// Maximum number of queued records written under a single configuration snapshot
+	maxBatchSize = 256
+	// Consecutive errors after which an output is reported as failed instead of degraded
+	sinkFailedThreshold = 5
+	// Power-of-two record size buckets, the last one holds every record above 1 GiB
+	recordSizeBuckets = 32
+	// Leading bytes of the largest record's message kept for size statistics
+	recordSizeMessageLen = 64
+	// Power-of-two call duration buckets in nanoseconds, the last one holds every call above 1s
+	callLatencyBuckets = 31
 )
 
 // Timers
@@ -54,4 +81,94 @@ const (
 	// Factors to adjust check interval
 	adaptiveIntervalFactor float64 = 1.5 // Slow down
 	adaptiveSpeedUpFactor  float64 = 0.8 // Speed up
+	// Dial and write timeout of the syslog output
+	syslogTimeout = 2 * time.Second
+	// Wait after a failed syslog dial before the next attempt, records in between are not sent
+	syslogRetryInterval = 5 * time.Second
+	// Time allowed for flushing loggers with flush_on_exit when an exit signal arrives
+	exitFlushTimeout = 2 * time.Second
+)
+
+// Self-test
+const (
+	// Pattern of the temporary directory SelfTest creates inside the log directory
+	selfTestDirPattern = ".selftest-*"
+	// Flush and shutdown timeout of the self-test logger
+	selfTestTimeout = 5 * time.Second
+)
+
+// HTTP sink defaults
+const (
+	httpSinkBatchSize     = 100
+	httpSinkBatchBytes    = 1 << 20
+	httpSinkFlushInterval = time.Second
+	httpSinkQueueSize     = 10000
+	httpSinkMaxRetries    = 3
+	httpSinkRetryBackoff  = 500 * time.Millisecond
+	httpSinkTimeout       = 10 * time.Second
+)
+
+// Elasticsearch sink defaults
+const (
+	elasticsearchSinkIndex         = "logs-{name}-{yyyy}.{mm}.{dd}"
+	elasticsearchSinkName          = "log"
+	elasticsearchSinkBatchSize     = 500
+	elasticsearchSinkBatchBytes    = 5 << 20
+	elasticsearchSinkFlushInterval = time.Second
+	elasticsearchSinkQueueSize     = 10000
+	elasticsearchSinkMaxRetries    = 3
+	elasticsearchSinkRetryBackoff  = 500 * time.Millisecond
+	elasticsearchSinkTimeout       = 10 * time.Second
+)
+
+// NATS sink defaults
+const (
+	natsSinkBatchSize     = 100
+	natsSinkFlushInterval = time.Second
+	natsSinkQueueSize     = 10000
+	natsSinkMaxRetries    = 3
+	natsSinkRetryBackoff  = 500 * time.Millisecond
+	natsSinkTimeout       = 10 * time.Second
+)
+
+// Fluent sink defaults
+const (
+	fluentSinkTag           = "log"
+	fluentSinkBatchSize     = 100
+	fluentSinkFlushInterval = time.Second
+	fluentSinkQueueSize     = 10000
+	fluentSinkMaxRetries    = 3
+	fluentSinkRetryBackoff  = 500 * time.Millisecond
+	fluentSinkTimeout       = 10 * time.Second
+)
+
+// S3 archive upload
+const (
+	// Archives waiting for upload, further rotations keep their archive local
+	s3UploadQueueSize = 64
+	// Retries of a failed upload before the archive is kept local
+	s3UploadMaxRetries = 5
+	// Delay before the first retry, doubled for each further retry up to s3UploadMaxBackoff
+	s3UploadRetryBackoff = time.Second
+	s3UploadMaxBackoff   = time.Minute
+	// Timeout of each upload request
+	s3UploadTimeout = 5 * time.Minute
+)
+
+// Journald output
+const (
+	// Native protocol socket of systemd-journald
+	journaldSocketPath = "/run/systemd/journal/socket"
+	// Longest field name journald accepts
+	journaldMaxFieldName = 64
+)
+
+// GELF output
+const (
+	// Largest datagram sent to a GELF UDP input, larger messages are chunked; fits a typical path MTU
+	gelfChunkSize = 1420
+	// Bytes of magic, message ID, sequence number, and sequence count preceding each chunk
+	gelfChunkHeaderSize = 12
+	// Most chunks a GELF message may be split into
+	gelfMaxChunks = 128
 )
\ No newline at end of file
@@ -0,0 +1,93 @@
+package log
+
+import (
+	"context"
+)
+
+// contextKey carries a *Logger in a context.Context
+type contextKey struct{}
+
+// discardLogger is returned by FromContext when no logger is attached, it is never started and drops every record
+var discardLogger = NewLogger()
+
+// NewContext returns a copy of ctx carrying the logger, e.g. a request-scoped logger with bound fields
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or a logger discarding every record if there is none
+// The result is never nil, so handlers can log without checking
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok && l != nil {
+		return l
+	}
+	return discardLogger
+}
+
+// contextFieldsKey carries the key-value pairs added by WithContextFields
+type contextFieldsKey struct{}
+
+// contextTraceKey carries the trace and span IDs added by WithTraceContext
+type contextTraceKey struct{}
+
+// contextTrace holds the IDs of the span a context belongs to
+type contextTrace struct {
+	traceID string
+	spanID  string
+}
+
+// WithContextFields returns a copy of ctx carrying the key-value pairs after those it already carries
+// The *Context logging methods and the compat/slog handler add them to every record
+func WithContextFields(ctx context.Context, fields ...any) context.Context {
+	current, _ := ctx.Value(contextFieldsKey{}).([]any)
+	merged := make([]any, 0, len(current)+len(fields))
+	merged = append(merged, current...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// WithTraceContext returns a copy of ctx carrying trace and span IDs, written as trace_id and span_id by the
+// *Context logging methods; empty IDs are left out
+func WithTraceContext(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, contextTraceKey{}, contextTrace{traceID: traceID, spanID: spanID})
+}
+
+// ContextFields returns the key-value pairs carried by ctx: trace_id and span_id, then the fields added with
+// WithContextFields. Returns nil when there are none
+func ContextFields(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+	trace, _ := ctx.Value(contextTraceKey{}).(contextTrace)
+	fields, _ := ctx.Value(contextFieldsKey{}).([]any)
+	if trace.traceID == "" && trace.spanID == "" && len(fields) == 0 {
+		return nil
+	}
+
+	pairs := make([]any, 0, 4+len(fields))
+	if trace.traceID != "" {
+		pairs = append(pairs, "trace_id", trace.traceID)
+	}
+	if trace.spanID != "" {
+		pairs = append(pairs, "span_id", trace.spanID)
+	}
+	return append(pairs, fields...)
+}
+
+// withContext returns a logger adding the fields carried by ctx after its bound fields, or l when there are none
+// Keys already bound to the logger keep their bound value; context fields are not qualified by the logger's group
+func (l *Logger) withContext(ctx context.Context) *Logger {
+	fields := ContextFields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	bound := make([]any, 0, len(l.fields)+len(fields))
+	bound = append(bound, l.fields...)
+	for i := 0; i+1 < len(fields); i += 2 {
+		if key, ok := fields[i].(string); ok && hasArgKey(l.fields, key) {
+			continue
+		}
+		bound = append(bound, fields[i], fields[i+1])
+	}
+	return &Logger{loggerCore: l.loggerCore, fields: bound, labels: l.labels, group: l.group}
+}
\ No newline at end of file
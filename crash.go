@@ -0,0 +1,92 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+)
+
+// crashHandler tracks the process-wide crash output installed by a logger
+// debug.SetCrashOutput is global, so at most one logger owns it at a time
+var crashHandler struct {
+	mu    sync.Mutex
+	owner *loggerCore
+	file  *os.File
+}
+
+// crashFilePath returns the crash log path, {directory}/{name}.crash, which is never matched as a log archive
+func crashFilePath(c *Config) string {
+	return filepath.Join(c.Directory, c.Name+".crash")
+}
+
+// InstallCrashHandler redirects the runtime's output for unrecovered panics and fatal errors into a crash
+// file in the logger's directory, so post-mortem stack traces live next to the application logs
+// Output is appended to {directory}/{name}.crash in addition to stderr. The handler is process-wide: installing
+// it replaces one installed by another logger. It outlives Shutdown, which a deferred call runs while a panic unwinds,
+// before the runtime prints it; RemoveCrashHandler uninstalls it
+// Returns the crash file path
+func (l *Logger) InstallCrashHandler() (string, error) {
+	c := l.getConfig()
+	if err := checkLogDirectory(c.Directory); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(c.Directory, 0755); err != nil {
+		return "", fmtErrorf("failed to create log directory '%s': %w", c.Directory, err)
+	}
+
+	path := crashFilePath(c)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", fmtErrorf("failed to open crash file '%s': %w", path, err)
+	}
+
+	crashHandler.mu.Lock()
+	defer crashHandler.mu.Unlock()
+
+	if err := debug.SetCrashOutput(file, debug.CrashOptions{}); err != nil {
+		_ = file.Close()
+		return "", fmtErrorf("failed to set crash output: %w", err)
+	}
+
+	// The runtime holds its own duplicate of the descriptor, the replaced file is released
+	if crashHandler.file != nil {
+		if crashHandler.file.Name() == path {
+			_ = crashHandler.file.Close()
+		} else {
+			closeCrashFile(crashHandler.file)
+		}
+	}
+	crashHandler.owner = l.loggerCore
+	crashHandler.file = file
+	return path, nil
+}
+
+// RemoveCrashHandler restores the default crash output if this logger installed the current handler, deleting the
+// crash file if nothing was written. Deferred calls run while a panic unwinds, so call it once the work is done
+func (l *Logger) RemoveCrashHandler() error {
+	crashHandler.mu.Lock()
+	defer crashHandler.mu.Unlock()
+
+	if crashHandler.owner != l.loggerCore {
+		return nil
+	}
+
+	err := debug.SetCrashOutput(nil, debug.CrashOptions{})
+	closeCrashFile(crashHandler.file)
+	crashHandler.owner = nil
+	crashHandler.file = nil
+	if err != nil {
+		return fmtErrorf("failed to reset crash output: %w", err)
+	}
+	return nil
+}
+
+// closeCrashFile closes a crash file and removes it if no crash was ever recorded in it
+func closeCrashFile(file *os.File) {
+	info, err := file.Stat()
+	_ = file.Close()
+	if err == nil && info.Size() == 0 {
+		_ = os.Remove(file.Name())
+	}
+}
\ No newline at end of file
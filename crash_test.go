@@ -0,0 +1,62 @@
+package log
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInstallCrashHandler verifies that the handler survives Shutdown and an unused crash file is removed with it
+func TestInstallCrashHandler(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+
+	path, err := logger.InstallCrashHandler()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmpDir, "log.crash"), path)
+	assert.FileExists(t, path)
+
+	// Reinstalling keeps the same file
+	_, err = logger.InstallCrashHandler()
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+
+	require.NoError(t, logger.Shutdown())
+	assert.FileExists(t, path, "Shutdown keeps the handler")
+
+	require.NoError(t, logger.RemoveCrashHandler())
+	assert.NoFileExists(t, path, "Empty crash file should be removed")
+	require.NoError(t, logger.RemoveCrashHandler(), "Removing twice is a no-op")
+}
+
+// TestCrashHandlerPanic verifies that an unrecovered panic is written to the crash file
+// The test re-executes itself so the panic terminates a child process
+func TestCrashHandlerPanic(t *testing.T) {
+	if dir := os.Getenv("LOG_CRASH_TEST_DIR"); dir != "" {
+		logger := NewLogger()
+		cfg := DefaultConfig()
+		cfg.Directory = dir
+		cfg.EnableConsole = false
+		require.NoError(t, logger.ApplyConfig(cfg))
+		require.NoError(t, logger.Start())
+		// A deferred Shutdown runs while the panic unwinds, before the runtime writes the crash
+		defer logger.Shutdown()
+		_, err := logger.InstallCrashHandler()
+		require.NoError(t, err)
+		panic("crash handler test")
+	}
+
+	tmpDir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCrashHandlerPanic$")
+	cmd.Env = append(os.Environ(), "LOG_CRASH_TEST_DIR="+tmpDir)
+	err := cmd.Run()
+	require.Error(t, err, "Child process should exit with a panic")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.crash"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "panic: crash handler test")
+	assert.Contains(t, string(content), "goroutine")
+}
\ No newline at end of file
@@ -0,0 +1,17 @@
+package log
+
+// RecordFormatter renders records in place of the built-in formats, set with Config.CustomFormatter
+// FormatRecord returns the bytes written to the file, console, and sinks for a record, including any line ending;
+// it must be safe for concurrent use, as shard, error file, and mirror processors call it concurrently, and must
+// return a slice no other call modifies
+type RecordFormatter interface {
+	FormatRecord(record Record) []byte
+}
+
+// RecordFormatterFunc adapts a function to a RecordFormatter
+type RecordFormatterFunc func(record Record) []byte
+
+// FormatRecord calls f
+func (f RecordFormatterFunc) FormatRecord(record Record) []byte {
+	return f(record)
+}
\ No newline at end of file
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// dirSizeAccount keeps the total size and number of archived log files up to date as files are rotated and
+// deleted, so size checks do not read the whole directory
+// A reconciliation scan replaces the totals every size_reconcile_interval_s, picking up files changed by others
+type dirSizeAccount struct {
+	mu           sync.Mutex
+	dir          string          // Directory of the last scan
+	matcher      *logFileMatcher // Matcher of the last scan, nil before the first
+	scanned      time.Time       // Time of the last scan, zero forces the next check to scan
+	archiveBytes int64
+	archiveCount int
+}
+
+// archived adds an archive created by rotation
+func (a *dirSizeAccount) archived(size int64) {
+	a.mu.Lock()
+	a.archiveBytes += size
+	a.archiveCount++
+	a.mu.Unlock()
+}
+
+// removed subtracts a deleted archive
+func (a *dirSizeAccount) removed(size int64) {
+	a.mu.Lock()
+	a.archiveBytes = max(a.archiveBytes-size, 0)
+	a.archiveCount = max(a.archiveCount-1, 0)
+	a.mu.Unlock()
+}
+
+// invalidate makes the next check scan the directory
+func (a *dirSizeAccount) invalidate() {
+	a.mu.Lock()
+	a.scanned = time.Time{}
+	a.mu.Unlock()
+}
+
+// archiveTotals returns the total size and number of archives in dir, scanning it when the totals are stale
+func (l *Logger) archiveTotals(dir string) (int64, int, error) {
+	a := &l.state.dirSize
+	matcher := l.getFileMatcher()
+	interval := time.Duration(l.getConfig().SizeReconcileIntervalS) * time.Second
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.dir == dir && a.matcher == matcher && !a.scanned.IsZero() && interval > 0 && time.Since(a.scanned) < interval {
+		return a.archiveBytes, a.archiveCount, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, 0, fmtErrorf("failed to read log directory '%s': %w", dir, err)
+	}
+	var size int64
+	var count int
+	for _, entry := range entries {
+		if entry.IsDir() || !matcher.isArchive(entry.Name()) {
+			continue
+		}
+		info, errInfo := entry.Info()
+		if errInfo != nil {
+			continue
+		}
+		size += info.Size()
+		count++
+	}
+	a.dir, a.matcher, a.scanned = dir, matcher, time.Now()
+	a.archiveBytes, a.archiveCount = size, count
+	return size, count, nil
+}
+
+// activeFileSize returns the size of the active log file in dir, false when it does not exist
+func (l *Logger) activeFileSize(dir string) (int64, bool) {
+	info, err := os.Stat(filepath.Join(dir, l.getFileMatcher().activeName))
+	if err != nil || info.IsDir() {
+		return 0, false
+	}
+	return info.Size(), true
+}
\ No newline at end of file
@@ -0,0 +1,336 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// ElasticsearchSinkOptions configures a sink indexing records into Elasticsearch or OpenSearch with the bulk API
+// Zero values select the defaults noted on each field
+type ElasticsearchSinkOptions struct {
+	Endpoint      string            // Cluster URL, e.g. "https://es.example.com:9200"; required
+	Index         string            // Index name template, expands {name}, {host}, {yyyy}, {mm}, {dd}, {hh}; default "logs-{name}-{yyyy}.{mm}.{dd}"
+	Name          string            // Value of {name} in Index, default "log"
+	Username      string            // Basic authentication user, empty disables basic authentication
+	Password      string            // Basic authentication password
+	Headers       map[string]string // Added to every request, e.g. "Authorization: ApiKey ..."
+	Filter        Filter            // Selects the records indexed, nil indexes all records except heartbeats
+	BatchSize     int               // Records per bulk request, default 500
+	BatchBytes    int               // Uncompressed request size that triggers a send, default 5 MiB
+	FlushInterval time.Duration     // Longest time a record waits for its batch to fill, default 1s
+	QueueSize     int               // Records buffered for delivery, new records are dropped when full; default 10000
+	MaxRetries    int               // Retries of failed requests and records before they are dropped, default 3; negative disables retries
+	RetryBackoff  time.Duration     // Delay before the first retry, doubled for each further retry; default 500ms
+	Timeout       time.Duration     // Timeout of each request, default 10s
+	Gzip          bool              // Compress request bodies with gzip
+	Client        *http.Client      // Client for requests, e.g. with custom TLS settings; nil uses a default client
+}
+
+// ElasticsearchSinkStats counts the records handled by an Elasticsearch sink
+type ElasticsearchSinkStats struct {
+	Indexed uint64 // Records the cluster accepted
+	Failed  uint64 // Records dropped: rejected by the cluster, out of retries, or not queued because the queue was full
+	Retried uint64 // Records sent again after a 429 or 5xx response
+}
+
+// ElasticsearchSink is a Sink indexing records into Elasticsearch or OpenSearch
+type ElasticsearchSink struct {
+	*sinkQueue
+	opts      ElasticsearchSinkOptions
+	bulkURL   string
+	index     *strings.Replacer    // Expands {name} and {host}, date placeholders are expanded per record
+	formatter *formatter.Formatter // Used by the delivery goroutine only
+	indexHour time.Time            // Hour of the cached index name, used by the delivery goroutine only
+	indexName string
+	indexed   atomic.Uint64
+	failed    atomic.Uint64
+	retried   atomic.Uint64
+}
+
+var _ Sink = (*ElasticsearchSink)(nil)
+
+// esItem is one bulk operation, the action line followed by the document line
+type esItem []byte
+
+// esBulkResponse is the part of a bulk API response reporting the outcome of each operation
+type esBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// NewElasticsearchSink starts a sink indexing records into Elasticsearch or OpenSearch, register it with
+// Logger.AddSink
+// Returns an error if the endpoint is not a valid http or https URL
+func NewElasticsearchSink(opts ElasticsearchSinkOptions) (*ElasticsearchSink, error) {
+	endpoint, err := url.Parse(opts.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmtErrorf("invalid elasticsearch sink endpoint '%s': use an http or https URL", opts.Endpoint)
+	}
+
+	if opts.Index == "" {
+		opts.Index = elasticsearchSinkIndex
+	}
+	if opts.Name == "" {
+		opts.Name = elasticsearchSinkName
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = elasticsearchSinkBatchSize
+	}
+	if opts.BatchBytes <= 0 {
+		opts.BatchBytes = elasticsearchSinkBatchBytes
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = elasticsearchSinkFlushInterval
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = elasticsearchSinkQueueSize
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = elasticsearchSinkMaxRetries
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = elasticsearchSinkRetryBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = elasticsearchSinkTimeout
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{}
+	}
+
+	host, _ := os.Hostname()
+	s := &ElasticsearchSink{
+		sinkQueue: newSinkQueue("elasticsearch sink", opts.QueueSize, opts.Filter, opts.Timeout),
+		opts:      opts,
+		bulkURL:   strings.TrimRight(opts.Endpoint, "/") + "/_bulk",
+		index:     strings.NewReplacer("{name}", opts.Name, "{host}", host),
+		formatter: formatter.New(sanitizer.New()).
+			Type("json").
+			TimestampFormat(time.RFC3339Nano),
+	}
+	s.start(s.run)
+	return s, nil
+}
+
+// Stats returns the record counts of the sink
+func (s *ElasticsearchSink) Stats() ElasticsearchSinkStats {
+	return ElasticsearchSinkStats{
+		Indexed: s.indexed.Load(),
+		Failed:  s.failed.Load(),
+		Retried: s.retried.Load(),
+	}
+}
+
+// Write queues a matching record for delivery, counting it as failed when the queue is full
+func (s *ElasticsearchSink) Write(data []byte, record Record) error {
+	err := s.sinkQueue.Write(data, record)
+	if err == errSinkQueueFull {
+		s.failed.Add(1)
+	}
+	return err
+}
+
+// run collects queued records into bulk requests, sent once they reach BatchSize records or BatchBytes bytes
+func (s *ElasticsearchSink) run() {
+	var batch []esItem
+	size := 0
+	add := func(record Record) bool {
+		item := s.appendItem(nil, record)
+		batch = append(batch, item)
+		size += len(item)
+		return len(batch) >= s.opts.BatchSize || size >= s.opts.BatchBytes
+	}
+	flush := func() {
+		s.deliver(batch)
+		batch = nil
+		size = 0
+	}
+	s.batch(s.opts.FlushInterval, add, flush)
+}
+
+// appendItem appends the create action and the JSON document of a record, the document leads with @timestamp
+func (s *ElasticsearchSink) appendItem(buf []byte, record Record) []byte {
+	ts := record.Time.UTC()
+	buf = append(buf, `{"create":{"_index":`...)
+	buf = appendJSONString(buf, s.indexFor(ts))
+	buf = append(buf, "}}\n"...)
+
+	flags := record.Flags&^FlagRaw | FlagShowTimestamp | FlagShowLevel
+	doc := bytes.TrimRight(s.formatter.FormatLabeled(flags, record.Time, record.Level, record.Trace, record.Labels, record.Args), "\n")
+	buf = append(buf, `{"@timestamp":`...)
+	buf = appendJSONString(buf, ts.Format(time.RFC3339Nano))
+	if len(doc) > 2 {
+		buf = append(buf, ',')
+	}
+	buf = append(buf, doc[1:]...)
+	return append(buf, '\n')
+}
+
+// indexFor returns the index name of a record timestamped t, reusing the name of the previous record's hour
+func (s *ElasticsearchSink) indexFor(t time.Time) string {
+	hour := t.Truncate(time.Hour)
+	if s.indexName == "" || !hour.Equal(s.indexHour) {
+		s.indexHour = hour
+		s.indexName = strings.ToLower(strings.NewReplacer(
+			"{yyyy}", t.Format("2006"),
+			"{mm}", t.Format("01"),
+			"{dd}", t.Format("02"),
+			"{hh}", t.Format("15"),
+		).Replace(s.index.Replace(s.opts.Index)))
+	}
+	return s.indexName
+}
+
+// appendJSONString appends s as a quoted JSON string
+func appendJSONString(buf []byte, s string) []byte {
+	quoted, _ := json.Marshal(s)
+	return append(buf, quoted...)
+}
+
+// deliver sends a batch with the bulk API
+// Failed requests, transport errors, 429, and 5xx responses are retried with exponential backoff, as are
+// records the cluster rejected with 429 or 5xx; records rejected otherwise are dropped and counted as failed
+func (s *ElasticsearchSink) deliver(items []esItem) {
+	backoff := s.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		result, retry, err := s.bulk(items)
+		if err == nil {
+			if result.rejected > 0 {
+				s.drop(result.rejected, fmtErrorf("rejected by the cluster: %s", result.reason))
+			}
+			if len(result.retry) == 0 {
+				if result.rejected == 0 {
+					s.status.success()
+				}
+				return
+			}
+			items, retry = result.retry, true
+			err = fmtErrorf("cluster asked to retry %d records", len(items))
+		}
+		if !retry || attempt >= s.opts.MaxRetries {
+			s.drop(len(items), err)
+			return
+		}
+		s.retried.Add(uint64(len(items)))
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-s.ctx.Done():
+			s.drop(len(items), s.ctx.Err())
+			return
+		}
+	}
+}
+
+// drop counts records that will not be indexed and records the cause in the sink's health
+func (s *ElasticsearchSink) drop(count int, err error) {
+	s.failed.Add(uint64(count))
+	s.status.failure(fmtErrorf("elasticsearch sink dropped %d records: %w", count, err))
+}
+
+// esBulkResult is the outcome of a bulk request the cluster processed
+type esBulkResult struct {
+	retry    []esItem // Records rejected with 429 or 5xx, worth sending again
+	rejected int      // Records rejected for good
+	reason   string   // Error of the first record rejected for good
+}
+
+// bulk sends one bulk request and counts the records the cluster accepted
+// A failed request reports whether it is worth retrying
+func (s *ElasticsearchSink) bulk(items []esItem) (esBulkResult, bool, error) {
+	var body []byte
+	for _, item := range items {
+		body = append(body, item...)
+	}
+	if s.opts.Gzip {
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		_, _ = zw.Write(body)
+		_ = zw.Close()
+		body = compressed.Bytes()
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, s.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.bulkURL, bytes.NewReader(body))
+	if err != nil {
+		return esBulkResult{}, false, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if s.opts.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if s.opts.Username != "" {
+		req.SetBasicAuth(s.opts.Username, s.opts.Password)
+	}
+	for k, v := range s.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return esBulkResult{}, s.ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return esBulkResult{}, true, fmtErrorf("cluster responded %s", resp.Status)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return esBulkResult{}, false, fmtErrorf("cluster rejected bulk request: %s", resp.Status)
+	}
+
+	var response esBulkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		// The request was accepted, an unreadable response is not worth duplicating records for
+		s.indexed.Add(uint64(len(items)))
+		return esBulkResult{}, false, nil
+	}
+	if !response.Errors {
+		s.indexed.Add(uint64(len(items)))
+		return esBulkResult{}, false, nil
+	}
+
+	var result esBulkResult
+	for i, entry := range response.Items {
+		if i >= len(items) {
+			break
+		}
+		for _, outcome := range entry {
+			switch {
+			case outcome.Status >= 200 && outcome.Status < 300:
+				s.indexed.Add(1)
+			case outcome.Status == http.StatusTooManyRequests || outcome.Status >= 500:
+				result.retry = append(result.retry, items[i])
+			default:
+				result.rejected++
+				if result.reason == "" {
+					result.reason = outcome.Error.Type + ": " + outcome.Error.Reason
+				}
+			}
+		}
+	}
+	return result, false, nil
+}
\ No newline at end of file
@@ -0,0 +1,127 @@
+package log
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkCollector is a test bulk API endpoint recording indexed documents
+// Each document whose message contains "retry" is rejected with 429 once, "reject" is rejected with 400
+type bulkCollector struct {
+	mu       sync.Mutex
+	requests int
+	indices  []string
+	docs     []map[string]any
+	retried  map[string]bool
+	user     string
+}
+
+func (c *bulkCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+	c.user, _, _ = r.BasicAuth()
+
+	var items []string
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		var action map[string]map[string]string
+		if err := json.Unmarshal(scanner.Bytes(), &action); err != nil || !scanner.Scan() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var doc map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		message := fmt.Sprint(doc["fields"])
+		status := http.StatusCreated
+		switch {
+		case strings.Contains(message, "reject"):
+			status = http.StatusBadRequest
+		case strings.Contains(message, "retry") && !c.retried[message]:
+			c.retried[message] = true
+			status = http.StatusTooManyRequests
+		default:
+			c.indices = append(c.indices, action["create"]["_index"])
+			c.docs = append(c.docs, doc)
+		}
+		items = append(items, fmt.Sprintf(`{"create":{"status":%d,"error":{"type":"mapper_parsing_exception","reason":"bad"}}}`, status))
+	}
+	fmt.Fprintf(w, `{"errors":true,"items":[%s]}`, strings.Join(items, ","))
+}
+
+func (c *bulkCollector) snapshot() ([]string, []map[string]any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.indices...), append([]map[string]any(nil), c.docs...)
+}
+
+// TestElasticsearchSink verifies bulk indexing, index templating, per-record retries, and failure counting
+func TestElasticsearchSink(t *testing.T) {
+	collector := &bulkCollector{retried: make(map[string]bool)}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	sink, err := NewElasticsearchSink(ElasticsearchSinkOptions{
+		Endpoint:      server.URL + "/",
+		Name:          "App",
+		Username:      "elastic",
+		Password:      "secret",
+		FlushInterval: 20 * time.Millisecond,
+		RetryBackoff:  10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, logger.AddSink("es", sink))
+
+	logger.Info("indexed", "n", 1)
+	logger.Warn("retry once")
+	logger.Error("reject")
+	require.NoError(t, logger.Flush(time.Second))
+
+	require.Eventually(t, func() bool {
+		stats := sink.Stats()
+		return stats.Indexed == 2 && stats.Failed == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint64(1), sink.Stats().Retried)
+
+	indices, docs := collector.snapshot()
+	today := time.Now().UTC().Format("2006.01.02")
+	assert.Equal(t, []string{"logs-app-" + today, "logs-app-" + today}, indices)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "INFO", docs[0]["level"])
+	assert.NotEmpty(t, docs[0]["@timestamp"])
+	assert.Equal(t, "WARN", docs[1]["level"])
+	assert.Equal(t, "elastic", collector.user)
+
+	health := logger.Stats().Sinks
+	var found bool
+	for _, h := range health {
+		if h.Name == "es" {
+			found = true
+			assert.Contains(t, h.LastError, "mapper_parsing_exception")
+		}
+	}
+	assert.True(t, found)
+
+	_, err = NewElasticsearchSink(ElasticsearchSinkOptions{Endpoint: "es.example.com:9200"})
+	assert.Error(t, err)
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package log
+
+import "os"
+
+// enrichment returns the key-value pairs the enrich_host, enrich_pid, app_name, and app_version settings add to every
+// record, nil when none are set. The hostname is resolved once per configuration, "unknown" when unavailable
+func enrichment(cfg *Config) []any {
+	var pairs []any
+	if cfg.EnrichHost {
+		host, err := os.Hostname()
+		if err != nil || host == "" {
+			host = "unknown"
+		}
+		pairs = append(pairs, "host", host)
+	}
+	if cfg.EnrichPID {
+		pairs = append(pairs, "pid", os.Getpid())
+	}
+	if cfg.AppName != "" {
+		pairs = append(pairs, "app", cfg.AppName)
+	}
+	if cfg.AppVersion != "" {
+		pairs = append(pairs, "version", cfg.AppVersion)
+	}
+	return pairs
+}
+
+// enrich returns record with the epoch's enrichment pairs appended, keys the record already has keep their value
+// Structured records get the pairs merged into a copy of their field map, raw records are left untouched
+func (e *configEpoch) enrich(record logRecord) logRecord {
+	if len(e.enrichment) == 0 || record.Flags&FlagRaw != 0 {
+		return record
+	}
+
+	if record.Flags&FlagStructuredJSON != 0 && len(record.Args) == 2 {
+		if fields, ok := record.Args[1].(map[string]any); ok {
+			merged := make(map[string]any, len(fields)+len(e.enrichment)/2)
+			for i := 0; i+1 < len(e.enrichment); i += 2 {
+				merged[e.enrichment[i].(string)] = e.enrichment[i+1]
+			}
+			for k, v := range fields {
+				merged[k] = v
+			}
+			record.Args = []any{record.Args[0], merged}
+			return record
+		}
+	}
+
+	args := make([]any, len(record.Args), len(record.Args)+len(e.enrichment))
+	copy(args, record.Args)
+	for i := 0; i+1 < len(e.enrichment); i += 2 {
+		if !hasArgKey(record.Args, e.enrichment[i].(string)) {
+			args = append(args, e.enrichment[i], e.enrichment[i+1])
+		}
+	}
+	record.Args = args
+	return record
+}
+
+// hasArgKey reports whether key appears among args followed by a value
+func hasArgKey(args []any, key string) bool {
+	for i := 0; i+1 < len(args); i++ {
+		if k, ok := args[i].(string); ok && k == key {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
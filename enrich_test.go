@@ -0,0 +1,54 @@
+package log
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecordEnrichment verifies the processor adds host, pid, app, and version without overriding record keys
+func TestRecordEnrichment(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=json", "json_fields=object", "enrich_host=true", "enrich_pid=true",
+		"app_name=billing", "app_version=1.4.2"))
+	logger.Info("invoice sent", "invoice_id", 1042)
+	logger.Info("forwarded", "app", "gateway")
+	logger.LogStructured(LevelInfo, "structured", map[string]any{"version": "2"})
+	logger.Write("raw line")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 4)
+
+	host, _ := os.Hostname()
+	var record struct {
+		Message string         `json:"message"`
+		Fields  map[string]any `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "invoice sent", record.Message)
+	assert.Equal(t, float64(1042), record.Fields["invoice_id"])
+	assert.Equal(t, host, record.Fields["host"])
+	assert.Equal(t, float64(os.Getpid()), record.Fields["pid"])
+	assert.Equal(t, "billing", record.Fields["app"])
+	assert.Equal(t, "1.4.2", record.Fields["version"])
+
+	record.Fields = nil
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "gateway", record.Fields["app"], "Record keys keep their value")
+	assert.Equal(t, "1.4.2", record.Fields["version"])
+
+	assert.Contains(t, lines[2], `"version":"2"`)
+	assert.Contains(t, lines[2], `"app":"billing"`)
+	assert.Equal(t, "raw line", lines[3], "Raw records are not enriched")
+}
\ No newline at end of file
@@ -0,0 +1,86 @@
+package log
+
+// errorFileSuffix is appended to Name for the file receiving WARN and ERROR records when split_error_file is enabled
+const errorFileSuffix = "_error"
+
+// errorFileConfig derives the configuration of the error file logger, writing {name}_error.{ext} in the same directory
+// Only file output is kept; rotation, retention, and size limits apply to the error file on its own
+func errorFileConfig(cfg *Config) *Config {
+	errCfg := cfg.Clone()
+	errCfg.Name = cfg.Name + errorFileSuffix
+	errCfg.Level = LevelWarn
+	errCfg.FileLevel = LevelWarn
+	errCfg.LevelOverridesByField = nil
+	errCfg.EnableConsole = false
+	errCfg.EnableSyslog = false
+	errCfg.EnableJournal = false
+	errCfg.EnableGELF = false
+	errCfg.Shards = 0
+	errCfg.HeartbeatLevel = 0
+	errCfg.SplitErrorFile = false
+	errCfg.MirrorDirectory = ""
+	errCfg.RecentRecords = 0
+	errCfg.OnErrorExec = ""
+	errCfg.InternalErrorsToLog = false
+	// Records arrive enriched from the primary
+	errCfg.EnrichHost = false
+	errCfg.EnrichPID = false
+	errCfg.AppName = ""
+	errCfg.AppVersion = ""
+	return errCfg
+}
+
+// getErrorFile returns the logger writing the error file, or nil when split_error_file is disabled
+func (c *loggerCore) getErrorFile() *Logger {
+	errorFile, _ := c.errorFile.Load().(*Logger)
+	return errorFile
+}
+
+// configureErrorFile creates or reconfigures the error file logger to match cfg, assuming initMu is held
+// Returns the error file logger to publish with the new configuration, nil when split_error_file is disabled, the
+// one no longer in use, to be shut down after the commit, and a function undoing the preparation if the commit
+// is abandoned; on error the current error file is left as it was
+func (l *Logger) configureErrorFile(cfg *Config) (*Logger, *Logger, func(), error) {
+	current := l.getErrorFile()
+	if !cfg.EnableFile || !cfg.SplitErrorFile || cfg.ReadOnly {
+		return nil, current, func() {}, nil
+	}
+
+	next := current
+	undo := func() { _ = next.Shutdown() }
+	if current == nil {
+		next = NewLogger()
+	} else {
+		previous := current.GetConfig()
+		undo = func() { _ = current.ApplyConfig(previous) }
+	}
+	if err := next.ApplyConfig(errorFileConfig(cfg)); err != nil {
+		undo()
+		return nil, nil, nil, fmtErrorf("failed to configure error file: %w", err)
+	}
+	if l.state.Started.Load() {
+		if err := next.Start(); err != nil {
+			undo()
+			return nil, nil, nil, fmtErrorf("failed to start error file: %w", err)
+		}
+	}
+	return next, nil, undo, nil
+}
+
+// forwardToErrorFile duplicates WARN and ERROR records to the error file, shard processors use their parent's
+func (l *Logger) forwardToErrorFile(record logRecord) {
+	if record.Level < LevelWarn || record.Level >= LevelProc {
+		return
+	}
+	owner := l.loggerCore
+	if owner.parent != nil {
+		owner = owner.parent
+	}
+	errorFile := owner.getErrorFile()
+	if errorFile == nil {
+		return
+	}
+	// The main file confirms audit records, the copy is written independently
+	record.ack = nil
+	errorFile.sendLogRecord(record)
+}
\ No newline at end of file
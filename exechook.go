@@ -0,0 +1,171 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// execTimeout bounds one run of the on_error_exec command, which is killed afterwards
+const execTimeout = 30 * time.Second
+
+// execSettings are the configuration values an exec hook is built from
+type execSettings struct {
+	command  string
+	level    int64
+	interval time.Duration
+	name     string // Logger name, passed as LOG_NAME
+}
+
+// execEvent is a triggering record handed to the command
+type execEvent struct {
+	level      int64
+	time       time.Time
+	line       string
+	suppressed uint64 // Triggering records skipped since the previous run
+}
+
+// execHook runs the on_error_exec command for severe records on its own goroutine, at most once per interval
+// Records arriving within the interval or while the command runs are counted and reported with the next run
+type execHook struct {
+	settings  execSettings
+	args      []string
+	formatter *formatter.Formatter // Renders LOG_RECORD, used under mu
+	events    chan execEvent
+	ctx       context.Context // Cancelled on close, killing a running command
+	cancel    context.CancelFunc
+	exited    chan struct{}
+	closed    sync.Once
+	status    healthTracker
+
+	mu         sync.Mutex
+	lastRun    time.Time
+	suppressed uint64
+	runs       atomic.Uint64
+}
+
+// configureExecHook returns the exec hook for cfg, reusing current when its settings are unchanged
+func configureExecHook(cfg *Config, current *execHook) *execHook {
+	if cfg.OnErrorExec == "" {
+		return nil
+	}
+	settings := execSettings{
+		command:  cfg.OnErrorExec,
+		level:    cfg.OnErrorExecLevel,
+		interval: time.Duration(cfg.OnErrorExecIntervalS) * time.Second,
+		name:     cfg.Name,
+	}
+	if current != nil && current.settings == settings {
+		return current
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &execHook{
+		settings:  settings,
+		args:      strings.Fields(settings.command),
+		formatter: formatter.New(sanitizer.New().Policy(PolicyTxt)).Type("txt"),
+		events:    make(chan execEvent, 1),
+		ctx:       ctx,
+		cancel:    cancel,
+		exited:    make(chan struct{}),
+	}
+	go h.run()
+	return h
+}
+
+// trigger hands a record at or above the hook's level to the command without blocking, heartbeats never trigger
+func (h *execHook) trigger(record logRecord) {
+	if record.Level < h.settings.level || record.Level >= LevelProc {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	if !h.lastRun.IsZero() && now.Sub(h.lastRun) < h.settings.interval {
+		h.suppressed++
+		return
+	}
+	line := h.formatter.FormatLabeled(FlagDefault, record.TimeStamp, record.Level, record.Trace, record.Labels, record.Args)
+	event := execEvent{
+		level:      record.Level,
+		time:       record.TimeStamp,
+		line:       string(bytes.TrimRight(line, "\n")),
+		suppressed: h.suppressed,
+	}
+	select {
+	case h.events <- event:
+		h.lastRun = now
+		h.suppressed = 0
+	default:
+		// A run is still waiting to start
+		h.suppressed++
+	}
+}
+
+// run executes the command for each event until closed
+func (h *execHook) run() {
+	defer close(h.exited)
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case event := <-h.events:
+			h.exec(event)
+		}
+	}
+}
+
+// exec runs the command once, describing the record in its environment
+func (h *execHook) exec(event execEvent) {
+	ctx, cancel := context.WithTimeout(h.ctx, execTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, h.args[0], h.args[1:]...)
+	cmd.Env = append(os.Environ(),
+		"LOG_NAME="+h.settings.name,
+		"LOG_LEVEL="+formatter.LevelToString(event.level),
+		"LOG_TIME="+event.time.Format(time.RFC3339Nano),
+		"LOG_RECORD="+event.line,
+		"LOG_SUPPRESSED="+strconv.FormatUint(event.suppressed, 10),
+	)
+	h.runs.Add(1)
+	if err := cmd.Run(); err != nil {
+		h.status.failure(fmtErrorf("on_error_exec command '%s' failed: %w", h.settings.command, err))
+		return
+	}
+	h.status.success()
+}
+
+// close stops the hook, killing a running command
+func (h *execHook) close() {
+	h.closed.Do(func() {
+		h.cancel()
+		<-h.exited
+	})
+}
+
+// health reports whether recent runs of the command succeeded
+func (h *execHook) health(name string) SinkHealth {
+	return h.status.snapshot(name)
+}
+
+// triggerExecHook hands a record to the on_error_exec hook, shard processors use their parent's
+func (l *Logger) triggerExecHook(record logRecord) {
+	owner := l.loggerCore
+	if owner.parent != nil {
+		owner = owner.parent
+	}
+	if hook := owner.epoch.Load().(*configEpoch).exec; hook != nil {
+		hook.trigger(record)
+	}
+}
\ No newline at end of file
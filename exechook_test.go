@@ -0,0 +1,63 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExecHook verifies the command runs for records at its level with the record in its environment, rate limited
+func TestExecHook(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	out := filepath.Join(tmpDir, "runs.txt")
+	script := filepath.Join(tmpDir, "notify.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"$LOG_NAME $LOG_LEVEL $LOG_SUPPRESSED $LOG_RECORD\" >> \""+out+"\"\n"), 0755))
+	require.NoError(t, logger.ApplyConfigString("on_error_exec="+script, "on_error_exec_level=warn", "on_error_exec_interval_s=60"))
+
+	logger.Info("ignored")
+	logger.Warn("disk slow", "latency_ms", 250)
+	logger.Error("within interval")
+	require.NoError(t, logger.Flush(time.Second))
+
+	require.Eventually(t, func() bool {
+		content, err := os.ReadFile(out)
+		return err == nil && strings.Contains(string(content), "\n")
+	}, 5*time.Second, 10*time.Millisecond)
+	content, err := os.ReadFile(out)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 1, "Records within the interval are counted, not run")
+	assert.Contains(t, lines[0], "log WARN 0 ")
+	assert.Contains(t, lines[0], `WARN "disk slow" latency_ms 250`)
+
+	hook := logger.getEpoch().exec
+	require.NotNil(t, hook)
+	hook.mu.Lock()
+	assert.Equal(t, uint64(1), hook.suppressed)
+	hook.mu.Unlock()
+
+	// A failing command is reported in the exec health
+	require.NoError(t, logger.ApplyConfigString("on_error_exec="+filepath.Join(tmpDir, "missing.sh"), "on_error_exec_interval_s=0"))
+	logger.Error("no command")
+	require.NoError(t, logger.Flush(time.Second))
+	require.Eventually(t, func() bool {
+		for _, h := range logger.Stats().Sinks {
+			if h.Name == "exec" && h.Status != SinkStatusOK {
+				return strings.Contains(h.LastError, "missing.sh")
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Disabling the hook stops it
+	require.NoError(t, logger.ApplyConfigString("on_error_exec="))
+	assert.Nil(t, logger.getEpoch().exec)
+	assert.Error(t, logger.ApplyConfigString("on_error_exec_interval_s=-1"))
+}
\ No newline at end of file
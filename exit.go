@@ -0,0 +1,115 @@
+package log
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// exitSignals are the signals on which loggers with flush_on_exit are flushed before the process terminates
+var exitSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// exitHook tracks the loggers with flush_on_exit enabled and the process-wide signal subscription serving them
+var exitHook struct {
+	mu      sync.Mutex
+	loggers map[*loggerCore]*Logger
+	signals chan os.Signal
+}
+
+// configureExitHook registers the logger for flushing on SIGINT and SIGTERM when flush_on_exit is enabled,
+// and removes it otherwise
+func (l *Logger) configureExitHook(cfg *Config) {
+	if !cfg.FlushOnExit {
+		l.removeExitHook()
+		return
+	}
+
+	exitHook.mu.Lock()
+	defer exitHook.mu.Unlock()
+
+	if exitHook.loggers == nil {
+		exitHook.loggers = make(map[*loggerCore]*Logger)
+	}
+	exitHook.loggers[l.loggerCore] = l
+	if exitHook.signals == nil {
+		exitHook.signals = make(chan os.Signal, 1)
+		signal.Notify(exitHook.signals, exitSignals...)
+		go watchExitSignals(exitHook.signals)
+	}
+}
+
+// removeExitHook unregisters the logger, releasing the signal subscription once no logger uses it
+func (l *Logger) removeExitHook() {
+	exitHook.mu.Lock()
+	defer exitHook.mu.Unlock()
+
+	delete(exitHook.loggers, l.loggerCore)
+	if len(exitHook.loggers) == 0 && exitHook.signals != nil {
+		signal.Stop(exitHook.signals)
+		close(exitHook.signals)
+		exitHook.signals = nil
+	}
+}
+
+// watchExitSignals waits for an exit signal and flushes the registered loggers. Handlers the application registered
+// receive the signal on their own; with flush_on_exit_reraise the signal is re-sent with its default disposition
+// restored so a process without handlers terminates as it would have without the hook
+func watchExitSignals(signals chan os.Signal) {
+	sig, ok := <-signals
+	if !ok {
+		return
+	}
+
+	exitHook.mu.Lock()
+	// A second signal during the flush terminates the process at once
+	signal.Stop(signals)
+	if exitHook.signals == signals {
+		exitHook.signals = nil
+	}
+	exitHook.mu.Unlock()
+
+	flushExitLoggers(exitFlushTimeout)
+	if !exitReraise() {
+		return
+	}
+
+	if p, err := os.FindProcess(os.Getpid()); err != nil || p.Signal(sig) != nil {
+		os.Exit(1)
+	}
+}
+
+// exitReraise reports whether a logger registered for flush_on_exit asks for the exit signal to be re-sent
+func exitReraise() bool {
+	exitHook.mu.Lock()
+	defer exitHook.mu.Unlock()
+
+	for _, l := range exitHook.loggers {
+		if l.getConfig().FlushOnExitReraise {
+			return true
+		}
+	}
+	return false
+}
+
+// flushExitLoggers flushes every logger registered for flush_on_exit, sharing timeout between them
+func flushExitLoggers(timeout time.Duration) {
+	exitHook.mu.Lock()
+	loggers := make([]*Logger, 0, len(exitHook.loggers))
+	for _, l := range exitHook.loggers {
+		loggers = append(loggers, l)
+	}
+	exitHook.mu.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	for _, l := range loggers {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return
+		}
+		if err := l.Flush(remaining); err != nil {
+			l.internalLog("warning - failed to flush on exit: %v\n", err)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,108 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFlushOnExitRegistration verifies loggers join and leave the exit hook with flush_on_exit and Shutdown
+func TestFlushOnExitRegistration(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	registered := func() bool {
+		exitHook.mu.Lock()
+		defer exitHook.mu.Unlock()
+		_, ok := exitHook.loggers[logger.loggerCore]
+		return ok
+	}
+
+	assert.False(t, registered())
+	require.NoError(t, logger.ApplyConfigString("flush_on_exit=true"))
+	assert.True(t, registered())
+	require.NoError(t, logger.ApplyConfigString("flush_on_exit=false"))
+	assert.False(t, registered())
+
+	require.NoError(t, logger.ApplyConfigString("flush_on_exit=true"))
+	require.NoError(t, logger.Shutdown())
+	assert.False(t, registered())
+}
+
+// TestFlushOnExitSignal verifies queued records reach the file when SIGINT ends a process that never calls Shutdown
+// The test re-executes itself so the signal terminates a child process
+func TestFlushOnExitSignal(t *testing.T) {
+	if dir := os.Getenv("LOG_EXIT_TEST_DIR"); dir != "" {
+		logger := NewLogger()
+		cfg := DefaultConfig()
+		cfg.Directory = dir
+		cfg.EnableConsole = false
+		cfg.EnableFile = true
+		cfg.FlushOnExit = true
+		cfg.FlushOnExitReraise = true
+		require.NoError(t, logger.ApplyConfig(cfg))
+		require.NoError(t, logger.Start())
+		for i := 0; i < 1000; i++ {
+			logger.Info("record", i)
+		}
+		p, err := os.FindProcess(os.Getpid())
+		require.NoError(t, err)
+		require.NoError(t, p.Signal(os.Interrupt))
+		time.Sleep(10 * time.Second)
+		return
+	}
+
+	tmpDir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFlushOnExitSignal$")
+	cmd.Env = append(os.Environ(), "LOG_EXIT_TEST_DIR="+tmpDir)
+	err := cmd.Run()
+	require.Error(t, err, "Child process should be terminated by the signal")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), fmt.Sprintf("record %d", 999))
+}
+
+// TestFlushOnExitAppHandler verifies an application handling SIGTERM itself receives the signal once while the
+// records are flushed, the process is left to its own shutdown
+func TestFlushOnExitAppHandler(t *testing.T) {
+	if dir := os.Getenv("LOG_EXIT_HANDLER_TEST_DIR"); dir != "" {
+		received := make(chan os.Signal, 4)
+		signal.Notify(received, syscall.SIGTERM)
+
+		logger := NewLogger()
+		cfg := DefaultConfig()
+		cfg.Directory = dir
+		cfg.EnableConsole = false
+		cfg.EnableFile = true
+		cfg.FlushOnExit = true
+		require.NoError(t, logger.ApplyConfig(cfg))
+		require.NoError(t, logger.Start())
+		for i := 0; i < 1000; i++ {
+			logger.Info("record", i)
+		}
+		p, err := os.FindProcess(os.Getpid())
+		require.NoError(t, err)
+		require.NoError(t, p.Signal(syscall.SIGTERM))
+
+		<-received
+		time.Sleep(time.Second)
+		assert.Len(t, received, 0, "The signal must not be delivered twice")
+		content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "record 999")
+		require.NoError(t, logger.Shutdown())
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFlushOnExitAppHandler$")
+	cmd.Env = append(os.Environ(), "LOG_EXIT_HANDLER_TEST_DIR="+t.TempDir())
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "Child process should shut down on its own: %s", output)
+}
\ No newline at end of file
@@ -0,0 +1,35 @@
+//go:build linux && (amd64 || arm64 || riscv64 || loong64 || ppc64 || ppc64le || s390x || mips64 || mips64le)
+
+package log
+
+import (
+	"os"
+	"syscall"
+)
+
+// fadvPosixDontNeed is POSIX_FADV_DONTNEED, identical on all 64-bit Linux architectures
+const fadvPosixDontNeed = 4
+
+// openDSyncFlag makes writes to the log file return once its data is on disk, see open_dsync
+const openDSyncFlag = syscall.O_DSYNC
+
+// fadviseDontNeed advises the kernel that the file's cached pages will not be accessed again
+func fadviseDontNeed(file *os.File) error {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var errno syscall.Errno
+	ctrlErr := conn.Control(func(fd uintptr) {
+		// offset 0 and length 0 cover the whole file
+		_, _, errno = syscall.Syscall6(syscall.SYS_FADVISE64, fd, 0, 0, fadvPosixDontNeed, 0, 0)
+	})
+	if ctrlErr != nil {
+		return ctrlErr
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,15 @@
+//go:build !linux || !(amd64 || arm64 || riscv64 || loong64 || ppc64 || ppc64le || s390x || mips64 || mips64le)
+
+package log
+
+import (
+	"os"
+)
+
+// openDSyncFlag falls back to O_SYNC, which every platform defines, also syncing file metadata on every write
+const openDSyncFlag = os.O_SYNC
+
+// fadviseDontNeed is a no-op on platforms without posix_fadvise support
+func fadviseDontNeed(file *os.File) error {
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// configFlag is a flag.Value setting one configuration key
+type configFlag struct {
+	cfg   *Config
+	key   string
+	value reflect.Value
+}
+
+// RegisterFlags defines a flag for every configuration key on fs, named prefix.key, e.g. -log.level for prefix "log"
+// An empty prefix uses the bare keys; flags default to the values c holds when they are registered
+// Parsing a flag applies its value to c like Set with SourceFlag, apply c with ApplyConfig after fs.Parse
+func (c *Config) RegisterFlags(fs *flag.FlagSet, prefix string) {
+	if prefix != "" {
+		prefix += "."
+	}
+	for _, field := range c.fields() {
+		fs.Var(&configFlag{cfg: c, key: field.key, value: field.value}, prefix+field.key,
+			fmt.Sprintf("log configuration '%s'", field.key))
+	}
+}
+
+// String returns the current value, empty for the zero value flag.PrintDefaults creates
+func (f *configFlag) String() string {
+	if f.cfg == nil {
+		return ""
+	}
+	return fmt.Sprint(f.value.Interface())
+}
+
+// Set applies a flag value to the configuration
+func (f *configFlag) Set(value string) error {
+	return f.cfg.Set(f.key, value, SourceFlag)
+}
+
+// IsBoolFlag lets boolean keys be set without a value, e.g. -log.enable_console
+func (f *configFlag) IsBoolFlag() bool {
+	return f.cfg != nil && f.value.Kind() == reflect.Bool
+}
\ No newline at end of file
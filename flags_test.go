@@ -0,0 +1,33 @@
+package log
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegisterFlags verifies parsed flags set configuration keys and are reported as their source
+func TestRegisterFlags(t *testing.T) {
+	cfg := DefaultConfig()
+	fs := flag.NewFlagSet("tool", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cfg.RegisterFlags(fs, "log")
+
+	require.NoError(t, fs.Parse([]string{"-log.level", "warn", "-log.format=json", "-log.enable_console", "-log.directory", "/var/log/tool"}))
+	assert.Equal(t, LevelWarn, cfg.Level)
+	assert.Equal(t, "json", cfg.Format)
+	assert.True(t, cfg.EnableConsole)
+	assert.Equal(t, "/var/log/tool", cfg.Directory)
+	assert.Equal(t, SourceFlag, cfg.Provenance()["format"])
+	assert.Equal(t, SourceDefault, cfg.Provenance()["name"])
+	assert.Equal(t, "log", fs.Lookup("log.name").DefValue)
+
+	assert.Error(t, fs.Parse([]string{"-log.max_size_kb", "large"}))
+
+	bare := flag.NewFlagSet("tool", flag.ContinueOnError)
+	DefaultConfig().RegisterFlags(bare, "")
+	assert.NotNil(t, bare.Lookup("level"))
+}
\ No newline at end of file
@@ -0,0 +1,272 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// FluentSinkOptions configures a sink sending records to a Fluentd or Fluent Bit forward input
+// Zero values select the defaults noted on each field
+type FluentSinkOptions struct {
+	Address       string        // Forward input address, "host:port" or a socket path; required
+	Network       string        // "tcp" or "unix", default "tcp"
+	Tag           string        // Fluentd tag of the records, default "log"
+	Filter        Filter        // Selects the records sent, nil sends all records except heartbeats
+	BatchSize     int           // Records per forward message, default 100
+	FlushInterval time.Duration // Longest time a record waits for its message to fill, default 1s
+	QueueSize     int           // Records buffered for delivery, new records are dropped when full; default 10000
+	MaxRetries    int           // Retries of a failed message before it is dropped, default 3; negative disables retries
+	RetryBackoff  time.Duration // Delay before the first retry, doubled for each further retry; default 500ms
+	Timeout       time.Duration // Dial, write, and acknowledgment timeout, default 10s
+	RequireAck    bool          // Wait for the input to acknowledge each message, retrying it otherwise
+}
+
+// FluentSink is a Sink sending records to Fluentd or Fluent Bit using the forward protocol
+type FluentSink struct {
+	*sinkQueue
+	opts      FluentSinkOptions
+	formatter *formatter.Formatter // Formats the message field, used by the delivery goroutine only
+	conn      net.Conn             // Used by the delivery goroutine only, nil until connected
+}
+
+var _ Sink = (*FluentSink)(nil)
+
+// NewFluentSink starts a sink sending records to a Fluentd or Fluent Bit forward input, register it with
+// Logger.AddSink
+// Returns an error if the address or network is invalid
+func NewFluentSink(opts FluentSinkOptions) (*FluentSink, error) {
+	if opts.Network == "" {
+		opts.Network = "tcp"
+	}
+	switch opts.Network {
+	case "tcp":
+		if _, _, err := net.SplitHostPort(opts.Address); err != nil {
+			return nil, fmtErrorf("invalid fluent sink address '%s': %w", opts.Address, err)
+		}
+	case "unix":
+		if opts.Address == "" {
+			return nil, fmtErrorf("fluent sink address cannot be empty")
+		}
+	default:
+		return nil, fmtErrorf("invalid fluent sink network '%s' (use tcp or unix)", opts.Network)
+	}
+
+	if opts.Tag == "" {
+		opts.Tag = fluentSinkTag
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = fluentSinkBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = fluentSinkFlushInterval
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = fluentSinkQueueSize
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = fluentSinkMaxRetries
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = fluentSinkRetryBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = fluentSinkTimeout
+	}
+
+	s := &FluentSink{
+		sinkQueue: newSinkQueue("fluent sink", opts.QueueSize, opts.Filter, opts.Timeout),
+		opts:      opts,
+		formatter: formatter.New(sanitizer.New()).
+			Type("txt").
+			ShowTimestamp(false).
+			ShowLevel(false),
+	}
+	s.start(s.run)
+	return s, nil
+}
+
+// run collects queued records into forward mode messages of up to BatchSize entries
+func (s *FluentSink) run() {
+	defer s.disconnect()
+
+	var entries []byte
+	count := 0
+	add := func(record Record) bool {
+		entries = s.appendEntry(entries, record)
+		count++
+		return count >= s.opts.BatchSize
+	}
+	flush := func() {
+		s.deliver(entries, count)
+		entries = entries[:0]
+		count = 0
+	}
+	s.batch(s.opts.FlushInterval, add, flush)
+}
+
+// appendEntry appends a record as a forward mode entry, [time, {level, message, trace, labels, fields...}]
+// Key-value pairs and structured fields become record keys; keys colliding with the entry's own are skipped
+func (s *FluentSink) appendEntry(buf []byte, record Record) []byte {
+	body := s.formatter.Format(
+		record.Flags&^(FlagShowTimestamp|FlagShowLevel),
+		record.Time,
+		record.Level,
+		record.Trace,
+		record.Args,
+	)
+
+	var keys []string
+	var values []any
+	forEachRecordField(record.Flags, record.Args, func(key string, value any) {
+		switch key {
+		case "", "level", "message", "trace", "labels":
+			return
+		}
+		for _, k := range keys {
+			if k == key {
+				return
+			}
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+	})
+
+	size := 2 + len(keys)
+	if record.Trace != "" {
+		size++
+	}
+	if len(record.Labels) > 0 {
+		size++
+	}
+	buf = appendMsgpackArrayHeader(buf, 2)
+	buf = appendMsgpackEventTime(buf, record.Time)
+	buf = appendMsgpackMapHeader(buf, size)
+	buf = appendMsgpackString(buf, "level")
+	buf = appendMsgpackString(buf, formatter.LevelToString(record.Level))
+	buf = appendMsgpackString(buf, "message")
+	buf = appendMsgpackString(buf, string(bytes.TrimRight(body, "\n")))
+	if record.Trace != "" {
+		buf = appendMsgpackString(buf, "trace")
+		buf = appendMsgpackString(buf, record.Trace)
+	}
+	if len(record.Labels) > 0 {
+		names := make([]string, 0, len(record.Labels))
+		for name := range record.Labels {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		buf = appendMsgpackString(buf, "labels")
+		buf = appendMsgpackMapHeader(buf, len(names))
+		for _, name := range names {
+			buf = appendMsgpackString(buf, name)
+			buf = appendMsgpackString(buf, record.Labels[name])
+		}
+	}
+	for i, key := range keys {
+		buf = appendMsgpackString(buf, key)
+		buf = appendMsgpackValue(buf, values[i], fluentText)
+	}
+	return buf
+}
+
+// fluentText renders values without a MessagePack form
+func fluentText(v any) string {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+// deliver sends a forward mode message of count entries, retrying failed connections, writes, and missing
+// acknowledgments with exponential backoff
+func (s *FluentSink) deliver(entries []byte, count int) {
+	chunk := ""
+	if s.opts.RequireAck {
+		id := make([]byte, 16)
+		_, _ = rand.Read(id)
+		chunk = base64.StdEncoding.EncodeToString(id)
+	}
+
+	// [tag, [entries...], {size, chunk}]
+	msg := appendMsgpackArrayHeader(nil, 3)
+	msg = appendMsgpackString(msg, s.opts.Tag)
+	msg = appendMsgpackArrayHeader(msg, count)
+	msg = append(msg, entries...)
+	if chunk != "" {
+		msg = appendMsgpackMapHeader(msg, 2)
+		msg = appendMsgpackString(msg, "chunk")
+		msg = appendMsgpackString(msg, chunk)
+	} else {
+		msg = appendMsgpackMapHeader(msg, 1)
+	}
+	msg = appendMsgpackString(msg, "size")
+	msg = appendMsgpackInt(msg, int64(count))
+
+	backoff := s.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := s.send(msg, chunk)
+		if err == nil {
+			s.status.success()
+			return
+		}
+		s.disconnect()
+		if s.ctx.Err() != nil || attempt >= s.opts.MaxRetries {
+			s.status.failure(fmtErrorf("fluent sink dropped message of %d records: %w", count, err))
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-s.ctx.Done():
+			s.status.failure(fmtErrorf("fluent sink dropped message of %d records: %w", count, s.ctx.Err()))
+			return
+		}
+	}
+}
+
+// send writes one message, connecting first if needed, and waits for its acknowledgment when chunk is set
+func (s *FluentSink) send(msg []byte, chunk string) error {
+	if s.conn == nil {
+		dialer := net.Dialer{Timeout: s.opts.Timeout}
+		conn, err := dialer.DialContext(s.ctx, s.opts.Network, s.opts.Address)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	_ = s.conn.SetDeadline(time.Now().Add(s.opts.Timeout))
+	if _, err := s.conn.Write(msg); err != nil {
+		return err
+	}
+	if chunk == "" {
+		return nil
+	}
+
+	resp, err := readMsgpack(bufio.NewReader(s.conn))
+	if err != nil {
+		return fmtErrorf("no acknowledgment: %w", err)
+	}
+	if ack, _ := resp.(map[string]any)["ack"].(string); ack != chunk {
+		return fmtErrorf("unexpected acknowledgment %v", resp)
+	}
+	return nil
+}
+
+// disconnect closes the connection, the next message reconnects
+func (s *FluentSink) disconnect() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
\ No newline at end of file
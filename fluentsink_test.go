@@ -0,0 +1,142 @@
+package log
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// forwardInput is a test forward protocol input decoding received messages, acknowledging chunks when asked
+type forwardInput struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	messages [][]any
+}
+
+func newForwardInput(t *testing.T) *forwardInput {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	in := &forwardInput{ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go in.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return in
+}
+
+func (in *forwardInput) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		v, err := readMsgpack(r)
+		if err != nil {
+			return
+		}
+		msg, _ := v.([]any)
+		in.mu.Lock()
+		in.messages = append(in.messages, msg)
+		in.mu.Unlock()
+		if len(msg) == 3 {
+			if chunk, ok := msg[2].(map[string]any)["chunk"].(string); ok {
+				_, _ = conn.Write(appendMsgpackString(appendMsgpackString(appendMsgpackMapHeader(nil, 1), "ack"), chunk))
+			}
+		}
+	}
+}
+
+func (in *forwardInput) snapshot() [][]any {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return append([][]any(nil), in.messages...)
+}
+
+// TestFluentSinkForward verifies records are sent as acknowledged forward mode messages with their fields
+func TestFluentSinkForward(t *testing.T) {
+	in := newForwardInput(t)
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+
+	sink, err := NewFluentSink(FluentSinkOptions{
+		Address:    in.ln.Addr().String(),
+		Tag:        "app.api",
+		BatchSize:  2,
+		RequireAck: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, logger.AddSink("fluent", sink))
+
+	ts := time.Now()
+	logger.Info("request served", "status", 200, "path", "/users", "level", "shadowed")
+	logger.Warn("slow", "latency", 1.5)
+	logger.WithLabels(map[string]string{"service": "api"}).Error("failed")
+	require.NoError(t, logger.Flush(time.Second))
+	require.NoError(t, logger.RemoveSink("fluent"))
+
+	messages := in.snapshot()
+	require.Len(t, messages, 2, "Batches of two records")
+	assert.Equal(t, "app.api", messages[0][0])
+	assert.Equal(t, map[string]any{"size": int64(2), "chunk": messages[0][2].(map[string]any)["chunk"]}, messages[0][2])
+
+	entries := messages[0][1].([]any)
+	require.Len(t, entries, 2)
+	entry := entries[0].([]any)
+	assert.WithinDuration(t, ts, entry[0].(time.Time), time.Second)
+	assert.Equal(t, map[string]any{
+		"level":   "INFO",
+		"message": `"request served" status 200 path /users level shadowed`,
+		"status":  uint64(200),
+		"path":    "/users",
+	}, entry[1])
+	assert.Equal(t, 1.5, entries[1].([]any)[1].(map[string]any)["latency"])
+	failed := messages[1][1].([]any)[0].([]any)[1].(map[string]any)
+	assert.Equal(t, "failed", failed["message"])
+	assert.Equal(t, map[string]any{"service": "api"}, failed["labels"])
+
+	assert.Equal(t, SinkStatusOK, sink.health().Status)
+}
+
+// TestFluentSinkUnavailable verifies failed deliveries are reported and invalid options rejected
+func TestFluentSinkUnavailable(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+
+	_, err := NewFluentSink(FluentSinkOptions{Address: "no-port"})
+	assert.Error(t, err)
+	_, err = NewFluentSink(FluentSinkOptions{Address: "127.0.0.1:24224", Network: "udp"})
+	assert.Error(t, err)
+
+	// A closed port refuses connections
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	sink, err := NewFluentSink(FluentSinkOptions{Address: addr, MaxRetries: -1, FlushInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	require.NoError(t, logger.AddSink("fluent", sink))
+	logger.Info("lost")
+	require.NoError(t, logger.Flush(time.Second))
+
+	// Delivery failures are reported under the registered name
+	require.Eventually(t, func() bool {
+		for _, h := range logger.Stats().Sinks {
+			if h.Name == "fluent" {
+				return h.Status != SinkStatusOK && strings.Contains(h.LastError, "dropped message of 1 records")
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+}
\ No newline at end of file
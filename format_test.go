@@ -2,12 +2,19 @@
 package log
 
 import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/lixenwraith/log/logreader"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -158,4 +165,490 @@ func TestRawSanitizedOutputWithFormatter(t *testing.T) {
 	}, " ")
 
 	assert.Equal(t, expectedOutput, logOutput)
+}
+
+// TestBinaryFormatOutput verifies the binary journal written by the logger can be read back
+func TestBinaryFormatOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=binary"))
+
+	logger.Info("msg", "binary record", "count", 3)
+	logger.Write("raw payload")
+	require.NoError(t, logger.Flush(time.Second))
+
+	var records []logreader.Record
+	require.Eventually(t, func() bool {
+		file, err := os.Open(filepath.Join(tmpDir, "log.log"))
+		if err != nil {
+			return false
+		}
+		defer file.Close()
+		records, err = logreader.NewBinaryReader(file).ReadAll()
+		return err == nil && len(records) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, LevelInfo, records[0].Level)
+	assert.Equal(t, []any{"msg", "binary record", "count", int64(3)}, records[0].Args)
+	assert.NotZero(t, records[1].Flags&FlagRaw, "Raw records keep their flag in the header")
+	assert.Equal(t, []any{"raw payload"}, records[1].Args)
+}
+
+// TestMsgpackFormatOutput verifies MessagePack records and the file header written by the logger decode as maps
+func TestMsgpackFormatOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=msgpack", "file_header=true", "name=packed"))
+
+	before := time.Now()
+	logger.WithLabels(map[string]string{"service": "api"}).Info("served", "status", 200, "ok", true)
+	logger.LogStructured(LevelWarn, "slow", map[string]any{"ms": 1.5})
+	logger.Write("raw payload")
+	require.NoError(t, logger.Flush(time.Second))
+
+	file, err := os.Open(filepath.Join(tmpDir, "packed.log"))
+	require.NoError(t, err)
+	defer file.Close()
+	r := bufio.NewReader(file)
+	var records []map[string]any
+	for {
+		v, err := readMsgpack(r)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		records = append(records, v.(map[string]any))
+	}
+	require.Len(t, records, 4)
+
+	assert.Equal(t, "msgpack", records[0]["log_header"].(map[string]any)["format"])
+	assert.Equal(t, "INFO", records[1]["level"])
+	assert.WithinDuration(t, before, records[1]["time"].(time.Time), time.Second)
+	assert.Equal(t, map[string]any{"service": "api"}, records[1]["labels"])
+	assert.Equal(t, []any{"served", "status", uint64(200), "ok", true}, records[1]["fields"])
+	assert.Equal(t, "slow", records[2]["message"])
+	assert.Equal(t, map[string]any{"ms": 1.5}, records[2]["fields"])
+	assert.Equal(t, map[string]any{"fields": []any{"raw payload"}}, records[3])
+}
+
+// TestCSVFormatOutput verifies csv files start with a row of column names and keep one row per record
+func TestCSVFormatOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=csv", "file_header=true", "name=table",
+		"csv_columns=level, message, extra", "show_timestamp=false"))
+	assert.Error(t, logger.ApplyConfigString("csv_columns=level,host"))
+	assert.Error(t, logger.ApplyConfigString("csv_columns=level,level"))
+
+	logger.Info("served", "status", 200)
+	logger.LogStructured(LevelWarn, "multi\nline", map[string]any{"ms": 1.5})
+	require.NoError(t, logger.Flush(time.Second))
+
+	file, err := os.Open(filepath.Join(tmpDir, "table.log"))
+	require.NoError(t, err)
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"level", "message", "extra"},
+		{"INFO", "served", `{"status":200}`},
+		{"WARN", "multi\nline", `{"ms":1.5}`},
+	}, rows)
+}
+
+// TestCEFFormatOutput verifies CEF lines written by the logger use the configured device and extension keys
+func TestCEFFormatOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=cef", "name=siem", "show_timestamp=false",
+		"cef_vendor=acme", "cef_product_version=1.4", "cef_extension_map=client_ip=src, user=suser"))
+	assert.Error(t, logger.ApplyConfigString("cef_extension_map=user=s_user"))
+	assert.Error(t, logger.ApplyConfigString("cef_extension_map=user"))
+
+	logger.Error("login failed", "user", "alice", "client_ip", "10.0.0.7")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "siem.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "CEF:0|acme|siem|1.4|ERROR|login failed|8|suser=alice src=10.0.0.7\n", string(content))
+}
+
+// TestReservedKeyPolicyOutput verifies reserved_key_policy applies to flattened fields and counts dropped keys
+func TestReservedKeyPolicyOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=json", "auto_kv=true", "show_timestamp=false"))
+	assert.Error(t, logger.ApplyConfigString("reserved_key_policy=rename"))
+
+	logger.Info("level", "debug", "user", "alice")
+	require.NoError(t, logger.Flush(time.Second))
+	require.NoError(t, logger.ApplyConfigString("reserved_key_policy=drop"))
+	logger.Info("time", "now", "user", "bob")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"level":"INFO","field_level":"debug","user":"alice"}`, lines[0])
+	assert.Equal(t, `{"level":"INFO","user":"bob"}`, lines[1])
+	assert.Equal(t, uint64(1), logger.Stats().DroppedReservedKeys)
+}
+
+// TestECSFormatOutput verifies ecs files carry ECS records after a JSON file header
+func TestECSFormatOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=ecs", "file_header=true", "name=elastic"))
+	logger.Warn("disk slow", "span_id", "00f067aa", "latency_ms", 250)
+	// Compat adapters write the message as a leading "msg" pair
+	logger.With("trace_id", "4bf92f35").Info("msg", "adapter", "source", "gnet")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "elastic.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], `{"log_header":`))
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "warn", record["log.level"])
+	assert.Equal(t, "disk slow", record["message"])
+	assert.Equal(t, "00f067aa", record["span.id"])
+	assert.Equal(t, 250.0, record["latency_ms"])
+	assert.Contains(t, record, "@timestamp")
+
+	var adapter map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &adapter))
+	assert.Equal(t, "adapter", adapter["message"])
+	assert.Equal(t, "4bf92f35", adapter["trace.id"])
+	assert.Equal(t, "gnet", adapter["source"])
+	assert.NotContains(t, adapter, "msg")
+}
+
+func TestGCPFormatOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=gcp", "gcp_project=shop-prod", "name=cloud"))
+	logger.Error("charge failed", "trace_id", "4bf92f35", "order", 42)
+	logger.With("order", 7).Info("msg", "adapter", "source", "gnet")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "cloud.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "ERROR", record["severity"])
+	assert.Equal(t, "charge failed", record["message"])
+	assert.Equal(t, "projects/shop-prod/traces/4bf92f35", record["logging.googleapis.com/trace"])
+	assert.Equal(t, map[string]any{"order": 42.0}, record["jsonPayload"])
+	assert.Contains(t, record, "timestamp")
+
+	var adapter map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &adapter))
+	assert.Equal(t, "adapter", adapter["message"])
+	assert.Equal(t, map[string]any{"order": 7.0, "source": "gnet"}, adapter["jsonPayload"])
+}
+
+func TestRFC5424FormatOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=rfc5424", "file_header=true", "name=syslog",
+		"syslog_facility=local0", "syslog_tag=edge", "syslog_sd_id=meta@99999"))
+	logger.Error("upstream failed", "msgid", "PROXY", "status", 502)
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "syslog.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `^<134>1 \S+ \S+ edge \d+ log_header \[meta@99999 schema_version="1" format="rfc5424" name="syslog"`, lines[0])
+	assert.Regexp(t, `^<131>1 \S+ \S+ edge \d+ PROXY \[meta@99999 status="502"\] upstream failed$`, lines[1])
+
+	assert.Error(t, logger.ApplyConfigString("syslog_sd_id=fields"))
+	assert.Error(t, logger.ApplyConfigString("syslog_sd_id=a b@1"))
+}
+
+func TestCustomFormatterOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger, err := NewBuilder().
+		EnableConsole(false).
+		EnableFile(true).
+		Directory(tmpDir).
+		Name("custom").
+		HeartbeatLevel(1).
+		HeartbeatIntervalS(1).
+		HeartbeatFormat("json").
+		CustomFormatter(RecordFormatterFunc(func(r Record) []byte {
+			return fmt.Appendf(nil, "%d|%v\n", r.Level, r.Args)
+		})).
+		Build()
+	require.NoError(t, err)
+	defer logger.Shutdown()
+	require.NoError(t, logger.Start())
+
+	logger.Warn("disk slow", "device", "sda")
+	time.Sleep(1200 * time.Millisecond)
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "custom.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.GreaterOrEqual(t, len(lines), 2)
+	assert.Equal(t, "4|[disk slow device sda]", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "{"), "heartbeats use heartbeat_format")
+}
+
+func TestTxtLayoutOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=txt", "show_timestamp=false", "txt_layout=[{level}] {msg} | {fields}"))
+	logger.Warn("disk slow", "device", "sda")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "[WARN] disk slow | device=sda\n", string(content))
+
+	assert.Error(t, logger.ApplyConfigString("txt_layout={time} {message}"))
+}
+
+func TestOutputSanitization(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=txt", "show_timestamp=false", "sanitization=raw",
+		"sanitization_by_format=txt=txt,json=json", "console_sanitization=shell"))
+	logger.Info("bell\x07")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "\x07", "txt files use the txt policy")
+
+	cfg := logger.GetConfig()
+	assert.Equal(t, PolicyTxt, cfg.sanitization(cfg.FileSanitization, cfg.fileFormat()))
+	assert.Equal(t, PolicyShell, cfg.sanitization(cfg.ConsoleSanitization, cfg.consoleFormat()))
+	assert.Equal(t, PolicyJSON, cfg.sanitization(cfg.NetworkSanitization, "json"))
+	assert.Equal(t, PolicyRaw, cfg.sanitization(cfg.NetworkSanitization, "gelf"))
+
+	assert.Error(t, logger.ApplyConfigString("file_sanitization=strip"))
+	assert.Error(t, logger.ApplyConfigString("sanitization_by_format=yaml=txt"))
+	assert.Error(t, logger.ApplyConfigString("sanitization_by_format=txt"))
+}
+
+func TestJSONFieldsObjectOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=json", "json_fields=object"))
+	logger.Info("user logged in", "user_id", 123)
+	bound := logger.With("request_id", "r1")
+	bound.Info("bound fields", "user_id", 7)
+	bound.WithGroup("db").With("table", "users").Info("grouped fields", "rows", 2)
+	registered, err := NewRegistry().Register("tenant", logger, "tenant_id", "a")
+	require.NoError(t, err)
+	registered.InfoContext(WithTraceContext(context.Background(), "t1", "s1"), "context fields")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 4)
+	records := make([]map[string]any, len(lines))
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &records[i]))
+	}
+	assert.Equal(t, "user logged in", records[0]["message"])
+	assert.Equal(t, map[string]any{"user_id": 123.0}, records[0]["fields"])
+	assert.Equal(t, "bound fields", records[1]["message"])
+	assert.Equal(t, map[string]any{"request_id": "r1", "user_id": 7.0}, records[1]["fields"])
+	assert.Equal(t, "grouped fields", records[2]["message"])
+	assert.Equal(t, map[string]any{"request_id": "r1", "db": map[string]any{"table": "users", "rows": 2.0}}, records[2]["fields"])
+	assert.Equal(t, "context fields", records[3]["message"])
+	assert.Equal(t, map[string]any{"tenant_id": "a", "trace_id": "t1", "span_id": "s1"}, records[3]["fields"])
+
+	// Forwarded records get the target's bound fields after their message
+	target, targetDir := createTestLogger(t)
+	defer target.Shutdown()
+	require.NoError(t, target.ApplyConfigString("format=json", "json_fields=object"))
+	require.NoError(t, logger.ForwardTo(target.With("source", "app"), nil))
+	logger.Warn("forwarded", "code", 3)
+	require.NoError(t, logger.Flush(time.Second))
+	require.NoError(t, target.Flush(time.Second))
+
+	content, err = os.ReadFile(filepath.Join(targetDir, "log.log"))
+	require.NoError(t, err)
+	var forwarded map[string]any
+	require.NoError(t, json.Unmarshal(content, &forwarded))
+	assert.Equal(t, "forwarded", forwarded["message"])
+	assert.Equal(t, map[string]any{"source": "app", "code": 3.0}, forwarded["fields"])
+
+	assert.Error(t, logger.ApplyConfigString("json_fields=map"))
+}
+
+func TestNestedKeysOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=json", "auto_kv=true", "nested_keys=true"))
+	logger.Info("http.method", "GET", "http.status", 200)
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(content, &record))
+	assert.Equal(t, map[string]any{"method": "GET", "status": 200.0}, record["http"])
+}
+
+func TestJSONKeysOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("format=json", "field_time_key=@timestamp", "field_level_key=severity"))
+	logger.Info("started")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(content, &record))
+	assert.Equal(t, "INFO", record["severity"])
+	assert.Contains(t, record, "@timestamp")
+	assert.NotContains(t, record, "time")
+
+	assert.Error(t, logger.ApplyConfigString("field_trace_key=severity"))
+	assert.Error(t, logger.ApplyConfigString("field_fields_key=labels"))
+	assert.Error(t, logger.ApplyConfigString("field_message_key="))
+}
+
+// TestBoundFieldsOutput verifies fields bound with With follow the message, so formats telling the message apart
+// from the key-value pairs still find it
+func TestBoundFieldsOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		config []string
+		check  func(t *testing.T, record map[string]any)
+	}{
+		{
+			name:   "json fields object",
+			config: []string{"format=json", "json_fields=object"},
+			check: func(t *testing.T, record map[string]any) {
+				assert.Equal(t, "cache miss", record["message"])
+				assert.Equal(t, map[string]any{"request_id": "r1", "key": "k1"}, record["fields"])
+			},
+		},
+		{
+			name:   "ecs",
+			config: []string{"format=ecs"},
+			check: func(t *testing.T, record map[string]any) {
+				assert.Equal(t, "cache miss", record["message"])
+				assert.Equal(t, "r1", record["request_id"])
+				assert.Equal(t, "k1", record["key"])
+			},
+		},
+		{
+			name:   "gcp",
+			config: []string{"format=gcp"},
+			check: func(t *testing.T, record map[string]any) {
+				assert.Equal(t, "cache miss", record["message"])
+				assert.Equal(t, map[string]any{"request_id": "r1", "key": "k1"}, record["jsonPayload"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, tmpDir := createTestLogger(t)
+			defer logger.Shutdown()
+
+			require.NoError(t, logger.ApplyConfigString(tt.config...))
+			logger.With("request_id", "r1").Info("cache miss", "key", "k1")
+			require.NoError(t, logger.Flush(time.Second))
+
+			content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+			require.NoError(t, err)
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(content, &record))
+			tt.check(t, record)
+		})
+	}
+}
+
+// TestContextFieldsOutput verifies the fields carried by a context follow the message in each message-detecting format
+func TestContextFieldsOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		config []string
+		check  func(t *testing.T, record map[string]any)
+	}{
+		{
+			name:   "json fields object",
+			config: []string{"format=json", "json_fields=object"},
+			check: func(t *testing.T, record map[string]any) {
+				assert.Equal(t, "ctx msg", record["message"])
+				assert.Equal(t, map[string]any{"trace_id": "t1", "span_id": "s1", "user": 7.0, "k": 1.0}, record["fields"])
+			},
+		},
+		{
+			name:   "ecs",
+			config: []string{"format=ecs"},
+			check: func(t *testing.T, record map[string]any) {
+				assert.Equal(t, "ctx msg", record["message"])
+				assert.Equal(t, "t1", record["trace.id"])
+				assert.Equal(t, "s1", record["span.id"])
+				assert.Equal(t, 7.0, record["user"])
+				assert.Equal(t, 1.0, record["k"])
+			},
+		},
+		{
+			name:   "gcp",
+			config: []string{"format=gcp"},
+			check: func(t *testing.T, record map[string]any) {
+				assert.Equal(t, "ctx msg", record["message"])
+				assert.Equal(t, "t1", record["logging.googleapis.com/trace"])
+				assert.Equal(t, "s1", record["logging.googleapis.com/spanId"])
+				assert.Equal(t, map[string]any{"user": 7.0, "k": 1.0}, record["jsonPayload"])
+			},
+		},
+		{
+			name:   "json auto kv",
+			config: []string{"format=json", "auto_kv=true"},
+			check: func(t *testing.T, record map[string]any) {
+				assert.Equal(t, []any{"ctx msg", "trace_id", "t1", "span_id", "s1", "user", 7.0, "k", 1.0}, record["fields"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, tmpDir := createTestLogger(t)
+			defer logger.Shutdown()
+
+			require.NoError(t, logger.ApplyConfigString(tt.config...))
+			ctx := WithContextFields(WithTraceContext(context.Background(), "t1", "s1"), "user", 7)
+			logger.InfoContext(ctx, "ctx msg", "k", 1)
+			require.NoError(t, logger.Flush(time.Second))
+
+			content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+			require.NoError(t, err)
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(content, &record))
+			tt.check(t, record)
+		})
+	}
 }
\ No newline at end of file
@@ -0,0 +1,147 @@
+package formatter
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Binary journal record layout, all fixed-width integers little-endian:
+//
+//	uint32  length of the remainder of the record
+//	int64   timestamp, unix nanoseconds
+//	int16   level
+//	uint8   flags
+//	uvarint trace length, followed by trace bytes
+//	uvarint argument count, followed by tagged arguments
+//
+// Each argument is a one-byte tag followed by its payload
+const (
+	BinaryTagNil    byte = iota // No payload
+	BinaryTagString             // uvarint length + bytes
+	BinaryTagInt                // zigzag varint
+	BinaryTagUint               // uvarint
+	BinaryTagFloat              // 8-byte IEEE 754 bits
+	BinaryTagBool               // 1 byte, 0 or 1
+	BinaryTagBytes              // uvarint length + bytes
+	BinaryTagTime               // int64 unix nanoseconds
+)
+
+// BinaryHeaderSize is the fixed size of the record header following the length prefix
+const BinaryHeaderSize = 8 + 2 + 1
+
+// formatBinary encodes a record in the length-prefixed binary journal format
+// Values are stored losslessly without sanitization, unsupported types are stored as strings
+func (f *Formatter) formatBinary(flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
+	f.buf = append(f.buf, 0, 0, 0, 0) // Length placeholder
+	f.buf = binary.LittleEndian.AppendUint64(f.buf, uint64(timestamp.UnixNano()))
+	f.buf = binary.LittleEndian.AppendUint16(f.buf, uint16(int16(level)))
+	f.buf = append(f.buf, byte(flags))
+
+	f.buf = binary.AppendUvarint(f.buf, uint64(len(trace)))
+	f.buf = append(f.buf, trace...)
+
+	f.buf = binary.AppendUvarint(f.buf, uint64(len(args)))
+	for _, arg := range args {
+		f.appendBinaryValue(arg)
+	}
+
+	binary.LittleEndian.PutUint32(f.buf[0:4], uint32(len(f.buf)-4))
+	return f.buf
+}
+
+// appendBinaryValue appends a single tagged argument
+func (f *Formatter) appendBinaryValue(v any) {
+	switch val := v.(type) {
+	case nil:
+		f.buf = append(f.buf, BinaryTagNil)
+	case string:
+		f.appendBinaryString(BinaryTagString, val)
+	case []byte:
+		if val == nil {
+			f.buf = append(f.buf, BinaryTagNil)
+			return
+		}
+		f.buf = append(f.buf, BinaryTagBytes)
+		f.buf = binary.AppendUvarint(f.buf, uint64(len(val)))
+		f.buf = append(f.buf, val...)
+	case int:
+		f.appendBinaryInt(int64(val))
+	case int8:
+		f.appendBinaryInt(int64(val))
+	case int16:
+		f.appendBinaryInt(int64(val))
+	case int32:
+		f.appendBinaryInt(int64(val))
+	case int64:
+		f.appendBinaryInt(val)
+	case uint:
+		f.appendBinaryUint(uint64(val))
+	case uint8:
+		f.appendBinaryUint(uint64(val))
+	case uint16:
+		f.appendBinaryUint(uint64(val))
+	case uint32:
+		f.appendBinaryUint(uint64(val))
+	case uint64:
+		f.appendBinaryUint(val)
+	case float32:
+		f.buf = append(f.buf, BinaryTagFloat)
+		f.buf = binary.LittleEndian.AppendUint64(f.buf, math.Float64bits(float64(val)))
+	case float64:
+		f.buf = append(f.buf, BinaryTagFloat)
+		f.buf = binary.LittleEndian.AppendUint64(f.buf, math.Float64bits(val))
+	case bool:
+		b := byte(0)
+		if val {
+			b = 1
+		}
+		f.buf = append(f.buf, BinaryTagBool, b)
+	case json.RawMessage:
+		if val == nil {
+			f.buf = append(f.buf, BinaryTagNil)
+			return
+		}
+		f.appendBinaryString(BinaryTagString, string(val))
+	case time.Time:
+		f.buf = append(f.buf, BinaryTagTime)
+		f.buf = binary.LittleEndian.AppendUint64(f.buf, uint64(val.UnixNano()))
+	default:
+		// Typed nils are stored like an untyped nil, other values as their string form
+		if isNilValue(val) {
+			f.buf = append(f.buf, BinaryTagNil)
+			return
+		}
+		switch val := val.(type) {
+		case RawJSON:
+			f.appendBinaryString(BinaryTagString, string(val.RawJSON()))
+		case error:
+			f.appendBinaryString(BinaryTagString, val.Error())
+		case fmt.Stringer:
+			f.appendBinaryString(BinaryTagString, val.String())
+		default:
+			f.appendBinaryString(BinaryTagString, fmt.Sprintf("%+v", val))
+		}
+	}
+}
+
+// appendBinaryString appends a length-prefixed string with the given tag
+func (f *Formatter) appendBinaryString(tag byte, s string) {
+	f.buf = append(f.buf, tag)
+	f.buf = binary.AppendUvarint(f.buf, uint64(len(s)))
+	f.buf = append(f.buf, s...)
+}
+
+// appendBinaryInt appends a zigzag varint
+func (f *Formatter) appendBinaryInt(n int64) {
+	f.buf = append(f.buf, BinaryTagInt)
+	f.buf = binary.AppendVarint(f.buf, n)
+}
+
+// appendBinaryUint appends an unsigned varint
+func (f *Formatter) appendBinaryUint(n uint64) {
+	f.buf = append(f.buf, BinaryTagUint)
+	f.buf = binary.AppendUvarint(f.buf, n)
+}
\ No newline at end of file
@@ -0,0 +1,148 @@
+package formatter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// cefVersion is the CEF format version written in the header
+const cefVersion = "CEF:0"
+
+// CEFDevice sets the Device Vendor, Device Product, and Device Version header fields of CEF records
+func (f *Formatter) CEFDevice(vendor, product, version string) *Formatter {
+	f.cefVendor, f.cefProduct, f.cefVersion = vendor, product, version
+	return f
+}
+
+// CEFExtensionMap sets the CEF extension keys written for argument keys and labels, e.g. "client_ip" -> "src"
+// Unmapped keys are written with the characters CEF allows in keys
+func (f *Formatter) CEFExtensionMap(keys map[string]string) *Formatter {
+	f.cefKeys = keys
+	return f
+}
+
+// formatCEF renders a record as an ArcSight Common Event Format line:
+//
+//	CEF:0|vendor|product|version|level name|message|severity|rt=<unix ms> key=value ...
+//
+// Key-value pairs, structured fields, the trace, and labels become extension fields, raw records only set the name
+func (f *Formatter) formatCEF(flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
+	message, fields := gelfSplit(flags, args)
+	plain := sanitizer.NewSerializer("raw", f.sanitizer)
+
+	var text []byte
+	for i, arg := range message {
+		f.convertValue(&text, arg, plain, i > 0)
+	}
+	name := string(text)
+	if name == "" {
+		name = "-"
+	}
+
+	f.buf = append(f.buf, cefVersion...)
+	for _, header := range []string{f.cefVendor, f.cefProduct, f.cefVersion, LevelToString(level), name} {
+		f.buf = append(f.buf, '|')
+		f.buf = appendCEFHeader(f.buf, header)
+	}
+	f.buf = append(f.buf, '|')
+	f.buf = strconv.AppendInt(f.buf, cefSeverity(level), 10)
+	f.buf = append(f.buf, '|')
+
+	needsSpace := false
+	extension := func(key, value string) {
+		if mapped, ok := f.cefKeys[key]; ok {
+			key = mapped
+		} else {
+			key = cefKeyName(key)
+		}
+		if key == "" {
+			return
+		}
+		if needsSpace {
+			f.buf = append(f.buf, ' ')
+		}
+		f.buf = append(f.buf, key...)
+		f.buf = append(f.buf, '=')
+		f.buf = appendCEFValue(f.buf, value)
+		needsSpace = true
+	}
+
+	if flags&FlagShowTimestamp != 0 && flags&FlagRaw == 0 {
+		extension("rt", strconv.FormatInt(timestamp.UnixMilli(), 10))
+	}
+	if trace != "" {
+		extension("trace", f.sanitizer.Sanitize(trace))
+	}
+	for i := 0; i+1 < len(fields); i += 2 {
+		var value []byte
+		f.convertValue(&value, fields[i+1], plain, false)
+		extension(fields[i].(string), string(value))
+	}
+	for _, key := range f.labelKeys() {
+		extension(key, f.sanitizer.Sanitize(f.labels[key]))
+	}
+
+	f.buf = append(f.buf, '\n')
+	return f.buf
+}
+
+// cefSeverity maps log levels to the CEF severity scale of 0 to 10, heartbeats are low
+func cefSeverity(level int64) int64 {
+	switch {
+	case level >= 12:
+		return 3
+	case level >= 8:
+		return 8
+	case level >= 4:
+		return 6
+	case level >= 0:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// cefKeyName reduces a key to the letters and digits CEF allows in extension keys
+func cefKeyName(key string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, key)
+}
+
+// appendCEFHeader appends a header field with '\' and '|' escaped, line breaks become spaces
+func appendCEFHeader(buf []byte, value string) []byte {
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\', '|':
+			buf = append(buf, '\\', c)
+		case '\r', '\n':
+			buf = append(buf, ' ')
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
+
+// appendCEFValue appends an extension value with '\' and '=' escaped and line breaks written as \n and \r
+func appendCEFValue(buf []byte, value string) []byte {
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\', '=':
+			buf = append(buf, '\\', c)
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
\ No newline at end of file
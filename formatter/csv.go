@@ -0,0 +1,101 @@
+package formatter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// CSVColumnNames are the column names accepted by the csv format
+//
+//	time     timestamp in the configured format, empty when timestamps are hidden
+//	level    level name, empty when levels are hidden
+//	trace    function trace, empty when absent
+//	message  arguments that are not key-value pairs, as space-separated text
+//	extra    key-value pairs and structured fields as a JSON object, empty when there are none
+//	labels   labels of the record as a JSON object, empty when there are none
+var CSVColumnNames = []string{"time", "level", "trace", "message", "extra", "labels"}
+
+// DefaultCSVColumns is the column order used when none is configured
+var DefaultCSVColumns = []string{"time", "level", "trace", "message", "extra"}
+
+// CSVColumns sets the columns of csv records and their order, empty keeps the default
+func (f *Formatter) CSVColumns(columns []string) *Formatter {
+	if len(columns) > 0 {
+		f.csvColumns = columns
+	}
+	return f
+}
+
+// formatCSV renders a record as one RFC 4180 row in the configured column order, raw records fill the message column
+// Fields holding commas, quotes, or line breaks are quoted with inner quotes doubled, rows end with '\n'
+func (f *Formatter) formatCSV(flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
+	message, fields := gelfSplit(flags, args)
+	plain := sanitizer.NewSerializer("raw", f.sanitizer)
+	structured := sanitizer.NewSerializer("json", f.sanitizer)
+
+	var field []byte
+	for i, column := range f.csvColumns {
+		if i > 0 {
+			f.buf = append(f.buf, ',')
+		}
+		field = field[:0]
+		switch column {
+		case "time":
+			if flags&FlagShowTimestamp != 0 && flags&FlagRaw == 0 {
+				field = timestamp.AppendFormat(field, f.timestampFormat)
+			}
+		case "level":
+			if flags&FlagShowLevel != 0 && flags&FlagRaw == 0 {
+				field = append(field, LevelToString(level)...)
+			}
+		case "trace":
+			field = append(field, f.sanitizer.Sanitize(trace)...)
+		case "message":
+			for j, arg := range message {
+				f.convertValue(&field, arg, plain, j > 0)
+			}
+		case "extra":
+			if len(fields) > 0 {
+				field = append(field, '{')
+				for j := 0; j+1 < len(fields); j += 2 {
+					if j > 0 {
+						field = append(field, ',')
+					}
+					structured.WriteString(&field, fields[j].(string))
+					field = append(field, ':')
+					f.convertValue(&field, fields[j+1], structured, false)
+				}
+				field = append(field, '}')
+			}
+		case "labels":
+			if len(f.labels) > 0 {
+				field = append(field, '{')
+				for j, key := range f.labelKeys() {
+					if j > 0 {
+						field = append(field, ',')
+					}
+					structured.WriteString(&field, key)
+					field = append(field, ':')
+					structured.WriteString(&field, f.labels[key])
+				}
+				field = append(field, '}')
+			}
+		}
+		f.buf = appendCSVField(f.buf, string(field))
+	}
+
+	f.buf = append(f.buf, '\n')
+	return f.buf
+}
+
+// appendCSVField appends a field, quoted when it holds a comma, quote, or line break, or starts or ends with a space
+func appendCSVField(buf []byte, field string) []byte {
+	if field == "" || (!strings.ContainsAny(field, ",\"\r\n") && field[0] != ' ' && field[len(field)-1] != ' ') {
+		return append(buf, field...)
+	}
+	buf = append(buf, '"')
+	buf = append(buf, strings.ReplaceAll(field, `"`, `""`)...)
+	return append(buf, '"')
+}
\ No newline at end of file
@@ -0,0 +1,115 @@
+package formatter
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// ecsVersion is the Elastic Common Schema version declared by ecs records
+const ecsVersion = "8.11.0"
+
+// ecsReservedKeys are the top-level keys ecs records write themselves
+var ecsReservedKeys = []string{"@timestamp", "log.level", "message", "ecs.version", "log.origin.function", "labels"}
+
+// ecsFieldNames maps conventional field keys to their ECS names
+var ecsFieldNames = map[string]string{
+	"err":            "error.message",
+	"error":          "error.message",
+	"trace_id":       "trace.id",
+	"span_id":        "span.id",
+	"transaction_id": "transaction.id",
+}
+
+// formatECS renders a record as a JSON object following the Elastic Common Schema
+// Errors among the message arguments become error.message, and keys listed in ecsFieldNames are renamed unless
+// their ECS name is already written
+func (f *Formatter) formatECS(flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
+	serializer := sanitizer.NewSerializer("json", f.sanitizer)
+	message, fields := ecsSplit(flags, args)
+
+	plain := sanitizer.NewSerializer("raw", f.sanitizer)
+	var text []byte
+	var errText string
+	for _, arg := range message {
+		if err, ok := arg.(error); ok && !isNilValue(err) && errText == "" {
+			errText = err.Error()
+			continue
+		}
+		f.convertValue(&text, arg, plain, len(text) > 0)
+	}
+
+	f.buf = append(f.buf, '{')
+	needsComma := false
+	key := func(name string) {
+		if needsComma {
+			f.buf = append(f.buf, ',')
+		}
+		serializer.WriteString(&f.buf, name)
+		f.buf = append(f.buf, ':')
+		needsComma = true
+	}
+
+	if flags&FlagShowTimestamp != 0 {
+		key("@timestamp")
+		f.buf = append(f.buf, '"')
+		f.buf = timestamp.UTC().AppendFormat(f.buf, time.RFC3339Nano)
+		f.buf = append(f.buf, '"')
+	}
+	if flags&FlagShowLevel != 0 {
+		key("log.level")
+		serializer.WriteString(&f.buf, strings.ToLower(LevelToString(level)))
+	}
+	if len(text) > 0 {
+		key("message")
+		serializer.WriteString(&f.buf, string(text))
+	}
+	key("ecs.version")
+	serializer.WriteString(&f.buf, ecsVersion)
+	if trace != "" {
+		key("log.origin.function")
+		serializer.WriteString(&f.buf, trace)
+	}
+	if len(f.labels) > 0 {
+		if needsComma {
+			f.buf = append(f.buf, ',')
+		}
+		f.appendLabelObject(serializer)
+	}
+
+	written := make([]string, 0, 2)
+	if errText != "" {
+		key("error.message")
+		serializer.WriteString(&f.buf, errText)
+		written = append(written, "error.message")
+	}
+	fields = f.resolveReserved(fields, ecsReservedKeys)
+	members := make([]any, 0, len(fields))
+	for i := 0; i+1 < len(fields); i += 2 {
+		name := fields[i].(string)
+		if renamed, ok := ecsFieldNames[name]; ok && !slices.Contains(written, renamed) {
+			name = renamed
+			written = append(written, renamed)
+		}
+		members = append(members, name, fields[i+1])
+	}
+	f.appendMembers(members, ecsReservedKeys, serializer, needsComma)
+
+	f.buf = append(f.buf, '}', '\n')
+	return f.buf
+}
+
+// ecsSplit separates the message arguments from the trailing key-value pairs, structured records as for GELF
+// The value of a leading "msg" pair written by the compat adapters is the message
+func ecsSplit(flags int64, args []any) (message []any, fields []any) {
+	if flags&FlagStructuredJSON != 0 {
+		return gelfSplit(flags, args)
+	}
+	start := MessageLen(args)
+	if start == 2 && args[0] == "msg" {
+		return args[1:2], args[2:]
+	}
+	return args[:start], args[start:]
+}
\ No newline at end of file
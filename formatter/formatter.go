@@ -1,8 +1,12 @@
 package formatter
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"slices"
 	"strconv"
 	"time"
 	"unicode/utf8"
@@ -16,9 +20,19 @@ const (
 	FlagShowTimestamp  int64 = 0b0010
 	FlagShowLevel      int64 = 0b0100
 	FlagStructuredJSON int64 = 0b1000
+	FlagNestedKeys     int64 = 0b10000 // Expands the record's dotted keys as NestedKeys does, set for grouped loggers
 	FlagDefault              = FlagShowTimestamp | FlagShowLevel
 )
 
+// RawJSON is implemented by values holding already serialized JSON, embedded verbatim in json output instead of
+// being escaped as a string; json.RawMessage is handled the same way, other formats write the JSON text as a string
+type RawJSON interface {
+	RawJSON() []byte
+}
+
+// ansiReset ends a color set by LevelColor
+const ansiReset = "\x1b[0m"
+
 // Formatter manages the buffered writing and formatting of log entries
 type Formatter struct {
 	sanitizer       *sanitizer.Sanitizer
@@ -26,6 +40,31 @@ type Formatter struct {
 	timestampFormat string
 	showTimestamp   bool
 	showLevel       bool
+	autoKV          bool
+	jsonFields      string
+	nestedKeys      bool
+	nestRecord      bool // The record being formatted has FlagNestedKeys
+	jsonKeys        JSONKeyNames
+	bytesEncoding   string
+	durationFormat  string
+	timeValueFormat string
+	host            string
+	levelColor      func(level int64) string
+	csvColumns      []string
+	txtLayout       []layoutPart // Custom txt layout, nil for the built-in one
+	cefVendor       string
+	cefProduct      string
+	cefVersion      string
+	cefKeys         map[string]string
+	gcpProject      string
+	syslogFacility  int
+	syslogHost      string
+	syslogApp       string
+	syslogProcID    string
+	syslogSDID      string
+	reservedPolicy  string
+	droppedKey      func(key string)
+	labels          map[string]string // Labels of the record being formatted, set by FormatLabeled
 	buf             []byte
 }
 
@@ -43,11 +82,20 @@ func New(s ...*sanitizer.Sanitizer) *Formatter {
 		timestampFormat: time.RFC3339Nano,
 		showTimestamp:   true,
 		showLevel:       true,
+		bytesEncoding:   "string",
+		durationFormat:  "string",
+		timeValueFormat: "timestamp",
+		jsonFields:      "array",
+		jsonKeys:        DefaultJSONKeys,
+		csvColumns:      DefaultCSVColumns,
+		reservedPolicy:  "prefix",
+		syslogFacility:  1, // user
+		syslogSDID:      "fields@32473",
 		buf:             make([]byte, 0, 1024),
 	}
 }
 
-// Type sets the output format ("txt", "json", or "raw")
+// Type sets the output format ("txt", "pretty", "logfmt", "json", "gelf", "ecs", "gcp", "raw", "binary", "msgpack", "csv", "cef", or "rfc5424")
 func (f *Formatter) Type(format string) *Formatter {
 	f.format = format
 	return f
@@ -73,6 +121,59 @@ func (f *Formatter) ShowTimestamp(show bool) *Formatter {
 	return f
 }
 
+// AutoKV sets whether JSON output flattens args forming string-key/value pairs into top-level fields
+func (f *Formatter) AutoKV(enable bool) *Formatter {
+	f.autoKV = enable
+	return f
+}
+
+// JSONFields sets how JSON output writes args not flattened by AutoKV: "array" as a positional fields array,
+// "object" as a message and a fields object unless the args after the message repeat a key
+func (f *Formatter) JSONFields(mode string) *Formatter {
+	f.jsonFields = mode
+	return f
+}
+
+// BytesEncoding sets how []byte arguments are rendered in text formats: "string" passes them through the
+// sanitizer as text, "hex" and "base64" encode them losslessly. Binary records keep the raw bytes
+// Fields of structured JSON records are written as hex with "hex" and as base64 otherwise, at any depth
+func (f *Formatter) BytesEncoding(encoding string) *Formatter {
+	if encoding != "" {
+		f.bytesEncoding = encoding
+	}
+	return f
+}
+
+// Host sets the host field of GELF messages, defaults to the machine hostname
+func (f *Formatter) Host(host string) *Formatter {
+	if host != "" {
+		f.host = host
+	}
+	return f
+}
+
+// ReservedKeyPolicy sets how key-value pairs written as top-level keys handle keys the format writes itself:
+// "prefix" renames them to "field_{key}", "drop" leaves them out, and "allow" writes them as they are
+func (f *Formatter) ReservedKeyPolicy(policy string) *Formatter {
+	if policy != "" {
+		f.reservedPolicy = policy
+	}
+	return f
+}
+
+// OnDroppedKey sets a function called for each key left out by the "drop" reserved key policy
+func (f *Formatter) OnDroppedKey(dropped func(key string)) *Formatter {
+	f.droppedKey = dropped
+	return f
+}
+
+// LevelColor sets a function returning the ANSI color sequence wrapped around the txt and pretty level token, ""
+// leaves it plain
+func (f *Formatter) LevelColor(color func(level int64) string) *Formatter {
+	f.levelColor = color
+	return f
+}
+
 // Format formats a log entry using configured options and explicit flags
 func (f *Formatter) Format(flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
 	// Override configured values with explicit flags
@@ -94,6 +195,37 @@ func (f *Formatter) Format(flags int64, timestamp time.Time, level int64, trace
 // FormatWithOptions formats with explicit format and flags, ignoring configured values
 func (f *Formatter) FormatWithOptions(format string, flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
 	f.Reset()
+	f.nestRecord = flags&FlagNestedKeys != 0
+
+	// Binary records are always framed, FlagRaw is preserved in the record header
+	if format == "binary" {
+		return f.formatBinary(flags, timestamp, level, trace, args)
+	}
+
+	// MessagePack records are always framed, raw records keep their arguments as fields
+	if format == "msgpack" {
+		return f.formatMsgpack(flags, timestamp, level, trace, args)
+	}
+
+	// CSV records are always complete rows, raw records fill the message column
+	if format == "csv" {
+		return f.formatCSV(flags, timestamp, level, trace, args)
+	}
+
+	// CEF lines always carry the level as severity, raw records become the event name
+	if format == "cef" {
+		return f.formatCEF(flags, timestamp, level, trace, args)
+	}
+
+	// RFC 5424 lines always carry the PRI header, raw records only set the MSG
+	if format == "rfc5424" {
+		return f.formatRFC5424(flags, timestamp, level, trace, args)
+	}
+
+	// GELF messages always carry timestamp and level, raw records become their short_message
+	if format == "gelf" {
+		return f.formatGELF(flags, timestamp, level, trace, args)
+	}
 
 	// FlagRaw completely bypasses formatting and sanitization
 	if flags&FlagRaw != 0 {
@@ -101,7 +233,11 @@ func (f *Formatter) FormatWithOptions(format string, flags int64, timestamp time
 			if i > 0 {
 				f.buf = append(f.buf, ' ')
 			}
-			// Direct conversion without sanitization
+			// Direct conversion without sanitization, nil values are never asked to format themselves
+			if arg == nil || isNilValue(arg) {
+				f.buf = append(f.buf, "nil"...)
+				continue
+			}
 			switch v := arg.(type) {
 			case string:
 				f.buf = append(f.buf, v...)
@@ -132,8 +268,20 @@ func (f *Formatter) FormatWithOptions(format string, flags int64, timestamp time
 	case "json":
 		return f.formatJSON(flags, timestamp, level, trace, args, serializer)
 
+	case "ecs":
+		return f.formatECS(flags, timestamp, level, trace, args)
+
+	case "gcp":
+		return f.formatGCP(flags, timestamp, level, trace, args)
+
 	case "txt":
 		return f.formatTxt(flags, timestamp, level, trace, args, serializer)
+
+	case "pretty":
+		return f.formatPretty(flags, timestamp, level, trace, args)
+
+	case "logfmt":
+		return f.formatLogfmt(flags, timestamp, level, trace, args)
 	}
 
 	return nil // forcing panic on unrecognized format
@@ -195,7 +343,32 @@ func (f *Formatter) convertValue(buf *[]byte, v any, serializer *sanitizer.Seria
 		serializer.WriteString(buf, val)
 
 	case []byte:
-		serializer.WriteString(buf, string(val))
+		if val == nil {
+			serializer.WriteNil(buf)
+			return
+		}
+		switch f.bytesEncoding {
+		case "hex":
+			serializer.WriteString(buf, hex.EncodeToString(val))
+		case "base64":
+			serializer.WriteString(buf, base64.StdEncoding.EncodeToString(val))
+		default:
+			serializer.WriteString(buf, string(val))
+		}
+
+	case json.RawMessage:
+		if val == nil {
+			serializer.WriteNil(buf)
+			return
+		}
+		serializer.WriteRawJSON(buf, val)
+
+	case RawJSON:
+		if isNilValue(val) {
+			serializer.WriteNil(buf)
+			return
+		}
+		serializer.WriteRawJSON(buf, val.RawJSON())
 
 	case rune:
 		var runeStr [utf8.UTFMax]byte
@@ -233,27 +406,124 @@ func (f *Formatter) convertValue(buf *[]byte, v any, serializer *sanitizer.Seria
 		serializer.WriteNil(buf)
 
 	case time.Time:
-		timeStr := val.Format(f.timestampFormat)
-		serializer.WriteString(buf, timeStr)
+		f.writeTimeValue(buf, val, serializer)
+
+	case time.Duration:
+		f.writeDuration(buf, val, serializer)
+
+	case []error:
+		if val == nil {
+			serializer.WriteNil(buf)
+			return
+		}
+		f.writeErrors(buf, val, serializer)
 
 	case error:
+		if isNilValue(val) {
+			serializer.WriteNil(buf)
+			return
+		}
+		// Joined errors keep their individual messages
+		if multi, ok := val.(interface{ Unwrap() []error }); ok {
+			f.writeErrors(buf, multi.Unwrap(), serializer)
+			return
+		}
 		serializer.WriteString(buf, val.Error())
 
 	case fmt.Stringer:
+		if isNilValue(val) {
+			serializer.WriteNil(buf)
+			return
+		}
 		serializer.WriteString(buf, val.String())
 
 	default:
+		if isNilValue(val) {
+			serializer.WriteNil(buf)
+			return
+		}
 		serializer.WriteComplex(buf, val)
 	}
 }
 
+// writeErrors writes errors as an array of their messages, nil errors as nil
+func (f *Formatter) writeErrors(buf *[]byte, errs []error, serializer *sanitizer.Serializer) {
+	serializer.WriteArray(buf, len(errs), func(i int) {
+		if errs[i] == nil || isNilValue(errs[i]) {
+			serializer.WriteNil(buf)
+			return
+		}
+		serializer.WriteString(buf, errs[i].Error())
+	})
+}
+
+// isNilValue reports whether v holds a typed nil pointer, map, slice, interface, channel, or function
+// Such values render like an untyped nil in every format instead of through their type's formatting
+func isNilValue(v any) bool {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// hexBytes returns v with the []byte values of nested maps and slices replaced by their hex strings
+// Maps and slices are copied only when they hold bytes, leaving the caller's values untouched
+func hexBytes(v any) any {
+	if !holdsBytes(v) {
+		return v
+	}
+	switch val := v.(type) {
+	case []byte:
+		return hex.EncodeToString(val)
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = hexBytes(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = hexBytes(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// holdsBytes reports whether v is a non-nil []byte or a map or slice holding one at any depth
+func holdsBytes(v any) bool {
+	switch val := v.(type) {
+	case []byte:
+		return val != nil
+	case map[string]any:
+		for _, item := range val {
+			if holdsBytes(item) {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range val {
+			if holdsBytes(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // formatJSON unifies JSON output
 func (f *Formatter) formatJSON(flags int64, timestamp time.Time, level int64, trace string, args []any, serializer *sanitizer.Serializer) []byte {
 	f.buf = append(f.buf, '{')
 	needsComma := false
 
 	if flags&FlagShowTimestamp != 0 {
-		f.buf = append(f.buf, `"time":"`...)
+		f.appendJSONKey(f.jsonKeys.Time, serializer)
+		f.buf = append(f.buf, '"')
 		f.buf = timestamp.AppendFormat(f.buf, f.timestampFormat)
 		f.buf = append(f.buf, '"')
 		needsComma = true
@@ -263,7 +533,8 @@ func (f *Formatter) formatJSON(flags int64, timestamp time.Time, level int64, tr
 		if needsComma {
 			f.buf = append(f.buf, ',')
 		}
-		f.buf = append(f.buf, `"level":"`...)
+		f.appendJSONKey(f.jsonKeys.Level, serializer)
+		f.buf = append(f.buf, '"')
 		f.buf = append(f.buf, LevelToString(level)...)
 		f.buf = append(f.buf, '"')
 		needsComma = true
@@ -273,11 +544,19 @@ func (f *Formatter) formatJSON(flags int64, timestamp time.Time, level int64, tr
 		if needsComma {
 			f.buf = append(f.buf, ',')
 		}
-		f.buf = append(f.buf, `"trace":`...)
+		f.appendJSONKey(f.jsonKeys.Trace, serializer)
 		serializer.WriteString(&f.buf, trace)
 		needsComma = true
 	}
 
+	if len(f.labels) > 0 {
+		if needsComma {
+			f.buf = append(f.buf, ',')
+		}
+		f.appendLabelObject(serializer)
+		needsComma = true
+	}
+
 	// Handle structured JSON if flag is set and args match pattern
 	if flags&FlagStructuredJSON != 0 && len(args) >= 2 {
 		if message, ok := args[0].(string); ok {
@@ -285,13 +564,18 @@ func (f *Formatter) formatJSON(flags int64, timestamp time.Time, level int64, tr
 				if needsComma {
 					f.buf = append(f.buf, ',')
 				}
-				f.buf = append(f.buf, `"message":`...)
+				f.appendJSONKey(f.jsonKeys.Message, serializer)
 				serializer.WriteString(&f.buf, message)
 
 				f.buf = append(f.buf, ',')
-				f.buf = append(f.buf, `"fields":`...)
+				f.appendJSONKey(f.jsonKeys.Fields, serializer)
 
-				marshaledFields, err := json.Marshal(fields)
+				// encoding/json writes []byte as base64, hex is applied before marshaling
+				var marshaled any = fields
+				if f.bytesEncoding == "hex" {
+					marshaled = hexBytes(fields)
+				}
+				marshaledFields, err := json.Marshal(marshaled)
 				if err != nil {
 					f.buf = append(f.buf, `{"_marshal_error":"`...)
 					serializer.WriteString(&f.buf, err.Error())
@@ -306,12 +590,31 @@ func (f *Formatter) formatJSON(flags int64, timestamp time.Time, level int64, tr
 		}
 	}
 
+	// Flat key-value fields when args pair up cleanly, keys the record writes itself follow the reserved key policy
+	if f.autoKV && isKVPairs(args) {
+		reserved := f.jsonReservedKeys()
+		args = f.resolveReserved(args, reserved)
+		f.appendMembers(args, reserved, serializer, needsComma)
+		f.buf = append(f.buf, '}', '\n')
+		return f.buf
+	}
+
+	// A fields object after the message when the trailing args pair up, told apart from the message as for ECS
+	if f.jsonFields == "object" {
+		if message, fields := ecsSplit(flags, args); len(fields) == 0 || isKVPairs(fields) {
+			f.appendJSONObjectFields(message, fields, serializer, needsComma)
+			f.buf = append(f.buf, '}', '\n')
+			return f.buf
+		}
+	}
+
 	// Regular JSON with fields array
 	if len(args) > 0 {
 		if needsComma {
 			f.buf = append(f.buf, ',')
 		}
-		f.buf = append(f.buf, `"fields":[`...)
+		f.appendJSONKey(f.jsonKeys.Fields, serializer)
+		f.buf = append(f.buf, '[')
 		for i, arg := range args {
 			if i > 0 {
 				f.buf = append(f.buf, ',')
@@ -327,6 +630,9 @@ func (f *Formatter) formatJSON(flags int64, timestamp time.Time, level int64, tr
 
 // formatTxt handles txt format output
 func (f *Formatter) formatTxt(flags int64, timestamp time.Time, level int64, trace string, args []any, serializer *sanitizer.Serializer) []byte {
+	if f.txtLayout != nil {
+		return f.formatTxtLayout(flags, timestamp, level, trace, args, serializer)
+	}
 	needsSpace := false
 
 	if flags&FlagShowTimestamp != 0 {
@@ -338,7 +644,15 @@ func (f *Formatter) formatTxt(flags int64, timestamp time.Time, level int64, tra
 		if needsSpace {
 			f.buf = append(f.buf, ' ')
 		}
-		f.buf = append(f.buf, LevelToString(level)...)
+		f.appendLevel(level)
+		needsSpace = true
+	}
+
+	if len(f.labels) > 0 {
+		if needsSpace {
+			f.buf = append(f.buf, ' ')
+		}
+		f.appendLabelGroup(serializer)
 		needsSpace = true
 	}
 
@@ -366,4 +680,103 @@ func (f *Formatter) formatTxt(flags int64, timestamp time.Time, level int64, tra
 
 	f.buf = append(f.buf, '\n')
 	return f.buf
+}
+
+// appendLevel appends the level name, wrapped in the color set by LevelColor
+func (f *Formatter) appendLevel(level int64) {
+	color := ""
+	if f.levelColor != nil {
+		color = f.levelColor(level)
+	}
+	f.buf = append(f.buf, color...)
+	f.buf = append(f.buf, LevelToString(level)...)
+	if color != "" {
+		f.buf = append(f.buf, ansiReset...)
+	}
+}
+
+// appendJSONObjectFields appends the message arguments as "message" text and key-value pairs as a "fields" object,
+// each left out when empty
+func (f *Formatter) appendJSONObjectFields(message, fields []any, serializer *sanitizer.Serializer, needsComma bool) {
+	if len(message) > 0 {
+		plain := sanitizer.NewSerializer("raw", f.sanitizer)
+		var text []byte
+		for i, arg := range message {
+			f.convertValue(&text, arg, plain, i > 0)
+		}
+		if needsComma {
+			f.buf = append(f.buf, ',')
+		}
+		f.appendJSONKey(f.jsonKeys.Message, serializer)
+		serializer.WriteString(&f.buf, string(text))
+		needsComma = true
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	if needsComma {
+		f.buf = append(f.buf, ',')
+	}
+	f.appendJSONKey(f.jsonKeys.Fields, serializer)
+	f.buf = append(f.buf, '{')
+	f.appendMembers(fields, nil, serializer, false)
+	f.buf = append(f.buf, '}')
+}
+
+// reservedKeys are the top-level keys of json and msgpack records, "labels" only when the record has labels
+var reservedKeys = []string{"time", "level", "trace", "message", "fields", "labels"}
+
+// reservedKeyPrefix is prepended to reserved keys by the "prefix" policy
+const reservedKeyPrefix = "field_"
+
+// resolveReserved applies the reserved key policy to key-value pairs args written next to the reserved keys
+// Returns args unchanged when no key collides, otherwise a rewritten copy
+func (f *Formatter) resolveReserved(args []any, reserved []string) []any {
+	if f.reservedPolicy == "allow" {
+		return args
+	}
+	var resolved []any
+	for i := 0; i < len(args); i += 2 {
+		key := args[i].(string)
+		if !slices.Contains(reserved, key) || (key == "labels" && len(f.labels) == 0) {
+			if resolved != nil {
+				resolved = append(resolved, args[i], args[i+1])
+			}
+			continue
+		}
+		if resolved == nil {
+			resolved = append(make([]any, 0, len(args)), args[:i]...)
+		}
+		if f.reservedPolicy == "drop" {
+			if f.droppedKey != nil {
+				f.droppedKey(key)
+			}
+			continue
+		}
+		resolved = append(resolved, reservedKeyPrefix+key, args[i+1])
+	}
+	if resolved == nil {
+		return args
+	}
+	return resolved
+}
+
+// isKVPairs reports whether args are alternating non-empty string keys and values with unique keys
+func isKVPairs(args []any) bool {
+	if len(args) == 0 || len(args)%2 != 0 {
+		return false
+	}
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok || key == "" {
+			return false
+		}
+		for j := 0; j < i; j += 2 {
+			if args[j].(string) == key {
+				return false
+			}
+		}
+	}
+	return true
 }
\ No newline at end of file
@@ -1,8 +1,10 @@
 package formatter
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -59,6 +61,26 @@ func TestFormatter(t *testing.T) {
 		assert.Equal(t, true, fields[1])
 	})
 
+	t.Run("json auto kv", func(t *testing.T) {
+		s := sanitizer.New().Policy(sanitizer.PolicyJSON)
+		f := New(s).Type("json").AutoKV(true)
+
+		data := f.Format(FlagDefault, timestamp, 0, "", []any{"user", 123, "action", "login"})
+		assert.Equal(t, `{"time":"2024-01-01T12:00:00Z","level":"INFO","user":123,"action":"login"}`+"\n", string(data))
+
+		// Pairing failures fall back to the fields array
+		for _, args := range [][]any{
+			{"odd", 1, "count"},
+			{1, "non-string key"},
+			{"dup", 1, "dup", 2},
+		} {
+			data = f.Format(FlagDefault, timestamp, 0, "", args)
+			var result map[string]any
+			require.NoError(t, json.Unmarshal(data, &result))
+			assert.Contains(t, result, "fields", "Args %v should fall back to array", args)
+		}
+	})
+
 	t.Run("raw format", func(t *testing.T) {
 		s := sanitizer.New().Policy(sanitizer.PolicyRaw)
 		f := New(s).Type("raw")
@@ -139,4 +161,531 @@ func TestLevelToString(t *testing.T) {
 			assert.Equal(t, tt.expected, LevelToString(tt.level))
 		})
 	}
+}
+
+// nilStringer has pointer methods that panic when called on a nil receiver
+type nilStringer struct{ name string }
+
+func (n *nilStringer) String() string { return n.name }
+func (n *nilStringer) Error() string  { return n.name }
+
+// TestNilValues locks the nil policy: null in json, nil in txt, raw, and FlagRaw records, the nil tag in binary
+func TestNilValues(t *testing.T) {
+	var (
+		nilPtr     *int
+		nilMap     map[string]int
+		nilSlice   []string
+		nilBytes   []byte
+		nilErr     *nilStringer
+		nilStr     fmt.Stringer = (*nilStringer)(nil)
+		nilFunc    func()
+		nilChannel chan int
+	)
+	values := map[string]any{
+		"untyped nil":     nil,
+		"nil pointer":     nilPtr,
+		"nil map":         nilMap,
+		"nil slice":       nilSlice,
+		"nil bytes":       nilBytes,
+		"nil error":       error(nilErr),
+		"nil stringer":    nilStr,
+		"nil func":        nilFunc,
+		"nil channel":     nilChannel,
+		"nil struct ptr":  (*struct{ A int })(nil),
+		"nil any pointer": (*any)(nil),
+	}
+
+	for name, v := range values {
+		t.Run(name, func(t *testing.T) {
+			f := New(sanitizer.New())
+			assert.Equal(t, "null", string(f.Type("json").FormatValue(v)))
+			assert.Equal(t, "nil", string(f.Type("txt").FormatValue(v)))
+			assert.Equal(t, "nil", string(f.Type("raw").FormatValue(v)))
+			assert.Equal(t, "raw nil", string(f.Type("txt").Format(FlagRaw, time.Time{}, 0, "", []any{"raw", v})))
+
+			data := f.Type("binary").Format(0, time.Time{}, 0, "", []any{v})
+			assert.Equal(t, BinaryTagNil, data[len(data)-1])
+		})
+	}
+
+	// Empty but non-nil values keep their type's rendering
+	f := New(sanitizer.New()).Type("json")
+	assert.Equal(t, `""`, string(f.FormatValue([]byte{})))
+	assert.Equal(t, `"map[]"`, string(f.FormatValue(map[string]int{})))
+}
+
+// TestErrorLists verifies error slices and joined errors render as arrays of their messages
+func TestErrorLists(t *testing.T) {
+	joined := errors.Join(errors.New("disk full"), errors.New(`quota "a" exceeded`))
+	wrapped := fmt.Errorf("save: %w, %w", errors.New("first"), errors.New("second"))
+	list := []error{errors.New("timeout"), nil}
+
+	f := New(sanitizer.New())
+	assert.Equal(t, `["disk full","quota \"a\" exceeded"]`, string(f.Type("json").FormatValue(joined)))
+	assert.Equal(t, `["first","second"]`, string(f.Type("json").FormatValue(wrapped)))
+	assert.Equal(t, `["timeout",null]`, string(f.Type("json").FormatValue(list)))
+	assert.Equal(t, `["disk full" "quota \"a\" exceeded"]`, string(f.Type("txt").FormatValue(joined)))
+	assert.Equal(t, `[timeout nil]`, string(f.Type("txt").FormatValue(list)))
+	assert.Equal(t, `[]`, string(f.Type("json").FormatValue([]error{})))
+
+	// Records with error lists stay valid JSON
+	line := f.Type("json").Format(FlagDefault, time.Now(), 0, "", []any{"batch failed", "errors", joined})
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(line, &entry))
+	assert.Equal(t, []any{"batch failed", "errors", []any{"disk full", `quota "a" exceeded`}}, entry["fields"])
+}
+
+// rawDoc holds serialized JSON through the RawJSON marker
+type rawDoc string
+
+// RawJSON returns the document
+func (d rawDoc) RawJSON() []byte {
+	return []byte(d)
+}
+
+// TestRawJSON verifies json.RawMessage and RawJSON values are embedded verbatim in json and as strings elsewhere
+func TestRawJSON(t *testing.T) {
+	msg := json.RawMessage(`{"id":7,"tags":["a","b"]}`)
+
+	f := New(sanitizer.New())
+	assert.Equal(t, `{"id":7,"tags":["a","b"]}`, string(f.Type("json").FormatValue(msg)))
+	assert.Equal(t, `[1,2]`, string(f.Type("json").FormatValue(rawDoc(`[1,2]`))))
+	assert.Equal(t, `"{\"id\":7,\"tags\":[\"a\",\"b\"]}"`, string(f.Type("txt").FormatValue(msg)))
+	assert.Equal(t, `null`, string(f.Type("json").FormatValue(json.RawMessage(nil))))
+
+	// Multi-line documents are compacted, invalid ones are escaped as strings so the line stays valid
+	assert.Equal(t, `{"a":1}`, string(f.Type("json").FormatValue(json.RawMessage("{\n  \"a\": 1\n}"))))
+	assert.Equal(t, `"{broken"`, string(f.Type("json").FormatValue(json.RawMessage(`{broken`))))
+
+	line := f.Type("json").Format(FlagDefault, time.Now(), 0, "", []any{"request", "body", msg})
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(line, &entry))
+	assert.Equal(t, []any{"request", "body", map[string]any{"id": 7.0, "tags": []any{"a", "b"}}}, entry["fields"])
+}
+
+// TestBytesEncoding verifies []byte arguments render as hex or base64 when configured
+func TestLevelColor(t *testing.T) {
+	timestamp := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := New().ShowTimestamp(false).LevelColor(func(level int64) string {
+		if level >= 8 {
+			return "\x1b[31m"
+		}
+		return ""
+	})
+
+	assert.Equal(t, "\x1b[31mERROR\x1b[0m failed\n", string(f.Format(0, timestamp, 8, "", []any{"failed"})))
+	assert.Equal(t, "INFO started\n", string(f.Format(0, timestamp, 0, "", []any{"started"})))
+
+	f.Type("json")
+	assert.NotContains(t, string(f.Format(0, timestamp, 8, "", []any{"failed"})), "\x1b", "Only txt levels are colored")
+}
+
+func TestFormatLabeled(t *testing.T) {
+	timestamp := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	labels := map[string]string{"service": "api", "env": "prod"}
+	f := New().ShowTimestamp(false)
+
+	assert.Equal(t, "INFO {env=prod service=api} started workers 4\n",
+		string(f.FormatLabeled(FlagShowLevel, timestamp, 0, "", labels, []any{"started", "workers", 4})))
+	assert.Equal(t, "INFO started\n", string(f.Format(FlagShowLevel, timestamp, 0, "", []any{"started"})), "Labels apply to one call")
+
+	f.Type("json").AutoKV(true)
+	assert.Equal(t, `{"level":"INFO","labels":{"env":"prod","service":"api"},"status":200}`+"\n",
+		string(f.FormatLabeled(FlagShowLevel, timestamp, 0, "", labels, []any{"status", 200})))
+	assert.Equal(t, `{"level":"INFO","labels":{"env":"prod","service":"api"},"field_labels":"x"}`+"\n",
+		string(f.FormatLabeled(FlagShowLevel, timestamp, 0, "", labels, []any{"labels", "x"})), "A labels field does not shadow the labels object")
+
+	f.Type("raw")
+	assert.Equal(t, "started", string(f.FormatLabeled(FlagShowLevel, timestamp, 0, "", labels, []any{"started"})))
+}
+
+func TestPrettyFormat(t *testing.T) {
+	timestamp := time.Date(2024, 1, 1, 12, 30, 45, 123456789, time.UTC)
+	f := New().Type("pretty")
+
+	t.Run("message and fields", func(t *testing.T) {
+		output := string(f.Format(0, timestamp, 0, "", []any{"request_id", "r1", "request served", "path", "/users", "status", 200}))
+		assert.Equal(t, "12:30:45.123 INFO  request served\n    request_id=r1\n    path=/users\n    status=200\n", output)
+
+		output = string(f.Format(0, timestamp, 0, "", []any{"started", "workers", 4}))
+		assert.Equal(t, "12:30:45.123 INFO  started\n    workers=4\n", output)
+	})
+
+	t.Run("multi-line values", func(t *testing.T) {
+		output := string(f.Format(0, timestamp, 8, "", []any{"failed", "stack", "main.run()\nmain.main()\n"}))
+		assert.Equal(t, "12:30:45.123 ERROR failed\n    stack=main.run()\n          main.main()\n", output)
+	})
+
+	t.Run("fields only", func(t *testing.T) {
+		output := string(f.Format(0, timestamp, 4, "", []any{"disk", "slow", "empty", ""}))
+		assert.Equal(t, "12:30:45.123 WARN\n    disk=slow\n    empty=\"\"\n", output)
+	})
+
+	t.Run("unpaired arguments", func(t *testing.T) {
+		output := string(f.Format(0, timestamp, 0, "", []any{"count", 3, true}))
+		assert.Equal(t, "12:30:45.123 INFO  count 3 true\n", output)
+	})
+
+	t.Run("structured", func(t *testing.T) {
+		output := string(f.Format(FlagStructuredJSON|FlagDefault, timestamp, 0, "", []any{"login", map[string]any{"user": "alice", "attempt": 2}}))
+		assert.Equal(t, "12:30:45.123 INFO  login\n    attempt=2\n    user=alice\n", output)
+	})
+
+	t.Run("sanitized lines", func(t *testing.T) {
+		sf := New(sanitizer.New().Policy(sanitizer.PolicyTxt)).Type("pretty").ShowTimestamp(false)
+		output := string(sf.Format(0, timestamp, 0, "", []any{"note", "text", "a\x1b[31m\nb"}))
+		assert.Equal(t, "INFO  note\n    text=a<1b>[31m\n         b\n", output)
+	})
+}
+
+func TestLogfmtFormat(t *testing.T) {
+	timestamp := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := New().Type("logfmt").TimestampFormat(time.RFC3339)
+
+	output := string(f.Format(0, timestamp, 12, "", []any{"type", "proc", "uptime_hours", "1.50", "note", `say "hi"`, "empty", ""}))
+	assert.Equal(t, `time=2024-01-01T12:00:00Z level=PROC type=proc uptime_hours=1.50 note="say \"hi\"" empty=""`+"\n", output)
+
+	output = string(f.Format(0, timestamp, 0, "", []any{"user login", "user", "alice"}))
+	assert.Equal(t, `time=2024-01-01T12:00:00Z level=INFO msg="user login" user=alice`+"\n", output)
+
+	output = string(f.Format(0, timestamp, 0, "", []any{"count", 3, true}))
+	assert.Equal(t, `time=2024-01-01T12:00:00Z level=INFO msg="count 3 true"`+"\n", output)
+}
+
+func TestBytesEncoding(t *testing.T) {
+	data := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	f := New(sanitizer.New())
+	assert.Equal(t, `"deadbeef"`, string(f.BytesEncoding("hex").Type("json").FormatValue(data)))
+	assert.Equal(t, `deadbeef`, string(f.Type("txt").FormatValue(data)))
+	assert.Equal(t, `"3q2+7w=="`, string(f.BytesEncoding("base64").Type("json").FormatValue(data)))
+
+	// An empty encoding keeps the current one
+	assert.Equal(t, `"3q2+7w=="`, string(f.BytesEncoding("").Type("json").FormatValue(data)))
+
+	// Records with encoded bytes stay valid JSON
+	line := f.Type("json").Format(FlagDefault, time.Now(), 0, "", []any{"payload", data})
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(line, &entry))
+	assert.Equal(t, []any{"payload", "3q2+7w=="}, entry["fields"])
+
+	// Structured fields round-trip at any depth, the caller's map is left untouched
+	fields := map[string]any{"body": data, "parts": []any{data, "text"}, "meta": map[string]any{"id": 7}}
+	line = f.BytesEncoding("hex").Format(FlagDefault|FlagStructuredJSON, time.Now(), 0, "", []any{"payload", fields})
+	var structured struct {
+		Fields struct {
+			Body  string         `json:"body"`
+			Parts []any          `json:"parts"`
+			Meta  map[string]any `json:"meta"`
+		} `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(line, &structured))
+	decoded, err := hex.DecodeString(structured.Fields.Body)
+	require.NoError(t, err)
+	assert.Equal(t, data, decoded)
+	assert.Equal(t, []any{"deadbeef", "text"}, structured.Fields.Parts)
+	assert.Equal(t, map[string]any{"id": float64(7)}, structured.Fields.Meta)
+	assert.Equal(t, data, fields["body"])
+
+	line = f.BytesEncoding("base64").Format(FlagDefault|FlagStructuredJSON, time.Now(), 0, "", []any{"payload", fields})
+	assert.Contains(t, string(line), `"body":"3q2+7w=="`)
+}
+
+// TestGELFFormat verifies GELF messages carry the envelope, level mapping, message split, and custom fields
+func TestGELFFormat(t *testing.T) {
+	f := New(sanitizer.New()).Type("gelf").Host("web-1")
+	ts := time.Unix(1767225600, 123456000)
+
+	var entry map[string]any
+	line := f.Format(FlagDefault, ts, 8, "main.run", []any{"upload failed", "status", 502, "path", "/api", "id", 7, "ok", true})
+	require.NoError(t, json.Unmarshal(line, &entry))
+	assert.True(t, strings.HasSuffix(string(line), "}\n"))
+	assert.Equal(t, "1.1", entry["version"])
+	assert.Equal(t, "web-1", entry["host"])
+	assert.Equal(t, "upload failed", entry["short_message"])
+	assert.NotContains(t, entry, "full_message")
+	assert.Equal(t, 1767225600.123456, entry["timestamp"])
+	assert.Equal(t, 3.0, entry["level"])
+	assert.Equal(t, "main.run", entry["_trace"])
+	assert.Equal(t, 502.0, entry["_status"])
+	assert.Equal(t, "/api", entry["_path"])
+	assert.Equal(t, "true", entry["_ok"])
+	assert.NotContains(t, entry, "_id", "Reserved by GELF")
+
+	// Multi-line messages keep their first line as short_message
+	entry = nil
+	line = f.Format(0, ts, -4, "", []any{"panic: boom\ngoroutine 1"})
+	require.NoError(t, json.Unmarshal(line, &entry))
+	assert.Equal(t, "panic: boom", entry["short_message"])
+	assert.Equal(t, "panic: boom\ngoroutine 1", entry["full_message"])
+	assert.Equal(t, 7.0, entry["level"])
+
+	// Leading msg pairs, structured records, and records without a message
+	entry = nil
+	line = f.Format(0, ts, 0, "", []any{"msg", "started", "user id", "bob"})
+	require.NoError(t, json.Unmarshal(line, &entry))
+	assert.Equal(t, "started", entry["short_message"])
+	assert.Equal(t, "bob", entry["_user_id"])
+
+	entry = nil
+	line = f.Format(FlagStructuredJSON, ts, 12, "", []any{"tick", map[string]any{"seq": 3}})
+	require.NoError(t, json.Unmarshal(line, &entry))
+	assert.Equal(t, "tick", entry["short_message"])
+	assert.Equal(t, 3.0, entry["_seq"])
+	assert.Equal(t, 5.0, entry["level"])
+
+	entry = nil
+	line = f.Format(0, ts, 4, "", []any{"a", 1})
+	require.NoError(t, json.Unmarshal(line, &entry))
+	assert.Equal(t, "a 1", entry["short_message"])
+	assert.Equal(t, 1.0, entry["_a"])
+}
+
+// TestMsgpackFormat verifies the MessagePack record layout and timestamp extension forms
+func TestMsgpackFormat(t *testing.T) {
+	f := New(sanitizer.New()).Type("msgpack")
+	ts := time.Unix(1767225600, 0)
+
+	// {"time": ts32, "level": "INFO", "fields": ["a", 1]}
+	expected := []byte{0x83, 0xa4, 't', 'i', 'm', 'e', 0xd6, 0xff, 0x69, 0x55, 0xb9, 0x00}
+	expected = append(expected, 0xa5, 'l', 'e', 'v', 'e', 'l', 0xa4, 'I', 'N', 'F', 'O')
+	expected = append(expected, 0xa6, 'f', 'i', 'e', 'l', 'd', 's', 0x92, 0xa1, 'a', 0x01)
+	assert.Equal(t, expected, f.Format(FlagDefault, ts, 0, "", []any{"a", 1}))
+
+	// Key-value pairs become top-level keys with AutoKV: {"ok": true, "err": "boom", "n": nil}
+	f.AutoKV(true)
+	assert.Equal(t, []byte{0x83, 0xa2, 'o', 'k', 0xc3, 0xa3, 'e', 'r', 'r', 0xa4, 'b', 'o', 'o', 'm', 0xa1, 'n', 0xc0},
+		f.Format(FlagRaw, ts, 0, "", []any{"ok", true, "err", errors.New("boom"), "n", (*int)(nil)}))
+
+	// Sub-second times use the 64-bit form, times before 1970 the 96-bit form
+	assert.Equal(t, []byte{0xd7, 0xff, 0x00, 0x00, 0x00, 0x04, 0x69, 0x55, 0xb9, 0x00}, appendMsgpackTime(nil, ts.Add(1)))
+	assert.Equal(t, []byte{0xc7, 12, 0xff, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, appendMsgpackTime(nil, time.Unix(-1, 0)))
+}
+
+// TestCSVFormat verifies column order, empty columns, and RFC 4180 quoting of csv rows
+func TestCSVFormat(t *testing.T) {
+	f := New(sanitizer.New()).Type("csv")
+	ts := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "2026-01-01T00:00:00Z,INFO,,served,\"{\"\"status\"\":200}\"\n",
+		string(f.Format(FlagDefault, ts, 0, "", []any{"served", "status", 200})))
+	assert.Equal(t, ",WARN,main.run,\"line one\nsaid \"\"hi\"\", twice\",\n",
+		string(f.Format(FlagShowLevel, ts, 4, "main.run", []any{"line one\nsaid \"hi\", twice"})))
+
+	f.CSVColumns([]string{"message", "labels", "level"})
+	assert.Equal(t, "slow,\"{\"\"env\"\":\"\"prod\"\"}\",ERROR\n",
+		string(f.FormatLabeled(FlagDefault, ts, 8, "", map[string]string{"env": "prod"}, []any{"slow"})))
+	assert.Equal(t, "\" padded \",,\n", string(f.Format(FlagRaw, ts, 0, "", []any{" padded "})))
+}
+
+// TestCEFFormat verifies CEF header fields, severities, extension key mapping, and escaping
+func TestCEFFormat(t *testing.T) {
+	f := New(sanitizer.New()).Type("cef").
+		CEFDevice("acme", "billing", "2.1").
+		CEFExtensionMap(map[string]string{"client_ip": "src"})
+	ts := time.UnixMilli(1767225600123)
+
+	assert.Equal(t, "CEF:0|acme|billing|2.1|WARN|login failed|6|rt=1767225600123 user=alice src=10.0.0.7\n",
+		string(f.Format(FlagDefault, ts, 4, "", []any{"login failed", "user", "alice", "client_ip", "10.0.0.7"})))
+	assert.Equal(t, "CEF:0|acme|billing|2.1|ERROR|a\\|b c|8|trace=main.run query=x\\=1\\nlimit 2 env=prod\n",
+		string(f.FormatLabeled(FlagShowLevel, ts, 8, "main.run", map[string]string{"env": "prod"},
+			[]any{"a|b\nc", "query", "x=1\nlimit 2"})))
+	assert.Equal(t, "CEF:0|acme|billing|2.1|INFO|request-id 7|3|requestid=7\n",
+		string(f.Format(FlagShowLevel, ts, 0, "", []any{"request-id", 7})))
+}
+
+// TestReservedKeyPolicy verifies keys colliding with the keys a record writes itself are prefixed, dropped, or allowed
+func TestReservedKeyPolicy(t *testing.T) {
+	ts := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	args := []any{"level", "debug", "user", "alice", "message", "hi"}
+
+	f := New(sanitizer.New()).Type("json").AutoKV(true)
+	assert.Equal(t, `{"level":"INFO","field_level":"debug","user":"alice","field_message":"hi"}`+"\n",
+		string(f.Format(FlagShowLevel, ts, 0, "", args)))
+	assert.Equal(t, []any{"level", "debug", "user", "alice", "message", "hi"}, args, "Caller args are not modified")
+
+	var dropped []string
+	f.ReservedKeyPolicy("drop").OnDroppedKey(func(key string) { dropped = append(dropped, key) })
+	assert.Equal(t, `{"level":"INFO","user":"alice"}`+"\n", string(f.Format(FlagShowLevel, ts, 0, "", args)))
+	assert.Equal(t, []string{"level", "message"}, dropped)
+
+	f.ReservedKeyPolicy("allow")
+	assert.Equal(t, `{"level":"INFO","level":"debug","user":"alice","message":"hi"}`+"\n",
+		string(f.Format(FlagShowLevel, ts, 0, "", args)))
+
+	f = New(sanitizer.New()).Type("logfmt")
+	assert.Equal(t, "level=INFO msg=started field_level=debug field_msg=x\n",
+		string(f.Format(FlagShowLevel, ts, 0, "", []any{"started", "level", "debug", "msg", "x"})))
+}
+
+// TestECSFormat verifies ECS field names, error and trace id mapping, and key-value records without a message
+func TestECSFormat(t *testing.T) {
+	f := New(sanitizer.New()).Type("ecs")
+	ts := time.Date(2026, 1, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	assert.Equal(t, `{"@timestamp":"2026-01-01T00:00:00Z","log.level":"error","message":"payment failed","ecs.version":"8.11.0",`+
+		`"log.origin.function":"main.pay","error.message":"card declined","trace.id":"4bf92f35","amount":12.5}`+"\n",
+		string(f.Format(FlagDefault, ts, 8, "main.pay", []any{"payment failed", errors.New("card declined"), "trace_id", "4bf92f35", "amount", 12.5})))
+
+	assert.Equal(t, `{"log.level":"info","ecs.version":"8.11.0","labels":{"env":"prod"},"error.message":"timeout","field_message":"x"}`+"\n",
+		string(f.FormatLabeled(FlagShowLevel, ts, 0, "", map[string]string{"env": "prod"}, []any{"err", "timeout", "message", "x"})))
+
+	// A leading "msg" pair written by the compat adapters is the message
+	assert.Equal(t, `{"message":"slog msg","ecs.version":"8.11.0","source":"slog"}`+"\n",
+		string(f.FormatWithOptions("ecs", 0, ts, 0, "", []any{"msg", "slog msg", "source", "slog"})))
+	assert.Equal(t, 2, MessageLen([]any{"msg", "slog msg", "user", 7}))
+	assert.Equal(t, 1, MessageLen([]any{"cache miss", "user", 7}))
+	assert.Equal(t, 0, MessageLen([]any{"user", 7}))
+	assert.Equal(t, 2, MessageLen([]any{"msg", "x"}))
+	assert.Equal(t, 3, MessageLen([]any{"a", "b c", "d", "user", 7}))
+	assert.Equal(t, 1, MessageLen([]any{"msg", "user", 7}))
+	assert.Equal(t, 0, MessageLen(nil))
+}
+
+func TestGCPFormat(t *testing.T) {
+	f := New(sanitizer.New()).Type("gcp").GCPProject("shop-prod")
+	ts := time.Date(2026, 1, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	assert.Equal(t, `{"severity":"WARNING","timestamp":"2026-01-01T00:00:00Z","message":"slow checkout",`+
+		`"logging.googleapis.com/trace":"projects/shop-prod/traces/4bf92f35","logging.googleapis.com/spanId":"00f067aa",`+
+		`"logging.googleapis.com/sourceLocation":{"function":"main.pay"},"jsonPayload":{"ms":812}}`+"\n",
+		string(f.Format(FlagDefault, ts, 4, "main.pay", []any{"slow checkout", "trace_id", "4bf92f35", "span_id", "00f067aa", "ms", 812})))
+
+	assert.Equal(t, `{"severity":"DEBUG","logging.googleapis.com/trace":"projects/other/traces/1",`+
+		`"logging.googleapis.com/labels":{"env":"prod"}}`+"\n",
+		string(f.FormatLabeled(FlagShowLevel, ts, -4, "", map[string]string{"env": "prod"}, []any{"trace_id", "projects/other/traces/1"})))
+
+	assert.Equal(t, `{"severity":"INFO","message":"PROC","jsonPayload":{"uptime_hours":1}}`+"\n",
+		string(New(sanitizer.New()).Type("gcp").Format(FlagShowLevel, ts, 12, "", []any{"PROC", "uptime_hours", 1})))
+
+	// Adapter records carry their message as a leading "msg" pair
+	assert.Equal(t, `{"message":"http request","logging.googleapis.com/trace":"projects/shop-prod/traces/4bf92f35",`+
+		`"jsonPayload":{"status":200}}`+"\n",
+		string(f.FormatWithOptions("gcp", 0, ts, 0, "", []any{"msg", "http request", "trace_id", "4bf92f35", "status", 200})))
+}
+
+func TestRFC5424Format(t *testing.T) {
+	f := New(sanitizer.New()).Type("rfc5424").SyslogHeader(16, "web01", "app", "4242", "fields@32473")
+	ts := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, `<132>1 2026-01-01T00:00:00.000000Z web01 app 4242 DISK [fields@32473 trace="main.check" device="sda" note="a \"quoted\\ \]"] disk slow`+"\n",
+		string(f.Format(FlagDefault, ts, 4, "main.check", []any{"disk slow", "msgid", "DISK", "device", "sda", "note", `a "quoted\ ]`})))
+
+	assert.Equal(t, `<134>1 - web01 app 4242 - [labels@32473 env="prod"] started`+"\n",
+		string(f.FormatLabeled(FlagShowLevel, ts, 0, "", map[string]string{"env": "prod"}, []any{"started"})))
+
+	assert.Equal(t, `<15>1 - - - - - [fields@32473 keyname="1"]`+"\n",
+		string(New(sanitizer.New()).Type("rfc5424").Format(FlagStructuredJSON, ts, -4, "", []any{"", map[string]any{"key=name": 1, "]\"=": 2}})))
+}
+
+func TestTxtLayout(t *testing.T) {
+	f := New(sanitizer.New()).Type("txt").TimestampFormat(time.DateTime).TxtLayout("{time} [{level}] {labels} {trace} {msg} {fields}")
+	ts := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "2026-01-01 00:00:00 [WARN] main.check disk slow device=sda ms=250\n",
+		string(f.Format(FlagDefault, ts, 4, "main.check", []any{"disk slow", "device", "sda", "ms", 250})))
+	assert.Equal(t, "2026-01-01 00:00:00 [INFO] {env=prod} started\n",
+		string(f.FormatLabeled(FlagDefault, ts, 0, "", map[string]string{"env": "prod"}, []any{"started"})))
+	assert.Equal(t, "[] hello world\n",
+		string(f.Format(FlagStructuredJSON, ts, 0, "", []any{"hello world", map[string]any{}})))
+
+	literal := New(sanitizer.New()).Type("txt").TxtLayout("{{{level}}} {msg}")
+	assert.Equal(t, "{ERROR} failed\n", string(literal.Format(FlagShowLevel, ts, 8, "", []any{"failed"})))
+
+	assert.NoError(t, ValidateTxtLayout(""))
+	assert.NoError(t, ValidateTxtLayout("{time} {{literal}} {msg}"))
+	assert.Error(t, ValidateTxtLayout("{time} {message}"))
+	assert.Error(t, ValidateTxtLayout("{time"))
+	assert.Error(t, ValidateTxtLayout("time}"))
+}
+
+func TestJSONFieldsObject(t *testing.T) {
+	f := New(sanitizer.New()).Type("json").ShowTimestamp(false).JSONFields("object")
+
+	assert.Equal(t, `{"level":"INFO","message":"user logged in","fields":{"user_id":123,"ok":true}}`+"\n",
+		string(f.Format(FlagShowLevel, time.Time{}, 0, "", []any{"user logged in", "user_id", 123, "ok", true})))
+	assert.Equal(t, `{"level":"INFO","message":"workers started 4"}`+"\n",
+		string(f.Format(FlagShowLevel, time.Time{}, 0, "", []any{"workers started", 4})))
+	assert.Equal(t, `{"level":"INFO","fields":{"user_id":123}}`+"\n",
+		string(f.Format(FlagShowLevel, time.Time{}, 0, "", []any{"user_id", 123})))
+
+	// Repeated keys keep the array
+	assert.Equal(t, `{"level":"INFO","fields":["a",1,"a",2]}`+"\n",
+		string(f.Format(FlagShowLevel, time.Time{}, 0, "", []any{"a", 1, "a", 2})))
+
+	// Flattening takes precedence when enabled
+	assert.Equal(t, `{"level":"INFO","user_id":123}`+"\n",
+		string(f.AutoKV(true).Format(FlagShowLevel, time.Time{}, 0, "", []any{"user_id", 123})))
+}
+
+func TestNestedKeys(t *testing.T) {
+	f := New(sanitizer.New()).Type("json").ShowTimestamp(false).AutoKV(true).NestedKeys(true)
+
+	assert.Equal(t, `{"level":"INFO","http":{"method":"GET","status":200},"user":{"id":7},"a..b":1}`+"\n",
+		string(f.Format(FlagShowLevel, time.Time{}, 0, "", []any{"http.method", "GET", "user.id", 7, "http.status", 200, "a..b", 1})))
+
+	// Keys prefixing others, including the record's own, keep the dotted keys flat
+	assert.Equal(t, `{"level":"INFO","db":"main","db.rows":3,"time.zone":"UTC"}`+"\n",
+		string(f.Format(FlagShowLevel, time.Time{}, 0, "", []any{"db", "main", "db.rows", 3, "time.zone", "UTC"})))
+
+	object := New(sanitizer.New()).Type("json").ShowTimestamp(false).JSONFields("object").NestedKeys(true)
+	assert.Equal(t, `{"message":"request done","fields":{"http":{"status":200}}}`+"\n",
+		string(object.FormatWithOptions("json", 0, time.Time{}, 0, "", []any{"request done", "http.status", 200})))
+
+	ecs := New(sanitizer.New()).Type("ecs").NestedKeys(true)
+	assert.Equal(t, `{"message":"request done","ecs.version":"8.11.0","http":{"response":{"status_code":200}},"log.level.raw":1}`+"\n",
+		string(ecs.FormatWithOptions("ecs", 0, time.Time{}, 0, "", []any{"request done", "http.response.status_code", 200, "log.level.raw", 1})))
+
+	flat := New(sanitizer.New()).Type("json").ShowTimestamp(false).AutoKV(true)
+	assert.Equal(t, `{"level":"INFO","http.status":200}`+"\n",
+		string(flat.Format(FlagShowLevel, time.Time{}, 0, "", []any{"http.status", 200})))
+}
+
+func TestJSONKeys(t *testing.T) {
+	f := New(sanitizer.New()).Type("json").TimestampFormat(time.DateOnly).AutoKV(true).
+		JSONKeys(JSONKeyNames{Time: "@timestamp", Level: "severity", Fields: "args"})
+	ts := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, `{"@timestamp":"2026-01-01","severity":"INFO","trace":"main.run","args":["started",4,"done"]}`+"\n",
+		string(f.Format(FlagDefault, ts, 0, "main.run", []any{"started", 4, "done"})))
+
+	// Renamed keys are reserved in place of the defaults
+	assert.Equal(t, `{"severity":"INFO","field_severity":"x","level":"y"}`+"\n",
+		string(f.Format(FlagShowLevel, ts, 0, "", []any{"severity", "x", "level", "y"})))
+
+	object := New(sanitizer.New()).Type("json").JSONFields("object").JSONKeys(JSONKeyNames{Message: "msg", Fields: "attrs"})
+	assert.Equal(t, `{"msg":"user logged in","attrs":{"id":7}}`+"\n",
+		string(object.FormatWithOptions("json", 0, ts, 0, "", []any{"user logged in", "id", 7})))
+}
+
+// TestDurationAndTimeValues verifies the numeric and text renderings of time.Duration and time.Time arguments
+func TestDurationAndTimeValues(t *testing.T) {
+	d := 1500 * time.Millisecond
+	ts := time.Date(2024, 1, 15, 10, 30, 0, 5e8, time.FixedZone("CET", 3600))
+
+	f := New(sanitizer.New()).Type("json")
+	assert.Equal(t, `"1.5s"`, string(f.FormatValue(d)))
+	assert.Equal(t, `"2024-01-15T10:30:00.5+01:00"`, string(f.FormatValue(ts)))
+
+	f.DurationFormat("ns")
+	assert.Equal(t, `1500000000`, string(f.FormatValue(d)))
+	f.DurationFormat("ms")
+	assert.Equal(t, `1500`, string(f.FormatValue(d)))
+	assert.Equal(t, `0.25`, string(f.FormatValue(250*time.Microsecond)))
+
+	f.TimeValueFormat("rfc3339")
+	assert.Equal(t, `"2024-01-15T09:30:00.5Z"`, string(f.FormatValue(ts)))
+	f.TimeValueFormat("unix")
+	assert.Equal(t, `1705311000`, string(f.FormatValue(ts)))
+	f.TimeValueFormat("unix_ms")
+	assert.Equal(t, `1705311000500`, string(f.FormatValue(ts)))
+
+	// Numbers stay numbers in flattened records
+	line := f.AutoKV(true).Format(FlagDefault, ts, 0, "", []any{"took", d, "started", ts})
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(line, &entry))
+	assert.Equal(t, float64(1500), entry["took"])
+	assert.Equal(t, float64(1705311000500), entry["started"])
+
+	assert.Equal(t, "1500 1705311000500", string(f.Type("txt").FormatArgs(d, ts)))
 }
\ No newline at end of file
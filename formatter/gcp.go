@@ -0,0 +1,136 @@
+package formatter
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// GCP Cloud Logging special keys, read by the logging agent of GKE and Cloud Run
+const (
+	gcpTraceKey          = "logging.googleapis.com/trace"
+	gcpSpanKey           = "logging.googleapis.com/spanId"
+	gcpSourceLocationKey = "logging.googleapis.com/sourceLocation"
+	gcpLabelsKey         = "logging.googleapis.com/labels"
+)
+
+// GCPProject sets the project ID completing trace IDs to "projects/<project>/traces/<id>", empty writes IDs as given
+// IDs already starting with "projects/" are written as given
+func (f *Formatter) GCPProject(project string) *Formatter {
+	f.gcpProject = project
+	return f
+}
+
+// formatGCP renders a record as a JSON object for Google Cloud Logging structured logging
+// The trace_id and span_id fields become the trace correlation keys, other fields are written in a jsonPayload object
+func (f *Formatter) formatGCP(flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
+	serializer := sanitizer.NewSerializer("json", f.sanitizer)
+	message, fields := ecsSplit(flags, args)
+
+	plain := sanitizer.NewSerializer("raw", f.sanitizer)
+	var text []byte
+	for i, arg := range message {
+		f.convertValue(&text, arg, plain, i > 0)
+	}
+
+	f.buf = append(f.buf, '{')
+	needsComma := false
+	key := func(name string) {
+		if needsComma {
+			f.buf = append(f.buf, ',')
+		}
+		serializer.WriteString(&f.buf, name)
+		f.buf = append(f.buf, ':')
+		needsComma = true
+	}
+
+	if flags&FlagShowLevel != 0 {
+		key("severity")
+		serializer.WriteString(&f.buf, gcpSeverity(level))
+	}
+	if flags&FlagShowTimestamp != 0 {
+		key("timestamp")
+		f.buf = append(f.buf, '"')
+		f.buf = timestamp.UTC().AppendFormat(f.buf, time.RFC3339Nano)
+		f.buf = append(f.buf, '"')
+	}
+	if len(text) > 0 {
+		key("message")
+		serializer.WriteString(&f.buf, string(text))
+	}
+
+	payload := make([]any, 0, len(fields))
+	for i := 0; i+1 < len(fields); i += 2 {
+		switch fields[i] {
+		case "trace_id":
+			var id []byte
+			f.convertValue(&id, fields[i+1], plain, false)
+			if f.gcpProject != "" && !bytes.HasPrefix(id, []byte("projects/")) {
+				id = append([]byte("projects/"+f.gcpProject+"/traces/"), id...)
+			}
+			key(gcpTraceKey)
+			serializer.WriteString(&f.buf, string(id))
+		case "span_id":
+			var id []byte
+			f.convertValue(&id, fields[i+1], plain, false)
+			key(gcpSpanKey)
+			serializer.WriteString(&f.buf, string(id))
+		default:
+			payload = append(payload, fields[i], fields[i+1])
+		}
+	}
+	if trace != "" {
+		key(gcpSourceLocationKey)
+		f.buf = append(f.buf, '{')
+		serializer.WriteString(&f.buf, "function")
+		f.buf = append(f.buf, ':')
+		serializer.WriteString(&f.buf, trace)
+		f.buf = append(f.buf, '}')
+	}
+	if len(f.labels) > 0 {
+		key(gcpLabelsKey)
+		f.buf = append(f.buf, '{')
+		for i, name := range f.labelKeys() {
+			if i > 0 {
+				f.buf = append(f.buf, ',')
+			}
+			serializer.WriteString(&f.buf, name)
+			f.buf = append(f.buf, ':')
+			serializer.WriteString(&f.buf, f.labels[name])
+		}
+		f.buf = append(f.buf, '}')
+	}
+	if len(payload) > 0 {
+		key("jsonPayload")
+		f.buf = append(f.buf, '{')
+		for i := 0; i+1 < len(payload); i += 2 {
+			if i > 0 {
+				f.buf = append(f.buf, ',')
+			}
+			serializer.WriteString(&f.buf, payload[i].(string))
+			f.buf = append(f.buf, ':')
+			f.convertValue(&f.buf, payload[i+1], serializer, false)
+		}
+		f.buf = append(f.buf, '}')
+	}
+
+	f.buf = append(f.buf, '}', '\n')
+	return f.buf
+}
+
+// gcpSeverity maps log levels to Cloud Logging severities, heartbeats are INFO
+func gcpSeverity(level int64) string {
+	switch {
+	case level >= 12:
+		return "INFO"
+	case level >= 8:
+		return "ERROR"
+	case level >= 4:
+		return "WARNING"
+	case level >= 0:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
\ No newline at end of file
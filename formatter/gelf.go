@@ -0,0 +1,171 @@
+package formatter
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// formatGELF renders a GELF 1.1 message as one JSON object
+// The record message becomes short_message, truncated to its first line with the complete text in full_message
+// when it spans several lines; key-value fields become additional "_key" fields and the trace "_trace"
+func (f *Formatter) formatGELF(flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
+	serializer := sanitizer.NewSerializer("json", f.sanitizer)
+	message, fields := gelfSplit(flags, args)
+	text := f.gelfText(message...)
+	if text == "" {
+		text = "-" // short_message must not be empty
+	}
+
+	if f.host == "" {
+		f.host = localHost()
+	}
+	f.buf = append(f.buf, `{"version":"1.1","host":`...)
+	serializer.WriteString(&f.buf, f.host)
+	f.buf = append(f.buf, `,"short_message":`...)
+	if short, _, multiline := strings.Cut(text, "\n"); multiline {
+		serializer.WriteString(&f.buf, short)
+		f.buf = append(f.buf, `,"full_message":`...)
+	}
+	serializer.WriteString(&f.buf, text)
+
+	f.buf = append(f.buf, `,"timestamp":`...)
+	f.buf = strconv.AppendInt(f.buf, timestamp.Unix(), 10)
+	f.buf = append(f.buf, '.')
+	micros := strconv.Itoa(timestamp.Nanosecond() / 1000)
+	f.buf = append(f.buf, "000000"[len(micros):]...)
+	f.buf = append(f.buf, micros...)
+	f.buf = append(f.buf, `,"level":`...)
+	f.buf = strconv.AppendInt(f.buf, int64(gelfLevel(level)), 10)
+
+	if trace != "" {
+		f.buf = append(f.buf, `,"_trace":`...)
+		serializer.WriteString(&f.buf, trace)
+	}
+
+	seen := make([]string, 0, len(fields)/2+len(f.labels))
+	for _, key := range f.labelKeys() {
+		name := gelfFieldName(key)
+		if name == "" || name == "id" || slices.Contains(seen, name) {
+			continue
+		}
+		seen = append(seen, name)
+		f.buf = append(f.buf, `,"_`...)
+		f.buf = append(f.buf, name...)
+		f.buf = append(f.buf, `":`...)
+		serializer.WriteString(&f.buf, f.labels[key])
+	}
+	for i := 0; i+1 < len(fields); i += 2 {
+		name := gelfFieldName(fields[i].(string))
+		if name == "" || name == "id" || slices.Contains(seen, name) {
+			// Invalid, reserved by GELF, or already written
+			continue
+		}
+		seen = append(seen, name)
+		f.buf = append(f.buf, `,"_`...)
+		f.buf = append(f.buf, name...)
+		f.buf = append(f.buf, `":`...)
+		switch v := fields[i+1].(type) {
+		case int, int64, uint, uint64, float32, float64:
+			// GELF fields are numbers or strings
+			f.convertValue(&f.buf, v, serializer, false)
+		default:
+			serializer.WriteString(&f.buf, f.gelfText(v))
+		}
+	}
+
+	f.buf = append(f.buf, '}', '\n')
+	return f.buf
+}
+
+// gelfSplit separates the message arguments of a record from its key-value fields
+// Structured records yield their message and sorted field map; other records take a leading odd argument or a
+// leading "msg" pair as the message and string-keyed pairs as fields, remaining pairs stay in the message
+// Records without a message keep all arguments as the message text
+func gelfSplit(flags int64, args []any) (message []any, fields []any) {
+	if flags&FlagRaw != 0 {
+		return args, nil
+	}
+	if flags&FlagStructuredJSON != 0 && len(args) >= 2 {
+		if msg, ok := args[0].(string); ok {
+			if m, ok := args[1].(map[string]any); ok {
+				keys := make([]string, 0, len(m))
+				for k := range m {
+					keys = append(keys, k)
+				}
+				slices.Sort(keys)
+				for _, k := range keys {
+					fields = append(fields, k, m[k])
+				}
+				return []any{msg}, fields
+			}
+		}
+	}
+
+	start := len(args) % 2
+	message = append(message, args[:start]...)
+	for i := start; i+1 < len(args); i += 2 {
+		key, ok := args[i].(string)
+		switch {
+		case ok && i == 0 && key == "msg":
+			message = append(message, args[i+1])
+		case ok && key != "":
+			fields = append(fields, key, args[i+1])
+		default:
+			message = append(message, args[i], args[i+1])
+		}
+	}
+	if len(message) == 0 {
+		message = args
+	}
+	return message, fields
+}
+
+// gelfText renders values as space-separated unquoted text, sanitized when written as a JSON string
+func (f *Formatter) gelfText(values ...any) string {
+	plain := sanitizer.NewSerializer("raw", sanitizer.New())
+	var buf []byte
+	for i, v := range values {
+		f.convertValue(&buf, v, plain, i > 0)
+	}
+	return string(buf)
+}
+
+// gelfFieldName reduces a key to the characters GELF allows in field names: letters, digits, '_', '-', and '.'
+func gelfFieldName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '_' && c != '-' && c != '.' {
+			name[i] = '_'
+		}
+	}
+	return strings.TrimLeft(string(name), "_")
+}
+
+// gelfLevel maps log levels to the syslog severities GELF uses, heartbeats are notices
+func gelfLevel(level int64) int {
+	switch {
+	case level >= 12:
+		return 5 // notice
+	case level >= 8:
+		return 3 // err
+	case level >= 4:
+		return 4 // warning
+	case level >= 0:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// localHost returns the machine hostname, "localhost" when unavailable
+func localHost() string {
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return "localhost"
+}
\ No newline at end of file
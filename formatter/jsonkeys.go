@@ -0,0 +1,45 @@
+package formatter
+
+import (
+	"cmp"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// JSONKeyNames are the keys json records write their own members under, empty names keep the defaults
+type JSONKeyNames struct {
+	Time    string
+	Level   string
+	Trace   string
+	Fields  string
+	Message string // Message of structured records and of json_fields=object records
+}
+
+// DefaultJSONKeys are the keys json records use unless renamed
+var DefaultJSONKeys = JSONKeyNames{Time: "time", Level: "level", Trace: "trace", Fields: "fields", Message: "message"}
+
+// JSONKeys renames the keys json records write their own members under, e.g. Time: "@timestamp"
+func (f *Formatter) JSONKeys(keys JSONKeyNames) *Formatter {
+	f.jsonKeys = JSONKeyNames{
+		Time:    cmp.Or(keys.Time, DefaultJSONKeys.Time),
+		Level:   cmp.Or(keys.Level, DefaultJSONKeys.Level),
+		Trace:   cmp.Or(keys.Trace, DefaultJSONKeys.Trace),
+		Fields:  cmp.Or(keys.Fields, DefaultJSONKeys.Fields),
+		Message: cmp.Or(keys.Message, DefaultJSONKeys.Message),
+	}
+	return f
+}
+
+// jsonReservedKeys returns the keys json records write themselves, "labels" only applying when the record has labels
+func (f *Formatter) jsonReservedKeys() []string {
+	if f.jsonKeys == DefaultJSONKeys {
+		return reservedKeys
+	}
+	return []string{f.jsonKeys.Time, f.jsonKeys.Level, f.jsonKeys.Trace, f.jsonKeys.Message, f.jsonKeys.Fields, "labels"}
+}
+
+// appendJSONKey appends a member key and its colon
+func (f *Formatter) appendJSONKey(name string, serializer *sanitizer.Serializer) {
+	serializer.WriteString(&f.buf, name)
+	f.buf = append(f.buf, ':')
+}
\ No newline at end of file
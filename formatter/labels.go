@@ -0,0 +1,55 @@
+package formatter
+
+import (
+	"sort"
+	"time"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// FormatLabeled formats a log entry like Format, adding labels: a "labels" object in json and ecs, a "logging.googleapis.com/labels" object in gcp, a {key=value} group after
+// the level in txt and pretty, additional fields in gelf, the labels column in csv, and extension fields in cef; raw, logfmt, and binary
+// output leave them out
+func (f *Formatter) FormatLabeled(flags int64, timestamp time.Time, level int64, trace string, labels map[string]string, args []any) []byte {
+	f.labels = labels
+	defer func() { f.labels = nil }()
+	return f.Format(flags, timestamp, level, trace, args)
+}
+
+// labelKeys returns the keys of the labels being formatted in sorted order
+func (f *Formatter) labelKeys() []string {
+	keys := make([]string, 0, len(f.labels))
+	for key := range f.labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appendLabelGroup appends the labels being formatted as "{key=value ...}", values written by serializer
+func (f *Formatter) appendLabelGroup(serializer *sanitizer.Serializer) {
+	f.buf = append(f.buf, '{')
+	for i, key := range f.labelKeys() {
+		if i > 0 {
+			f.buf = append(f.buf, ' ')
+		}
+		f.buf = append(f.buf, f.sanitizer.Sanitize(key)...)
+		f.buf = append(f.buf, '=')
+		serializer.WriteString(&f.buf, f.labels[key])
+	}
+	f.buf = append(f.buf, '}')
+}
+
+// appendLabelObject appends the labels being formatted as a JSON object
+func (f *Formatter) appendLabelObject(serializer *sanitizer.Serializer) {
+	f.buf = append(f.buf, `"labels":{`...)
+	for i, key := range f.labelKeys() {
+		if i > 0 {
+			f.buf = append(f.buf, ',')
+		}
+		serializer.WriteString(&f.buf, key)
+		f.buf = append(f.buf, ':')
+		serializer.WriteString(&f.buf, f.labels[key])
+	}
+	f.buf = append(f.buf, '}')
+}
\ No newline at end of file
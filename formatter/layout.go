@@ -0,0 +1,135 @@
+package formatter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// TxtLayoutFields are the placeholders accepted in txt layouts
+//
+//	time    timestamp in the configured format, empty when timestamps are hidden
+//	level   level name, empty when levels are hidden
+//	labels  labels of the record as {key=value ...}, empty when there are none
+//	trace   function trace, empty when absent
+//	msg     arguments that are not key-value pairs, as space-separated text
+//	fields  key-value pairs and structured fields as key=value, space-separated
+var TxtLayoutFields = []string{"time", "level", "labels", "trace", "msg", "fields"}
+
+// layoutPart is the literal text or the placeholder of one piece of a txt layout
+type layoutPart struct {
+	text  string
+	field string // Placeholder name, empty for literal text
+}
+
+// TxtLayout sets the layout of txt records, e.g. "{time} [{level}] {trace} {msg} {fields}"
+// "{{" and "}}" write literal braces; an empty or invalid layout keeps the built-in txt layout
+func (f *Formatter) TxtLayout(layout string) *Formatter {
+	f.txtLayout, _ = parseTxtLayout(layout)
+	return f
+}
+
+// ValidateTxtLayout reports an unknown placeholder or unbalanced brace in a txt layout
+func ValidateTxtLayout(layout string) error {
+	_, err := parseTxtLayout(layout)
+	return err
+}
+
+// parseTxtLayout splits a layout into literal text and placeholders, nil for an empty layout
+func parseTxtLayout(layout string) ([]layoutPart, error) {
+	var parts []layoutPart
+	var text []byte
+	for i := 0; i < len(layout); i++ {
+		c := layout[i]
+		switch {
+		case (c == '{' || c == '}') && i+1 < len(layout) && layout[i+1] == c:
+			text = append(text, c)
+			i++
+		case c == '{':
+			end := strings.IndexByte(layout[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '{' at offset %d", i)
+			}
+			field := layout[i+1 : i+end]
+			if !slices.Contains(TxtLayoutFields, field) {
+				return nil, fmt.Errorf("unknown placeholder '{%s}' (use %s)", field, strings.Join(TxtLayoutFields, ", "))
+			}
+			if len(text) > 0 {
+				parts = append(parts, layoutPart{text: string(text)})
+				text = text[:0]
+			}
+			parts = append(parts, layoutPart{field: field})
+			i += end
+		case c == '}':
+			return nil, fmt.Errorf("unmatched '}' at offset %d", i)
+		default:
+			text = append(text, c)
+		}
+	}
+	if len(text) > 0 {
+		parts = append(parts, layoutPart{text: string(text)})
+	}
+	return parts, nil
+}
+
+// formatTxtLayout renders a txt record in the configured layout
+// A placeholder rendering empty takes one following space with it, or the preceding one at the end of the layout,
+// so optional parts leave no doubled or trailing spaces
+func (f *Formatter) formatTxtLayout(flags int64, timestamp time.Time, level int64, trace string, args []any, serializer *sanitizer.Serializer) []byte {
+	message, fields := ecsSplit(flags, args)
+	plain := sanitizer.NewSerializer("raw", f.sanitizer)
+
+	emptyBefore := false
+	for _, part := range f.txtLayout {
+		if part.field == "" {
+			text := part.text
+			if emptyBefore && text[0] == ' ' {
+				text = text[1:]
+			}
+			f.buf = append(f.buf, text...)
+			emptyBefore = false
+			continue
+		}
+
+		start := len(f.buf)
+		switch part.field {
+		case "time":
+			if flags&FlagShowTimestamp != 0 {
+				f.buf = timestamp.AppendFormat(f.buf, f.timestampFormat)
+			}
+		case "level":
+			if flags&FlagShowLevel != 0 {
+				f.appendLevel(level)
+			}
+		case "labels":
+			if len(f.labels) > 0 {
+				f.appendLabelGroup(serializer)
+			}
+		case "trace":
+			f.buf = append(f.buf, f.sanitizer.Sanitize(trace)...)
+		case "msg":
+			for i, arg := range message {
+				f.convertValue(&f.buf, arg, plain, i > 0)
+			}
+		case "fields":
+			for i := 0; i+1 < len(fields); i += 2 {
+				if i > 0 {
+					f.buf = append(f.buf, ' ')
+				}
+				f.buf = append(f.buf, f.sanitizer.Sanitize(fields[i].(string))...)
+				f.buf = append(f.buf, '=')
+				f.convertValue(&f.buf, fields[i+1], serializer, false)
+			}
+		}
+		emptyBefore = len(f.buf) == start
+	}
+	if emptyBefore && len(f.buf) > 0 && f.buf[len(f.buf)-1] == ' ' {
+		f.buf = f.buf[:len(f.buf)-1]
+	}
+
+	f.buf = append(f.buf, '\n')
+	return f.buf
+}
\ No newline at end of file
@@ -0,0 +1,75 @@
+package formatter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// logfmtReservedKeys are the keys logfmt records write themselves
+var logfmtReservedKeys = []string{"time", "level", "trace", "msg"}
+
+// formatLogfmt renders a record as logfmt: time, level, and trace, then the key-value arguments as key=value pairs
+// Arguments that do not pair up are joined into a msg field, a leading message before pairs keeps its own msg field
+func (f *Formatter) formatLogfmt(flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
+	serializer := sanitizer.NewSerializer("raw", f.sanitizer)
+	needsSpace := false
+	field := func(key string, value []byte) {
+		if needsSpace {
+			f.buf = append(f.buf, ' ')
+		}
+		f.buf = append(f.buf, key...)
+		f.buf = append(f.buf, '=')
+		f.buf = appendLogfmtValue(f.buf, string(value))
+		needsSpace = true
+	}
+
+	if flags&FlagShowTimestamp != 0 {
+		field("time", timestamp.AppendFormat(nil, f.timestampFormat))
+	}
+	if flags&FlagShowLevel != 0 {
+		field("level", []byte(LevelToString(level)))
+	}
+	if trace != "" {
+		field("trace", []byte(f.sanitizer.Sanitize(trace)))
+	}
+
+	message, pairs := args, []any(nil)
+	if isKVPairs(args) {
+		message, pairs = nil, args
+	} else if len(args) > 1 && isKVPairs(args[1:]) {
+		if _, ok := args[0].(string); ok {
+			message, pairs = args[:1], args[1:]
+		}
+	}
+	pairs = f.resolveReserved(pairs, logfmtReservedKeys)
+	if len(message) > 0 {
+		var msg []byte
+		for i, arg := range message {
+			f.convertValue(&msg, arg, serializer, i > 0)
+		}
+		field("msg", msg)
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		var value []byte
+		f.convertValue(&value, pairs[i+1], serializer, false)
+		field(f.sanitizer.Sanitize(pairs[i].(string)), value)
+	}
+
+	f.buf = append(f.buf, '\n')
+	return f.buf
+}
+
+// appendLogfmtValue appends a logfmt value, quoted when empty or containing spaces, quotes, '=', or control characters
+func appendLogfmtValue(buf []byte, value string) []byte {
+	needsQuotes := value == "" || strings.IndexFunc(value, func(r rune) bool {
+		return r == '"' || r == '=' || r == '\\' || unicode.IsSpace(r) || !unicode.IsPrint(r)
+	}) >= 0
+	if !needsQuotes {
+		return append(buf, value...)
+	}
+	return strconv.AppendQuote(buf, value)
+}
\ No newline at end of file
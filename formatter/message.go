@@ -0,0 +1,26 @@
+package formatter
+
+import "strings"
+
+// MessageLen returns the number of leading arguments forming the message of a record whose other arguments are
+// key-value pairs: 2 for a leading "msg" pair, otherwise the shortest prefix after which the arguments pair up
+// Keys are made of letters, digits, '_', '.', and '-', so a leading message with spaces is not taken for a key
+// The pairs are scanned once from the end, callers run it on every record
+func MessageLen(args []any) int {
+	start := len(args)
+	for start >= 2 && isPairKey(args[start-2]) {
+		start -= 2
+	}
+	if start <= 2 && len(args) >= 2 && len(args)%2 == 0 && args[0] == "msg" {
+		return 2
+	}
+	return start
+}
+
+// isPairKey reports whether an argument can be the key of a key-value pair
+func isPairKey(arg any) bool {
+	key, ok := arg.(string)
+	return ok && key != "" && strings.IndexFunc(key, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' && r != '.' && r != '-'
+	}) < 0
+}
\ No newline at end of file
@@ -0,0 +1,290 @@
+package formatter
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// MessagePack record layout: one map per record, records follow each other without separators
+//
+//	time    timestamp extension (type -1), when timestamps are shown
+//	level   level name, when levels are shown
+//	trace   function trace, when present
+//	labels  map of label names to values, when present
+//	message and fields (map) for structured records, top-level keys for key-value pairs with AutoKV,
+//	        otherwise fields (array) holding the arguments
+//
+// Values keep their types: integers, floats, booleans, strings, binary, nil, and timestamps; nested []any and
+// map[string]any are encoded as arrays and maps, other types as their string form
+
+// msgpackTimestamp is the extension type of MessagePack timestamps
+const msgpackTimestamp = -1
+
+// formatMsgpack encodes a record as a MessagePack map, raw records keep their arguments as fields
+// Strings are stored without sanitization, MessagePack frames every value with its length
+func (f *Formatter) formatMsgpack(flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
+	showTimestamp := flags&FlagShowTimestamp != 0 && flags&FlagRaw == 0
+	showLevel := flags&FlagShowLevel != 0 && flags&FlagRaw == 0
+
+	var message string
+	var structured map[string]any
+	if flags&FlagStructuredJSON != 0 && len(args) >= 2 {
+		if msg, ok := args[0].(string); ok {
+			if fields, ok := args[1].(map[string]any); ok {
+				message, structured = msg, fields
+			}
+		}
+	}
+	flat := structured == nil && f.autoKV && isKVPairs(args)
+	if flat {
+		args = f.resolveReserved(args, reservedKeys)
+	}
+
+	size := 0
+	for _, present := range []bool{showTimestamp, showLevel, trace != "", len(f.labels) > 0} {
+		if present {
+			size++
+		}
+	}
+	switch {
+	case structured != nil:
+		size += 2
+	case flat:
+		size += len(args) / 2
+	case len(args) > 0:
+		size++
+	}
+
+	f.buf = appendMsgpackMapHeader(f.buf, size)
+	if showTimestamp {
+		f.buf = appendMsgpackString(f.buf, "time")
+		f.buf = appendMsgpackTime(f.buf, timestamp)
+	}
+	if showLevel {
+		f.buf = appendMsgpackString(f.buf, "level")
+		f.buf = appendMsgpackString(f.buf, LevelToString(level))
+	}
+	if trace != "" {
+		f.buf = appendMsgpackString(f.buf, "trace")
+		f.buf = appendMsgpackString(f.buf, trace)
+	}
+	if len(f.labels) > 0 {
+		f.buf = appendMsgpackString(f.buf, "labels")
+		f.buf = appendMsgpackMapHeader(f.buf, len(f.labels))
+		for _, key := range f.labelKeys() {
+			f.buf = appendMsgpackString(f.buf, key)
+			f.buf = appendMsgpackString(f.buf, f.labels[key])
+		}
+	}
+
+	switch {
+	case structured != nil:
+		f.buf = appendMsgpackString(f.buf, "message")
+		f.buf = appendMsgpackString(f.buf, message)
+		f.buf = appendMsgpackString(f.buf, "fields")
+		f.buf = appendMsgpackValue(f.buf, structured)
+	case flat:
+		for i := 0; i < len(args); i += 2 {
+			f.buf = appendMsgpackString(f.buf, args[i].(string))
+			f.buf = appendMsgpackValue(f.buf, args[i+1])
+		}
+	case len(args) > 0:
+		f.buf = appendMsgpackString(f.buf, "fields")
+		f.buf = appendMsgpackValue(f.buf, args)
+	}
+	return f.buf
+}
+
+// appendMsgpackValue appends a value in its MessagePack form
+func appendMsgpackValue(buf []byte, v any) []byte {
+	switch val := v.(type) {
+	case nil:
+		return append(buf, 0xc0)
+	case bool:
+		if val {
+			return append(buf, 0xc3)
+		}
+		return append(buf, 0xc2)
+	case int:
+		return appendMsgpackInt(buf, int64(val))
+	case int8:
+		return appendMsgpackInt(buf, int64(val))
+	case int16:
+		return appendMsgpackInt(buf, int64(val))
+	case int32:
+		return appendMsgpackInt(buf, int64(val))
+	case int64:
+		return appendMsgpackInt(buf, val)
+	case uint:
+		return appendMsgpackUint(buf, uint64(val))
+	case uint8:
+		return appendMsgpackUint(buf, uint64(val))
+	case uint16:
+		return appendMsgpackUint(buf, uint64(val))
+	case uint32:
+		return appendMsgpackUint(buf, uint64(val))
+	case uint64:
+		return appendMsgpackUint(buf, val)
+	case float32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xca), math.Float32bits(val))
+	case float64:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcb), math.Float64bits(val))
+	case string:
+		return appendMsgpackString(buf, val)
+	case []byte:
+		if val == nil {
+			return append(buf, 0xc0)
+		}
+		return appendMsgpackBinary(buf, val)
+	case json.RawMessage:
+		if val == nil {
+			return append(buf, 0xc0)
+		}
+		return appendMsgpackString(buf, string(val))
+	case time.Time:
+		return appendMsgpackTime(buf, val)
+	case []any:
+		buf = appendMsgpackArrayHeader(buf, len(val))
+		for _, item := range val {
+			buf = appendMsgpackValue(buf, item)
+		}
+		return buf
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf = appendMsgpackMapHeader(buf, len(keys))
+		for _, key := range keys {
+			buf = appendMsgpackString(buf, key)
+			buf = appendMsgpackValue(buf, val[key])
+		}
+		return buf
+	}
+
+	// Typed nils are stored like an untyped nil, other values as their string form
+	if isNilValue(v) {
+		return append(buf, 0xc0)
+	}
+	switch val := v.(type) {
+	case RawJSON:
+		return appendMsgpackString(buf, string(val.RawJSON()))
+	case error:
+		return appendMsgpackString(buf, val.Error())
+	case fmt.Stringer:
+		return appendMsgpackString(buf, val.String())
+	default:
+		return appendMsgpackString(buf, fmt.Sprintf("%+v", val))
+	}
+}
+
+// appendMsgpackTime appends a timestamp extension in its shortest form: 32-bit seconds, 64-bit seconds and
+// nanoseconds, or 96-bit for times outside 1970 to 2514
+func appendMsgpackTime(buf []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case sec >= 0 && sec>>34 == 0 && nsec == 0:
+		buf = append(buf, 0xd6, byte(msgpackTimestamp&0xff))
+		return binary.BigEndian.AppendUint32(buf, uint32(sec))
+	case sec >= 0 && sec>>34 == 0:
+		buf = append(buf, 0xd7, byte(msgpackTimestamp&0xff))
+		return binary.BigEndian.AppendUint64(buf, nsec<<34|uint64(sec))
+	default:
+		buf = append(buf, 0xc7, 12, byte(msgpackTimestamp&0xff))
+		buf = binary.BigEndian.AppendUint32(buf, uint32(nsec))
+		return binary.BigEndian.AppendUint64(buf, uint64(sec))
+	}
+}
+
+// appendMsgpackArrayHeader appends the header of an array with n elements
+func appendMsgpackArrayHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xdc), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(buf, 0xdd), uint32(n))
+	}
+}
+
+// appendMsgpackMapHeader appends the header of a map with n key-value pairs
+func appendMsgpackMapHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xde), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(buf, 0xdf), uint32(n))
+	}
+}
+
+// appendMsgpackString appends a UTF-8 string
+func appendMsgpackString(buf []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, 0xda), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xdb), uint32(n))
+	}
+	return append(buf, s...)
+}
+
+// appendMsgpackBinary appends a byte string
+func appendMsgpackBinary(buf []byte, b []byte) []byte {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, 0xc5), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xc6), uint32(n))
+	}
+	return append(buf, b...)
+}
+
+// appendMsgpackInt appends a signed integer in its shortest form
+func appendMsgpackInt(buf []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendMsgpackUint(buf, uint64(v))
+	case v >= -32:
+		return append(buf, byte(v))
+	case v >= math.MinInt8:
+		return append(buf, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(v))
+	}
+}
+
+// appendMsgpackUint appends an unsigned integer in its shortest form
+func appendMsgpackUint(buf []byte, v uint64) []byte {
+	switch {
+	case v < 128:
+		return append(buf, byte(v))
+	case v <= math.MaxUint8:
+		return append(buf, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xce), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcf), v)
+	}
+}
\ No newline at end of file
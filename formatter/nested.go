@@ -0,0 +1,110 @@
+package formatter
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// NestedKeys sets whether JSON and ECS output expands dotted field keys into nested objects,
+// e.g. "http.status" -> {"http":{"status":...}}; disabled keeps them as flat keys
+func (f *Formatter) NestedKeys(enable bool) *Formatter {
+	f.nestedKeys = enable
+	return f
+}
+
+// fieldNode is a member of a nested field object, a value or a group of members sharing a key prefix
+type fieldNode struct {
+	name     string
+	value    any
+	children []*fieldNode // Members of a group, nil for values
+}
+
+// appendMembers appends key-value pairs as JSON object members, nested by dotted keys when enabled or set by the record
+// A dotted key stays flat when one of its prefixes is a key of its own, in fields or taken by the record
+// Returns whether a member was written, for the caller's comma handling
+func (f *Formatter) appendMembers(fields []any, taken []string, serializer *sanitizer.Serializer, needsComma bool) bool {
+	if !f.nestedKeys && !f.nestRecord {
+		for i := 0; i+1 < len(fields); i += 2 {
+			if needsComma {
+				f.buf = append(f.buf, ',')
+			}
+			serializer.WriteString(&f.buf, fields[i].(string))
+			f.buf = append(f.buf, ':')
+			f.convertValue(&f.buf, fields[i+1], serializer, false)
+			needsComma = true
+		}
+		return needsComma
+	}
+
+	keys := make([]string, 0, len(fields)/2+len(taken))
+	keys = append(keys, taken...)
+	for i := 0; i+1 < len(fields); i += 2 {
+		keys = append(keys, fields[i].(string))
+	}
+
+	root := &fieldNode{}
+	for i := 0; i+1 < len(fields); i += 2 {
+		key := fields[i].(string)
+		path := nestedPath(key, keys)
+		group := root
+		for _, segment := range path[:len(path)-1] {
+			group = group.group(segment)
+		}
+		group.children = append(group.children, &fieldNode{name: path[len(path)-1], value: fields[i+1]})
+	}
+
+	for _, member := range root.children {
+		if needsComma {
+			f.buf = append(f.buf, ',')
+		}
+		f.appendNode(member, serializer)
+		needsComma = true
+	}
+	return needsComma
+}
+
+// nestedPath returns the segments key is nested under, the whole key when it stays flat
+func nestedPath(key string, keys []string) []string {
+	segments := strings.Split(key, ".")
+	if len(segments) == 1 || slices.Contains(segments, "") {
+		return []string{key}
+	}
+	for i := 1; i < len(segments); i++ {
+		if slices.Contains(keys, strings.Join(segments[:i], ".")) {
+			return []string{key}
+		}
+	}
+	return segments
+}
+
+// group returns the member group named name, adding it after the existing members when absent
+func (n *fieldNode) group(name string) *fieldNode {
+	for _, child := range n.children {
+		if child.name == name && child.children != nil {
+			return child
+		}
+	}
+	child := &fieldNode{name: name, children: []*fieldNode{}}
+	n.children = append(n.children, child)
+	return child
+}
+
+// appendNode appends a member, groups as objects holding their members in insertion order
+func (f *Formatter) appendNode(n *fieldNode, serializer *sanitizer.Serializer) {
+	serializer.WriteString(&f.buf, n.name)
+	f.buf = append(f.buf, ':')
+	if n.children == nil {
+		f.convertValue(&f.buf, n.value, serializer, false)
+		return
+	}
+	f.buf = append(f.buf, '{')
+	for i, child := range n.children {
+		if i > 0 {
+			f.buf = append(f.buf, ',')
+		}
+		f.appendNode(child, serializer)
+	}
+	f.buf = append(f.buf, '}')
+}
\ No newline at end of file
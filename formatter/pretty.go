@@ -0,0 +1,166 @@
+package formatter
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// prettyTimestamp is the short time of day shown by the pretty format, timestamp_format does not apply
+const prettyTimestamp = "15:04:05.000"
+
+// prettyLevelWidth pads level names so messages start in the same column
+const prettyLevelWidth = 5
+
+// prettyIndent prefixes the field lines below a message and the continuation lines of a multi-line message
+const prettyIndent = "    "
+
+// formatPretty renders a record for people reading a terminal: short time, padded level, and the message on the first
+// line, then one indented key=value line per field; multi-line values continue at their value's column
+func (f *Formatter) formatPretty(flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
+	// Values are rendered unsanitized and sanitized line by line, so policies escaping newlines keep multi-line values
+	serializer := sanitizer.NewSerializer("raw", sanitizer.New())
+	needsSpace := false
+
+	if flags&FlagShowTimestamp != 0 {
+		f.buf = timestamp.AppendFormat(f.buf, prettyTimestamp)
+		needsSpace = true
+	}
+
+	if flags&FlagShowLevel != 0 {
+		if needsSpace {
+			f.buf = append(f.buf, ' ')
+		}
+		name := LevelToString(level)
+		color := ""
+		if f.levelColor != nil {
+			color = f.levelColor(level)
+		}
+		f.buf = append(f.buf, color...)
+		f.buf = append(f.buf, name...)
+		if color != "" {
+			f.buf = append(f.buf, ansiReset...)
+		}
+		for i := len(name); i < prettyLevelWidth; i++ {
+			f.buf = append(f.buf, ' ')
+		}
+		needsSpace = true
+	}
+
+	if len(f.labels) > 0 {
+		if needsSpace {
+			f.buf = append(f.buf, ' ')
+		}
+		f.appendLabelGroup(sanitizer.NewSerializer("raw", f.sanitizer))
+		needsSpace = true
+	}
+
+	if trace != "" {
+		if needsSpace {
+			f.buf = append(f.buf, ' ')
+		}
+		f.buf = append(f.buf, f.sanitizer.Sanitize(trace)...)
+		needsSpace = true
+	}
+
+	message, fields := splitPretty(flags, args)
+	for _, arg := range message {
+		if needsSpace {
+			f.buf = append(f.buf, ' ')
+		}
+		f.appendPrettyValue(arg, serializer, prettyIndent)
+		needsSpace = true
+	}
+	// Level padding is left out when nothing follows it
+	f.buf = bytes.TrimRight(f.buf, " ")
+
+	for i := 0; i+1 < len(fields); i += 2 {
+		key := f.sanitizer.Sanitize(fields[i].(string))
+		f.buf = append(f.buf, '\n')
+		f.buf = append(f.buf, prettyIndent...)
+		f.buf = append(f.buf, key...)
+		f.buf = append(f.buf, '=')
+		f.appendPrettyValue(fields[i+1], serializer, prettyIndent+strings.Repeat(" ", len(key)+1))
+	}
+
+	f.buf = append(f.buf, '\n')
+	return f.buf
+}
+
+// appendPrettyValue appends a sanitized value unquoted, indenting each of its continuation lines by indent
+func (f *Formatter) appendPrettyValue(v any, serializer *sanitizer.Serializer, indent string) {
+	if s, ok := v.(string); ok && s == "" {
+		f.buf = append(f.buf, `""`...)
+		return
+	}
+	var value []byte
+	f.convertValue(&value, v, serializer, false)
+	for i, line := range strings.Split(strings.TrimRight(string(value), "\n"), "\n") {
+		if i > 0 {
+			f.buf = append(f.buf, '\n')
+			f.buf = append(f.buf, indent...)
+		}
+		f.buf = append(f.buf, f.sanitizer.Sanitize(strings.TrimSuffix(line, "\r"))...)
+	}
+}
+
+// splitPretty separates the message of a record from its key-value fields
+// The message is a string argument surrounded by key-value pairs, such as bound fields before it and call-site fields
+// after it. When several arguments qualify, the first containing a space is taken as the more message-like, otherwise
+// the first; arguments that do not pair up this way form the message as a whole
+func splitPretty(flags int64, args []any) ([]any, []any) {
+	if flags&FlagStructuredJSON != 0 && len(args) == 2 {
+		if structured, ok := args[1].(map[string]any); ok {
+			keys := make([]string, 0, len(structured))
+			for key := range structured {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			fields := make([]any, 0, 2*len(keys))
+			for _, key := range keys {
+				fields = append(fields, key, structured[key])
+			}
+			return args[:1], fields
+		}
+	}
+
+	if len(args) > 0 && prettyPairs(args) {
+		return nil, args
+	}
+	message := -1
+	for i := 0; i < len(args); i += 2 {
+		s, ok := args[i].(string)
+		if !ok || !prettyPairs(args[:i]) || !prettyPairs(args[i+1:]) {
+			continue
+		}
+		if message < 0 {
+			message = i
+		}
+		if strings.Contains(s, " ") {
+			message = i
+			break
+		}
+	}
+	if message < 0 {
+		return args, nil
+	}
+	fields := make([]any, 0, len(args)-1)
+	fields = append(fields, args[:message]...)
+	return args[message : message+1], append(fields, args[message+1:]...)
+}
+
+// prettyPairs reports whether args alternate non-empty string keys and values, an empty list included
+func prettyPairs(args []any) bool {
+	if len(args)%2 != 0 {
+		return false
+	}
+	for i := 0; i < len(args); i += 2 {
+		if key, ok := args[i].(string); !ok || key == "" {
+			return false
+		}
+	}
+	return true
+}
\ No newline at end of file
@@ -0,0 +1,156 @@
+package formatter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// rfc5424MaxName is the longest MSGID, SD-ID, and PARAM-NAME RFC 5424 allows
+const rfc5424MaxName = 32
+
+// SyslogHeader sets the facility code, HOSTNAME, APP-NAME, PROCID, and the SD-ID of the fields element of rfc5424
+// records; empty header fields are written as "-"
+func (f *Formatter) SyslogHeader(facility int, hostname, appName, procID, sdID string) *Formatter {
+	f.syslogFacility = facility
+	f.syslogHost, f.syslogApp, f.syslogProcID, f.syslogSDID = hostname, appName, procID, sdID
+	return f
+}
+
+// formatRFC5424 renders a record as an RFC 5424 syslog line:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [fields@32473 trace="..." key="value"][labels@32473 ...] MSG
+//
+// A msgid field becomes the MSGID, other fields and the trace the parameters of the fields element, and labels a
+// labels element under the same enterprise number; raw records only set the MSG
+func (f *Formatter) formatRFC5424(flags int64, timestamp time.Time, level int64, trace string, args []any) []byte {
+	message, fields := ecsSplit(flags, args)
+	plain := sanitizer.NewSerializer("raw", f.sanitizer)
+
+	f.buf = append(f.buf, '<')
+	f.buf = strconv.AppendInt(f.buf, int64(f.syslogFacility*8+rfc5424Severity(level)), 10)
+	f.buf = append(f.buf, ">1 "...)
+	if flags&FlagShowTimestamp != 0 && flags&FlagRaw == 0 {
+		f.buf = timestamp.AppendFormat(f.buf, "2006-01-02T15:04:05.000000Z07:00")
+	} else {
+		f.buf = append(f.buf, '-')
+	}
+	for _, header := range []string{f.syslogHost, f.syslogApp, f.syslogProcID} {
+		f.buf = append(f.buf, ' ')
+		f.buf = appendRFC5424Field(f.buf, header, 255)
+	}
+
+	msgID := ""
+	params := make([]any, 0, len(fields)+2)
+	if trace != "" {
+		params = append(params, "trace", trace)
+	}
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i] == "msgid" && msgID == "" {
+			var value []byte
+			f.convertValue(&value, fields[i+1], plain, false)
+			msgID = string(value)
+			continue
+		}
+		params = append(params, fields[i], fields[i+1])
+	}
+	f.buf = append(f.buf, ' ')
+	f.buf = appendRFC5424Field(f.buf, msgID, rfc5424MaxName)
+	f.buf = append(f.buf, ' ')
+
+	labels := f.labelKeys()
+	if len(params) == 0 && len(labels) == 0 {
+		f.buf = append(f.buf, '-')
+	}
+	if len(params) > 0 {
+		f.buf = append(f.buf, '[')
+		f.buf = append(f.buf, f.syslogSDID...)
+		for i := 0; i+1 < len(params); i += 2 {
+			var value []byte
+			f.convertValue(&value, params[i+1], plain, false)
+			f.appendRFC5424Param(params[i].(string), string(value))
+		}
+		f.buf = append(f.buf, ']')
+	}
+	if len(labels) > 0 {
+		f.buf = append(f.buf, "[labels"...)
+		if _, enterprise, ok := strings.Cut(f.syslogSDID, "@"); ok {
+			f.buf = append(f.buf, '@')
+			f.buf = append(f.buf, enterprise...)
+		}
+		for _, key := range labels {
+			f.appendRFC5424Param(key, f.sanitizer.Sanitize(f.labels[key]))
+		}
+		f.buf = append(f.buf, ']')
+	}
+
+	var text []byte
+	for i, arg := range message {
+		f.convertValue(&text, arg, plain, i > 0)
+	}
+	if len(text) > 0 {
+		f.buf = append(f.buf, ' ')
+		f.buf = append(f.buf, text...)
+	}
+
+	f.buf = append(f.buf, '\n')
+	return f.buf
+}
+
+// appendRFC5424Param appends ` name="value"`, skipping names without allowed characters
+// '"', '\', and ']' are escaped in the value
+func (f *Formatter) appendRFC5424Param(name, value string) {
+	name = rfc5424Name(name, rfc5424MaxName, `="]`)
+	if name == "" {
+		return
+	}
+	f.buf = append(f.buf, ' ')
+	f.buf = append(f.buf, name...)
+	f.buf = append(f.buf, '=', '"')
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '"', '\\', ']':
+			f.buf = append(f.buf, '\\', c)
+		default:
+			f.buf = append(f.buf, c)
+		}
+	}
+	f.buf = append(f.buf, '"')
+}
+
+// appendRFC5424Field appends a header field cut to limit bytes, "-" when it has no allowed characters
+func appendRFC5424Field(buf []byte, value string, limit int) []byte {
+	if value = rfc5424Name(value, limit, ""); value == "" {
+		return append(buf, '-')
+	}
+	return append(buf, value...)
+}
+
+// rfc5424Name keeps the printable US-ASCII characters of name not listed in excluded, cut to limit bytes
+func rfc5424Name(name string, limit int, excluded string) string {
+	kept := make([]byte, 0, min(len(name), limit))
+	for i := 0; i < len(name) && len(kept) < limit; i++ {
+		if c := name[i]; c > ' ' && c < 0x7f && strings.IndexByte(excluded, c) < 0 {
+			kept = append(kept, c)
+		}
+	}
+	return string(kept)
+}
+
+// rfc5424Severity maps log levels to syslog severities, heartbeats are notices
+func rfc5424Severity(level int64) int {
+	switch {
+	case level >= 12:
+		return 5
+	case level >= 8:
+		return 3
+	case level >= 4:
+		return 4
+	case level >= 0:
+		return 6
+	default:
+		return 7
+	}
+}
\ No newline at end of file
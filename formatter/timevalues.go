@@ -0,0 +1,67 @@
+package formatter
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// DurationFormats are the renderings accepted by DurationFormat
+//
+//	string  Go duration text, e.g. "1.5s"
+//	ns      integer nanoseconds
+//	ms      fractional milliseconds
+var DurationFormats = []string{"string", "ns", "ms"}
+
+// TimeValueFormats are the renderings accepted by TimeValueFormat
+//
+//	timestamp  text in the timestamp format of the record's own time
+//	rfc3339    RFC 3339 text with nanoseconds, in UTC
+//	unix       integer seconds since the Unix epoch
+//	unix_ms    integer milliseconds since the Unix epoch
+var TimeValueFormats = []string{"timestamp", "rfc3339", "unix", "unix_ms"}
+
+// DurationFormat sets how time.Duration arguments are rendered in text and JSON formats, empty keeps the current one
+// Binary and msgpack records keep their own encodings
+func (f *Formatter) DurationFormat(format string) *Formatter {
+	if format != "" {
+		f.durationFormat = format
+	}
+	return f
+}
+
+// TimeValueFormat sets how time.Time arguments are rendered in text and JSON formats, empty keeps the current one
+// Binary and msgpack records keep their typed timestamps
+func (f *Formatter) TimeValueFormat(format string) *Formatter {
+	if format != "" {
+		f.timeValueFormat = format
+	}
+	return f
+}
+
+// writeDuration writes a duration argument in the configured rendering
+func (f *Formatter) writeDuration(buf *[]byte, d time.Duration, serializer *sanitizer.Serializer) {
+	switch f.durationFormat {
+	case "ns":
+		serializer.WriteNumber(buf, strconv.FormatInt(int64(d), 10))
+	case "ms":
+		serializer.WriteNumber(buf, strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64))
+	default:
+		serializer.WriteString(buf, d.String())
+	}
+}
+
+// writeTimeValue writes a time argument in the configured rendering
+func (f *Formatter) writeTimeValue(buf *[]byte, t time.Time, serializer *sanitizer.Serializer) {
+	switch f.timeValueFormat {
+	case "rfc3339":
+		serializer.WriteString(buf, t.UTC().Format(time.RFC3339Nano))
+	case "unix":
+		serializer.WriteNumber(buf, strconv.FormatInt(t.Unix(), 10))
+	case "unix_ms":
+		serializer.WriteNumber(buf, strconv.FormatInt(t.UnixMilli(), 10))
+	default:
+		serializer.WriteString(buf, t.Format(f.timestampFormat))
+	}
+}
\ No newline at end of file
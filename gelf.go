@@ -0,0 +1,162 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// errGELFUnavailable is recorded for records written while reconnection is backing off
+var errGELFUnavailable = fmtErrorf("GELF input unavailable, waiting to reconnect")
+
+// gelfChunkMagic starts every chunk of a GELF message split across datagrams
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+// gelfSettings are the configuration values a GELF output is built from
+type gelfSettings struct {
+	network         string
+	address         string
+	host            string
+	bytesEncoding   string
+	durationFormat  string
+	timeValueFormat string
+	sanitization    sanitizer.PolicyPreset
+}
+
+// gelfOutput ships records to a Graylog GELF input over UDP or TCP
+// The connection is established on the first write and re-established after failures, all on the processor goroutine
+type gelfOutput struct {
+	settings  gelfSettings
+	formatter *formatter.Formatter // Formats records as GELF messages
+	conn      net.Conn
+	retryAt   time.Time // No reconnect attempts before this time after a failed dial
+	status    healthTracker
+	buf       []byte // Delimited message or chunk, reused across records
+}
+
+// configureGELF returns the GELF output for cfg, reusing current when its settings are unchanged
+// Returns nil when GELF output is disabled
+func configureGELF(cfg *Config, current *gelfOutput) *gelfOutput {
+	if !cfg.EnableGELF {
+		return nil
+	}
+	settings := gelfSettings{
+		network:         cfg.GELFNetwork,
+		address:         cfg.GELFAddress,
+		host:            cfg.GELFHost,
+		bytesEncoding:   cfg.BytesEncoding,
+		durationFormat:  cfg.DurationFormat,
+		timeValueFormat: cfg.TimeValueFormat,
+		sanitization:    cfg.sanitization(cfg.NetworkSanitization, "gelf"),
+	}
+	if current != nil && current.settings == settings {
+		return current
+	}
+
+	return &gelfOutput{
+		settings: settings,
+		formatter: formatter.New(sanitizer.New().Policy(settings.sanitization)).
+			Type("gelf").
+			Host(settings.host).
+			BytesEncoding(settings.bytesEncoding).
+			DurationFormat(settings.durationFormat).
+			TimeValueFormat(settings.timeValueFormat),
+	}
+}
+
+// write sends a record as one GELF message, connecting first if needed
+// A failed write drops the connection for the next record
+func (o *gelfOutput) write(record logRecord) {
+	if o.conn == nil {
+		if time.Now().Before(o.retryAt) {
+			o.status.failure(errGELFUnavailable)
+			return
+		}
+		conn, err := net.DialTimeout(o.settings.network, o.settings.address, syslogTimeout)
+		if err != nil {
+			o.retryAt = time.Now().Add(syslogRetryInterval)
+			o.status.failure(fmtErrorf("failed to connect to GELF input at %s://%s: %w", o.settings.network, o.settings.address, err))
+			return
+		}
+		o.conn = conn
+	}
+
+	msg := o.formatter.FormatLabeled(record.Flags, record.TimeStamp, record.Level, record.Trace, record.Labels, record.Args)
+	msg = bytes.TrimRight(msg, "\n")
+	_ = o.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+
+	var err error
+	if o.settings.network == "tcp" {
+		// TCP inputs delimit messages with a null byte
+		o.buf = append(append(o.buf[:0], msg...), 0)
+		_, err = o.conn.Write(o.buf)
+	} else {
+		err = o.writeChunked(msg)
+	}
+	if err != nil {
+		o.status.failure(fmtErrorf("failed to write to GELF input: %w", err))
+		o.close()
+		return
+	}
+	o.status.success()
+}
+
+// writeChunked sends a message as one datagram, or as numbered chunks sharing a random message ID when it
+// exceeds gelfChunkSize. Messages needing more than gelfMaxChunks chunks are dropped with an error
+func (o *gelfOutput) writeChunked(msg []byte) error {
+	if len(msg) <= gelfChunkSize {
+		_, err := o.conn.Write(msg)
+		return err
+	}
+
+	payload := gelfChunkSize - gelfChunkHeaderSize
+	count := (len(msg) + payload - 1) / payload
+	if count > gelfMaxChunks {
+		return fmtErrorf("message of %d bytes needs %d chunks, GELF allows %d", len(msg), count, gelfMaxChunks)
+	}
+	id := rand.Uint64()
+	for seq := range count {
+		chunk := msg[seq*payload : min((seq+1)*payload, len(msg))]
+		// Magic bytes, message ID, sequence number, and sequence count precede each chunk
+		o.buf = append(o.buf[:0], gelfChunkMagic...)
+		o.buf = binary.BigEndian.AppendUint64(o.buf, id)
+		o.buf = append(o.buf, byte(seq), byte(count))
+		o.buf = append(o.buf, chunk...)
+		if _, err := o.conn.Write(o.buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close releases the connection, the next write reconnects
+func (o *gelfOutput) close() {
+	if o.conn != nil {
+		_ = o.conn.Close()
+		o.conn = nil
+	}
+}
+
+// health reports delivery health of the GELF output
+func (o *gelfOutput) health(name string) SinkHealth {
+	return o.status.snapshot(name)
+}
+
+// validateGELF checks the GELF output settings of cfg
+func validateGELF(c *Config) error {
+	if !c.EnableGELF {
+		return nil
+	}
+	if c.GELFNetwork != "udp" && c.GELFNetwork != "tcp" {
+		return fmtErrorf("invalid gelf_network: '%s' (use udp or tcp)", c.GELFNetwork)
+	}
+	if _, _, err := net.SplitHostPort(c.GELFAddress); err != nil {
+		return fmtErrorf("invalid gelf_address '%s': %w", c.GELFAddress, err)
+	}
+	return nil
+}
\ No newline at end of file
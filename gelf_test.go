@@ -0,0 +1,136 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGELFUDP verifies GELF datagrams carry the message and fields, and large messages are chunked
+func TestGELFUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString(
+		"enable_gelf=true",
+		"gelf_address="+conn.LocalAddr().String(),
+		"gelf_host=web-1",
+		"format=txt",
+	))
+
+	logger.Warn("disk slow", "latency_ms", 250)
+	require.NoError(t, logger.Flush(time.Second))
+
+	buf := make([]byte, 65536)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	var msg map[string]any
+	require.NoError(t, json.Unmarshal(buf[:n], &msg), string(buf[:n]))
+	assert.Equal(t, "web-1", msg["host"])
+	assert.Equal(t, "disk slow", msg["short_message"])
+	assert.Equal(t, 4.0, msg["level"])
+	assert.Equal(t, 250.0, msg["_latency_ms"])
+
+	// A record above the chunk size arrives as ordered chunks of one message
+	large := strings.Repeat("x", 3*gelfChunkSize)
+	logger.Info("large", "payload", large)
+	require.NoError(t, logger.Flush(time.Second))
+
+	var chunks [][]byte
+	for len(chunks) == 0 || len(chunks) < int(chunks[0][11]) {
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		require.LessOrEqual(t, n, gelfChunkSize)
+		require.Equal(t, gelfChunkMagic, buf[:2])
+		chunks = append(chunks, append([]byte(nil), buf[:n]...))
+	}
+	var whole []byte
+	for i, chunk := range chunks {
+		assert.Equal(t, chunks[0][2:10], chunk[2:10], "Chunks share the message ID")
+		assert.Equal(t, byte(i), chunk[10])
+		whole = append(whole, chunk[gelfChunkHeaderSize:]...)
+	}
+	msg = nil
+	require.NoError(t, json.Unmarshal(whole, &msg))
+	assert.Equal(t, large, msg["_payload"])
+
+	for _, sink := range logger.Stats().Sinks {
+		if sink.Name == "gelf" {
+			assert.Equal(t, SinkStatusOK, sink.Status)
+		}
+	}
+}
+
+// TestGELFTCP verifies stream messages are null-byte delimited
+func TestGELFTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	messages := make(chan []byte, 4)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			data, err := reader.ReadBytes(0)
+			if err != nil {
+				return
+			}
+			messages <- bytes.TrimSuffix(data, []byte{0})
+		}
+	}()
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString(
+		"enable_gelf=true",
+		"gelf_network=tcp",
+		"gelf_address="+ln.Addr().String(),
+	))
+
+	logger.Info("first")
+	logger.Error("second", "code", "E1")
+	require.NoError(t, logger.Flush(time.Second))
+
+	for _, want := range []string{"first", "second"} {
+		select {
+		case data := <-messages:
+			var msg map[string]any
+			require.NoError(t, json.Unmarshal(data, &msg), string(data))
+			assert.Equal(t, want, msg["short_message"])
+		case <-time.After(2 * time.Second):
+			t.Fatalf("message %q not received", want)
+		}
+	}
+}
+
+// TestGELFValidation verifies the GELF network and address are checked when enabled
+func TestGELFValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.EnableGELF = true
+	cfg.GELFAddress = "graylog:12201"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.GELFNetwork = "unix"
+	assert.Error(t, cfg.Validate())
+
+	cfg.GELFNetwork = "tcp"
+	cfg.GELFAddress = "graylog"
+	assert.Error(t, cfg.Validate())
+}
\ No newline at end of file
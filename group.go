@@ -0,0 +1,77 @@
+package log
+
+import (
+	"strings"
+
+	"github.com/lixenwraith/log/formatter"
+)
+
+// WithGroup returns a logger sharing this logger's output whose later bound fields and per-call key-value pairs are
+// nested under name, as slog groups are. Keys are qualified as "name.key" and records of the logger expand dotted
+// keys into nested objects wherever pairs become JSON members (auto_kv, json_fields=object, ecs); text formats show
+// the dotted keys. Groups nest across calls, fields bound before the group stay at the top level
+// An empty name returns the logger itself
+func (l *Logger) WithGroup(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	group := name
+	if l.group != "" {
+		group = l.group + "." + name
+	}
+	return &Logger{loggerCore: l.loggerCore, fields: l.fields, labels: l.labels, group: group}
+}
+
+// applyGroup qualifies the keys of a record's arguments with the logger's group and marks the record for nested keys
+// Raw records and loggers without a group are left untouched
+func (l *Logger) applyGroup(flags int64, args []any) (int64, []any) {
+	if l.group == "" || flags&FlagRaw != 0 {
+		return flags, args
+	}
+	if flags == 0 {
+		// Ingested records without flags keep the configured defaults
+		flags = l.getFlags()
+	}
+	return flags | FlagNestedKeys, groupArgs(l.group, flags, args)
+}
+
+// groupArgs qualifies the keys of a record's arguments with group
+// Structured records get their field map nested under the group, other records the string keys of their pairs after
+// the message arguments, a leading "msg" key written by the compat adapters is kept
+func groupArgs(group string, flags int64, args []any) []any {
+	if flags&FlagStructuredJSON != 0 && len(args) == 2 {
+		if fields, ok := args[1].(map[string]any); ok {
+			path := strings.Split(group, ".")
+			var nested any = fields
+			for i := len(path) - 1; i >= 0; i-- {
+				nested = map[string]any{path[i]: nested}
+			}
+			return []any{args[0], nested}
+		}
+	}
+	return groupPairs(group, formatter.MessageLen(args), args)
+}
+
+// groupPairs returns args with the string keys of the pairs starting at start prefixed by "group."
+// The args slice is copied only if a key is qualified, leaving the caller's slice untouched
+func groupPairs(group string, start int, args []any) []any {
+	if group == "" {
+		return args
+	}
+	var out []any
+	for i := start; i+1 < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok || (i == 0 && key == "msg") {
+			continue
+		}
+		if out == nil {
+			out = make([]any, len(args))
+			copy(out, args)
+		}
+		out[i] = group + "." + key
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
\ No newline at end of file
@@ -0,0 +1,109 @@
+package log
+
+import (
+	"compress/gzip"
+	"os"
+	"sync/atomic"
+)
+
+// gzipFlushBytes is the uncompressed volume after which the active gzip stream emits a flush point
+const gzipFlushBytes = 64 * 1024
+
+// gzipStream compresses writes to the active log file when gzip_active is enabled
+// Each open of the file appends a gzip member, flush points make everything written before them readable
+// Used by the processor only, under batchMu
+type gzipStream struct {
+	file    *os.File
+	zw      *gzip.Writer
+	size    atomic.Int64 // Compressed bytes in the file, including members written before this open
+	pending int64        // Uncompressed bytes since the last flush point
+}
+
+// newGzipStream starts a gzip member at the end of file
+func newGzipStream(file *os.File) *gzipStream {
+	s := &gzipStream{file: file}
+	if fi, err := file.Stat(); err == nil {
+		s.size.Store(fi.Size())
+	}
+	s.zw = gzip.NewWriter(fileCounter{s})
+	return s
+}
+
+// fileCounter passes compressed output to the file and counts it
+type fileCounter struct {
+	s *gzipStream
+}
+
+// Write appends compressed data to the log file
+func (c fileCounter) Write(p []byte) (int, error) {
+	n, err := c.s.file.Write(p)
+	c.s.size.Add(int64(n))
+	return n, err
+}
+
+// write compresses data into the stream, emitting a flush point every gzipFlushBytes
+// Returns the uncompressed bytes accepted
+func (s *gzipStream) write(data []byte) (int, error) {
+	n, err := s.zw.Write(data)
+	if err != nil {
+		return n, err
+	}
+	s.pending += int64(n)
+	if s.pending >= gzipFlushBytes {
+		return n, s.flush()
+	}
+	return n, nil
+}
+
+// flush writes a flush point, readers decompress everything written so far
+func (s *gzipStream) flush() error {
+	if s.pending == 0 {
+		return nil
+	}
+	s.pending = 0
+	return s.zw.Flush()
+}
+
+// close completes the gzip member, the file itself stays open
+func (s *gzipStream) close() error {
+	return s.zw.Close()
+}
+
+// getGzipStream returns the stream compressing writes to file, nil when gzip_active is disabled
+func (l *Logger) getGzipStream(file *os.File) *gzipStream {
+	if s, ok := l.state.gzipStreams.Load(file); ok {
+		return s.(*gzipStream)
+	}
+	return nil
+}
+
+// closeGzipStream completes the gzip member of file before it is closed, no-op for uncompressed files
+func (l *Logger) closeGzipStream(file *os.File) error {
+	s, ok := l.state.gzipStreams.LoadAndDelete(file)
+	if !ok {
+		return nil
+	}
+	if err := s.(*gzipStream).close(); err != nil {
+		return fmtErrorf("failed to complete gzip stream of log file '%s': %w", file.Name(), err)
+	}
+	return nil
+}
+
+// addFileSize advances the current size counter by n bytes written, or to the compressed size of stream
+func (l *Logger) addFileSize(stream *gzipStream, n int) {
+	if stream != nil {
+		l.state.CurrentSize.Store(stream.size.Load())
+		return
+	}
+	l.state.CurrentSize.Add(int64(n))
+}
+
+// activateLogFile makes file the active log file, starting its gzip stream when gzip_active is enabled and
+// writing the header; the current size counter is seeded from the result
+func (l *Logger) activateLogFile(c *Config, file *os.File) {
+	if c.GzipActive {
+		l.state.gzipStreams.Store(file, newGzipStream(file))
+	}
+	l.state.CurrentFile.Store(file)
+	l.state.CurrentSize.Store(l.writeFileHeader(c, file))
+}
\ No newline at end of file
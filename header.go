@@ -0,0 +1,107 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+)
+
+// fileHeader is the metadata written as the first line of each new log file
+type fileHeader struct {
+	SchemaVersion int    `json:"schema_version"`
+	Host          string `json:"host"`
+	PID           int    `json:"pid"`
+	StartTime     string `json:"start_time"`
+	Format        string `json:"format"`
+	Name          string `json:"name"`
+}
+
+// writeFileHeader writes the metadata header to an empty log file if FileHeader is enabled
+// Returns the file size after the write, used to seed the current size counter
+func (l *Logger) writeFileHeader(c *Config, file *os.File) int64 {
+	fi, err := file.Stat()
+	if err != nil {
+		return 0
+	}
+	// Reopened active files already carry a header or predate the option
+	if !c.FileHeader || fi.Size() > 0 {
+		return fi.Size()
+	}
+
+	data := l.formatFileHeader(c)
+	if stream := l.getGzipStream(file); stream != nil {
+		_, err := stream.write(data)
+		if err == nil {
+			err = stream.flush()
+		}
+		if err != nil {
+			l.internalLog("failed to write log file header: %v\n", err)
+		}
+		return stream.size.Load()
+	}
+	n, err := file.Write(data)
+	if err != nil {
+		l.internalLog("failed to write log file header: %v\n", err)
+	}
+	return int64(n)
+}
+
+// formatFileHeader renders the header in a form parsers of the configured format can recognize and skip
+// JSON, ECS, and GELF files get a {"log_header":{...}} object, binary files a regular record, text formats a '#' comment line
+// CSV has no comments, its files get a row of column names instead, and RFC 5424 files a record with msgid log_header
+func (l *Logger) formatFileHeader(c *Config) []byte {
+	host, _ := os.Hostname()
+	startTime, _ := l.state.LoggerStartTime.Load().(time.Time)
+	header := fileHeader{
+		SchemaVersion: fileHeaderSchemaVersion,
+		Host:          host,
+		PID:           os.Getpid(),
+		StartTime:     startTime.Format(time.RFC3339Nano),
+		Format:        c.fileFormat(),
+		Name:          c.Name,
+	}
+
+	switch header.Format {
+	case "json", "ecs", "gcp", "gelf":
+		data, _ := json.Marshal(map[string]fileHeader{"log_header": header})
+		return append(data, '\n')
+	case "binary":
+		return formatter.New().Type("binary").Format(0, time.Now(), LevelInfo, "", []any{
+			"log_header",
+			"schema_version", header.SchemaVersion,
+			"host", header.Host,
+			"pid", header.PID,
+			"start_time", header.StartTime,
+			"format", header.Format,
+			"name", header.Name,
+		})
+	case "msgpack":
+		return formatter.New().Type("msgpack").AutoKV(true).Format(FlagRaw, time.Now(), LevelInfo, "", []any{
+			"log_header", map[string]any{
+				"schema_version": header.SchemaVersion,
+				"host":           header.Host,
+				"pid":            header.PID,
+				"start_time":     header.StartTime,
+				"format":         header.Format,
+				"name":           header.Name,
+			},
+		})
+	case "csv":
+		return append([]byte(strings.Join(c.csvColumns(), ",")), '\n')
+	case "rfc5424":
+		return newRFC5424Settings(c).apply(formatter.New().Type("rfc5424")).Format(FlagShowTimestamp, startTime, LevelInfo, "", []any{
+			"msgid", "log_header",
+			"schema_version", header.SchemaVersion,
+			"format", header.Format,
+			"name", header.Name,
+			"start_time", header.StartTime,
+		})
+	default:
+		return fmt.Appendf(nil, "# log_header schema_version=%d host=%s pid=%d start_time=%s format=%s name=%s\n",
+			header.SchemaVersion, header.Host, header.PID, header.StartTime, header.Format, header.Name)
+	}
+}
\ No newline at end of file
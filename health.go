@@ -0,0 +1,169 @@
+package log
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Errors recorded for file output that drops records without a write attempt
+var (
+	errDiskLimit = fmtErrorf("disk space or size limit exceeded, records dropped")
+	errNoLogFile = fmtErrorf("no active log file")
+)
+
+// Sink health states reported in Stats and the DISK heartbeat
+const (
+	SinkStatusOK       = "ok"       // Last write succeeded
+	SinkStatusDegraded = "degraded" // Recent writes failed, fewer than the failure threshold in a row
+	SinkStatusFailed   = "failed"   // Writes keep failing
+)
+
+// SinkHealth is a snapshot of an output's health
+type SinkHealth struct {
+	Name              string    // "file", "console", "file:<shard name>", or "forward:<target name>"
+	Status            string    // SinkStatusOK, SinkStatusDegraded, or SinkStatusFailed
+	ConsecutiveErrors uint64    // Errors since the last successful write
+	TotalErrors       uint64    // Errors since logger creation
+	LastError         string    // Most recent error message, empty if none occurred
+	LastErrorTime     time.Time // Time of the most recent error
+	FailingSince      time.Time // Time of the first error of the current error run, zero when ok
+}
+
+// healthEvent is the most recent error of an output
+type healthEvent struct {
+	message string
+	time    time.Time
+	since   time.Time
+}
+
+// healthTracker records write outcomes of an output, safe for concurrent use
+type healthTracker struct {
+	consecutive atomic.Uint64
+	total       atomic.Uint64
+	last        atomic.Value // stores *healthEvent
+}
+
+// success resets the consecutive error count, avoiding a store on the common healthy path
+func (h *healthTracker) success() {
+	if h.consecutive.Load() != 0 {
+		h.consecutive.Store(0)
+	}
+}
+
+// failure records a write error
+func (h *healthTracker) failure(err error) {
+	now := time.Now()
+	since := now
+	if h.consecutive.Add(1) > 1 {
+		if prev, ok := h.last.Load().(*healthEvent); ok {
+			since = prev.since
+		}
+	}
+	h.total.Add(1)
+	h.last.Store(&healthEvent{message: err.Error(), time: now, since: since})
+}
+
+// snapshot returns the current health under the given output name
+func (h *healthTracker) snapshot(name string) SinkHealth {
+	health := SinkHealth{
+		Name:              name,
+		Status:            SinkStatusOK,
+		ConsecutiveErrors: h.consecutive.Load(),
+		TotalErrors:       h.total.Load(),
+	}
+	if last, ok := h.last.Load().(*healthEvent); ok {
+		health.LastError = last.message
+		health.LastErrorTime = last.time
+		if health.ConsecutiveErrors > 0 {
+			health.FailingSince = last.since
+		}
+	}
+
+	switch {
+	case health.ConsecutiveErrors >= sinkFailedThreshold:
+		health.Status = SinkStatusFailed
+	case health.ConsecutiveErrors > 0:
+		health.Status = SinkStatusDegraded
+	}
+	return health
+}
+
+// healthReporter is implemented by sinks that track their own health, such as those delivering on their own goroutine
+type healthReporter interface {
+	health() SinkHealth
+}
+
+// sinkHealth returns the health of the console, file, shard files, health-reporting sinks, and registered sinks
+func (l *Logger) sinkHealth() []SinkHealth {
+	c := l.getConfig()
+	var sinks []SinkHealth
+
+	if c.EnableConsole {
+		sinks = append(sinks, l.state.consoleHealth.snapshot("console"))
+	}
+	if c.fileOutput() {
+		sinks = append(sinks, l.state.fileHealth.snapshot("file"))
+	}
+	if out := l.getEpoch().syslog; out != nil {
+		sinks = append(sinks, out.health("syslog"))
+	}
+	if out := l.getEpoch().journald; out != nil {
+		sinks = append(sinks, out.health("journald"))
+	}
+	if out := l.getEpoch().gelf; out != nil {
+		sinks = append(sinks, out.health("gelf"))
+	}
+	if u := l.getEpoch().s3; u != nil {
+		sinks = append(sinks, u.health("s3"))
+	}
+	if h := l.getEpoch().exec; h != nil {
+		sinks = append(sinks, h.health("exec"))
+	}
+	l.forEachShard(func(shard *Logger) {
+		sinks = append(sinks, shard.state.fileHealth.snapshot("file:"+shard.getConfig().Name))
+		if u := shard.getEpoch().s3; u != nil {
+			sinks = append(sinks, u.health("s3:"+shard.getConfig().Name))
+		}
+	})
+	if errorFile := l.getErrorFile(); errorFile != nil {
+		sinks = append(sinks, errorFile.state.fileHealth.snapshot("file:"+errorFile.getConfig().Name))
+	}
+	if mirror := l.getMirror(); mirror != nil {
+		sinks = append(sinks, mirror.state.fileHealth.snapshot("mirror:"+mirror.getConfig().Directory))
+	}
+	for _, s := range l.getSinks() {
+		if reporter, ok := s.(healthReporter); ok {
+			sinks = append(sinks, reporter.health())
+		}
+	}
+	for _, ns := range l.getOutputs() {
+		sinks = append(sinks, ns.health())
+	}
+	return sinks
+}
+
+// sinkHealthArgs returns heartbeat fields summarizing output health
+// Every output appears in "sink_status"; outputs not ok add their error count, failure start, and last error
+func sinkHealthArgs(sinks []SinkHealth) []any {
+	if len(sinks) == 0 {
+		return nil
+	}
+
+	statuses := make([]string, len(sinks))
+	var details []any
+	for i, s := range sinks {
+		statuses[i] = s.Name + "=" + s.Status
+		if s.Status == SinkStatusOK {
+			continue
+		}
+		prefix := "sink_" + strings.ReplaceAll(s.Name, ":", "_")
+		details = append(details,
+			prefix+"_errors", s.ConsecutiveErrors,
+			prefix+"_failing_since", s.FailingSince.UTC().Format(time.RFC3339),
+			prefix+"_last_error", s.LastError,
+		)
+	}
+
+	return append([]any{"sink_status", strings.Join(statuses, ",")}, details...)
+}
\ No newline at end of file
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHealthTracker verifies status transitions and failure run tracking
+func TestHealthTracker(t *testing.T) {
+	var h healthTracker
+	assert.Equal(t, SinkStatusOK, h.snapshot("file").Status)
+
+	h.failure(errors.New("first"))
+	first := h.snapshot("file")
+	assert.Equal(t, SinkStatusDegraded, first.Status)
+	assert.False(t, first.FailingSince.IsZero())
+
+	for i := 1; i < sinkFailedThreshold; i++ {
+		h.failure(errors.New("disk gone"))
+	}
+	failed := h.snapshot("file")
+	assert.Equal(t, SinkStatusFailed, failed.Status)
+	assert.Equal(t, uint64(sinkFailedThreshold), failed.ConsecutiveErrors)
+	assert.Equal(t, "disk gone", failed.LastError)
+	assert.Equal(t, first.FailingSince, failed.FailingSince, "Failure start is kept across the error run")
+
+	h.success()
+	recovered := h.snapshot("file")
+	assert.Equal(t, SinkStatusOK, recovered.Status)
+	assert.Equal(t, uint64(sinkFailedThreshold), recovered.TotalErrors)
+	assert.True(t, recovered.FailingSince.IsZero())
+	assert.Equal(t, "disk gone", recovered.LastError, "Last error remains visible after recovery")
+}
+
+// TestStatsSinkHealth verifies that Stats and the DISK heartbeat report output health
+func TestStatsSinkHealth(t *testing.T) {
+	app, _ := createTestLogger(t)
+	defer app.Shutdown()
+	audit, _ := createTestLogger(t)
+	auditCfg := audit.GetConfig()
+	auditCfg.Name = "audit"
+	require.NoError(t, audit.ApplyConfig(auditCfg))
+	require.NoError(t, app.ForwardTo(audit, nil))
+
+	app.Info("delivered")
+	require.NoError(t, app.Flush(time.Second))
+
+	sinks := app.Stats().Sinks
+	require.Len(t, sinks, 2)
+	assert.Equal(t, "file", sinks[0].Name)
+	assert.Equal(t, SinkStatusOK, sinks[0].Status)
+	assert.Equal(t, "forward:audit", sinks[1].Name)
+	assert.Equal(t, SinkStatusOK, sinks[1].Status)
+
+	require.NoError(t, audit.Shutdown())
+	for i := 0; i < sinkFailedThreshold; i++ {
+		app.Info("undeliverable")
+	}
+	require.NoError(t, app.Flush(time.Second))
+
+	forward := app.Stats().Sinks[1]
+	assert.Equal(t, SinkStatusFailed, forward.Status)
+	assert.Equal(t, errForwardTargetStopped.Error(), forward.LastError)
+
+	args := sinkHealthArgs(app.Stats().Sinks)
+	assert.Equal(t, []any{"sink_status", "file=ok,forward:audit=failed"}, args[:2])
+	assert.Contains(t, args, "sink_forward_audit_failing_since")
+	assert.Contains(t, args, errForwardTargetStopped.Error())
+}
\ No newline at end of file
@@ -27,7 +27,8 @@ func (l *Logger) handleHeartbeat() {
 // logProcHeartbeat logs process/logger statistics heartbeat
 func (l *Logger) logProcHeartbeat() {
 	processed := l.state.TotalLogsProcessed.Load()
-	sequence := l.state.HeartbeatSequence.Add(1)
+	bytesWritten := l.state.TotalBytesWritten.Load()
+	sequence := l.state.ProcSequence.Add(1)
 
 	startTimeVal := l.state.LoggerStartTime.Load()
 	var uptimeHours float64 = 0
@@ -43,44 +44,99 @@ func (l *Logger) logProcHeartbeat() {
 	// If PROC heartbeat fails, interval drops are lost and total count tracks such fails
 	// Design choice is not to parse the heartbeat log record and restore the count
 	droppedInInterval := l.state.DroppedLogs.Swap(0)
+	sizes := l.state.recordSizes.snapshot(true)
+
+	// Shard processors do the writing for sharded loggers
+	l.forEachShard(func(shard *Logger) {
+		processed += shard.state.TotalLogsProcessed.Load()
+		bytesWritten += shard.state.TotalBytesWritten.Load()
+		totalDropped += shard.state.TotalDroppedLogs.Load()
+		droppedInInterval += shard.state.DroppedLogs.Swap(0)
+		sizes.merge(shard.state.recordSizes.snapshot(true))
+	})
 
 	procArgs := []any{
 		"type", "proc",
 		"sequence", sequence,
+		"interval_s", l.heartbeatIntervalS(l.getConfig()),
 		"uptime_hours", fmt.Sprintf("%.2f", uptimeHours),
 		"processed_logs", processed,
 		"total_dropped_logs", totalDropped,
 	}
 
+	// Throughput since the previous PROC heartbeat, omitted on the first one
+	now := time.Now()
+	mark := l.state.procRateMark
+	if elapsed := now.Sub(mark.time).Seconds(); !mark.time.IsZero() && elapsed > 0 {
+		procArgs = append(procArgs,
+			"logs_per_sec", fmt.Sprintf("%.2f", ratePerSec(processed, mark.logs, elapsed)),
+			"bytes_per_sec", fmt.Sprintf("%.2f", ratePerSec(bytesWritten, mark.bytes, elapsed)),
+		)
+	}
+	l.state.procRateMark = rateMark{time: now, logs: processed, bytes: bytesWritten}
+
 	// Add interval (since last proc heartbeat) drops if > 0
 	if droppedInInterval > 0 {
 		procArgs = append(procArgs, "dropped_since_last", droppedInInterval)
 	}
 
+	if suppressed := l.state.SuppressedCancelled.Load(); suppressed > 0 {
+		procArgs = append(procArgs, "suppressed_cancelled", suppressed)
+	}
+
+	if l.state.HeartbeatIncident.Load() {
+		procArgs = append(procArgs, "incident", true)
+	}
+
+	// Record size distribution of the interval, the largest record's message points at its call site
+	if sizes.count > 0 {
+		sizeStats := sizes.stats()
+		procArgs = append(procArgs,
+			"record_size_p50", sizeStats.P50,
+			"record_size_p99", sizeStats.P99,
+			"record_size_max", sizeStats.Max,
+		)
+		if sizeStats.MaxMessage != "" {
+			procArgs = append(procArgs, "record_size_max_msg", sizeStats.MaxMessage)
+		}
+	}
+
+	// Logging call durations of the interval, measured by call_latency_stats or call_latency_budget_us
+	if cfg := l.getConfig(); cfg.measuresCallLatency() {
+		latency := l.callLatency(true)
+		procArgs = append(procArgs,
+			"call_p50_us", latency.P50.Microseconds(),
+			"call_p99_us", latency.P99.Microseconds(),
+			"call_max_us", latency.Max.Microseconds(),
+		)
+		if latency.Degraded {
+			procArgs = append(procArgs, "call_degraded", true)
+		}
+	}
+
 	l.writeHeartbeatRecord(LevelProc, procArgs)
 }
 
 // logDiskHeartbeat logs disk/file statistics heartbeat
 func (l *Logger) logDiskHeartbeat() {
-	sequence := l.state.HeartbeatSequence.Load()
+	sequence := l.state.DiskSequence.Add(1)
 	rotations := l.state.TotalRotations.Load()
 	deletions := l.state.TotalDeletions.Load()
 
 	c := l.getConfig()
 	dir := c.Directory
-	ext := c.Extension
 	currentSizeMB := float64(l.state.CurrentSize.Load()) / (1024 * 1024) // Current file size
 	totalSizeMB := float64(-1.0)                                         // Default error value
 	fileCount := -1                                                      // Default error value
 
-	dirSize, err := l.getLogDirSize(dir, ext)
+	dirSize, err := l.getLogDirSize(dir)
 	if err == nil {
 		totalSizeMB = float64(dirSize) / (1024 * 1024)
 	} else {
 		l.internalLog("warning - heartbeat failed to get dir size: %v\n", err)
 	}
 
-	count, err := l.getLogFileCount(dir, ext)
+	count, err := l.getLogFileCount(dir)
 	if err == nil {
 		fileCount = count
 	} else {
@@ -90,6 +146,7 @@ func (l *Logger) logDiskHeartbeat() {
 	diskArgs := []any{
 		"type", "disk",
 		"sequence", sequence,
+		"interval_s", l.heartbeatIntervalS(c),
 		"rotated_files", rotations,
 		"deleted_files", deletions,
 		"total_log_size_mb", fmt.Sprintf("%.2f", totalSizeMB),
@@ -105,12 +162,25 @@ func (l *Logger) logDiskHeartbeat() {
 		diskArgs = append(diskArgs, "disk_free_mb", fmt.Sprintf("%.2f", freeSpaceMB))
 	}
 
+	// Records written to stderr instead of the blocked file, see on_disk_full
+	fallback := l.state.StderrFallback.Load()
+	l.forEachShard(func(shard *Logger) {
+		fallback += shard.state.StderrFallback.Load()
+	})
+	if fallback > 0 {
+		diskArgs = append(diskArgs, "stderr_fallback_logs", fallback)
+	}
+
+	diskArgs = append(diskArgs, l.s3UploadArgs()...)
+	diskArgs = append(diskArgs, sinkHealthArgs(l.sinkHealth())...)
+
 	l.writeHeartbeatRecord(LevelDisk, diskArgs)
 }
 
 // logSysHeartbeat logs system/runtime statistics heartbeat
 func (l *Logger) logSysHeartbeat() {
-	sequence := l.state.HeartbeatSequence.Load()
+	sequence := l.state.SysSequence.Add(1)
+	c := l.getConfig()
 
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
@@ -118,6 +188,7 @@ func (l *Logger) logSysHeartbeat() {
 	sysArgs := []any{
 		"type", "sys",
 		"sequence", sequence,
+		"interval_s", l.heartbeatIntervalS(c),
 		"alloc_mb", fmt.Sprintf("%.2f", float64(memStats.Alloc)/(1000*1000)),
 		"sys_mb", fmt.Sprintf("%.2f", float64(memStats.Sys)/(1000*1000)),
 		"num_gc", memStats.NumGC,
@@ -144,4 +215,64 @@ func (l *Logger) writeHeartbeatRecord(level int64, args []any) {
 	}
 
 	l.sendLogRecord(record)
+}
+
+// ratePerSec returns the increase of a counter per second, zero if the counter went back (e.g. shards removed)
+func ratePerSec(current, previous uint64, elapsedSec float64) float64 {
+	if current < previous {
+		return 0
+	}
+	return float64(current-previous) / elapsedSec
+}
+
+// heartbeatIntervalS returns the current spacing of heartbeats, shortened while an incident is tracked
+func (l *Logger) heartbeatIntervalS(c *Config) int64 {
+	if l.state.HeartbeatIncident.Load() {
+		return c.HeartbeatIncidentIntervalS
+	}
+	return c.HeartbeatIntervalS
+}
+
+// incidentHeartbeatInterval returns the heartbeat interval during incidents, 0 when it would not shorten heartbeats
+func incidentHeartbeatInterval(c *Config) time.Duration {
+	if c.HeartbeatIncidentIntervalS <= 0 || c.HeartbeatIncidentIntervalS >= c.HeartbeatIntervalS {
+		return 0
+	}
+	return time.Duration(c.HeartbeatIncidentIntervalS) * time.Second
+}
+
+// watchHeartbeatIncident switches heartbeats to heartbeat_incident_interval_s while records are dropped or
+// disk status is not OK, for this logger or any of its shards
+func (l *Logger) watchHeartbeatIncident(timers *TimerSet) {
+	if timers.heartbeatTicker == nil || timers.incidentInterval == 0 {
+		return
+	}
+
+	incident := l.state.DroppedLogs.Load() > 0 || !l.state.DiskStatusOK.Load()
+	l.forEachShard(func(shard *Logger) {
+		incident = incident || shard.state.DroppedLogs.Load() > 0 || !shard.state.DiskStatusOK.Load()
+	})
+	if !incident {
+		return
+	}
+
+	timers.incidentSeen = true
+	if !l.state.HeartbeatIncident.Load() {
+		l.state.HeartbeatIncident.Store(true)
+		timers.heartbeatTicker.Reset(timers.incidentInterval)
+	}
+}
+
+// settleHeartbeatIncident restores heartbeat_interval_s once a full incident interval passed without drops or
+// disk limit violations; called after each heartbeat
+func (l *Logger) settleHeartbeatIncident(timers *TimerSet) {
+	if !l.state.HeartbeatIncident.Load() {
+		return
+	}
+	if timers.incidentSeen {
+		timers.incidentSeen = false
+		return
+	}
+	l.state.HeartbeatIncident.Store(false)
+	timers.heartbeatTicker.Reset(timers.heartbeatInterval)
 }
\ No newline at end of file
@@ -0,0 +1,175 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// HTTPSinkOptions configures a sink posting batches of JSON records to an HTTP(S) endpoint
+// Zero values select the defaults noted on each field
+type HTTPSinkOptions struct {
+	Endpoint      string            // URL receiving the batches, http or https; required
+	Headers       map[string]string // Added to every request, e.g. "Authorization"
+	Filter        Filter            // Selects the records sent, nil sends all records except heartbeats
+	BatchSize     int               // Records per batch, default 100
+	BatchBytes    int               // Uncompressed batch size that triggers a send, default 1 MiB
+	FlushInterval time.Duration     // Longest time a record waits for its batch to fill, default 1s
+	QueueSize     int               // Records buffered for delivery, new records are dropped when full; default 10000
+	MaxRetries    int               // Retries of a failed batch before it is dropped, default 3; negative disables retries
+	RetryBackoff  time.Duration     // Delay before the first retry, doubled for each further retry; default 500ms
+	Timeout       time.Duration     // Timeout of each request, default 10s
+	Gzip          bool              // Compress request bodies with gzip
+	Client        *http.Client      // Client for requests, e.g. with custom TLS settings; nil uses a default client
+}
+
+// HTTPSink is a Sink posting records to an HTTP(S) endpoint as JSON arrays
+type HTTPSink struct {
+	*sinkQueue
+	opts      HTTPSinkOptions
+	formatter *formatter.Formatter // Used by the delivery goroutine only
+}
+
+var _ Sink = (*HTTPSink)(nil)
+
+// NewHTTPSink starts a sink posting records to an HTTP(S) endpoint, register it with Logger.AddSink
+// Returns an error if the endpoint is not a valid http or https URL
+func NewHTTPSink(opts HTTPSinkOptions) (*HTTPSink, error) {
+	endpoint, err := url.Parse(opts.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmtErrorf("invalid http sink endpoint '%s': use an http or https URL", opts.Endpoint)
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = httpSinkBatchSize
+	}
+	if opts.BatchBytes <= 0 {
+		opts.BatchBytes = httpSinkBatchBytes
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = httpSinkFlushInterval
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = httpSinkQueueSize
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = httpSinkMaxRetries
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = httpSinkRetryBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = httpSinkTimeout
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{}
+	}
+
+	s := &HTTPSink{
+		sinkQueue: newSinkQueue("http sink", opts.QueueSize, opts.Filter, opts.Timeout),
+		opts:      opts,
+		formatter: formatter.New(sanitizer.New()).
+			Type("json").
+			TimestampFormat(time.RFC3339Nano),
+	}
+	s.start(s.run)
+	return s, nil
+}
+
+// run collects queued records into a JSON array, sent once it reaches BatchSize records or BatchBytes bytes
+func (s *HTTPSink) run() {
+	var batch []byte
+	count := 0
+	add := func(record Record) bool {
+		flags := record.Flags&^FlagRaw | FlagShowTimestamp | FlagShowLevel
+		entry := s.formatter.FormatLabeled(flags, record.Time, record.Level, record.Trace, record.Labels, record.Args)
+		if count == 0 {
+			batch = append(batch[:0], '[')
+		} else {
+			batch = append(batch, ',')
+		}
+		batch = append(batch, bytes.TrimRight(entry, "\n")...)
+		count++
+		return count >= s.opts.BatchSize || len(batch) >= s.opts.BatchBytes
+	}
+	flush := func() {
+		s.deliver(append(batch, ']'), count)
+		count = 0
+	}
+	s.batch(s.opts.FlushInterval, add, flush)
+}
+
+// deliver posts a batch, retrying transport errors, 429, and 5xx responses with exponential backoff
+func (s *HTTPSink) deliver(batch []byte, count int) {
+	body := batch
+	if s.opts.Gzip {
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		_, _ = zw.Write(batch)
+		_ = zw.Close()
+		body = compressed.Bytes()
+	}
+
+	backoff := s.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body)
+		if err == nil {
+			s.status.success()
+			return
+		}
+		if !retry || attempt >= s.opts.MaxRetries {
+			s.status.failure(fmtErrorf("http sink dropped batch of %d records: %w", count, err))
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-s.ctx.Done():
+			s.status.failure(fmtErrorf("http sink dropped batch of %d records: %w", count, s.ctx.Err()))
+			return
+		}
+	}
+}
+
+// post sends one request, reporting whether a failure is worth retrying
+func (s *HTTPSink) post(body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.opts.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.opts.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range s.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return s.ctx.Err() == nil, err
+	}
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmtErrorf("endpoint responded %s", resp.Status)
+	default:
+		return false, fmtErrorf("endpoint rejected batch: %s", resp.Status)
+	}
+}
\ No newline at end of file
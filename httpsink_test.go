@@ -0,0 +1,178 @@
+package log
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchCollector is a test endpoint decoding posted batches
+type batchCollector struct {
+	mu      sync.Mutex
+	batches [][]map[string]any
+	headers []http.Header
+}
+
+func (c *batchCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	var batch []map[string]any
+	if err := json.NewDecoder(body).Decode(&batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	c.batches = append(c.batches, batch)
+	c.headers = append(c.headers, r.Header.Clone())
+	c.mu.Unlock()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (c *batchCollector) snapshot() ([][]map[string]any, []http.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]map[string]any(nil), c.batches...), append([]http.Header(nil), c.headers...)
+}
+
+// TestHTTPSinkBatches verifies records are posted as gzip-compressed JSON arrays split by batch size
+func TestHTTPSinkBatches(t *testing.T) {
+	collector := &batchCollector{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	sink, err := NewHTTPSink(HTTPSinkOptions{
+		Endpoint:      server.URL,
+		Headers:       map[string]string{"Authorization": "Bearer token"},
+		BatchSize:     2,
+		FlushInterval: 20 * time.Millisecond,
+		Gzip:          true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, logger.AddSink("ingest", sink))
+
+	logger.Info("first", "n", 1)
+	logger.Warn("second")
+	logger.Error("third")
+	require.NoError(t, logger.Flush(time.Second))
+
+	require.Eventually(t, func() bool {
+		batches, _ := collector.snapshot()
+		return len(batches) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	batches, headers := collector.snapshot()
+	assert.Len(t, batches[0], 2, "A full batch is sent immediately")
+	assert.Len(t, batches[1], 1, "A partial batch is sent after the flush interval")
+	assert.Equal(t, "INFO", batches[0][0]["level"])
+	assert.Equal(t, []any{"first", "n", float64(1)}, batches[0][0]["fields"])
+	assert.Equal(t, "ERROR", batches[1][0]["level"])
+	assert.Equal(t, "Bearer token", headers[0].Get("Authorization"))
+	assert.Equal(t, "application/json", headers[0].Get("Content-Type"))
+
+	var found bool
+	for _, sink := range logger.Stats().Sinks {
+		if sink.Name == "ingest" {
+			found = true
+			assert.Equal(t, SinkStatusOK, sink.Status)
+		}
+	}
+	assert.True(t, found)
+}
+
+// TestHTTPSinkRetries verifies 5xx responses are retried and 4xx responses drop the batch
+func TestHTTPSinkRetries(t *testing.T) {
+	var calls atomic.Int32
+	collector := &batchCollector{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1, 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 3:
+			collector.ServeHTTP(w, r)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	sink, err := NewHTTPSink(HTTPSinkOptions{
+		Endpoint:     server.URL,
+		BatchSize:    1,
+		RetryBackoff: 5 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, logger.AddSink("ingest", sink))
+
+	logger.Info("retried")
+	require.Eventually(t, func() bool {
+		batches, _ := collector.snapshot()
+		return len(batches) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(3), calls.Load())
+
+	logger.Info("rejected")
+	require.Eventually(t, func() bool {
+		return sink.health().TotalErrors == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(4), calls.Load(), "Client errors are not retried")
+	assert.Contains(t, sink.health().LastError, "400")
+}
+
+// TestHTTPSinkShutdown verifies queued records are sent on shutdown and removal, and invalid endpoints are rejected
+func TestHTTPSinkShutdown(t *testing.T) {
+	collector := &batchCollector{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	logger, _ := createTestLogger(t)
+	sink, err := NewHTTPSink(HTTPSinkOptions{Endpoint: server.URL, FlushInterval: time.Hour})
+	require.NoError(t, err)
+	require.NoError(t, logger.AddSink("ingest", sink))
+
+	for range 5 {
+		logger.Info("pending")
+	}
+	require.NoError(t, logger.Shutdown(2*time.Second))
+
+	batches, _ := collector.snapshot()
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0], 5)
+	assert.Error(t, sink.Write(nil, Record{Level: LevelInfo}), "A closed sink rejects records")
+
+	// RemoveSink sends what the removed sink queued
+	logger, _ = createTestLogger(t)
+	defer logger.Shutdown()
+	sink, err = NewHTTPSink(HTTPSinkOptions{Endpoint: server.URL, FlushInterval: time.Hour})
+	require.NoError(t, err)
+	require.NoError(t, logger.AddSink("ingest", sink))
+	logger.Info("removed")
+	require.NoError(t, logger.Flush(time.Second))
+	require.NoError(t, logger.RemoveSink("ingest"))
+	batches, _ = collector.snapshot()
+	assert.Len(t, batches, 2)
+
+	for _, endpoint := range []string{"", "ftp://example.com", "http://"} {
+		_, err := NewHTTPSink(HTTPSinkOptions{Endpoint: endpoint})
+		assert.Error(t, err, endpoint)
+	}
+}
\ No newline at end of file
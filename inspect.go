@@ -0,0 +1,111 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/lixenwraith/log/logreader"
+)
+
+// LogFiles lists the archives in the log directory, oldest first, followed by the active log file when it exists
+// Reason is left empty. Together with read_only, tools can inspect the files of another process safely
+func (l *Logger) LogFiles() ([]FileInfo, error) {
+	c := l.getConfig()
+	files, err := l.listArchives(c.Directory)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmtErrorf("failed to read log directory '%s': %w", c.Directory, err)
+	}
+
+	path := logFilePath(c)
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		files = append(files, FileInfo{
+			Name:    filepath.Base(path),
+			Path:    path,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+	return files, nil
+}
+
+// OpenLogs returns a reader of the content of every file listed by LogFiles in order, gzip-compressed files
+// decompressed. Files are opened as they are reached; those removed by the writing process meanwhile are skipped
+func (l *Logger) OpenLogs() (io.ReadCloser, error) {
+	files, err := l.LogFiles()
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	return &logFilesReader{paths: paths}, nil
+}
+
+// logFilesReader reads a sequence of log files as one stream
+type logFilesReader struct {
+	paths []string
+	file  *os.File
+	r     io.Reader
+}
+
+// Read reads from the current file, moving to the next one at its end
+func (lr *logFilesReader) Read(p []byte) (int, error) {
+	for {
+		if lr.r == nil {
+			if len(lr.paths) == 0 {
+				return 0, io.EOF
+			}
+			if err := lr.open(); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		n, err := lr.r.Read(p)
+		if errors.Is(err, io.EOF) {
+			lr.closeFile()
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+// open opens the next file, skipping it when it no longer exists
+func (lr *logFilesReader) open() error {
+	path := lr.paths[0]
+	lr.paths = lr.paths[1:]
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmtErrorf("failed to open log file '%s': %w", path, err)
+	}
+	r, err := logreader.Decompress(file)
+	if err != nil {
+		file.Close()
+		return fmtErrorf("failed to read log file '%s': %w", path, err)
+	}
+	lr.file, lr.r = file, r
+	return nil
+}
+
+// closeFile closes the current file
+func (lr *logFilesReader) closeFile() {
+	if lr.file != nil {
+		lr.file.Close()
+	}
+	lr.file, lr.r = nil, nil
+}
+
+// Close closes the current file, later reads return io.EOF
+func (lr *logFilesReader) Close() error {
+	lr.closeFile()
+	lr.paths = nil
+	return nil
+}
\ No newline at end of file
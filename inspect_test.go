@@ -0,0 +1,66 @@
+package log
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadOnly verifies a read-only logger lists and reads another process's files without touching the directory
+func TestReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	archive := "log_00000001_240101_120000_0.log"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, archive), []byte("first\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "log.log"), []byte("second\n"), 0644))
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, archive), old, old))
+
+	logger, err := NewBuilder().
+		Directory(dir).
+		EnableFile(true).
+		EnableConsole(false).
+		SplitErrorFile(true).
+		RetentionPeriodHrs(1).
+		ReadOnly(true).
+		Build()
+	require.NoError(t, err)
+	defer logger.Shutdown()
+
+	assert.Error(t, logger.Start(), "A read-only logger cannot log")
+	logger.Info("ignored")
+
+	files, err := logger.LogFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, archive, files[0].Name)
+	assert.Equal(t, uint64(1), files[0].Generation)
+	assert.Equal(t, "log.log", files[1].Name)
+	assert.Equal(t, int64(7), logger.Stats().CurrentFileSize)
+
+	r, err := logger.OpenLogs()
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "first\nsecond\n", string(content))
+
+	preview, err := logger.RetentionPreview()
+	require.NoError(t, err)
+	require.Len(t, preview, 1)
+	assert.Equal(t, archive, preview[0].Name)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "No file is created, rotated, or deleted")
+
+	// Only existing directories can be inspected
+	_, err = NewBuilder().Directory(filepath.Join(dir, "missing")).EnableFile(true).ReadOnly(true).Build()
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(dir, "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
\ No newline at end of file
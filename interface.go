@@ -0,0 +1,61 @@
+package log
+
+import "time"
+
+// LoggerInterface is the logging surface implemented by *Logger
+// Downstream code can depend on it to inject NopLogger or test doubles
+type LoggerInterface interface {
+	Debug(args ...any)
+	Info(args ...any)
+	Warn(args ...any)
+	Error(args ...any)
+	DebugTrace(depth int, args ...any)
+	InfoTrace(depth int, args ...any)
+	WarnTrace(depth int, args ...any)
+	ErrorTrace(depth int, args ...any)
+	LogStructured(level int64, message string, fields map[string]any)
+	Flush(timeout time.Duration) error
+	Shutdown(timeout ...time.Duration) error
+}
+
+// Compile-time interface checks
+var (
+	_ LoggerInterface = (*Logger)(nil)
+	_ LoggerInterface = NopLogger{}
+)
+
+// NopLogger is a LoggerInterface implementation that discards all records
+type NopLogger struct{}
+
+// Debug discards the record
+func (NopLogger) Debug(args ...any) {}
+
+// Info discards the record
+func (NopLogger) Info(args ...any) {}
+
+// Warn discards the record
+func (NopLogger) Warn(args ...any) {}
+
+// Error discards the record
+func (NopLogger) Error(args ...any) {}
+
+// DebugTrace discards the record
+func (NopLogger) DebugTrace(depth int, args ...any) {}
+
+// InfoTrace discards the record
+func (NopLogger) InfoTrace(depth int, args ...any) {}
+
+// WarnTrace discards the record
+func (NopLogger) WarnTrace(depth int, args ...any) {}
+
+// ErrorTrace discards the record
+func (NopLogger) ErrorTrace(depth int, args ...any) {}
+
+// LogStructured discards the record
+func (NopLogger) LogStructured(level int64, message string, fields map[string]any) {}
+
+// Flush always succeeds
+func (NopLogger) Flush(timeout time.Duration) error { return nil }
+
+// Shutdown always succeeds
+func (NopLogger) Shutdown(timeout ...time.Duration) error { return nil }
\ No newline at end of file
@@ -0,0 +1,24 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNopLogger verifies the no-op implementation can stand in for a real logger
+func TestNopLogger(t *testing.T) {
+	var logger LoggerInterface = NopLogger{}
+
+	assert.NotPanics(t, func() {
+		logger.Debug("discarded")
+		logger.Info("discarded")
+		logger.Warn("discarded")
+		logger.Error("discarded")
+		logger.InfoTrace(2, "discarded")
+		logger.LogStructured(LevelInfo, "discarded", map[string]any{"k": "v"})
+	})
+	assert.NoError(t, logger.Flush(time.Second))
+	assert.NoError(t, logger.Shutdown())
+}
\ No newline at end of file
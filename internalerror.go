@@ -0,0 +1,116 @@
+package log
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// internalErrorMirror reports output and retention errors as WARN records when internal_errors_to_log is enabled
+// Each source is reported at most once per internal_errors_log_interval_s with the errors counted since its last report
+type internalErrorMirror struct {
+	mu       sync.Mutex
+	seen     map[string]uint64         // Total errors of each output at its last report
+	noted    map[string]*internalError // Errors of subsystems without health tracking, e.g. retention
+	lastScan time.Time
+}
+
+// internalError counts the errors of a source since its last report
+type internalError struct {
+	count uint64
+	last  string
+}
+
+// noteInternalError records an error of a subsystem without health tracking for the next report
+func (l *Logger) noteInternalError(source string, err error) {
+	if !l.getConfig().InternalErrorsToLog {
+		return
+	}
+	core := l.loggerCore
+	if core.parent != nil {
+		core = core.parent
+	}
+	m := &core.state.internalErrors
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.noted == nil {
+		m.noted = make(map[string]*internalError)
+	}
+	e := m.noted[source]
+	if e == nil {
+		e = &internalError{}
+		m.noted[source] = e
+	}
+	e.count++
+	e.last = err.Error()
+}
+
+// mirrorInternalErrors logs errors of outputs other than the main files, and noted errors, once the interval has passed
+// Nothing is reported while the file output is failing, errors accumulate until it recovers
+func (l *Logger) mirrorInternalErrors() {
+	c := l.getConfig()
+	if !c.InternalErrorsToLog || l.parent != nil {
+		return
+	}
+	mainFiles, healthy := l.mainFileHealth(c)
+	if !healthy {
+		return
+	}
+
+	m := &l.state.internalErrors
+	m.mu.Lock()
+	now := time.Now()
+	if !m.lastScan.IsZero() && now.Sub(m.lastScan) < time.Duration(c.InternalErrorsLogIntervalS)*time.Second {
+		m.mu.Unlock()
+		return
+	}
+	m.lastScan = now
+
+	if m.seen == nil {
+		m.seen = make(map[string]uint64)
+	}
+	reports := make(map[string]internalError)
+	for _, h := range l.sinkHealth() {
+		if mainFiles[h.Name] || h.TotalErrors <= m.seen[h.Name] {
+			continue
+		}
+		reports[h.Name] = internalError{count: h.TotalErrors - m.seen[h.Name], last: h.LastError}
+		m.seen[h.Name] = h.TotalErrors
+	}
+	for source, e := range m.noted {
+		reports[source] = *e
+	}
+	m.noted = nil
+	m.mu.Unlock()
+
+	sources := make([]string, 0, len(reports))
+	for source := range reports {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		e := reports[source]
+		l.sendLogRecord(logRecord{
+			Flags:     FlagDefault,
+			TimeStamp: now,
+			Level:     LevelWarn,
+			Args:      []any{"internal logger error", "source", source, "errors", e.count, "last_error", e.last},
+		})
+	}
+}
+
+// mainFileHealth returns the health names of the logger's own file or shard files, and whether all of them are writing
+func (l *Logger) mainFileHealth(c *Config) (map[string]bool, bool) {
+	if c.fileOutput() {
+		return map[string]bool{"file": true}, l.state.fileHealth.consecutive.Load() == 0
+	}
+	names := make(map[string]bool)
+	healthy := true
+	l.forEachShard(func(shard *Logger) {
+		names["file:"+shard.getConfig().Name] = true
+		if shard.state.fileHealth.consecutive.Load() > 0 {
+			healthy = false
+		}
+	})
+	return names, healthy && len(names) > 0
+}
\ No newline at end of file
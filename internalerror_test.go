@@ -0,0 +1,45 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInternalErrorsToLog verifies sink and retention errors are written to the file as rate-limited WARN records
+func TestInternalErrorsToLog(t *testing.T) {
+	logger, dir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	failing := &memorySink{err: errors.New("endpoint down")}
+	require.NoError(t, logger.AddSink("remote", failing))
+	require.NoError(t, logger.ApplyConfigString("format=txt", "internal_errors_to_log=true", "internal_errors_log_interval_s=3600"))
+
+	logger.Info("first")
+	logger.Info("second")
+	logger.noteInternalError("retention", errors.New("permission denied"))
+	require.NoError(t, logger.Flush(time.Second))
+
+	// The first scan reports both sources, later errors wait for the interval
+	require.Eventually(t, func() bool {
+		content, _ := os.ReadFile(filepath.Join(dir, "log.log"))
+		return strings.Count(string(content), "internal logger error") == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	logger.Info("third")
+	require.NoError(t, logger.Flush(time.Second))
+	time.Sleep(50 * time.Millisecond)
+
+	content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(content), "internal logger error"))
+	assert.Contains(t, string(content), `WARN "internal logger error" source remote errors 2 last_error "endpoint down"`)
+	assert.Contains(t, string(content), `WARN "internal logger error" source retention errors 1 last_error "permission denied"`)
+
+	assert.Error(t, logger.ApplyConfigString("internal_errors_log_interval_s=-1"))
+}
\ No newline at end of file
@@ -0,0 +1,102 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Rotation triggers recorded in the rotation journal
+const (
+	rotationTriggerSize     = "size"     // Active file reached MaxSizeKB
+	rotationTriggerSelfTest = "selftest" // Forced by SelfTest on its temporary logger
+)
+
+// rotationEvent is one line of the rotation journal, the schema read by logreader.RotationEvent
+type rotationEvent struct {
+	Time       time.Time     `json:"time"`
+	Trigger    string        `json:"trigger"`
+	File       string        `json:"file"`
+	Archive    string        `json:"archive"`
+	Generation uint64        `json:"generation"`
+	Size       int64         `json:"size"`
+	Duration   time.Duration `json:"duration_ns"`
+}
+
+// rotationJournalPath returns the journal path, {directory}/{name}.rotations, which is never matched as a log archive
+func rotationJournalPath(c *Config) string {
+	return filepath.Join(c.Directory, c.Name+".rotations")
+}
+
+// recordRotation appends an event to the rotation journal if RotationJournal is enabled
+// Journal failures are reported as internal errors and never affect rotation
+func (l *Logger) recordRotation(c *Config, event rotationEvent) {
+	if !c.RotationJournal {
+		return
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		l.internalLog("failed to encode rotation journal event: %v\n", err)
+		return
+	}
+
+	path := rotationJournalPath(c)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		l.internalLog("failed to open rotation journal '%s': %v\n", path, err)
+		return
+	}
+	_, err = file.Write(append(line, '\n'))
+	fi, statErr := file.Stat()
+	_ = file.Close()
+	if err != nil {
+		l.internalLog("failed to write rotation journal '%s': %v\n", path, err)
+		return
+	}
+
+	if statErr == nil && fi.Size() > rotationJournalMaxBytes {
+		if err := trimRotationJournal(path); err != nil {
+			l.internalLog("failed to trim rotation journal '%s': %v\n", path, err)
+		}
+	}
+}
+
+// lastJournalGeneration returns the highest archive generation recorded in the rotation journal at path
+// Returns 0 when the journal is missing, unreadable, or records no generations
+func lastJournalGeneration(path string) uint64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	var last uint64
+	for line := range bytes.Lines(data) {
+		var event rotationEvent
+		if json.Unmarshal(line, &event) == nil {
+			last = max(last, event.Generation)
+		}
+	}
+	return last
+}
+
+// trimRotationJournal keeps the newest half of the journal, replacing the file atomically
+func trimRotationJournal(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	keep := data[len(data)-rotationJournalMaxBytes/2:]
+	// Drop the partial line at the cut
+	if i := bytes.IndexByte(keep, '\n'); i >= 0 {
+		keep = keep[i+1:]
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, keep, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
\ No newline at end of file
@@ -0,0 +1,220 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// errJournaldUnavailable is recorded for records written while reconnection is backing off
+var errJournaldUnavailable = fmtErrorf("journald unavailable, waiting to reconnect")
+
+// journaldSettings are the configuration values a journald output is built from
+type journaldSettings struct {
+	socket          string
+	identifier      string
+	bodyFormat      string
+	bytesEncoding   string
+	durationFormat  string
+	timeValueFormat string
+	sanitization    sanitizer.PolicyPreset
+}
+
+// journaldOutput writes records to the systemd journal using its native datagram protocol
+// The socket is connected on the first write and re-connected after failures, all on the processor goroutine
+type journaldOutput struct {
+	settings  journaldSettings
+	pid       string
+	formatter *formatter.Formatter // Formats the MESSAGE field without timestamp and level, carried by the journal
+	conn      *net.UnixConn
+	retryAt   time.Time // No reconnect attempts before this time after a failed connect
+	status    healthTracker
+	buf       []byte // Serialized entry, reused across records
+}
+
+// configureJournald returns the journald output for cfg, reusing current when its settings are unchanged
+// Returns nil when journald output is disabled
+func configureJournald(cfg *Config, current *journaldOutput) *journaldOutput {
+	if !cfg.EnableJournal {
+		return nil
+	}
+
+	socket := cfg.JournalSocket
+	if socket == "" {
+		socket = journaldSocketPath
+	}
+	bodyFormat := cfg.bodyFormat()
+	if bodyFormat == "binary" || bodyFormat == "msgpack" || bodyFormat == "gelf" || bodyFormat == "csv" || bodyFormat == "cef" ||
+		bodyFormat == "rfc5424" {
+		bodyFormat = "txt"
+	}
+	settings := journaldSettings{
+		socket:          socket,
+		identifier:      cfg.Name,
+		bodyFormat:      bodyFormat,
+		bytesEncoding:   cfg.BytesEncoding,
+		durationFormat:  cfg.DurationFormat,
+		timeValueFormat: cfg.TimeValueFormat,
+		sanitization:    cfg.sanitization(cfg.NetworkSanitization, bodyFormat),
+	}
+	if current != nil && current.settings == settings {
+		return current
+	}
+
+	return &journaldOutput{
+		settings: settings,
+		pid:      strconv.Itoa(os.Getpid()),
+		formatter: formatter.New(sanitizer.New().Policy(settings.sanitization)).
+			Type(settings.bodyFormat).
+			ShowTimestamp(false).
+			ShowLevel(false).
+			BytesEncoding(settings.bytesEncoding).
+			DurationFormat(settings.durationFormat).
+			TimeValueFormat(settings.timeValueFormat),
+	}
+}
+
+// write sends a record as one journal entry, entries too large for a datagram are passed as a file descriptor
+func (o *journaldOutput) write(record logRecord) {
+	if o.conn == nil {
+		if time.Now().Before(o.retryAt) {
+			o.status.failure(errJournaldUnavailable)
+			return
+		}
+		conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: o.settings.socket, Net: "unixgram"})
+		if err != nil {
+			o.retryAt = time.Now().Add(syslogRetryInterval)
+			o.status.failure(fmtErrorf("failed to connect to journald at '%s': %w", o.settings.socket, err))
+			return
+		}
+		o.conn = conn
+	}
+
+	o.buf = o.appendEntry(o.buf[:0], record)
+	_ = o.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+	_, err := o.conn.Write(o.buf)
+	if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+		err = sendJournalFD(o.conn, o.buf)
+	}
+	if err != nil {
+		o.status.failure(fmtErrorf("failed to write to journald: %w", err))
+		o.close()
+		return
+	}
+	o.status.success()
+}
+
+// close releases the socket, the next write reconnects
+func (o *journaldOutput) close() {
+	if o.conn != nil {
+		_ = o.conn.Close()
+		o.conn = nil
+	}
+}
+
+// health reports delivery health of the journald output
+func (o *journaldOutput) health(name string) SinkHealth {
+	return o.status.snapshot(name)
+}
+
+// appendEntry serializes a record as MESSAGE, PRIORITY, SYSLOG_IDENTIFIER, SYSLOG_PID, and its key-value fields
+func (o *journaldOutput) appendEntry(buf []byte, record logRecord) []byte {
+	body := o.formatter.Format(
+		record.Flags&^(FlagShowTimestamp|FlagShowLevel),
+		record.TimeStamp,
+		record.Level,
+		record.Trace,
+		record.Args,
+	)
+	buf = appendJournalField(buf, "MESSAGE", bytes.TrimRight(body, "\n"))
+	buf = appendJournalField(buf, "PRIORITY", []byte(strconv.Itoa(syslogSeverity(record.Level))))
+	buf = appendJournalField(buf, "SYSLOG_IDENTIFIER", []byte(o.settings.identifier))
+	buf = appendJournalField(buf, "SYSLOG_PID", []byte(o.pid))
+
+	forEachRecordField(record.Flags, record.Args, func(key string, value any) {
+		name := journalFieldName(key)
+		switch name {
+		case "", "MESSAGE", "PRIORITY", "SYSLOG_IDENTIFIER", "SYSLOG_PID":
+			// Invalid or reserved by the entry itself
+			return
+		}
+		str, ok := value.(string)
+		if !ok {
+			str = fmt.Sprint(value)
+		}
+		buf = appendJournalField(buf, name, []byte(str))
+	})
+	return buf
+}
+
+// appendJournalField appends KEY=value, or the binary-safe KEY, length, value form for multi-line values
+func appendJournalField(buf []byte, key string, value []byte) []byte {
+	buf = append(buf, key...)
+	if bytes.IndexByte(value, '\n') < 0 {
+		buf = append(buf, '=')
+		buf = append(buf, value...)
+		return append(buf, '\n')
+	}
+	buf = append(buf, '\n')
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(value)))
+	buf = append(buf, value...)
+	return append(buf, '\n')
+}
+
+// journalFieldName converts a record key to a journal field name: uppercase letters, digits, and underscores,
+// not starting with an underscore or digit, at most 64 bytes. Returns empty if nothing valid remains
+func journalFieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	trimmed := strings.TrimLeft(string(name), "_0123456789")
+	if len(trimmed) > journaldMaxFieldName {
+		trimmed = trimmed[:journaldMaxFieldName]
+	}
+	return trimmed
+}
+
+// forEachRecordField calls fn for the key-value fields of a record
+// Structured records yield their field map in key order; other records yield string-keyed pairs following the
+// message, where an odd argument count means the first argument is the message and a leading "msg" key is skipped
+func forEachRecordField(flags int64, args []any, fn func(key string, value any)) {
+	if flags&FlagRaw != 0 {
+		return
+	}
+	if flags&FlagStructuredJSON != 0 && len(args) == 2 {
+		if fields, ok := args[1].(map[string]any); ok {
+			keys := make([]string, 0, len(fields))
+			for k := range fields {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+			for _, k := range keys {
+				fn(k, fields[k])
+			}
+			return
+		}
+	}
+
+	start := len(args) % 2
+	for i := start; i+1 < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok || (i == 0 && key == "msg") {
+			continue
+		}
+		fn(key, args[i+1])
+	}
+}
\ No newline at end of file
@@ -0,0 +1,44 @@
+//go:build linux
+
+package log
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// sendJournalFD passes an entry too large for a datagram to journald as an unlinked temporary file
+func sendJournalFD(conn *net.UnixConn, entry []byte) error {
+	file, err := os.CreateTemp("/dev/shm", "journal.*")
+	if err != nil {
+		file, err = os.CreateTemp("", "journal.*")
+		if err != nil {
+			return fmtErrorf("failed to create journal entry file: %w", err)
+		}
+	}
+	defer file.Close()
+	_ = os.Remove(file.Name())
+
+	if _, err := file.Write(entry); err != nil {
+		return fmtErrorf("failed to write journal entry file: %w", err)
+	}
+
+	// Send on the raw socket, the connected UnixConn rejects ancillary-only writes
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return fmtErrorf("failed to access journal socket: %w", err)
+	}
+	rights := syscall.UnixRights(int(file.Fd()))
+	var sendErr error
+	if err := raw.Write(func(fd uintptr) bool {
+		sendErr = syscall.Sendmsg(int(fd), nil, rights, nil, 0)
+		return sendErr != syscall.EAGAIN
+	}); err != nil {
+		return fmtErrorf("failed to pass journal entry file: %w", err)
+	}
+	if sendErr != nil {
+		return fmtErrorf("failed to pass journal entry file: %w", sendErr)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package log
+
+import (
+	"net"
+)
+
+// sendJournalFD is unsupported outside Linux, oversized entries are dropped
+func sendJournalFD(conn *net.UnixConn, entry []byte) error {
+	return fmtErrorf("journal entry of %d bytes exceeds the datagram limit", len(entry))
+}
\ No newline at end of file
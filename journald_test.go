@@ -0,0 +1,156 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listenJournal creates a journal socket in a temporary directory
+func listenJournal(t *testing.T) (*net.UnixConn, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, path
+}
+
+// readJournalEntry receives one entry, following a passed file descriptor for oversized entries
+func readJournalEntry(t *testing.T, conn *net.UnixConn) map[string]string {
+	t.Helper()
+	buf := make([]byte, 1<<16)
+	oob := make([]byte, 64)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	require.NoError(t, err)
+
+	data := buf[:n]
+	if oobn > 0 {
+		msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		require.NoError(t, err)
+		fds, err := syscall.ParseUnixRights(&msgs[0])
+		require.NoError(t, err)
+		file := os.NewFile(uintptr(fds[0]), "journal-entry")
+		defer file.Close()
+		_, err = file.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+		data, err = io.ReadAll(file)
+		require.NoError(t, err)
+	}
+	return parseJournalEntry(t, data)
+}
+
+// parseJournalEntry decodes the native protocol, including binary-safe multi-line fields
+func parseJournalEntry(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		nl := bytes.IndexByte(data, '\n')
+		require.GreaterOrEqual(t, nl, 0)
+		line := data[:nl]
+		data = data[nl+1:]
+		if key, value, ok := bytes.Cut(line, []byte("=")); ok {
+			fields[string(key)] = string(value)
+			continue
+		}
+		size := binary.LittleEndian.Uint64(data[:8])
+		fields[string(line)] = string(data[8 : 8+size])
+		data = data[8+size+1:]
+	}
+	return fields
+}
+
+// TestJournaldFields verifies entries carry MESSAGE, PRIORITY, identifier, and record fields as journal fields
+func TestJournaldFields(t *testing.T) {
+	conn, path := listenJournal(t)
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString(
+		"enable_journal=true",
+		"journal_socket="+path,
+		"name=api",
+		"format=txt",
+	))
+
+	logger.Warn("user login", "user_id", 42, "remote addr", "10.0.0.1", "_hidden", "x", "priority", "spoofed")
+	require.NoError(t, logger.Flush(time.Second))
+
+	fields := readJournalEntry(t, conn)
+	assert.Equal(t, `"user login" user_id 42 "remote addr" 10.0.0.1 _hidden x priority spoofed`, fields["MESSAGE"])
+	assert.Equal(t, "4", fields["PRIORITY"])
+	assert.Equal(t, "api", fields["SYSLOG_IDENTIFIER"])
+	assert.Equal(t, strconv.Itoa(os.Getpid()), fields["SYSLOG_PID"])
+	assert.Equal(t, "42", fields["USER_ID"])
+	assert.Equal(t, "10.0.0.1", fields["REMOTE_ADDR"])
+	assert.Equal(t, "x", fields["HIDDEN"], "Leading underscores are reserved for trusted fields")
+
+	for _, sink := range logger.Stats().Sinks {
+		if sink.Name == "journald" {
+			assert.Equal(t, SinkStatusOK, sink.Status)
+		}
+	}
+}
+
+// TestJournaldLargeEntries verifies multi-line values use length framing and oversized entries are passed as a file
+func TestJournaldLargeEntries(t *testing.T) {
+	conn, path := listenJournal(t)
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString(
+		"enable_journal=true",
+		"journal_socket="+path,
+		"format=raw",
+	))
+
+	logger.Info("stack", "trace", "line one\nline two")
+	require.NoError(t, logger.Flush(time.Second))
+	fields := readJournalEntry(t, conn)
+	assert.Equal(t, "line one\nline two", fields["TRACE"])
+
+	big := strings.Repeat("x", 512*1024)
+	logger.Info(big)
+	require.NoError(t, logger.Flush(time.Second))
+	fields = readJournalEntry(t, conn)
+	assert.Equal(t, big, fields["MESSAGE"])
+}
+
+// TestJournaldUnavailable verifies a missing journal socket degrades the output without affecting the file
+func TestJournaldUnavailable(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString(
+		"enable_journal=true",
+		"journal_socket="+t.TempDir()+"/missing.sock",
+	))
+
+	logger.Info("kept in file")
+	require.NoError(t, logger.Flush(time.Second))
+
+	var found bool
+	for _, sink := range logger.Stats().Sinks {
+		if sink.Name == "journald" {
+			found = true
+			assert.Equal(t, SinkStatusDegraded, sink.Status)
+			assert.Contains(t, sink.LastError, "failed to connect to journald")
+		}
+	}
+	assert.True(t, found)
+
+	assert.Equal(t, "REQUEST_ID", journalFieldName("request-id"))
+	assert.Equal(t, "", journalFieldName("__"))
+	assert.Len(t, journalFieldName(strings.Repeat("k", 100)), journaldMaxFieldName)
+}
\ No newline at end of file
@@ -0,0 +1,65 @@
+package log
+
+// WithLabels returns a logger sharing this logger's output that attaches the labels to every record
+// Labels are metadata for routing and network sinks, kept apart from the record's fields: they are fixed per logger
+// rather than per call, which keeps their cardinality under control. Labels accumulate across calls, later values
+// replacing earlier ones; keys must be label names of letters, digits, and underscores not starting with a digit,
+// other keys are skipped and reported as internal errors
+func (l *Logger) WithLabels(labels map[string]string) *Logger {
+	merged := make(map[string]string, len(l.labels)+len(labels))
+	for key, value := range l.labels {
+		merged[key] = value
+	}
+	for key, value := range labels {
+		if !validLabelName(key) {
+			l.internalLog("invalid label name '%s', label skipped\n", key)
+			continue
+		}
+		merged[key] = value
+	}
+	return &Logger{loggerCore: l.loggerCore, fields: l.fields, labels: merged, group: l.group}
+}
+
+// Labels returns a copy of the labels attached to this logger's records
+func (l *Logger) Labels() map[string]string {
+	labels := make(map[string]string, len(l.labels))
+	for key, value := range l.labels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// validLabelName reports whether name is a label name: letters, digits, and underscores, not starting with a digit
+func validLabelName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// mergeLabels returns the labels of base with those of extra added, sharing base or extra when the other is empty
+// Neither map is modified, record labels are shared read-only between outputs
+func mergeLabels(base, extra map[string]string) map[string]string {
+	if len(extra) == 0 {
+		return base
+	}
+	if len(base) == 0 {
+		return extra
+	}
+	merged := make(map[string]string, len(base)+len(extra))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range extra {
+		merged[key] = value
+	}
+	return merged
+}
\ No newline at end of file
@@ -0,0 +1,106 @@
+package log
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// callShedPressure is the queue occupancy from which a degraded logger drops records below WARN at the call site
+const callShedPressure = 0.5
+
+// CallLatencyStats describes the time spent inside logging calls since the last PROC heartbeat, or since the
+// logger started when heartbeats are disabled. Only collected when call_latency_stats or call_latency_budget_us is set
+// Percentiles are upper bounds of power-of-two nanosecond buckets, capped at Max
+type CallLatencyStats struct {
+	Count    uint64        // Calls measured
+	Max      time.Duration // Slowest call
+	P50      time.Duration // Median call
+	P99      time.Duration // 99th percentile call
+	Degraded bool          // A call exceeded call_latency_budget_us, the call path sheds work until reconfigured
+}
+
+// latencyTracker accumulates call durations observed by logging goroutines without locking
+// Buckets are reset one by one, a call measured during a reset may land in either interval
+type latencyTracker struct {
+	buckets [callLatencyBuckets]atomic.Uint64 // Bucket i counts durations in (2^(i-1), 2^i] ns
+	count   atomic.Uint64
+	max     atomic.Int64
+}
+
+// observe adds the duration of one logging call
+func (t *latencyTracker) observe(d time.Duration) {
+	ns := max(int64(d), 0)
+	idx := 0
+	if ns > 1 {
+		idx = min(bits.Len64(uint64(ns-1)), callLatencyBuckets-1)
+	}
+	t.buckets[idx].Add(1)
+	t.count.Add(1)
+	for {
+		old := t.max.Load()
+		if ns <= old || t.max.CompareAndSwap(old, ns) {
+			return
+		}
+	}
+}
+
+// snapshot returns the current distribution, starting a new interval if reset is set
+func (t *latencyTracker) snapshot(reset bool) sizeHistogram {
+	var h sizeHistogram
+	for i := range t.buckets {
+		if reset {
+			h.buckets[i] = t.buckets[i].Swap(0)
+		} else {
+			h.buckets[i] = t.buckets[i].Load()
+		}
+		h.count += h.buckets[i]
+	}
+	if reset {
+		t.count.Store(0)
+		h.max = t.max.Swap(0)
+	} else {
+		h.max = t.max.Load()
+	}
+	return h
+}
+
+// callLatency returns the public summary of the call durations since the last PROC heartbeat
+func (l *Logger) callLatency(reset bool) CallLatencyStats {
+	h := l.state.callLatency.snapshot(reset)
+	return CallLatencyStats{
+		Count:    h.count,
+		Max:      time.Duration(h.max),
+		P50:      time.Duration(h.percentile(0.50)),
+		P99:      time.Duration(h.percentile(0.99)),
+		Degraded: l.state.CallLatencyDegraded.Load(),
+	}
+}
+
+// measuresCallLatency reports whether logging calls are timed under cfg
+func (c *Config) measuresCallLatency() bool {
+	return c.CallLatencyStats || c.CallLatencyBudgetUs > 0
+}
+
+// observeCallLatency records the duration of a logging call started at start and degrades the call path the first
+// time the duration exceeds call_latency_budget_us
+func (l *Logger) observeCallLatency(cfg *Config, start time.Time) {
+	elapsed := time.Since(start)
+	l.state.callLatency.observe(elapsed)
+	if cfg.CallLatencyBudgetUs <= 0 || elapsed <= time.Duration(cfg.CallLatencyBudgetUs)*time.Microsecond {
+		return
+	}
+	if l.state.CallLatencyDegraded.CompareAndSwap(false, true) {
+		l.internalLog("warning - logging call took %v, over call_latency_budget_us=%d, skipping traces and shedding records below WARN under queue pressure\n",
+			elapsed, cfg.CallLatencyBudgetUs)
+	}
+}
+
+// shedsCall reports whether a degraded call path drops a record at level instead of queueing it
+// WARN and ERROR records are kept, lower levels are dropped once the queue is half full
+func (l *Logger) shedsCall(level int64) bool {
+	if level >= LevelWarn || !l.state.CallLatencyDegraded.Load() {
+		return false
+	}
+	return l.queuePressure() >= callShedPressure
+}
\ No newline at end of file
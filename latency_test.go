@@ -0,0 +1,41 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCallLatencyBudget verifies call durations are measured and a call over budget degrades the call path
+func TestCallLatencyBudget(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+
+	logger.Info("not measured")
+	assert.Equal(t, uint64(0), logger.Stats().CallLatency.Count, "Measuring is off by default")
+
+	require.NoError(t, logger.ApplyConfigString("call_latency_budget_us=60000000"))
+	for range 10 {
+		logger.Info("measured")
+	}
+	logger.Debug("filtered by level")
+	latency := logger.Stats().CallLatency
+	assert.Equal(t, uint64(10), latency.Count)
+	assert.Greater(t, latency.P99, time.Duration(0))
+	assert.LessOrEqual(t, latency.P99, latency.Max)
+	assert.False(t, latency.Degraded)
+
+	// A call over the budget switches the logger to the degraded path
+	logger.observeCallLatency(logger.getConfig(), time.Now().Add(-2*time.Minute))
+	assert.True(t, logger.Stats().CallLatency.Degraded)
+	assert.False(t, logger.shedsCall(LevelInfo), "Records are kept while the queue has room")
+	assert.False(t, logger.shedsCall(LevelWarn))
+
+	logger.logProcHeartbeat()
+	assert.Equal(t, uint64(0), logger.Stats().CallLatency.Count, "The PROC heartbeat starts a new interval")
+
+	require.NoError(t, logger.ApplyConfigString("call_latency_stats=true"))
+	assert.False(t, logger.Stats().CallLatency.Degraded, "Reconfiguring re-arms the budget")
+}
\ No newline at end of file
@@ -0,0 +1,239 @@
+package log
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// fieldRoutes holds the level overrides for one field name
+type fieldRoutes struct {
+	exact    map[string]*levelRule // Exact field value -> rule
+	patterns []levelPattern        // Wildcard value patterns
+}
+
+// levelPattern is a wildcard value pattern, '*' matches any sequence of characters
+type levelPattern struct {
+	pattern string
+	rule    *levelRule
+}
+
+// levelRule is one override rule, counting the records it dropped that the base level would have passed
+type levelRule struct {
+	name       string // Rule key as configured, "field=value"
+	level      int64
+	suppressed atomic.Uint64 // Reset by each suppression summary
+}
+
+// levelRoutes is the compiled form of Config.LevelOverridesByField, evaluated on the caller goroutine
+type levelRoutes struct {
+	fields   map[string]*fieldRoutes
+	rules    []*levelRule // All rules sorted by name, for suppression summaries
+	counts   bool         // Suppressed records are counted per rule, set with suppression_summary_interval_s
+	minLevel int64        // Lowest threshold of the base level and all rules, records below it are always dropped
+	maxLevel int64        // Highest threshold of the base level and all rules, records at or above it always pass
+}
+
+// compileLevelRoutes parses "field=pattern" -> level rules against the base level, nil when there are no rules
+func compileLevelRoutes(overrides map[string]string, base int64) (*levelRoutes, error) {
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+
+	r := &levelRoutes{
+		fields:   make(map[string]*fieldRoutes),
+		minLevel: base,
+		maxLevel: base,
+	}
+	for rule, levelStr := range overrides {
+		field, pattern, ok := strings.Cut(rule, "=")
+		field = strings.TrimSpace(field)
+		if !ok || field == "" {
+			return nil, fmtErrorf("invalid level override rule '%s' (use field=value)", rule)
+		}
+		level, err := parseLevelValue(levelStr)
+		if err != nil {
+			return nil, fmtErrorf("invalid level for override rule '%s': %w", rule, err)
+		}
+
+		fr := r.fields[field]
+		if fr == nil {
+			fr = &fieldRoutes{exact: make(map[string]*levelRule)}
+			r.fields[field] = fr
+		}
+		lr := &levelRule{name: field + "=" + pattern, level: level}
+		if strings.Contains(pattern, "*") {
+			fr.patterns = append(fr.patterns, levelPattern{pattern: pattern, rule: lr})
+		} else {
+			fr.exact[pattern] = lr
+		}
+		r.rules = append(r.rules, lr)
+		r.minLevel = min(r.minLevel, level)
+		r.maxLevel = max(r.maxLevel, level)
+	}
+	slices.SortFunc(r.rules, func(a, b *levelRule) int { return strings.Compare(a.name, b.name) })
+	return r, nil
+}
+
+// allows reports whether a record at level passes, given the logger's labels, its bound fields, and the call arguments
+// Without a matching rule the base level applies; when several rules match, the lowest level wins
+// A record the base level would have passed is counted as suppressed by the winning rule
+func (r *levelRoutes) allows(level, base int64, labels map[string]string, fields, args []any) bool {
+	if level < r.minLevel {
+		return false
+	}
+	if level >= r.maxLevel {
+		return true
+	}
+
+	var winner *levelRule
+	for label, value := range labels {
+		if fr := r.fields[label]; fr != nil {
+			if lr := fr.lookup(value); lr != nil && (winner == nil || lr.level < winner.level) {
+				winner = lr
+			}
+		}
+	}
+	winner = r.match(fields, winner)
+	winner = r.match(args, winner)
+	if winner == nil {
+		return level >= base
+	}
+	if level >= winner.level {
+		return true
+	}
+	if r.counts && level >= base {
+		winner.suppressed.Add(1)
+	}
+	return false
+}
+
+// match scans key-value positions of args, returning the lowest level rule among winner and the matching rules
+// Structured field maps are searched by key
+func (r *levelRoutes) match(args []any, winner *levelRule) *levelRule {
+	lower := func(lr *levelRule) {
+		if lr != nil && (winner == nil || lr.level < winner.level) {
+			winner = lr
+		}
+	}
+
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			if i+1 >= len(args) {
+				continue
+			}
+			if fr := r.fields[v]; fr != nil {
+				lower(fr.lookup(args[i+1]))
+			}
+		case map[string]any:
+			for field, fr := range r.fields {
+				if value, exists := v[field]; exists {
+					lower(fr.lookup(value))
+				}
+			}
+		}
+	}
+	return winner
+}
+
+// lookup returns the lowest level rule matching a field value, nil when none matches
+func (fr *fieldRoutes) lookup(value any) *levelRule {
+	s, ok := value.(string)
+	if !ok {
+		s = fmt.Sprint(value)
+	}
+
+	lr := fr.exact[s]
+	for _, p := range fr.patterns {
+		if (lr == nil || p.rule.level < lr.level) && matchWildcard(p.pattern, s) {
+			lr = p.rule
+		}
+	}
+	return lr
+}
+
+// matchWildcard reports whether s matches pattern, where '*' matches any sequence including an empty one
+func matchWildcard(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+
+	last := len(parts) - 1
+	for _, part := range parts[1:last] {
+		idx := strings.Index(s, part)
+		if idx < 0 {
+			return false
+		}
+		s = s[idx+len(part):]
+	}
+	return strings.HasSuffix(s, parts[last])
+}
+
+// parseLevelValue accepts a numeric level or a level name
+func parseLevelValue(value string) (int64, error) {
+	if numVal, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+		return numVal, nil
+	}
+	return Level(value)
+}
+
+// parseLevelOverrides parses the string form "field=pattern:level,field=pattern:level", empty clears all rules
+func parseLevelOverrides(value string) (map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	overrides := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		idx := strings.LastIndex(entry, ":")
+		if idx < 0 {
+			return nil, fmtErrorf("invalid level_overrides_by_field entry '%s' (use field=value:level)", entry)
+		}
+		overrides[strings.TrimSpace(entry[:idx])] = strings.TrimSpace(entry[idx+1:])
+	}
+	return overrides, nil
+}
+
+// logSuppressionSummary logs one INFO record per rule that suppressed records since the previous summary
+// level_overrides_by_field rules count records the base level would have passed, the call latency budget counts
+// the records its degraded call path shed
+func (l *Logger) logSuppressionSummary(interval time.Duration) {
+	epoch := l.getEpoch()
+	if epoch.config.SuppressionSummaryIntervalS <= 0 {
+		// The counters are only reset while summaries are enabled
+		return
+	}
+
+	intervalS := int64(interval / time.Second)
+	if r := epoch.levelRoutes; r != nil {
+		for _, lr := range r.rules {
+			if n := lr.suppressed.Swap(0); n > 0 {
+				l.writeHeartbeatRecord(LevelInfo, []any{
+					"type", "suppression",
+					"suppressor", "level_override",
+					"rule", lr.name,
+					"suppressed", n,
+					"interval_s", intervalS,
+				})
+			}
+		}
+	}
+	if n := l.state.CallsShed.Swap(0); n > 0 {
+		l.writeHeartbeatRecord(LevelInfo, []any{
+			"type", "suppression",
+			"suppressor", "call_latency_budget",
+			"rule", fmt.Sprintf("call_latency_budget_us=%d", epoch.config.CallLatencyBudgetUs),
+			"suppressed", n,
+			"interval_s", intervalS,
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,126 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLevelOverridesByField verifies field-based level routing for arguments, bound fields, and structured records
+func TestLevelOverridesByField(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	cfg := logger.GetConfig()
+	cfg.LevelOverridesByField = map[string]string{
+		"source=gnet":    "warn",
+		"component=db.*": "debug",
+	}
+	require.NoError(t, logger.ApplyConfig(cfg))
+
+	logger.Info("msg", "gnet info", "source", "gnet")
+	logger.Warn("msg", "gnet warn", "source", "gnet")
+	logger.Debug("msg", "pool debug", "component", "db.pool")
+	logger.Debug("msg", "http debug", "component", "http")
+	logger.Info("msg", "fiber info", "source", "fiber")
+	logger.withFields("component", "db.tx").Debug("bound debug")
+	logger.LogStructured(LevelDebug, "structured debug", map[string]any{"component": "db.cache"})
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	output := string(content)
+	assert.NotContains(t, output, "gnet info")
+	assert.Contains(t, output, "gnet warn")
+	assert.Contains(t, output, "pool debug")
+	assert.NotContains(t, output, "http debug")
+	assert.Contains(t, output, "fiber info")
+	assert.Contains(t, output, "bound debug")
+	assert.Contains(t, output, "structured debug")
+
+	// Clearing the rules restores the base level
+	require.NoError(t, logger.ApplyConfigString("level_overrides_by_field="))
+	logger.Debug("msg", "cleared debug", "component", "db.pool")
+	require.NoError(t, logger.Flush(time.Second))
+	content, err = os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "cleared debug")
+}
+
+// TestSuppressionSummary verifies per-rule counts of records suppressed by level overrides
+func TestSuppressionSummary(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("level_overrides_by_field=source=gnet:warn,source=fiber:error",
+		"suppression_summary_interval_s=60"))
+	for range 3 {
+		logger.Info("msg", "gnet info", "source", "gnet")
+	}
+	logger.Debug("msg", "gnet debug", "source", "gnet") // Below the base level, not counted
+	logger.Warn("msg", "fiber warn", "source", "fiber")
+	logger.Warn("msg", "gnet warn", "source", "gnet")
+
+	logger.logSuppressionSummary(time.Minute)
+	logger.logSuppressionSummary(time.Minute) // Counts were reset, nothing to report
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	output := string(content)
+	assert.Equal(t, 2, strings.Count(output, "suppression"), "One record per rule with suppressed records")
+	assert.Contains(t, output, "rule source=fiber suppressed 1 interval_s 60")
+	assert.Contains(t, output, "rule source=gnet suppressed 3 interval_s 60")
+	assert.Less(t, strings.Index(output, "source=fiber"), strings.Index(output, "source=gnet"), "Rules are reported by name")
+
+	// Disabled summaries leave the counters alone
+	assert.NoError(t, logger.ApplyConfigString("suppression_summary_interval_s=0"))
+	logger.state.CallsShed.Store(3)
+	logger.logSuppressionSummary(time.Minute)
+	assert.Equal(t, uint64(3), logger.state.CallsShed.Load())
+	assert.Error(t, logger.ApplyConfigString("suppression_summary_interval_s=-1"))
+}
+
+// TestLevelOverridesParsing verifies the string form and rule validation
+func TestLevelOverridesParsing(t *testing.T) {
+	cfg := DefaultConfig()
+	require.NoError(t, applyConfigField(cfg, "level_overrides_by_field", "source=gnet:warn, component=db.*:-4"))
+	assert.Equal(t, map[string]string{"source=gnet": "warn", "component=db.*": "-4"}, cfg.LevelOverridesByField)
+	assert.NoError(t, cfg.Validate())
+
+	clone := cfg.Clone()
+	clone.LevelOverridesByField["source=fiber"] = "error"
+	assert.NotContains(t, cfg.LevelOverridesByField, "source=fiber")
+
+	assert.Error(t, applyConfigField(cfg, "level_overrides_by_field", "source=gnet"))
+
+	cfg.LevelOverridesByField = map[string]string{"source": "warn"}
+	assert.Error(t, cfg.Validate())
+	cfg.LevelOverridesByField = map[string]string{"source=gnet": "loud"}
+	assert.Error(t, cfg.Validate())
+}
+
+// TestMatchWildcard verifies wildcard value matching
+func TestMatchWildcard(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		want           bool
+	}{
+		{"db.*", "db.pool", true},
+		{"db.*", "db.", true},
+		{"db.*", "cache", false},
+		{"*.pool", "db.pool", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "acb", false},
+		{"*", "", true},
+		{"exact", "exact", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchWildcard(tt.pattern, tt.value), "%s vs %s", tt.pattern, tt.value)
+	}
+}
\ No newline at end of file
@@ -1,8 +1,11 @@
 package log
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -146,6 +149,33 @@ func TestFlushOnStoppedLogger(t *testing.T) {
 	logger.Shutdown()
 }
 
+// TestFlushStats verifies that FlushStats reports records and bytes written since the previous flush
+func TestFlushStats(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	for i := 0; i < 5; i++ {
+		logger.Info("flush stats", i)
+	}
+
+	// Records queued before the flush request are written before it is served
+	result, err := logger.FlushStats(time.Second)
+	require.NoError(t, err)
+	assert.True(t, result.Synced, "File output should be synced")
+	assert.Equal(t, uint64(5), result.Records)
+	bytes := result.Bytes
+
+	info, err := os.Stat(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(info.Size()), bytes)
+
+	// Counters reset after each flush
+	result, err = logger.FlushStats(time.Second)
+	require.NoError(t, err)
+	assert.Zero(t, result.Records)
+	assert.Zero(t, result.Bytes)
+}
+
 // TestShutdownLifecycle checks the terminal state of the logger after shutdown
 func TestShutdownLifecycle(t *testing.T) {
 	logger, _ := createTestLogger(t)
@@ -173,4 +203,55 @@ func TestShutdownLifecycle(t *testing.T) {
 	err = logger.Flush(time.Second)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not initialized")
+}
+
+// TestStopWhileLogging verifies logging concurrently with Stop neither panics nor loses queued records silently
+// Records logged after Stop are discarded before queuing and are neither written nor counted
+func TestStopWhileLogging(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+
+	const writers, perWriter = 8, 200
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perWriter {
+				logger.Info("concurrent")
+			}
+		}()
+	}
+	require.NoError(t, logger.Stop(5*time.Second))
+	wg.Wait()
+	require.NoError(t, logger.Shutdown())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	written := uint64(strings.Count(string(content), "concurrent"))
+	assert.LessOrEqual(t, written+logger.state.TotalDroppedLogs.Load(), uint64(writers*perWriter))
+	assert.Equal(t, written, logger.state.TotalLogsProcessed.Load(), "Every queued record is written before the processor exits")
+}
+
+// TestLogQueueStop verifies a stopped queue rejects records and releases blocked senders
+func TestLogQueueStop(t *testing.T) {
+	q := newLogQueue(1)
+	assert.True(t, q.send(logRecord{}))
+	assert.False(t, q.send(logRecord{}), "Full queue rejects without blocking")
+
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- q.sendWait(context.Background(), logRecord{})
+	}()
+
+	q.stop()
+	select {
+	case err := <-blocked:
+		assert.ErrorIs(t, err, errQueueStopped)
+	case <-time.After(time.Second):
+		t.Fatal("Blocked sender not released by stop")
+	}
+	assert.False(t, q.send(logRecord{}))
+	q.stop()
+
+	assert.False(t, newStoppedQueue().send(logRecord{}))
 }
\ No newline at end of file
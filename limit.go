@@ -0,0 +1,163 @@
+package log
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/lixenwraith/log/formatter"
+)
+
+// applyFieldLimits enforces MaxFieldsPerRecord and MaxFieldKeyLen on a record's args
+// Returns the possibly rewritten args and false if the record must be rejected
+// The caller's args and field maps are never modified
+func (l *Logger) applyFieldLimits(cfg *Config, flags int64, args []any) ([]any, bool) {
+	if cfg.MaxFieldsPerRecord <= 0 && cfg.MaxFieldKeyLen <= 0 {
+		return args, true
+	}
+
+	var violated bool
+	if flags&FlagStructuredJSON != 0 && len(args) == 2 {
+		if fields, ok := args[1].(map[string]any); ok {
+			limited, changed := limitFieldMap(cfg, fields)
+			if changed {
+				violated = true
+				args = []any{args[0], limited}
+			}
+		}
+	} else if limited, changed := limitFieldArgs(cfg, args); changed {
+		violated = true
+		args = limited
+	}
+
+	if !violated {
+		return args, true
+	}
+
+	l.state.FieldLimitViolations.Add(1)
+	if cfg.FieldLimitPolicy == "reject" {
+		l.state.RejectedRecords.Add(1)
+		return nil, false
+	}
+	return args, true
+}
+
+// limitFieldArgs returns a copy of args within the configured limits
+// The message is kept and the key-value pairs following it are cut on pair boundaries, args that do not pair up
+// are cut at the limit; with the truncate policy, long keys are shortened and a "_truncated_fields" pair counts the
+// fields dropped
+// Returns the original args and false if no limit is exceeded
+func limitFieldArgs(cfg *Config, args []any) ([]any, bool) {
+	maxKeyLen := int(cfg.MaxFieldKeyLen)
+	maxFields := int(cfg.MaxFieldsPerRecord)
+	at := formatter.MessageLen(args)
+
+	changed := maxFields > 0 && len(args) > maxFields
+	if !changed && maxKeyLen > 0 {
+		for i := at; i < len(args); i += 2 {
+			if len(args[i].(string)) > maxKeyLen {
+				changed = true
+				break
+			}
+		}
+	}
+	if !changed {
+		return args, false
+	}
+
+	truncate := cfg.FieldLimitPolicy == "truncate"
+	dropped := 0
+	message := args[:at]
+	maxPairs := (len(args) - at) / 2
+	if maxFields > 0 && len(args) > maxFields {
+		if at >= maxFields {
+			dropped += at - maxFields
+			message, maxPairs = args[:maxFields], 0
+		} else {
+			maxPairs = min(maxPairs, (maxFields-at)/2)
+		}
+	}
+
+	limited := make([]any, 0, len(message)+2*maxPairs+2)
+	limited = append(limited, message...)
+	kept := 0
+	for i := at; i < len(args); i += 2 {
+		if kept >= maxPairs {
+			dropped++
+			continue
+		}
+		key := args[i].(string)
+		if maxKeyLen > 0 && len(key) > maxKeyLen {
+			if !truncate {
+				dropped++
+				continue
+			}
+			key = truncateUTF8(key, maxKeyLen)
+		}
+		limited = append(limited, key, args[i+1])
+		kept++
+	}
+	if truncate && dropped > 0 {
+		limited = append(limited, "_truncated_fields", dropped)
+	}
+	return limited, true
+}
+
+// limitFieldMap returns a copy of fields within the configured limits, keys are kept in sorted order
+// Returns the original map and false if no limit is exceeded
+func limitFieldMap(cfg *Config, fields map[string]any) (map[string]any, bool) {
+	maxKeyLen := int(cfg.MaxFieldKeyLen)
+	maxFields := int(cfg.MaxFieldsPerRecord)
+
+	changed := maxFields > 0 && len(fields) > maxFields
+	if !changed && maxKeyLen > 0 {
+		for k := range fields {
+			if len(k) > maxKeyLen {
+				changed = true
+				break
+			}
+		}
+	}
+	if !changed {
+		return fields, false
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	truncate := cfg.FieldLimitPolicy == "truncate"
+	limited := make(map[string]any, len(fields))
+	dropped := 0
+	for _, k := range keys {
+		if maxFields > 0 && len(limited) >= maxFields {
+			dropped++
+			continue
+		}
+		key := k
+		if maxKeyLen > 0 && len(key) > maxKeyLen {
+			if !truncate {
+				dropped++
+				continue
+			}
+			key = truncateUTF8(key, maxKeyLen)
+		}
+		limited[key] = fields[k]
+	}
+	if truncate && dropped > 0 {
+		limited["_truncated_fields"] = dropped
+	}
+	return limited, true
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a multi-byte rune
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
\ No newline at end of file
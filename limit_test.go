@@ -0,0 +1,109 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFieldLimitPolicies verifies truncate, drop_extra, and reject handling of oversized records
+func TestFieldLimitPolicies(t *testing.T) {
+	args := []any{"a", 1, "b", 2, "c", 3}
+	fields := map[string]any{"short": 1, "a_very_long_key": 2, "other": 3}
+
+	tests := []struct {
+		policy       string
+		wantArgs     []any
+		wantFields   map[string]any
+		wantAccepted bool
+	}{
+		{"truncate", []any{"a", 1, "b", 2, "_truncated_fields", 1}, map[string]any{"a_ver": 2, "other": 3, "_truncated_fields": 1}, true},
+		{"drop_extra", []any{"a", 1, "b", 2}, map[string]any{"other": 3, "short": 1}, true},
+		{"reject", nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			logger := NewLogger()
+			cfg := DefaultConfig()
+			cfg.MaxFieldsPerRecord = 4
+			cfg.MaxFieldKeyLen = 5
+			cfg.FieldLimitPolicy = tt.policy
+			require.NoError(t, cfg.Validate())
+
+			got, ok := logger.applyFieldLimits(cfg, FlagDefault, args)
+			assert.Equal(t, tt.wantAccepted, ok)
+			assert.Equal(t, tt.wantArgs, got)
+
+			cfg.MaxFieldsPerRecord = 2
+			got, ok = logger.applyFieldLimits(cfg, FlagStructuredJSON, []any{"msg", fields})
+			assert.Equal(t, tt.wantAccepted, ok)
+			if tt.wantAccepted {
+				assert.Equal(t, tt.wantFields, got[1])
+			}
+
+			assert.Len(t, fields, 3, "Caller map must not be modified")
+			assert.Equal(t, uint64(2), logger.Stats().FieldLimitViolations)
+		})
+	}
+}
+
+// TestFieldLimitPairs verifies positional args keep their message and are cut on pair boundaries with pair keys
+// limited in length
+func TestFieldLimitPairs(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxFieldsPerRecord = 6
+	cfg.MaxFieldKeyLen = 4
+
+	// A message followed by bound and call pairs, one key too long
+	args := []any{"request done", "svc", "api", "tenant_id", 7, "code", 200, "ms", 12}
+
+	limited, changed := limitFieldArgs(cfg, args)
+	require.True(t, changed)
+	assert.Equal(t, []any{"request done", "svc", "api", "tena", 7, "_truncated_fields", 2}, limited)
+
+	cfg.FieldLimitPolicy = "drop_extra"
+	limited, _ = limitFieldArgs(cfg, args)
+	assert.Equal(t, []any{"request done", "svc", "api", "code", 200}, limited)
+
+	// Within the count limit, only the key is shortened
+	cfg.FieldLimitPolicy = "truncate"
+	cfg.MaxFieldsPerRecord = 0
+	limited, _ = limitFieldArgs(cfg, []any{"msg", "hi", "tenant_id", 7})
+	assert.Equal(t, []any{"msg", "hi", "tena", 7}, limited)
+
+	// Args that do not pair up are cut at the limit
+	cfg.MaxFieldsPerRecord = 3
+	cfg.MaxFieldKeyLen = 0
+	limited, _ = limitFieldArgs(cfg, []any{"values:", 1, 2, 3, 4})
+	assert.Equal(t, []any{"values:", 1, 2, "_truncated_fields", 2}, limited)
+
+	_, changed = limitFieldArgs(cfg, []any{"ok", "a", 1})
+	assert.False(t, changed)
+}
+
+// TestFieldLimitReject verifies rejected records are not written and are reported via Stats
+func TestFieldLimitReject(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("max_fields_per_record=3", "field_limit_policy=reject"))
+
+	logger.Info("within", "limit")
+	logger.Info("far", "too", "many", "fields")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "within")
+	assert.NotContains(t, string(content), "far")
+
+	stats := logger.Stats()
+	assert.Equal(t, uint64(1), stats.FieldLimitViolations)
+	assert.Equal(t, uint64(1), stats.RejectedRecords)
+	assert.Error(t, logger.ApplyConfigString("field_limit_policy=ignore"))
+}
\ No newline at end of file
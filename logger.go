@@ -1,6 +1,7 @@
 package log
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -14,28 +15,53 @@ import (
 )
 
 // Logger is the core struct that encapsulates all logger functionality
+// Loggers derived from another logger share its core and differ only in bound fields
 type Logger struct {
-	currentConfig atomic.Value // stores *Config
-	state         State
-	initMu        sync.Mutex
-	formatter     atomic.Value // stores *formatter.Formatter
+	*loggerCore
+	fields []any             // Key-value pairs bound to this logger, inserted after the message of every record
+	labels map[string]string // Labels attached to every record, shared read-only with derived loggers
+	group  string            // Dotted group path qualifying the keys of later bound and per-call fields, see WithGroup
+}
+
+// loggerCore holds the configuration, state, and processor shared by a logger and its derived loggers
+type loggerCore struct {
+	epoch          atomic.Value // stores *configEpoch
+	state          State
+	initMu         sync.Mutex
+	batchMu        sync.Mutex   // Held by the processor while writing a record batch, excludes output swaps
+	networkMu      sync.Mutex   // Serializes syslog, journald, and GELF writes of the processor and its shards
+	fileMatcher    atomic.Value // stores *logFileMatcher
+	sinks          atomic.Value // stores []recordSink
+	outputs        atomic.Value // stores []*namedSink, registered with AddSink
+	sinkMu         sync.Mutex   // Serializes sink and output list updates
+	shards         atomic.Value // stores *shardSet, nil when writing a single file
+	errorFile      atomic.Value // stores *Logger writing {name}_error.{ext}, nil unless split_error_file is enabled
+	mirror         atomic.Value // stores *Logger writing mirror_directory, nil unless mirroring is enabled
+	parent         *loggerCore  // Set on shard loggers, whose records also feed the parent's sinks
+	syncGroup      atomic.Value // stores *SyncGroup, nil when syncing independently
+	pressureWatch  atomic.Value // stores *pressureWatch, nil without an OnPressure callback
+	writeError     atomic.Value // stores WriteErrorFunc, nil without an OnWriteError callback
+	recent         atomic.Value // stores *recentBuffer, nil unless recent_records is set
+	retentionClock retentionClock
 }
 
 // NewLogger creates a new Logger instance with default settings
 func NewLogger() *Logger {
-	l := &Logger{}
+	l := &Logger{loggerCore: &loggerCore{}}
 
-	// Set default configuration
+	// Set default configuration and formatter to prevent nil access
 	defaultCfg := DefaultConfig()
-	l.currentConfig.Store(defaultCfg)
-
-	// Initialize default formatter to prevent nil access
 	defaultFormatter := formatter.New(sanitizer.New()).
 		Type(defaultCfg.Format).
 		TimestampFormat(defaultCfg.TimestampFormat).
 		ShowLevel(defaultCfg.ShowLevel).
-		ShowTimestamp(defaultCfg.ShowTimestamp)
-	l.formatter.Store(defaultFormatter)
+		ShowTimestamp(defaultCfg.ShowTimestamp).
+		AutoKV(defaultCfg.AutoKV)
+	l.epoch.Store(&configEpoch{
+		config:    defaultCfg,
+		formatter: defaultFormatter,
+		console:   newConsoleSink(l, defaultCfg),
+	})
 
 	// Initialize the state
 	l.state.IsInitialized.Store(false)
@@ -48,18 +74,18 @@ func NewLogger() *Logger {
 	l.state.EarliestFileTime.Store(time.Time{})
 
 	// Initialize heartbeat counters
-	l.state.HeartbeatSequence.Store(0)
+	l.state.ProcSequence.Store(0)
+	l.state.DiskSequence.Store(0)
+	l.state.SysSequence.Store(0)
 	l.state.LoggerStartTime.Store(time.Now())
 	l.state.TotalLogsProcessed.Store(0)
 	l.state.TotalRotations.Store(0)
 	l.state.TotalDeletions.Store(0)
 
-	// Create a closed channel initially to prevent nil pointer issues
-	initialChan := make(chan logRecord)
-	close(initialChan)
-	l.state.ActiveLogChannel.Store(initialChan)
+	// Start with a stopped queue so records logged before Start are dropped instead of blocking
+	l.state.ActiveQueue.Store(newStoppedQueue())
 
-	l.state.flushRequestChan = make(chan chan struct{}, 1)
+	l.state.flushRequestChan = make(chan chan FlushResult, 1)
 
 	return l
 }
@@ -95,7 +121,7 @@ func (l *Logger) ApplyConfigString(overrides ...string) error {
 			continue
 		}
 
-		if err := applyConfigField(cfg, key, value); err != nil {
+		if err := cfg.Set(key, value, SourceOverride); err != nil {
 			errors = append(errors, err)
 		}
 	}
@@ -118,6 +144,9 @@ func (l *Logger) Start() error {
 	if !l.state.IsInitialized.Load() {
 		return fmtErrorf("logger not initialized, call ApplyConfig first")
 	}
+	if l.getConfig().ReadOnly {
+		return fmtErrorf("logger is read_only and cannot be started, it only inspects existing files")
+	}
 
 	// Check if processor didn't exit cleanly last time
 	if l.state.Started.Load() && !l.state.ProcessorExited.Load() {
@@ -132,13 +161,33 @@ func (l *Logger) Start() error {
 	if l.state.Started.CompareAndSwap(false, true) {
 		cfg := l.getConfig()
 
-		// Create log channel
-		logChannel := make(chan logRecord, cfg.BufferSize)
-		l.state.ActiveLogChannel.Store(logChannel)
+		// Create log queue
+		queue := newLogQueue(cfg.BufferSize)
+		l.state.ActiveQueue.Store(queue)
 
 		// Start processor
 		l.state.ProcessorExited.Store(false)
-		go l.processLogs(logChannel)
+		go l.processLogs(queue)
+	}
+
+	var shardErr error
+	l.forEachShard(func(shard *Logger) {
+		shardErr = errors.Join(shardErr, shard.Start())
+	})
+	if shardErr != nil {
+		return fmtErrorf("failed to start shards: %w", shardErr)
+	}
+
+	if errorFile := l.getErrorFile(); errorFile != nil {
+		if err := errorFile.Start(); err != nil {
+			return fmtErrorf("failed to start error file: %w", err)
+		}
+	}
+
+	if mirror := l.getMirror(); mirror != nil {
+		if err := mirror.Start(); err != nil {
+			return fmtErrorf("failed to start mirror: %w", err)
+		}
 	}
 
 	return nil
@@ -160,17 +209,8 @@ func (l *Logger) Stop(timeout ...time.Duration) error {
 		effectiveTimeout = 2 * time.Duration(cfg.FlushIntervalMs) * time.Millisecond
 	}
 
-	// Get current channel and close it
-	ch := l.getCurrentLogChannel()
-	if ch != nil {
-		// Create closed channel for immediate replacement
-		closedChan := make(chan logRecord)
-		close(closedChan)
-		l.state.ActiveLogChannel.Store(closedChan)
-
-		// Close the actual channel to signal processor
-		close(ch)
-	}
+	// Stop the queue, the processor writes the records already queued and exits
+	l.getQueue().stop()
 
 	// Wait for processor to exit (with timeout)
 	deadline := time.Now().Add(effectiveTimeout)
@@ -185,7 +225,18 @@ func (l *Logger) Stop(timeout ...time.Duration) error {
 		return fmtErrorf("processor did not exit within timeout (%v)", effectiveTimeout)
 	}
 
-	return nil
+	var shardErr error
+	l.forEachShard(func(shard *Logger) {
+		shardErr = errors.Join(shardErr, shard.Stop(timeout...))
+	})
+	// Stopped after the processors forwarding to it
+	if errorFile := l.getErrorFile(); errorFile != nil {
+		shardErr = errors.Join(shardErr, errorFile.Stop(timeout...))
+	}
+	if mirror := l.getMirror(); mirror != nil {
+		shardErr = errors.Join(shardErr, mirror.Stop(timeout...))
+	}
+	return shardErr
 }
 
 // Shutdown gracefully closes the logger, attempting to flush pending records
@@ -196,6 +247,7 @@ func (l *Logger) Shutdown(timeout ...time.Duration) error {
 	}
 
 	l.state.LoggerDisabled.Store(true)
+	l.removeExitHook()
 
 	if !l.state.IsInitialized.Load() {
 		l.state.ShutdownCalled.Store(false)
@@ -212,58 +264,112 @@ func (l *Logger) Shutdown(timeout ...time.Duration) error {
 	l.state.IsInitialized.Store(false)
 
 	var finalErr error
-	cfPtr := l.state.CurrentFile.Load()
-	if cfPtr != nil {
-		if currentLogFile, ok := cfPtr.(*os.File); ok && currentLogFile != nil {
-			if err := currentLogFile.Sync(); err != nil {
-				syncErr := fmtErrorf("failed to sync log file '%s' during shutdown: %w", currentLogFile.Name(), err)
-				finalErr = errors.Join(finalErr, syncErr)
-			}
-			if err := currentLogFile.Close(); err != nil {
-				closeErr := fmtErrorf("failed to close log file '%s' during shutdown: %w", currentLogFile.Name(), err)
-				finalErr = errors.Join(finalErr, closeErr)
-			}
-			l.state.CurrentFile.Store((*os.File)(nil))
+	if out := l.getEpoch().file; out != nil {
+		finalErr = out.Close()
+	}
+
+	l.networkMu.Lock()
+	if out := l.getEpoch().syslog; out != nil {
+		out.close()
+	}
+	if out := l.getEpoch().journald; out != nil {
+		out.close()
+	}
+	if out := l.getEpoch().gelf; out != nil {
+		out.close()
+	}
+	l.networkMu.Unlock()
+	if u := l.getEpoch().s3; u != nil {
+		// Queued archives are uploaded within the timeout, the rest stay local
+		drainTimeout := 2 * time.Duration(l.getConfig().FlushIntervalMs) * time.Millisecond
+		if len(timeout) > 0 {
+			drainTimeout = timeout[0]
 		}
+		u.drain(drainTimeout)
+	}
+	if h := l.getEpoch().exec; h != nil {
+		h.close()
 	}
 
 	if stopErr != nil {
 		finalErr = errors.Join(finalErr, stopErr)
 	}
 
+	if set := l.getShards(); set != nil {
+		finalErr = errors.Join(finalErr, shutdownShards(set.loggers, timeout...))
+	}
+	if errorFile := l.getErrorFile(); errorFile != nil {
+		finalErr = errors.Join(finalErr, errorFile.Shutdown(timeout...))
+	}
+	if mirror := l.getMirror(); mirror != nil {
+		finalErr = errors.Join(finalErr, mirror.Shutdown(timeout...))
+	}
+
+	finalErr = errors.Join(finalErr, l.closeOutputs())
+
 	return finalErr
 }
 
 // Flush explicitly triggers a sync of the current log file buffer to disk and waits for completion or timeout
 func (l *Logger) Flush(timeout time.Duration) error {
+	_, err := l.FlushStats(timeout)
+	return err
+}
+
+// FlushStats triggers a flush like Flush and reports the records and bytes written since the previous explicit flush
+// and whether a file sync was actually performed
+func (l *Logger) FlushStats(timeout time.Duration) (FlushResult, error) {
 	l.state.flushMutex.Lock()
 	defer l.state.flushMutex.Unlock()
 
 	// State checks
 	if !l.state.IsInitialized.Load() || l.state.ShutdownCalled.Load() {
-		return fmtErrorf("logger not initialized or already shut down")
+		return FlushResult{}, fmtErrorf("logger not initialized or already shut down")
 	}
 	if !l.state.Started.Load() {
-		return fmtErrorf("logger not started")
+		return FlushResult{}, fmtErrorf("logger not started")
 	}
 
 	// Create a channel to wait for confirmation from the processor
-	confirmChan := make(chan struct{})
+	resultChan := make(chan FlushResult, 1)
 
 	// Send the request with the confirmation channel
 	select {
-	case l.state.flushRequestChan <- confirmChan:
+	case l.state.flushRequestChan <- resultChan:
 		// Request sent
 	case <-time.After(minWaitTime): // Short timeout to prevent blocking if processor is stuck
-		return fmtErrorf("failed to send flush request to processor (possible deadlock or high load)")
+		return FlushResult{}, fmtErrorf("failed to send flush request to processor (possible deadlock or high load)")
 	}
 
+	var result FlushResult
 	select {
-	case <-confirmChan:
-		return nil
+	case result = <-resultChan:
+		if result.err != nil {
+			return FlushResult{}, result.err
+		}
 	case <-time.After(timeout):
-		return fmtErrorf("timeout waiting for flush confirmation (%v)", timeout)
+		return FlushResult{}, fmtErrorf("timeout waiting for flush confirmation (%v)", timeout)
 	}
+
+	// Sharded output is flushed per shard, the result covers all of them
+	var shardErr error
+	l.forEachShard(func(shard *Logger) {
+		shardResult, err := shard.FlushStats(timeout)
+		shardErr = errors.Join(shardErr, err)
+		result.Records += shardResult.Records
+		result.Bytes += shardResult.Bytes
+		result.Synced = result.Synced || shardResult.Synced
+	})
+	// Records forwarded by the processors above are queued, the error file and mirror count separately
+	if errorFile := l.getErrorFile(); errorFile != nil {
+		_, err := errorFile.FlushStats(timeout)
+		shardErr = errors.Join(shardErr, err)
+	}
+	if mirror := l.getMirror(); mirror != nil {
+		_, err := mirror.FlushStats(timeout)
+		shardErr = errors.Join(shardErr, err)
+	}
+	return result, shardErr
 }
 
 // Debug logs a message at debug level
@@ -294,6 +400,62 @@ func (l *Logger) Error(args ...any) {
 	l.log(flags, LevelError, cfg.TraceDepth, args...)
 }
 
+// DebugContext logs a message at debug level with the trace IDs and fields carried by ctx, see ContextFields
+// The record is skipped when ctx is already cancelled or past its deadline
+func (l *Logger) DebugContext(ctx context.Context, args ...any) {
+	if l.suppressCancelled(ctx, LevelDebug) {
+		return
+	}
+	flags := l.getFlags()
+	cfg := l.getConfig()
+	l.withContext(ctx).log(flags, LevelDebug, cfg.TraceDepth, args...)
+}
+
+// InfoContext logs a message at info level with the trace IDs and fields carried by ctx, see ContextFields
+// The record is skipped when ctx is already cancelled or past its deadline
+func (l *Logger) InfoContext(ctx context.Context, args ...any) {
+	if l.suppressCancelled(ctx, LevelInfo) {
+		return
+	}
+	flags := l.getFlags()
+	cfg := l.getConfig()
+	l.withContext(ctx).log(flags, LevelInfo, cfg.TraceDepth, args...)
+}
+
+// WarnContext logs a message at warning level with the trace IDs and fields carried by ctx, see ContextFields
+// The record is skipped when ctx is already cancelled or past its deadline
+func (l *Logger) WarnContext(ctx context.Context, args ...any) {
+	if l.suppressCancelled(ctx, LevelWarn) {
+		return
+	}
+	flags := l.getFlags()
+	cfg := l.getConfig()
+	l.withContext(ctx).log(flags, LevelWarn, cfg.TraceDepth, args...)
+}
+
+// ErrorContext logs a message at error level with the trace IDs and fields carried by ctx, see ContextFields
+// The record is skipped when ctx is already cancelled or past its deadline
+func (l *Logger) ErrorContext(ctx context.Context, args ...any) {
+	if l.suppressCancelled(ctx, LevelError) {
+		return
+	}
+	flags := l.getFlags()
+	cfg := l.getConfig()
+	l.withContext(ctx).log(flags, LevelError, cfg.TraceDepth, args...)
+}
+
+// suppressCancelled reports whether a record must be skipped because its request context is done
+// Only records that would otherwise be enqueued are counted as suppressed
+func (l *Logger) suppressCancelled(ctx context.Context, level int64) bool {
+	if ctx == nil || ctx.Err() == nil {
+		return false
+	}
+	if l.state.Started.Load() && level >= l.getConfig().Level {
+		l.state.SuppressedCancelled.Add(1)
+	}
+	return true
+}
+
 // DebugTrace logs a debug message with function call trace
 func (l *Logger) DebugTrace(depth int, args ...any) {
 	flags := l.getFlags()
@@ -335,7 +497,15 @@ func (l *Logger) LogTrace(depth int, args ...any) {
 
 // LogStructured logs a message with structured fields as proper JSON
 func (l *Logger) LogStructured(level int64, message string, fields map[string]any) {
-	l.log(l.getFlags()|FlagStructuredJSON, level, 0, []any{message, fields})
+	l.log(l.getFlags()|FlagStructuredJSON, level, 0, message, fields)
+}
+
+// LogWithConsoleTag logs at the given level with a level-colored "[tag]" prefix applied to console output only
+// File output is unaffected; used by compat adapters to identify the originating component in a shared terminal
+func (l *Logger) LogWithConsoleTag(level int64, tag string, args ...any) {
+	flags := l.getFlags()
+	cfg := l.getConfig()
+	l.logTagged(flags, level, cfg.TraceDepth, tag, args...)
 }
 
 // Write outputs raw, unformatted data ignoring configured format and sanitization without trailing new line
@@ -343,30 +513,143 @@ func (l *Logger) Write(args ...any) {
 	l.log(FlagRaw, LevelInfo, 0, args...)
 }
 
+// With returns a logger sharing this logger's output that adds the key-value pairs to every record, after the message
+// and before the call's own pairs. Fields accumulate across calls; the returned logger is cheap to create per request
+func (l *Logger) With(fields ...any) *Logger {
+	return l.withFields(fields...)
+}
+
+// withFields returns a logger sharing this logger's core with additional bound key-value pairs
+func (l *Logger) withFields(fields ...any) *Logger {
+	bound := make([]any, 0, len(l.fields)+len(fields))
+	bound = append(bound, l.fields...)
+	bound = append(bound, groupPairs(l.group, 0, fields)...)
+	return &Logger{loggerCore: l.loggerCore, fields: bound, labels: l.labels, group: l.group}
+}
+
 // getConfig returns the current configuration (thread-safe)
 func (l *Logger) getConfig() *Config {
-	return l.currentConfig.Load().(*Config)
+	return l.getEpoch().config
+}
+
+// getEpoch returns the current configuration snapshot
+func (l *Logger) getEpoch() *configEpoch {
+	return l.epoch.Load().(*configEpoch)
 }
 
 // applyConfig is the internal implementation for applying configuration, assuming initMu is held
+// All fallible preparation happens before the commit, which swaps configuration, formatter, and outputs
+// while holding batchMu so no record batch observes a partial change
 func (l *Logger) applyConfig(cfg *Config) error {
-	oldCfg := l.getConfig()
-	l.currentConfig.Store(cfg)
+	oldEpoch := l.getEpoch()
+	oldCfg := oldEpoch.config
 
-	// Create formatter with sanitizer
-	s := sanitizer.New().Policy(cfg.Sanitization)
+	// Create formatter with sanitizer, the console formatter and network outputs may use another policy
+	s := sanitizer.New().Policy(cfg.sanitization(cfg.FileSanitization, cfg.fileFormat()))
 	newFormatter := formatter.New(s).
-		Type(cfg.Format).
+		Type(cfg.fileFormat()).
 		TimestampFormat(cfg.TimestampFormat).
 		ShowLevel(cfg.ShowLevel).
-		ShowTimestamp(cfg.ShowTimestamp)
-	l.formatter.Store(newFormatter)
+		ShowTimestamp(cfg.ShowTimestamp).
+		AutoKV(cfg.AutoKV).
+		JSONFields(cfg.JSONFields).
+		NestedKeys(cfg.NestedKeys).
+		JSONKeys(cfg.jsonKeys()).
+		ReservedKeyPolicy(cfg.ReservedKeyPolicy).
+		OnDroppedKey(func(string) { l.state.DroppedReservedKeys.Add(1) }).
+		BytesEncoding(cfg.BytesEncoding).
+		DurationFormat(cfg.DurationFormat).
+		TimeValueFormat(cfg.TimeValueFormat).
+		CSVColumns(cfg.csvColumns()).
+		TxtLayout(cfg.TxtLayout).
+		Host(cfg.GELFHost).
+		GCPProject(cfg.GCPProject)
+	newCEFSettings(cfg).apply(newFormatter)
+	newRFC5424Settings(cfg).apply(newFormatter)
+
+	// Heartbeats get a structured formatter when heartbeat_format differs from the file format
+	var heartbeatFormatter *formatter.Formatter
+	if cfg.HeartbeatFormat != "" && (cfg.HeartbeatFormat != cfg.fileFormat() || cfg.CustomFormatter != nil) {
+		heartbeatFormatter = formatter.New(s).
+			Type(cfg.HeartbeatFormat).
+			TimestampFormat(cfg.TimestampFormat).
+			AutoKV(true).
+			JSONKeys(cfg.jsonKeys()).
+			ReservedKeyPolicy(cfg.ReservedKeyPolicy).
+			BytesEncoding(cfg.BytesEncoding)
+	}
+
+	var consoleOut *consoleSink
+	if cfg.EnableConsole {
+		consoleOut = newConsoleSink(l, cfg)
+	}
+
+	// A second formatter serves the console when it uses another format or policy, glyphs replace level names, or
+	// levels are colored; a custom formatter serves the console too unless console_format is set
+	consolePolicy := cfg.sanitization(cfg.ConsoleSanitization, cfg.consoleFormat())
+	var consoleFormatter *formatter.Formatter
+	if cfg.EnableConsole && (cfg.CustomFormatter == nil || cfg.ConsoleFormat != "") &&
+		(cfg.ConsoleGlyphs || consoleOut.colorsLevel(cfg) || cfg.consoleFormat() != cfg.fileFormat() || cfg.CustomFormatter != nil ||
+			consolePolicy != cfg.sanitization(cfg.FileSanitization, cfg.fileFormat())) {
+		consoleFormatter = formatter.New(sanitizer.New().Policy(consolePolicy)).
+			Type(cfg.consoleFormat()).
+			TimestampFormat(cfg.TimestampFormat).
+			ShowLevel(cfg.ShowLevel && !cfg.ConsoleGlyphs).
+			ShowTimestamp(cfg.ShowTimestamp).
+			AutoKV(cfg.AutoKV).
+			JSONFields(cfg.JSONFields).
+			NestedKeys(cfg.NestedKeys).
+			JSONKeys(cfg.jsonKeys()).
+			ReservedKeyPolicy(cfg.ReservedKeyPolicy).
+			BytesEncoding(cfg.BytesEncoding).
+			DurationFormat(cfg.DurationFormat).
+			TimeValueFormat(cfg.TimeValueFormat).
+			CSVColumns(cfg.csvColumns()).
+			TxtLayout(cfg.TxtLayout).
+			Host(cfg.GELFHost).
+			GCPProject(cfg.GCPProject)
+		newCEFSettings(cfg).apply(consoleFormatter)
+		newRFC5424Settings(cfg).apply(consoleFormatter)
+		if consoleOut.colorsLevel(cfg) {
+			consoleFormatter.LevelColor(consoleOut.color)
+		}
+	}
+
+	levelRoutes, err := compileLevelRoutes(cfg.LevelOverridesByField, cfg.Level)
+	if err != nil {
+		return err
+	}
+	if levelRoutes != nil {
+		levelRoutes.counts = cfg.SuppressionSummaryIntervalS > 0
+	}
+
+	// Syslog, journald, and GELF connect lazily on the processor, an unchanged output keeps its connection
+	syslogOut := configureSyslog(cfg, oldEpoch.syslog)
+	journaldOut := configureJournald(cfg, oldEpoch.journald)
+	gelfOut := configureGELF(cfg, oldEpoch.gelf)
+
+	// A read-only logger inspects an existing directory and never creates it
+	if cfg.ReadOnly {
+		if l.state.Started.Load() {
+			return fmtErrorf("cannot switch a started logger to read_only, stop it first")
+		}
+		info, err := os.Stat(cfg.Directory)
+		if err != nil {
+			return fmtErrorf("failed to inspect log directory '%s': %w", cfg.Directory, err)
+		}
+		if !info.IsDir() {
+			return fmtErrorf("log directory '%s' exists but is not a directory", cfg.Directory)
+		}
+	}
 
 	// Ensure log directory exists if file output is enabled
-	if cfg.EnableFile {
+	if cfg.EnableFile && !cfg.ReadOnly {
+		// Reject unusable directories before touching the filesystem, the current configuration stays active
+		if err := checkLogDirectory(cfg.Directory); err != nil {
+			return err
+		}
 		if err := os.MkdirAll(cfg.Directory, 0755); err != nil {
 			l.state.LoggerDisabled.Store(true)
-			l.currentConfig.Store(oldCfg) // Rollback
 			return fmtErrorf("failed to create log directory '%s': %w", cfg.Directory, err)
 		}
 	}
@@ -378,14 +661,28 @@ func (l *Logger) applyConfig(cfg *Config) error {
 	// Determine if restart is needed
 	needsRestart := wasStarted && wasInitialized && configRequiresRestart(oldCfg, cfg)
 
-	// Stop processor if restart needed
+	// Stop processor if restart needed, pending records are written under the old configuration
 	if needsRestart {
 		if err := l.Stop(); err != nil {
-			l.currentConfig.Store(oldCfg) // Rollback
 			return fmtErrorf("failed to stop processor for restart: %w", err)
 		}
 	}
 
+	// A failure from here on undoes what was prepared, in reverse order, and restarts a processor stopped above,
+	// so the old configuration keeps running
+	var undo []func()
+	rollback := func(err error) error {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+		if needsRestart {
+			if startErr := l.Start(); startErr != nil {
+				return errors.Join(err, fmtErrorf("failed to restart processor: %w", startErr))
+			}
+		}
+		return err
+	}
+
 	// Get current file handle
 	currentFilePtr := l.state.CurrentFile.Load()
 	var currentFile *os.File
@@ -397,62 +694,155 @@ func (l *Logger) applyConfig(cfg *Config) error {
 	needsNewFile := !wasInitialized || currentFile == nil ||
 		oldCfg.Directory != cfg.Directory ||
 		oldCfg.Name != cfg.Name ||
-		oldCfg.Extension != cfg.Extension
-
-	// Handle file state transitions
-	if !cfg.EnableFile {
-		// When disabling file output, close the current file
-		if currentFile != nil {
-			// Sync and close the file
-			_ = currentFile.Sync()
-			if err := currentFile.Close(); err != nil {
-				l.internalLog("warning - failed to close log file during disable: %v\n", err)
-			}
-		}
-		l.state.CurrentFile.Store((*os.File)(nil))
-		l.state.CurrentSize.Store(0)
-	} else if needsNewFile {
-		// When enabling file output or initializing, create new file
-		logFile, err := l.createNewLogFile()
+		oldCfg.Extension != cfg.Extension ||
+		oldCfg.GzipActive != cfg.GzipActive ||
+		oldCfg.OpenDSync != cfg.OpenDSync
+
+	// Open the new file before committing so a failure leaves the old configuration in place
+	var newFile *os.File
+	if cfg.fileOutput() && needsNewFile {
+		logFile, err := openLogFile(logFilePath(cfg), cfg.OpenDSync)
 		if err != nil {
-			l.state.LoggerDisabled.Store(true)
-			l.currentConfig.Store(oldCfg) // Rollback
-			return fmtErrorf("failed to create log file: %w", err)
-		}
-
-		// Close old file if transitioning from one file to another
-		if currentFile != nil && currentFile != logFile {
-			_ = currentFile.Sync()
-			if err := currentFile.Close(); err != nil {
-				l.internalLog("warning - failed to close old log file: %v\n", err)
-			}
-		}
-
-		l.state.CurrentFile.Store(logFile)
-		l.state.CurrentSize.Store(0)
-		if fi, errStat := logFile.Stat(); errStat == nil {
-			l.state.CurrentSize.Store(fi.Size())
+			return rollback(fmtErrorf("failed to create log file: %w", err))
 		}
+		newFile = logFile
+		undo = append(undo, func() { _ = logFile.Close() })
 	}
 
 	// Setup console writer based on config
+	var writer io.Writer = io.Discard
 	if cfg.EnableConsole {
-		var writer io.Writer
 		if cfg.ConsoleTarget == "stderr" {
 			writer = os.Stderr
 		} else {
 			writer = os.Stdout
 		}
-		l.state.StdoutWriter.Store(&sink{w: writer})
-	} else {
-		l.state.StdoutWriter.Store(&sink{w: io.Discard})
 	}
 
+	var fileOut *fileSink
+	if cfg.fileOutput() {
+		fileOut = &fileSink{l: l, cfg: cfg, special: isSpecialFile(logFilePath(cfg))}
+	}
+
+	l.configureRecent(cfg)
+
+	// Shard and error file loggers are configured before the commit so a failure leaves the old configuration in place
+	errorFile, retiredErrorFile, undoErrorFile, err := l.configureErrorFile(cfg)
+	if err != nil {
+		return rollback(err)
+	}
+	undo = append(undo, undoErrorFile)
+	shards, retiredShards, err := l.configureShards(cfg)
+	if err != nil {
+		return rollback(err)
+	}
+	retiredMirror := l.configureMirror(cfg)
+
+	// The uploader and exec hook start their goroutines, they are set up once nothing else can fail
+	uploader := configureS3Upload(cfg, oldEpoch.s3)
+	execOut := configureExecHook(cfg, oldEpoch.exec)
+
+	// Commit: wait for the in-flight batch, then publish everything before the next batch starts
+	var retiredFile *os.File
+	l.batchMu.Lock()
+	l.epoch.Store(&configEpoch{
+		seq:                oldEpoch.seq + 1,
+		config:             cfg,
+		formatter:          newFormatter,
+		consoleFormatter:   consoleFormatter,
+		heartbeatFormatter: heartbeatFormatter,
+		levelRoutes:        levelRoutes,
+		enrichment:         enrichment(cfg),
+		syslog:             syslogOut,
+		journald:           journaldOut,
+		gelf:               gelfOut,
+		s3:                 uploader,
+		exec:               execOut,
+		console:            consoleOut,
+		file:               fileOut,
+	})
+	l.errorFile.Store(errorFile)
+	l.shards.Store(shards)
+	if uploader != nil && oldEpoch.s3 == nil && !cfg.S3KeepLocal {
+		// Archives still local were not uploaded by a previous run, queued before any rotation can add one
+		if archives, err := l.listArchives(cfg.Directory); err == nil {
+			uploader.enqueueLeftover(archives)
+		}
+	}
+	if !cfg.fileOutput() {
+		// When disabling file output or delegating it to shards, retire the current file
+		retiredFile = currentFile
+		l.state.CurrentFile.Store((*os.File)(nil))
+		l.state.CurrentSize.Store(0)
+	} else if newFile != nil {
+		// Retire old file if transitioning from one file to another
+		if currentFile != newFile {
+			retiredFile = currentFile
+		}
+		l.activateLogFile(cfg, newFile)
+	}
+	if oldCfg.Directory != cfg.Directory || oldCfg.Name != cfg.Name {
+		// Generations continue from the archives of the new location on the next rotation
+		l.state.RotationGeneration.Store(0)
+	}
+	l.state.StdoutWriter.Store(&sink{w: writer})
+	l.batchMu.Unlock()
+
+	// No batch can reference the retired file or connections after the commit, close them outside the lock
+	// Shard processors write to these outside batchMu, networkMu keeps them off a retired connection
+	l.networkMu.Lock()
+	if oldEpoch.syslog != nil && oldEpoch.syslog != syslogOut {
+		oldEpoch.syslog.close()
+	}
+	if oldEpoch.journald != nil && oldEpoch.journald != journaldOut {
+		oldEpoch.journald.close()
+	}
+	if oldEpoch.gelf != nil && oldEpoch.gelf != gelfOut {
+		oldEpoch.gelf.close()
+	}
+	l.networkMu.Unlock()
+	if oldEpoch.exec != nil && oldEpoch.exec != execOut {
+		oldEpoch.exec.close()
+	}
+	if oldEpoch.s3 != nil && oldEpoch.s3 != uploader {
+		// Archives still queued move to the new uploader, or stay local when upload is disabled
+		for _, job := range oldEpoch.s3.close() {
+			if uploader != nil {
+				uploader.enqueue(job.path, job.rotated)
+			}
+		}
+	}
+	if retiredFile != nil {
+		if err := l.closeGzipStream(retiredFile); err != nil {
+			l.internalLog("warning - %v\n", err)
+		}
+		_ = retiredFile.Sync()
+		if err := retiredFile.Close(); err != nil {
+			l.internalLog("warning - failed to close old log file: %v\n", err)
+		}
+	}
+	if err := shutdownShards(retiredShards); err != nil {
+		l.internalLog("warning - failed to shut down retired shards: %v\n", err)
+	}
+	if retiredErrorFile != nil {
+		if err := retiredErrorFile.Shutdown(); err != nil {
+			l.internalLog("warning - failed to shut down retired error file: %v\n", err)
+		}
+	}
+	if retiredMirror != nil {
+		if err := retiredMirror.Shutdown(); err != nil {
+			l.internalLog("warning - failed to shut down retired mirror: %v\n", err)
+		}
+	}
+
+	l.configureExitHook(cfg)
+
 	// Mark as initialized
 	l.state.IsInitialized.Store(true)
 	l.state.ShutdownCalled.Store(false)
 	l.state.DiskFullLogged.Store(false)
 	l.state.DiskStatusOK.Store(true)
+	l.state.CallLatencyDegraded.Store(false)
 
 	// Restart processor if it was running and needs restart
 	if needsRestart {
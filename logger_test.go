@@ -1,6 +1,8 @@
 package log
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -58,6 +60,42 @@ func TestApplyConfig(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// TestApplyConfigRollback verifies a failing error file or shard leaves the old configuration running
+func TestApplyConfigRollback(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("split_error_file=true"))
+	errorFile := logger.getErrorFile()
+	require.NotNil(t, errorFile)
+
+	// The renamed error file cannot be opened, the current one keeps its configuration
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, "app_error.log"), 0755))
+	assert.Error(t, logger.ApplyConfigString("name=app"))
+	assert.Same(t, errorFile, logger.getErrorFile())
+	assert.Equal(t, "log_error", errorFile.GetConfig().Name)
+
+	// The added shards cannot be opened after the error file was prepared, both are undone
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, "app_error.log")))
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, "app_1.log"), 0755))
+	assert.Error(t, logger.ApplyConfigString("name=app", "shards=2", "flush_interval_ms=20"))
+	assert.Same(t, errorFile, logger.getErrorFile())
+	assert.Equal(t, "log_error", errorFile.GetConfig().Name)
+	assert.Nil(t, logger.getShards())
+	assert.Equal(t, "log", logger.GetConfig().Name)
+
+	// The processor stopped for the restart runs again under the old configuration
+	assert.True(t, logger.state.Started.Load())
+	logger.Warn("after rollback")
+	require.NoError(t, logger.Flush(time.Second))
+	require.NoError(t, errorFile.Flush(time.Second))
+	for _, name := range []string{"log.log", "log_error.log"} {
+		content, err := os.ReadFile(filepath.Join(tmpDir, name))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "after rollback", name)
+	}
+}
+
 // TestApplyConfigString tests applying configuration overrides from key-value strings
 func TestApplyConfigString(t *testing.T) {
 	logger, _ := createTestLogger(t)
@@ -191,6 +229,140 @@ func TestLoggerWithTrace(t *testing.T) {
 	// Just verify it doesn't panic - trace content varies by runtime
 }
 
+// TestLoggerContext verifies loggers carried by a context keep their bound fields and a missing logger discards
+func TestLoggerContext(t *testing.T) {
+	logger, dir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	ctx := NewContext(context.Background(), logger.With("request_id", "r-1"))
+	FromContext(ctx).Info("from context")
+	FromContext(context.Background()).Info("discarded")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "from context request_id r-1")
+	assert.NotContains(t, string(content), "discarded")
+	assert.NotNil(t, FromContext(context.Background()))
+}
+
+// TestLoggerContextFields verifies the *Context methods add trace IDs and context fields after the message and
+// bound fields
+func TestLoggerContextFields(t *testing.T) {
+	logger, dir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	assert.Nil(t, ContextFields(context.Background()))
+	ctx := WithTraceContext(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736", "")
+	ctx = WithContextFields(ctx, "tenant", "acme")
+	ctx = WithContextFields(ctx, "region", "eu")
+	assert.Equal(t, []any{"trace_id", "4bf92f3577b34da6a3ce929d0e0e4736", "tenant", "acme", "region", "eu"}, ContextFields(ctx))
+
+	logger.InfoContext(ctx, "order placed", "order_id", 42)
+	logger.With("tenant", "bound").WarnContext(ctx, "bound wins")
+	logger.InfoContext(context.Background(), "no context fields")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	output := string(content)
+	assert.Contains(t, output, "order placed trace_id 4bf92f3577b34da6a3ce929d0e0e4736 tenant acme region eu order_id 42")
+	assert.Contains(t, output, "bound wins tenant bound trace_id 4bf92f3577b34da6a3ce929d0e0e4736 region eu")
+	assert.Contains(t, output, "no context fields")
+	assert.Equal(t, 2, strings.Count(output, "trace_id"), "Only records given a trace context carry its ID")
+}
+
+// TestLoggerLabels verifies labels accumulate on derived loggers, render apart from fields, and route levels
+func TestLoggerLabels(t *testing.T) {
+	logger, dir := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString("format=json", "level_overrides_by_field=team=payments:debug"))
+
+	api := logger.WithLabels(map[string]string{"service": "api", "team": "core"})
+	payments := api.With("order", 7).WithLabels(map[string]string{"team": "payments", "bad-name": "x"})
+	assert.Equal(t, map[string]string{"service": "api", "team": "payments"}, payments.Labels(), "Invalid names are skipped")
+	assert.Equal(t, map[string]string{"service": "api", "team": "core"}, api.Labels(), "Parent labels are unchanged")
+
+	api.Info("served")
+	api.Debug("core debug")
+	payments.Debug("charged")
+	logger.Ingest(Record{Level: LevelInfo, Args: []any{"ingested"}, Labels: map[string]string{"origin": "relay"}})
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	output := string(content)
+	assert.Contains(t, output, `"labels":{"service":"api","team":"core"},"fields":["served"]`)
+	assert.NotContains(t, output, "core debug")
+	assert.Contains(t, output, `"labels":{"service":"api","team":"payments"},"fields":["charged","order",7]`)
+	assert.Contains(t, output, `"labels":{"origin":"relay"},"fields":["ingested"]`)
+}
+
+// TestLoggerGroups verifies WithGroup nests later bound and per-call keys in JSON and qualifies them in text
+func TestLoggerGroups(t *testing.T) {
+	logger, dir := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString("format=json", "auto_kv=true"))
+
+	assert.Same(t, logger, logger.WithGroup(""))
+	reqLog := logger.With("request_id", "r-1").WithGroup("http").With("method", "GET")
+	reqLog.Info("status", 200)
+	reqLog.WithGroup("client").Info("ip", "10.0.0.1")
+	reqLog.LogStructured(LevelInfo, "done", map[string]any{"bytes": 512})
+	logger.Info("plain.key", 1)
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	output := string(content)
+	assert.Contains(t, output, `"request_id":"r-1","http":{"method":"GET","status":200}}`)
+	assert.Contains(t, output, `"http":{"method":"GET","client":{"ip":"10.0.0.1"}}}`)
+	assert.Contains(t, output, `"fields":{"http":{"bytes":512},"http.method":"GET","request_id":"r-1"}`)
+	assert.Contains(t, output, `"plain.key":1`, "Ungrouped records keep dotted keys flat")
+
+	require.NoError(t, logger.ApplyConfigString("format=txt"))
+	reqLog.Warn("status", 503)
+	require.NoError(t, logger.Flush(time.Second))
+	content, err = os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "request_id r-1 http.method GET http.status 503")
+}
+
+// TestLoggerEnabled verifies Enabled follows the level and the lowest field-based override
+func TestLoggerEnabled(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+
+	assert.False(t, logger.Enabled(LevelDebug))
+	assert.True(t, logger.Enabled(LevelInfo))
+
+	require.NoError(t, logger.ApplyConfigString("level=warn", "level_overrides_by_field=tenant=acme:debug"))
+	assert.True(t, logger.Enabled(LevelDebug), "An override could admit debug records")
+	assert.True(t, logger.Enabled(LevelError))
+}
+
+// TestLoggerContextCancelled verifies records with a cancelled context are skipped and counted
+func TestLoggerContextCancelled(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	logger.InfoContext(ctx, "live request")
+
+	cancel()
+	logger.InfoContext(ctx, "aborted info")
+	logger.ErrorContext(ctx, "aborted error")
+	logger.DebugContext(ctx, "below level") // Filtered by level, not counted
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "live request")
+	assert.NotContains(t, string(content), "aborted")
+
+	assert.Equal(t, uint64(2), logger.Stats().SuppressedCancelled)
+}
+
 // TestLoggerFormats verifies that the logger produces the correct output for different formats
 func TestLoggerFormats(t *testing.T) {
 	tests := []struct {
@@ -307,6 +479,8 @@ func TestLoggerWrite(t *testing.T) {
 	defer logger.Shutdown()
 
 	logger.Write("raw", "output", 123)
+	var nilErr *os.PathError
+	logger.Write(" nil", fmt.Stringer((*mutableStringer)(nil)), error(nilErr))
 
 	logger.Flush(time.Second)
 
@@ -317,5 +491,35 @@ func TestLoggerWrite(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Contains(t, string(content), "raw output 123")
-	assert.True(t, strings.HasSuffix(string(content), "raw output 123"))
+	assert.True(t, strings.HasSuffix(string(content), "raw output 123 nil nil nil"), "Typed nil values are not formatted")
+}
+
+// mutableStringer is a Stringer whose output changes after logging
+type mutableStringer struct{ val string }
+
+func (m *mutableStringer) String() string { return m.val }
+
+// TestEagerStringify verifies that Stringer values are snapshotted at call time when enabled
+func TestEagerStringify(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	err := logger.ApplyConfigString("eager_stringify=true", "format=txt")
+	require.NoError(t, err)
+
+	obj := &mutableStringer{val: "before"}
+	args := []any{"state", obj}
+	logger.Info(args...)
+	obj.val = "after"
+
+	// Caller's slice must not be modified
+	assert.Same(t, obj, args[1])
+
+	require.NoError(t, logger.Flush(time.Second))
+	time.Sleep(50 * time.Millisecond)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "state before")
+	assert.NotContains(t, string(content), "after")
 }
\ No newline at end of file
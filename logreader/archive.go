@@ -0,0 +1,76 @@
+package logreader
+
+import (
+	"cmp"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// generationDigits is the minimum width of the zero-padded generation in archive names
+const generationDigits = 8
+
+// ArchiveInfo is the rotation order encoded in the name of a log archive
+type ArchiveInfo struct {
+	Generation uint64    // Rotation generation, 0 for archives named before generations were recorded
+	Time       time.Time // Rotation time from the name, in the writer's local clock
+}
+
+// ParseArchiveName parses the name of an archive of the log named name, {name}_{generation}_{YYMMDD}_{HHMMSS}_{nano}
+// with an optional extension, or {name}_{YYMMDD}_{HHMMSS}_{nano} as written by earlier versions
+// Returns false if file is not such an archive; directory components of file are ignored
+func ParseArchiveName(file, name string) (ArchiveInfo, bool) {
+	base := filepath.Base(file)
+	suffix, ok := strings.CutPrefix(base, name+"_")
+	if !ok {
+		return ArchiveInfo{}, false
+	}
+	suffix, _, _ = strings.Cut(suffix, ".")
+	parts := strings.Split(suffix, "_")
+
+	var info ArchiveInfo
+	if len(parts) == 4 && len(parts[0]) >= generationDigits {
+		generation, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return ArchiveInfo{}, false
+		}
+		info.Generation = generation
+		parts = parts[1:]
+	}
+	if len(parts) != 3 {
+		return ArchiveInfo{}, false
+	}
+	ts, err := time.ParseInLocation("060102_150405", parts[0]+"_"+parts[1], time.Local)
+	if err != nil {
+		return ArchiveInfo{}, false
+	}
+	nano, err := strconv.Atoi(parts[2])
+	if err != nil || nano < 0 || nano >= int(time.Second) {
+		return ArchiveInfo{}, false
+	}
+	info.Time = ts.Add(time.Duration(nano))
+	return info, true
+}
+
+// SortArchives orders archive paths of the log named name from oldest to newest by rotation generation, which
+// holds across clock adjustments; archives without a generation come first in name time order
+// Paths that are not archives of name are placed last in their original order
+func SortArchives(paths []string, name string) {
+	slices.SortStableFunc(paths, func(a, b string) int {
+		infoA, okA := ParseArchiveName(a, name)
+		infoB, okB := ParseArchiveName(b, name)
+		switch {
+		case okA != okB:
+			if okA {
+				return -1
+			}
+			return 1
+		case !okA:
+			return 0
+		default:
+			return cmp.Or(cmp.Compare(infoA.Generation, infoB.Generation), infoA.Time.Compare(infoB.Time))
+		}
+	})
+}
\ No newline at end of file
@@ -0,0 +1,43 @@
+package logreader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseArchiveName(t *testing.T) {
+	info, ok := ParseArchiveName("/var/log/app_00000042_240115_103000_5.log.gz", "app")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(42), info.Generation)
+	assert.Equal(t, time.Date(2024, 1, 15, 10, 30, 0, 5, time.Local), info.Time)
+
+	info, ok = ParseArchiveName("app_240115_103000_5", "app")
+	assert.True(t, ok, "Archives of earlier versions have no generation")
+	assert.Zero(t, info.Generation)
+
+	for _, name := range []string{"app.log", "app_0.log", "other_00000001_240115_103000_5.log", "app_0_240115_103000_5.log"} {
+		_, ok = ParseArchiveName(name, "app")
+		assert.False(t, ok, name)
+	}
+}
+
+func TestSortArchives(t *testing.T) {
+	// Generation 2 was written after the clock stepped back an hour
+	paths := []string{
+		"app_00000002_240115_093000_0.log",
+		"notes.txt",
+		"app_00000010_240115_120000_0.log",
+		"app_00000001_240115_100000_0.log",
+		"app_240114_230000_0.log",
+	}
+	SortArchives(paths, "app")
+	assert.Equal(t, []string{
+		"app_240114_230000_0.log",
+		"app_00000001_240115_100000_0.log",
+		"app_00000002_240115_093000_0.log",
+		"app_00000010_240115_120000_0.log",
+		"notes.txt",
+	}, paths)
+}
\ No newline at end of file
@@ -0,0 +1,225 @@
+// Package logreader provides readers for log files produced by lixenwraith/log
+package logreader
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+)
+
+// maxBinaryRecordSize bounds a single record to detect corrupt length prefixes
+const maxBinaryRecordSize = 64 << 20
+
+// ErrCorrupt is returned when a binary record cannot be decoded
+var ErrCorrupt = errors.New("logreader: corrupt binary record")
+
+// Record is a decoded log record
+type Record struct {
+	Time  time.Time
+	Level int64
+	Flags int64
+	Trace string
+	Args  []any
+}
+
+// BinaryReader reads records written in the binary journal format
+type BinaryReader struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+// NewBinaryReader creates a reader for a binary journal stream
+func NewBinaryReader(r io.Reader) *BinaryReader {
+	return &BinaryReader{
+		r:   bufio.NewReader(r),
+		buf: make([]byte, 0, 1024),
+	}
+}
+
+// Next decodes the next record
+// Returns io.EOF at a clean end of stream and io.ErrUnexpectedEOF for a truncated record
+func (br *BinaryReader) Next() (Record, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(br.r, lenBuf[:]); err != nil {
+		return Record{}, err
+	}
+	size := binary.LittleEndian.Uint32(lenBuf[:])
+	if size < formatter.BinaryHeaderSize+2 || size > maxBinaryRecordSize {
+		return Record{}, fmt.Errorf("%w: invalid record length %d", ErrCorrupt, size)
+	}
+
+	if cap(br.buf) < int(size) {
+		br.buf = make([]byte, size)
+	}
+	data := br.buf[:size]
+	if _, err := io.ReadFull(br.r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return Record{}, err
+	}
+
+	return decodeBinaryRecord(data)
+}
+
+// ReadAll decodes all remaining records
+func (br *BinaryReader) ReadAll() ([]Record, error) {
+	var records []Record
+	for {
+		rec, err := br.Next()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// decodeBinaryRecord decodes a record body following the length prefix
+func decodeBinaryRecord(data []byte) (Record, error) {
+	d := decoder{data: data}
+
+	rec := Record{
+		Time:  time.Unix(0, int64(d.uint64())),
+		Level: int64(int16(d.uint16())),
+		Flags: int64(d.byte()),
+	}
+	rec.Trace = string(d.bytes())
+
+	argc := d.uvarint()
+	if d.err == nil && argc > uint64(len(d.data)) {
+		d.fail("argument count %d exceeds record size", argc)
+	}
+	if d.err == nil && argc > 0 {
+		rec.Args = make([]any, 0, argc)
+	}
+	for i := uint64(0); i < argc && d.err == nil; i++ {
+		rec.Args = append(rec.Args, d.value())
+	}
+
+	if d.err != nil {
+		return Record{}, d.err
+	}
+	if d.pos != len(d.data) {
+		return Record{}, fmt.Errorf("%w: %d trailing bytes", ErrCorrupt, len(d.data)-d.pos)
+	}
+	return rec, nil
+}
+
+// decoder reads fields from a record body, recording the first error
+type decoder struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (d *decoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: "+format, append([]any{ErrCorrupt}, args...)...)
+	}
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.pos+n > len(d.data) {
+		d.fail("unexpected end of record at offset %d", d.pos)
+		return nil
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+func (d *decoder) byte() byte {
+	if b := d.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *decoder) uint16() uint16 {
+	if b := d.take(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (d *decoder) uint64() uint64 {
+	if b := d.take(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		d.fail("invalid uvarint at offset %d", d.pos)
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data[d.pos:])
+	if n <= 0 {
+		d.fail("invalid varint at offset %d", d.pos)
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+func (d *decoder) bytes() []byte {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		d.fail("invalid length %d at offset %d", n, d.pos)
+		return nil
+	}
+	return d.take(int(n))
+}
+
+// value decodes a tagged argument
+func (d *decoder) value() any {
+	switch tag := d.byte(); tag {
+	case formatter.BinaryTagNil:
+		return nil
+	case formatter.BinaryTagString:
+		return string(d.bytes())
+	case formatter.BinaryTagInt:
+		return d.varint()
+	case formatter.BinaryTagUint:
+		return d.uvarint()
+	case formatter.BinaryTagFloat:
+		return math.Float64frombits(d.uint64())
+	case formatter.BinaryTagBool:
+		return d.byte() != 0
+	case formatter.BinaryTagBytes:
+		b := d.bytes()
+		out := make([]byte, len(b))
+		copy(out, b)
+		return out
+	case formatter.BinaryTagTime:
+		return time.Unix(0, int64(d.uint64()))
+	default:
+		d.fail("unknown value tag %d", tag)
+		return nil
+	}
+}
\ No newline at end of file
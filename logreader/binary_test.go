@@ -0,0 +1,99 @@
+package logreader
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeRecords writes records using the binary formatter
+func encodeRecords(t *testing.T, timestamp time.Time, records ...[]any) []byte {
+	t.Helper()
+	f := formatter.New().Type("binary")
+	var buf bytes.Buffer
+	for i, args := range records {
+		buf.Write(f.Format(formatter.FlagDefault, timestamp.Add(time.Duration(i)), int64(i*4), "main -> run", args))
+	}
+	return buf.Bytes()
+}
+
+// TestBinaryRoundTrip verifies that all supported types survive encoding and decoding
+func TestBinaryRoundTrip(t *testing.T) {
+	timestamp := time.Date(2024, 1, 1, 12, 0, 0, 123, time.UTC)
+	data := encodeRecords(t, timestamp,
+		[]any{"msg", "hello", "count", 42, "neg", int64(-7), "big", uint64(1 << 63)},
+		[]any{"ratio", 0.5, "ok", true, "nothing", nil, "payload", []byte{0, 1, 255}, "at", timestamp},
+		[]any{"err", errors.New("boom"), "complex", map[string]int{"a": 1}},
+	)
+
+	records, err := NewBinaryReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	assert.True(t, timestamp.Equal(records[0].Time))
+	assert.Equal(t, int64(0), records[0].Level)
+	assert.Equal(t, int64(4), records[1].Level)
+	assert.Equal(t, formatter.FlagDefault, records[0].Flags)
+	assert.Equal(t, "main -> run", records[0].Trace)
+
+	assert.Equal(t, []any{"msg", "hello", "count", int64(42), "neg", int64(-7), "big", uint64(1 << 63)}, records[0].Args)
+	assert.Equal(t, 0.5, records[1].Args[1])
+	assert.Equal(t, true, records[1].Args[3])
+	assert.Nil(t, records[1].Args[5])
+	assert.Equal(t, []byte{0, 1, 255}, records[1].Args[7])
+	assert.True(t, timestamp.Equal(records[1].Args[9].(time.Time)))
+	assert.Equal(t, []any{"err", "boom", "complex", "map[a:1]"}, records[2].Args)
+}
+
+// TestBinaryReaderErrors verifies truncated and corrupt streams are reported
+func TestBinaryReaderErrors(t *testing.T) {
+	data := encodeRecords(t, time.Now(), []any{"msg", "hello"})
+
+	t.Run("truncated", func(t *testing.T) {
+		_, err := NewBinaryReader(bytes.NewReader(data[:len(data)-2])).Next()
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		_, err := NewBinaryReader(bytes.NewReader([]byte{1, 0, 0, 0, 0})).Next()
+		assert.ErrorIs(t, err, ErrCorrupt)
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		corrupt := append([]byte(nil), data...)
+		corrupt[len(corrupt)-7] = 0xEE // Tag of the last argument
+		_, err := NewBinaryReader(bytes.NewReader(corrupt)).Next()
+		assert.ErrorIs(t, err, ErrCorrupt)
+	})
+
+	t.Run("clean end", func(t *testing.T) {
+		_, err := NewBinaryReader(bytes.NewReader(nil)).Next()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+}
+
+// TestConvert verifies binary records are re-rendered in text formats
+func TestConvert(t *testing.T) {
+	timestamp := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	data := encodeRecords(t, timestamp, []any{"msg", "hello"}, []any{"msg", "world"})
+
+	var out bytes.Buffer
+	n, err := Convert(&out, bytes.NewReader(data), "json", time.RFC3339)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"time":"2024-01-01T12:00:00Z","level":"INFO","trace":"main -> run","fields":["msg","hello"]}`, lines[0])
+	assert.Contains(t, lines[1], `"level":"WARN"`)
+
+	_, err = Convert(&out, bytes.NewReader(data), "binary", time.RFC3339)
+	assert.Error(t, err)
+}
\ No newline at end of file
@@ -0,0 +1,55 @@
+package logreader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lixenwraith/log/formatter"
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// Convert re-renders a binary journal stream in a text format ("txt", "json", "gelf", or "raw")
+// Returns the number of records converted
+func Convert(dst io.Writer, src io.Reader, format string, timestampFormat string) (int, error) {
+	return convertRecords(dst, NewBinaryReader(src).Next, format, timestampFormat)
+}
+
+// MergeConvert merges binary journal streams, such as shard files, in timestamp order and re-renders them in a
+// text format like Convert
+// Returns the number of records converted
+func MergeConvert(dst io.Writer, format string, timestampFormat string, srcs ...io.Reader) (int, error) {
+	return convertRecords(dst, NewMergeReader(srcs...).Next, format, timestampFormat)
+}
+
+// convertRecords renders the records returned by next until io.EOF
+func convertRecords(dst io.Writer, next func() (Record, error), format string, timestampFormat string) (int, error) {
+	switch format {
+	case "txt", "json", "gelf", "raw":
+	default:
+		return 0, fmt.Errorf("logreader: unsupported conversion format '%s' (use txt, json, gelf, or raw)", format)
+	}
+
+	policy := sanitizer.PolicyRaw
+	if format == "txt" {
+		policy = sanitizer.PolicyTxt
+	}
+	f := formatter.New(sanitizer.New().Policy(policy)).Type(format).TimestampFormat(timestampFormat)
+
+	count := 0
+	for {
+		rec, err := next()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("logreader: record %d: %w", count+1, err)
+		}
+
+		data := f.FormatWithOptions(format, rec.Flags, rec.Time, rec.Level, rec.Trace, rec.Args)
+		if _, err := dst.Write(data); err != nil {
+			return count, fmt.Errorf("logreader: failed to write record %d: %w", count+1, err)
+		}
+		count++
+	}
+}
\ No newline at end of file
@@ -0,0 +1,44 @@
+package logreader
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// gzipMagic starts every gzip member
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Decompress returns a reader of the uncompressed content of r when r is gzip-compressed, such as a log file
+// written with gzip_active, and a reader of r itself otherwise
+// The active file ends in an unfinished gzip member; its data up to the last flush point is returned, then io.EOF
+func Decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil || magic[0] != gzipMagic[0] || magic[1] != gzipMagic[1] {
+		// Too short to be compressed, or plain content
+		return br, nil
+	}
+
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("logreader: invalid gzip stream: %w", err)
+	}
+	return &activeGzipReader{zr: zr}, nil
+}
+
+// activeGzipReader ends a stream whose last gzip member is still being written at the data read so far
+type activeGzipReader struct {
+	zr *gzip.Reader
+}
+
+// Read reads decompressed data, reporting a truncated final member as the end of the stream
+func (r *activeGzipReader) Read(p []byte) (int, error) {
+	n, err := r.zr.Read(p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return n, io.EOF
+	}
+	return n, err
+}
\ No newline at end of file
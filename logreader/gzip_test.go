@@ -0,0 +1,35 @@
+package logreader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecompress(t *testing.T) {
+	plain, err := Decompress(strings.NewReader("plain line\n"))
+	require.NoError(t, err)
+	data, err := io.ReadAll(plain)
+	require.NoError(t, err)
+	assert.Equal(t, "plain line\n", string(data))
+
+	// A completed member followed by one still being written, as in an active file after a reopen
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte("first member\n"))
+	require.NoError(t, zw.Close())
+	zw = gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte("flushed\n"))
+	require.NoError(t, zw.Flush())
+
+	r, err := Decompress(&buf)
+	require.NoError(t, err)
+	data, err = io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "first member\nflushed\n", string(data))
+}
\ No newline at end of file
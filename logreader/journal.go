@@ -0,0 +1,40 @@
+package logreader
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// RotationEvent is one entry of a rotation journal ({name}.rotations), written when rotation_journal is enabled
+type RotationEvent struct {
+	Time       time.Time     `json:"time"`        // Rotation start
+	Trigger    string        `json:"trigger"`     // Reason for the rotation, e.g. "size"
+	File       string        `json:"file"`        // Active file name that was rotated
+	Archive    string        `json:"archive"`     // Name the file was archived under
+	Generation uint64        `json:"generation"`  // Rotation generation in the archive name, 0 for earlier versions
+	Size       int64         `json:"size"`        // Archived file size in bytes
+	Duration   time.Duration `json:"duration_ns"` // Time spent renaming and reopening
+}
+
+// ReadRotationJournal reads all events of a rotation journal in the order they were recorded
+func ReadRotationJournal(r io.Reader) ([]RotationEvent, error) {
+	var events []RotationEvent
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event RotationEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return events, fmt.Errorf("logreader: rotation journal line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return events, fmt.Errorf("logreader: failed to read rotation journal: %w", err)
+	}
+	return events, nil
+}
\ No newline at end of file
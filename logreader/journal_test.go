@@ -0,0 +1,29 @@
+package logreader
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRotationJournal(t *testing.T) {
+	journal := `{"time":"2024-01-15T10:30:00Z","trigger":"size","file":"app.log","archive":"app_240115_103000_0.log","size":1024000,"duration_ns":350000}
+
+{"time":"2024-01-15T11:02:00Z","trigger":"size","file":"app.log","archive":"app_240115_110200_0.log","size":1024100,"duration_ns":410000}
+`
+	events, err := ReadRotationJournal(strings.NewReader(journal))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), events[0].Time)
+	assert.Equal(t, "app_240115_103000_0.log", events[0].Archive)
+	assert.Equal(t, int64(1024000), events[0].Size)
+	assert.Equal(t, 410*time.Microsecond, events[1].Duration)
+
+	events, err = ReadRotationJournal(strings.NewReader(journal + "{truncated\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 4")
+	assert.Len(t, events, 2, "Events before the corrupt line are returned")
+}
\ No newline at end of file
@@ -0,0 +1,162 @@
+package logreader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MergeReader reads several binary journal streams, such as shard files, as one stream in timestamp order
+// Records with equal timestamps are returned in source order
+type MergeReader struct {
+	readers []*BinaryReader
+	heads   []Record
+	valid   []bool
+	started bool
+}
+
+// NewMergeReader creates a merging reader over binary journal streams
+func NewMergeReader(srcs ...io.Reader) *MergeReader {
+	m := &MergeReader{
+		readers: make([]*BinaryReader, len(srcs)),
+		heads:   make([]Record, len(srcs)),
+		valid:   make([]bool, len(srcs)),
+	}
+	for i, src := range srcs {
+		m.readers[i] = NewBinaryReader(src)
+	}
+	return m
+}
+
+// Next returns the earliest pending record across all sources, io.EOF once every source is exhausted
+func (m *MergeReader) Next() (Record, error) {
+	if !m.started {
+		m.started = true
+		for i := range m.readers {
+			if err := m.advance(i); err != nil {
+				return Record{}, err
+			}
+		}
+	}
+
+	earliest := -1
+	for i, ok := range m.valid {
+		if ok && (earliest < 0 || m.heads[i].Time.Before(m.heads[earliest].Time)) {
+			earliest = i
+		}
+	}
+	if earliest < 0 {
+		return Record{}, io.EOF
+	}
+
+	rec := m.heads[earliest]
+	if err := m.advance(earliest); err != nil {
+		return Record{}, err
+	}
+	return rec, nil
+}
+
+// advance loads the next record of source i
+func (m *MergeReader) advance(i int) error {
+	rec, err := m.readers[i].Next()
+	if errors.Is(err, io.EOF) {
+		m.valid[i] = false
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("logreader: source %d: %w", i, err)
+	}
+	// Decoded args are copied out of the reader buffer, the record is safe to retain
+	m.heads[i] = rec
+	m.valid[i] = true
+	return nil
+}
+
+// TimeFunc extracts a record timestamp from a text line, returning false if the line carries none
+type TimeFunc func(line []byte) (time.Time, bool)
+
+// JSONTime extracts the "time" field of a JSON formatted line
+func JSONTime(line []byte) (time.Time, bool) {
+	var entry struct {
+		Time time.Time `json:"time"`
+	}
+	if err := json.Unmarshal(line, &entry); err != nil || entry.Time.IsZero() {
+		return time.Time{}, false
+	}
+	return entry.Time, true
+}
+
+// TextTime returns a TimeFunc parsing the first space-separated token of a txt or raw line with layout
+func TextTime(layout string) TimeFunc {
+	return func(line []byte) (time.Time, bool) {
+		token, _, _ := bytes.Cut(line, []byte{' '})
+		t, err := time.Parse(layout, string(token))
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t, true
+	}
+}
+
+// mergeSource is a line-oriented source with its current line and timestamp
+type mergeSource struct {
+	scanner *bufio.Scanner
+	line    []byte
+	time    time.Time
+	valid   bool
+}
+
+// MergeLines merges line-oriented log streams (txt, json, or raw) into dst in timestamp order
+// Lines without a timestamp inherit the previous line's time of the same source, keeping multi-line entries together
+// Returns the number of lines written
+func MergeLines(dst io.Writer, timeOf TimeFunc, srcs ...io.Reader) (int, error) {
+	sources := make([]*mergeSource, len(srcs))
+	for i, src := range srcs {
+		scanner := bufio.NewScanner(src)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxBinaryRecordSize)
+		sources[i] = &mergeSource{scanner: scanner}
+		if err := sources[i].advance(timeOf); err != nil {
+			return 0, fmt.Errorf("logreader: source %d: %w", i, err)
+		}
+	}
+
+	count := 0
+	for {
+		var earliest *mergeSource
+		for _, s := range sources {
+			if s.valid && (earliest == nil || s.time.Before(earliest.time)) {
+				earliest = s
+			}
+		}
+		if earliest == nil {
+			return count, nil
+		}
+
+		if _, err := dst.Write(append(earliest.line, '\n')); err != nil {
+			return count, fmt.Errorf("logreader: failed to write line %d: %w", count+1, err)
+		}
+		count++
+
+		if err := earliest.advance(timeOf); err != nil {
+			return count, fmt.Errorf("logreader: %w", err)
+		}
+	}
+}
+
+// advance reads the next line, keeping the previous timestamp if the line has none
+func (s *mergeSource) advance(timeOf TimeFunc) error {
+	if !s.scanner.Scan() {
+		s.valid = false
+		return s.scanner.Err()
+	}
+	s.line = append(s.line[:0], s.scanner.Bytes()...)
+	if t, ok := timeOf(s.line); ok {
+		s.time = t
+	}
+	s.valid = true
+	return nil
+}
\ No newline at end of file
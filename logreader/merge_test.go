@@ -0,0 +1,80 @@
+package logreader
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMergeReader verifies that binary shard streams are merged in timestamp order
+func TestMergeReader(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := formatter.New().Type("binary")
+
+	var shard0, shard1 bytes.Buffer
+	for i := 0; i < 6; i++ {
+		data := f.Format(formatter.FlagDefault, base.Add(time.Duration(i)*time.Millisecond), 0, "", []any{i})
+		if i%2 == 0 {
+			shard0.Write(data)
+		} else {
+			shard1.Write(data)
+		}
+	}
+
+	merged := NewMergeReader(&shard1, &shard0)
+	for i := 0; i < 6; i++ {
+		rec, err := merged.Next()
+		require.NoError(t, err)
+		assert.Equal(t, []any{int64(i)}, rec.Args)
+	}
+	_, err := merged.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+// TestMergeLines verifies chronological merging of text and JSON shard files
+func TestMergeLines(t *testing.T) {
+	shard0 := "2024-01-01T12:00:00.001Z INFO a\n2024-01-01T12:00:00.003Z INFO c\ncontinuation of c\n"
+	shard1 := "2024-01-01T12:00:00.002Z INFO b\n2024-01-01T12:00:00.004Z INFO d\n"
+
+	var out bytes.Buffer
+	n, err := MergeLines(&out, TextTime(time.RFC3339Nano), strings.NewReader(shard0), strings.NewReader(shard1))
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, "2024-01-01T12:00:00.001Z INFO a\n2024-01-01T12:00:00.002Z INFO b\n"+
+		"2024-01-01T12:00:00.003Z INFO c\ncontinuation of c\n2024-01-01T12:00:00.004Z INFO d\n", out.String())
+
+	jsonShard0 := `{"time":"2024-01-01T12:00:00.002Z","level":"INFO","fields":["b"]}` + "\n"
+	jsonShard1 := `{"time":"2024-01-01T12:00:00.001Z","level":"INFO","fields":["a"]}` + "\n"
+	out.Reset()
+	_, err = MergeLines(&out, JSONTime, strings.NewReader(jsonShard0), strings.NewReader(jsonShard1))
+	require.NoError(t, err)
+	assert.Equal(t, jsonShard1+jsonShard0, out.String())
+}
+
+// TestMergeConvert verifies binary shard streams are merged in timestamp order and rendered as text
+func TestMergeConvert(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := formatter.New().Type("binary")
+
+	var shard0, shard1 bytes.Buffer
+	shard0.Write(f.Format(formatter.FlagDefault, base.Add(2*time.Millisecond), 0, "", []any{"c"}))
+	shard1.Write(f.Format(formatter.FlagDefault, base, 0, "", []any{"a"}))
+	shard0.Write(f.Format(formatter.FlagDefault, base.Add(3*time.Millisecond), 0, "", []any{"d"}))
+	shard1.Write(f.Format(formatter.FlagDefault, base.Add(time.Millisecond), 0, "", []any{"b"}))
+
+	var out bytes.Buffer
+	n, err := MergeConvert(&out, "txt", time.RFC3339Nano, &shard0, &shard1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, "2024-01-01T12:00:00Z INFO a\n2024-01-01T12:00:00.001Z INFO b\n"+
+		"2024-01-01T12:00:00.002Z INFO c\n2024-01-01T12:00:00.003Z INFO d\n", out.String())
+
+	_, err = MergeConvert(&out, "binary", time.RFC3339Nano)
+	assert.Error(t, err)
+}
\ No newline at end of file
@@ -0,0 +1,152 @@
+// Package logtest provides loggers for tests, writing into a temporary directory and capturing every record
+package logtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lixenwraith/log"
+)
+
+// cleanupTimeout bounds the flush and shutdown run when a test finishes
+const cleanupTimeout = 2 * time.Second
+
+// Option customizes the builder of a test logger after the defaults are set
+type Option func(b *log.Builder)
+
+// Level sets the level of the test logger
+func Level(level int64) Option {
+	return func(b *log.Builder) { b.Level(level) }
+}
+
+// Format sets the format of the test logger's file and captured lines
+func Format(format string) Option {
+	return func(b *log.Builder) { b.Format(format) }
+}
+
+// NewTestLogger creates a started logger writing to a file in t.TempDir(), with console output disabled
+// The returned sink captures every record; the logger is flushed and shut down when the test finishes
+func NewTestLogger(t testing.TB, opts ...Option) (*log.Logger, *CaptureSink) {
+	t.Helper()
+	b := log.NewBuilder().
+		Directory(t.TempDir()).
+		EnableFile(true).
+		EnableConsole(false).
+		BufferSize(1000).
+		FlushIntervalMs(10)
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	logger, err := b.Build()
+	if err != nil {
+		t.Fatalf("logtest: failed to build logger: %v", err)
+	}
+	if err := logger.Start(); err != nil {
+		t.Fatalf("logtest: failed to start logger: %v", err)
+	}
+	capture := &CaptureSink{}
+	if err := logger.AddSink("capture", capture); err != nil {
+		logger.Shutdown()
+		t.Fatalf("logtest: failed to add capture sink: %v", err)
+	}
+
+	t.Cleanup(func() {
+		// A test may have shut the logger down already, the flush then has nothing to do
+		_ = logger.Flush(cleanupTimeout)
+		if err := logger.Shutdown(cleanupTimeout); err != nil {
+			t.Errorf("logtest: failed to shut down logger: %v", err)
+		}
+	})
+	return logger, capture
+}
+
+// CaptureSink is a log.Sink keeping every record it receives along with its formatted line
+// Records reach it asynchronously, call Logger.Flush before inspecting them
+type CaptureSink struct {
+	mu      sync.Mutex
+	records []log.Record
+	lines   []string
+}
+
+// Write keeps a copy of the record and its formatted line
+func (s *CaptureSink) Write(data []byte, record log.Record) error {
+	record.Args = append([]any(nil), record.Args...)
+	line := string(bytes.TrimSuffix(data, []byte{'\n'}))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	s.lines = append(s.lines, line)
+	return nil
+}
+
+// Flush is a no-op, records are kept as they arrive
+func (s *CaptureSink) Flush() error {
+	return nil
+}
+
+// Close is a no-op, captured records stay available after shutdown
+func (s *CaptureSink) Close() error {
+	return nil
+}
+
+// Records returns the captured records in the order they were processed
+func (s *CaptureSink) Records() []log.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]log.Record(nil), s.records...)
+}
+
+// Lines returns the captured records as formatted by the logger, without trailing newlines
+func (s *CaptureSink) Lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.lines...)
+}
+
+// JSON decodes the captured lines of a logger writing the json format, failing the test on a line that is not an object
+func (s *CaptureSink) JSON(t testing.TB) []map[string]any {
+	t.Helper()
+	lines := s.Lines()
+	entries := make([]map[string]any, 0, len(lines))
+	for _, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("logtest: captured line is not a JSON object: %v: %s", err, line)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Len returns the number of captured records
+func (s *CaptureSink) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+// Contains reports whether any captured line contains substr
+func (s *CaptureSink) Contains(substr string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, line := range s.lines {
+		if strings.Contains(line, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset discards the captured records
+func (s *CaptureSink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = nil
+	s.lines = nil
+}
\ No newline at end of file
@@ -0,0 +1,55 @@
+package logtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lixenwraith/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewTestLogger verifies records are captured and written to the temporary file, and cleanup shuts the logger down
+func TestNewTestLogger(t *testing.T) {
+	var logger *log.Logger
+	t.Run("scoped", func(t *testing.T) {
+		var capture *CaptureSink
+		logger, capture = NewTestLogger(t, Level(log.LevelDebug), Format("json"))
+
+		logger.Debug("cache miss", "key", "user:1")
+		logger.Error("failed", "retry", true)
+		require.NoError(t, logger.Flush(time.Second))
+
+		require.Equal(t, 2, capture.Len())
+		records := capture.Records()
+		assert.Equal(t, log.LevelDebug, records[0].Level)
+		assert.Equal(t, []any{"failed", "retry", true}, records[1].Args)
+		assert.True(t, capture.Contains(`"level":"ERROR"`))
+		assert.Contains(t, capture.Lines()[0], `"fields":["cache miss","key","user:1"]`)
+		assert.Equal(t, []any{"failed", "retry", true}, capture.JSON(t)[1]["fields"])
+
+		content, err := os.ReadFile(filepath.Join(logger.GetConfig().Directory, "log.log"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "cache miss")
+
+		capture.Reset()
+		assert.Zero(t, capture.Len())
+		assert.False(t, capture.Contains("failed"))
+	})
+
+	assert.Error(t, logger.Flush(100*time.Millisecond), "The logger must be shut down once the test finishes")
+}
+
+// TestNewTestLoggerOptions verifies options override the defaults
+func TestNewTestLoggerOptions(t *testing.T) {
+	logger, capture := NewTestLogger(t, func(b *log.Builder) { b.Level(log.LevelWarn) })
+	logger.Info("ignored")
+	logger.Warn("kept")
+	require.NoError(t, logger.Flush(time.Second))
+
+	require.Equal(t, 1, capture.Len())
+	assert.Equal(t, []any{"kept"}, capture.Records()[0].Args)
+	assert.Equal(t, []string{"kept"}, capture.Lines(), "The default raw format writes arguments only")
+}
\ No newline at end of file
@@ -0,0 +1,95 @@
+package log
+
+import "path/filepath"
+
+// mirrorConfig derives the configuration of the mirror logger, writing the same file name in mirror_directory
+// Only file output is kept; disk checks, rotation, retention, and size limits apply to the mirror on its own
+func mirrorConfig(cfg *Config) *Config {
+	mirrorCfg := cfg.Clone()
+	mirrorCfg.Directory = cfg.MirrorDirectory
+	mirrorCfg.MirrorDirectory = ""
+	// Records arrive already admitted by the primary's levels
+	mirrorCfg.Level = LevelDebug
+	mirrorCfg.FileLevel = LevelDebug
+	mirrorCfg.LevelOverridesByField = nil
+	mirrorCfg.EnableConsole = false
+	mirrorCfg.EnableSyslog = false
+	mirrorCfg.EnableJournal = false
+	mirrorCfg.EnableGELF = false
+	mirrorCfg.S3Upload = false
+	mirrorCfg.Shards = 0
+	mirrorCfg.HeartbeatLevel = 0
+	mirrorCfg.SplitErrorFile = false
+	mirrorCfg.RecentRecords = 0
+	mirrorCfg.OnErrorExec = ""
+	mirrorCfg.InternalErrorsToLog = false
+	// Records arrive enriched from the primary
+	mirrorCfg.EnrichHost = false
+	mirrorCfg.EnrichPID = false
+	mirrorCfg.AppName = ""
+	mirrorCfg.AppVersion = ""
+	mirrorCfg.FlushOnExit = false
+	mirrorCfg.OnDiskFull = "drop"
+	return mirrorCfg
+}
+
+// sameDirectory reports whether two directory paths name the same directory after cleaning
+func sameDirectory(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+// getMirror returns the logger writing mirror_directory, or nil when mirroring is disabled or unavailable
+func (c *loggerCore) getMirror() *Logger {
+	mirror, _ := c.mirror.Load().(*Logger)
+	return mirror
+}
+
+// configureMirror creates, reconfigures, or removes the mirror logger to match cfg, assuming initMu is held
+// Storage that cannot be set up leaves mirroring disabled with a warning, the primary configuration still applies
+// Returns the mirror logger no longer in use, to be shut down after the new configuration is committed
+func (l *Logger) configureMirror(cfg *Config) *Logger {
+	current := l.getMirror()
+	if !cfg.EnableFile || cfg.MirrorDirectory == "" || cfg.ReadOnly {
+		l.mirror.Store((*Logger)(nil))
+		return current
+	}
+
+	if current == nil {
+		current = NewLogger()
+	}
+	if err := current.ApplyConfig(mirrorConfig(cfg)); err != nil {
+		l.internalLog("warning - mirroring to '%s' disabled: %v\n", cfg.MirrorDirectory, err)
+		l.mirror.Store((*Logger)(nil))
+		return current
+	}
+	if l.state.Started.Load() {
+		if err := current.Start(); err != nil {
+			l.internalLog("warning - mirroring to '%s' disabled: %v\n", cfg.MirrorDirectory, err)
+			l.mirror.Store((*Logger)(nil))
+			return current
+		}
+	}
+	l.mirror.Store(current)
+	return nil
+}
+
+// forwardToMirror copies a record bound for the file to the mirror, shard processors use their parent's
+// The copy is queued without waiting, a slow or unavailable mirror drops records instead of stalling the processor
+func (l *Logger) forwardToMirror(record logRecord) {
+	owner := l.loggerCore
+	if owner.parent != nil {
+		owner = owner.parent
+	}
+	mirror := owner.getMirror()
+	if mirror == nil {
+		return
+	}
+	// The primary file confirms audit records
+	record.ack = nil
+	mirror.sendLogRecord(record)
+}
\ No newline at end of file
@@ -0,0 +1,334 @@
+package log
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+)
+
+// Minimal MessagePack encoding for the Fluent Forward protocol: nil, bool, integers, floats, strings, binary,
+// arrays, maps, and the EventTime extension
+
+// msgpackEventTime is the Fluent Forward extension type carrying seconds and nanoseconds of a record timestamp
+const msgpackEventTime = 0
+
+// msgpackTimestamp is the MessagePack timestamp extension type, written by format=msgpack
+const msgpackTimestamp = -1
+
+// appendMsgpackArrayHeader appends the header of an array with n elements
+func appendMsgpackArrayHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xdc), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(buf, 0xdd), uint32(n))
+	}
+}
+
+// appendMsgpackMapHeader appends the header of a map with n key-value pairs
+func appendMsgpackMapHeader(buf []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xde), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(buf, 0xdf), uint32(n))
+	}
+}
+
+// appendMsgpackString appends a UTF-8 string
+func appendMsgpackString(buf []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, 0xda), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xdb), uint32(n))
+	}
+	return append(buf, s...)
+}
+
+// appendMsgpackBinary appends a byte string
+func appendMsgpackBinary(buf []byte, b []byte) []byte {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, 0xc5), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xc6), uint32(n))
+	}
+	return append(buf, b...)
+}
+
+// appendMsgpackInt appends a signed integer in its shortest form
+func appendMsgpackInt(buf []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendMsgpackUint(buf, uint64(v))
+	case v >= -32:
+		return append(buf, byte(v))
+	case v >= math.MinInt8:
+		return append(buf, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(v))
+	}
+}
+
+// appendMsgpackUint appends an unsigned integer in its shortest form
+func appendMsgpackUint(buf []byte, v uint64) []byte {
+	switch {
+	case v < 128:
+		return append(buf, byte(v))
+	case v <= math.MaxUint8:
+		return append(buf, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xce), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcf), v)
+	}
+}
+
+// appendMsgpackEventTime appends a timestamp as the EventTime extension, a fixext 8 of seconds and nanoseconds
+func appendMsgpackEventTime(buf []byte, t time.Time) []byte {
+	buf = append(buf, 0xd7, msgpackEventTime)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(buf, uint32(t.Nanosecond()))
+}
+
+// appendMsgpackValue appends a record value, types without a MessagePack form are written as their text
+func appendMsgpackValue(buf []byte, v any, text func(any) string) []byte {
+	switch val := v.(type) {
+	case nil:
+		return append(buf, 0xc0)
+	case bool:
+		if val {
+			return append(buf, 0xc3)
+		}
+		return append(buf, 0xc2)
+	case int:
+		return appendMsgpackInt(buf, int64(val))
+	case int8:
+		return appendMsgpackInt(buf, int64(val))
+	case int16:
+		return appendMsgpackInt(buf, int64(val))
+	case int32:
+		return appendMsgpackInt(buf, int64(val))
+	case int64:
+		return appendMsgpackInt(buf, val)
+	case uint:
+		return appendMsgpackUint(buf, uint64(val))
+	case uint8:
+		return appendMsgpackUint(buf, uint64(val))
+	case uint16:
+		return appendMsgpackUint(buf, uint64(val))
+	case uint32:
+		return appendMsgpackUint(buf, uint64(val))
+	case uint64:
+		return appendMsgpackUint(buf, val)
+	case float32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xca), math.Float32bits(val))
+	case float64:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcb), math.Float64bits(val))
+	case string:
+		return appendMsgpackString(buf, val)
+	case []byte:
+		if val == nil {
+			return append(buf, 0xc0)
+		}
+		return appendMsgpackBinary(buf, val)
+	default:
+		return appendMsgpackString(buf, text(v))
+	}
+}
+
+// readMsgpack decodes one value: maps become map[string]any with non-string keys skipped, arrays []any,
+// integers int64 or uint64, binary []byte, and the EventTime and timestamp extensions time.Time
+func readMsgpack(r *bufio.Reader) (any, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case b <= 0x7f:
+		return int64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b&0xf0 == 0x80:
+		return readMsgpackMap(r, int(b&0x0f))
+	case b&0xf0 == 0x90:
+		return readMsgpackArray(r, int(b&0x0f))
+	case b&0xe0 == 0xa0:
+		data, err := readMsgpackBytes(r, int(b&0x1f))
+		return string(data), err
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := readMsgpackLength(r, b-0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackBytes(r, n)
+	case 0xca:
+		data, err := readMsgpackBytes(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), nil
+	case 0xcb:
+		data, err := readMsgpackBytes(r, 8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		data, err := readMsgpackBytes(r, 1<<(b-0xcc))
+		if err != nil {
+			return nil, err
+		}
+		var v uint64
+		for _, c := range data {
+			v = v<<8 | uint64(c)
+		}
+		return v, nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (b - 0xd0)
+		data, err := readMsgpackBytes(r, size)
+		if err != nil {
+			return nil, err
+		}
+		var v uint64
+		for _, c := range data {
+			v = v<<8 | uint64(c)
+		}
+		// Sign-extend from the encoded width
+		shift := 64 - 8*size
+		return int64(v<<shift) >> shift, nil
+	case 0xd6:
+		data, err := readMsgpackBytes(r, 5)
+		if err != nil {
+			return nil, err
+		}
+		if int8(data[0]) != msgpackTimestamp {
+			return nil, fmtErrorf("unsupported msgpack extension type %d", int8(data[0]))
+		}
+		return time.Unix(int64(binary.BigEndian.Uint32(data[1:5])), 0), nil
+	case 0xd7:
+		data, err := readMsgpackBytes(r, 9)
+		if err != nil {
+			return nil, err
+		}
+		switch int8(data[0]) {
+		case msgpackEventTime:
+			return time.Unix(int64(binary.BigEndian.Uint32(data[1:5])), int64(binary.BigEndian.Uint32(data[5:9]))), nil
+		case msgpackTimestamp:
+			v := binary.BigEndian.Uint64(data[1:9])
+			return time.Unix(int64(v&(1<<34-1)), int64(v>>34)), nil
+		}
+		return nil, fmtErrorf("unsupported msgpack extension type %d", int8(data[0]))
+	case 0xc7:
+		data, err := readMsgpackBytes(r, 14)
+		if err != nil {
+			return nil, err
+		}
+		if data[0] != 12 || int8(data[1]) != msgpackTimestamp {
+			return nil, fmtErrorf("unsupported msgpack extension type %d", int8(data[1]))
+		}
+		return time.Unix(int64(binary.BigEndian.Uint64(data[6:14])), int64(binary.BigEndian.Uint32(data[2:6]))), nil
+	case 0xd9, 0xda, 0xdb:
+		n, err := readMsgpackLength(r, b-0xd9)
+		if err != nil {
+			return nil, err
+		}
+		data, err := readMsgpackBytes(r, n)
+		return string(data), err
+	case 0xdc, 0xdd:
+		n, err := readMsgpackLength(r, b-0xdc+1)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackArray(r, n)
+	case 0xde, 0xdf:
+		n, err := readMsgpackLength(r, b-0xde+1)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackMap(r, n)
+	}
+	return nil, fmtErrorf("unsupported msgpack type 0x%02x", b)
+}
+
+// readMsgpackLength reads a length of 1, 2, or 4 bytes for width 0, 1, or 2
+func readMsgpackLength(r *bufio.Reader, width byte) (int, error) {
+	data, err := readMsgpackBytes(r, 1<<width)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, c := range data {
+		n = n<<8 | int(c)
+	}
+	return n, nil
+}
+
+// readMsgpackBytes reads n bytes
+func readMsgpackBytes(r *bufio.Reader, n int) ([]byte, error) {
+	data := make([]byte, n)
+	_, err := io.ReadFull(r, data)
+	return data, err
+}
+
+// readMsgpackArray reads n values
+func readMsgpackArray(r *bufio.Reader, n int) ([]any, error) {
+	values := make([]any, 0, min(n, 1024))
+	for range n {
+		v, err := readMsgpack(r)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// readMsgpackMap reads n key-value pairs
+func readMsgpackMap(r *bufio.Reader, n int) (map[string]any, error) {
+	m := make(map[string]any, min(n, 1024))
+	for range n {
+		k, err := readMsgpack(r)
+		if err != nil {
+			return nil, err
+		}
+		v, err := readMsgpack(r)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := k.(string); ok {
+			m[key] = v
+		}
+	}
+	return m, nil
+}
\ No newline at end of file
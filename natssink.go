@@ -0,0 +1,405 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// NATSSinkOptions configures a sink publishing records to a NATS subject
+// Zero values select the defaults noted on each field
+type NATSSinkOptions struct {
+	Address       string        // Server address, "host:port"; required
+	Subject       string        // Subject the records are published to; required
+	Format        string        // Message format, "json" or "txt"; default "json"
+	JetStream     bool          // Wait for a JetStream stream to acknowledge each record, retrying it otherwise
+	Token         string        // Authentication token, empty disables token authentication
+	User          string        // User name, empty disables user authentication
+	Password      string        // User password
+	TLSConfig     *tls.Config   // TLS settings, nil connects in plain text unless the server requires TLS
+	Filter        Filter        // Selects the records published, nil publishes all records except heartbeats
+	BatchSize     int           // Records written before waiting for the server, default 100
+	FlushInterval time.Duration // Longest time a record waits for its batch to fill, default 1s
+	QueueSize     int           // Records buffered for delivery, new records are dropped when full; default 10000
+	MaxRetries    int           // Retries of a failed batch before it is dropped, default 3; negative disables retries
+	RetryBackoff  time.Duration // Delay before the first retry, doubled for each further retry; default 500ms
+	Timeout       time.Duration // Dial, write, and acknowledgment timeout, default 10s
+}
+
+// NATSSink is a Sink publishing records to a NATS subject, optionally into a JetStream stream
+type NATSSink struct {
+	*sinkQueue
+	opts      NATSSinkOptions
+	formatter *formatter.Formatter // Used by the delivery goroutine only
+	conn      net.Conn             // Used by the delivery goroutine only, nil until connected
+	reader    *bufio.Reader
+	inbox     string // Reply subject prefix of JetStream acknowledgments
+	maxSize   int    // Largest payload the server accepts, from its INFO
+}
+
+var _ Sink = (*NATSSink)(nil)
+
+// natsInfo is the part of the server INFO message the sink uses
+type natsInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	MaxPayload  int  `json:"max_payload"`
+	Headers     bool `json:"headers"`
+}
+
+// NewNATSSink starts a sink publishing records to a NATS subject, register it with Logger.AddSink
+// Returns an error if the address, subject, or format is invalid
+func NewNATSSink(opts NATSSinkOptions) (*NATSSink, error) {
+	if _, _, err := net.SplitHostPort(opts.Address); err != nil {
+		return nil, fmtErrorf("invalid nats sink address '%s': %w", opts.Address, err)
+	}
+	if opts.Subject == "" || strings.ContainsAny(opts.Subject, " \t\r\n") {
+		return nil, fmtErrorf("invalid nats sink subject '%s'", opts.Subject)
+	}
+	if opts.Format == "" {
+		opts.Format = "json"
+	}
+	if opts.Format != "json" && opts.Format != "txt" {
+		return nil, fmtErrorf("invalid nats sink format '%s' (use json or txt)", opts.Format)
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = natsSinkBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = natsSinkFlushInterval
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = natsSinkQueueSize
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = natsSinkMaxRetries
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = natsSinkRetryBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = natsSinkTimeout
+	}
+
+	policy := sanitizer.PolicyRaw
+	if opts.Format == "txt" {
+		policy = sanitizer.PolicyTxt
+	}
+	s := &NATSSink{
+		sinkQueue: newSinkQueue("nats sink", opts.QueueSize, opts.Filter, opts.Timeout),
+		opts:      opts,
+		formatter: formatter.New(sanitizer.New().Policy(policy)).
+			Type(opts.Format).
+			TimestampFormat(time.RFC3339Nano),
+	}
+	s.start(s.run)
+	return s, nil
+}
+
+// run collects queued records into batches of up to BatchSize records
+func (s *NATSSink) run() {
+	defer s.disconnect()
+
+	var batch [][]byte
+	add := func(record Record) bool {
+		flags := record.Flags&^FlagRaw | FlagShowTimestamp | FlagShowLevel
+		data := s.formatter.FormatLabeled(flags, record.Time, record.Level, record.Trace, record.Labels, record.Args)
+		// The formatter reuses its buffer, each record keeps a copy
+		batch = append(batch, append([]byte(nil), bytes.TrimRight(data, "\n")...))
+		return len(batch) >= s.opts.BatchSize
+	}
+	flush := func() {
+		s.deliver(batch)
+		batch = nil
+	}
+	s.batch(s.opts.FlushInterval, add, flush)
+}
+
+// deliver publishes a batch, reconnecting and retrying with exponential backoff when the connection fails or,
+// with JetStream, for the records the stream did not acknowledge
+func (s *NATSSink) deliver(batch [][]byte) {
+	backoff := s.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		failed, oversized, err := s.publish(batch)
+		if oversized > 0 {
+			s.status.failure(fmtErrorf("nats sink dropped %d records larger than the server limit of %d bytes",
+				oversized, s.maxSize))
+		}
+		if err == nil {
+			if oversized == 0 {
+				s.status.success()
+			}
+			return
+		}
+		if failed == nil {
+			// The connection failed, the server may not have received any record of the batch
+			s.disconnect()
+		} else {
+			batch = failed
+		}
+		if s.ctx.Err() != nil || attempt >= s.opts.MaxRetries {
+			s.status.failure(fmtErrorf("nats sink dropped %d records: %w", len(batch), err))
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-s.ctx.Done():
+			s.status.failure(fmtErrorf("nats sink dropped %d records: %w", len(batch), s.ctx.Err()))
+			return
+		}
+	}
+}
+
+// publish writes the batch and waits for the server to process it, a PONG for core NATS and one acknowledgment
+// per record with JetStream
+// Records above the server's payload limit are skipped and counted
+// Returns the records JetStream rejected with the first rejection, or a nil slice with the connection error
+func (s *NATSSink) publish(batch [][]byte) ([][]byte, int, error) {
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	_ = s.conn.SetDeadline(time.Now().Add(s.opts.Timeout))
+	w := bufio.NewWriter(s.conn)
+	var oversized int
+	sent := make([][]byte, 0, len(batch))
+	for _, data := range batch {
+		if s.maxSize > 0 && len(data) > s.maxSize {
+			oversized++
+			continue
+		}
+		w.WriteString("PUB " + s.opts.Subject)
+		if s.inbox != "" {
+			w.WriteString(" " + s.inbox + strconv.Itoa(len(sent)))
+		}
+		w.WriteString(" " + strconv.Itoa(len(data)) + "\r\n")
+		w.Write(data)
+		w.WriteString("\r\n")
+		sent = append(sent, data)
+	}
+	w.WriteString("PING\r\n")
+	if err := w.Flush(); err != nil {
+		return nil, 0, err
+	}
+
+	answered := make([]bool, len(sent))
+	pending := len(sent)
+	pong := false
+	var failed [][]byte
+	var rejection error
+	for (s.inbox == "" && !pong) || (s.inbox != "" && pending > 0) {
+		line, err := s.readLine()
+		if err != nil {
+			return nil, oversized, err
+		}
+		op, args, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(op) {
+		case "PING":
+			if _, err := io.WriteString(s.conn, "PONG\r\n"); err != nil {
+				return nil, oversized, err
+			}
+		case "PONG":
+			pong = true
+		case "-ERR":
+			return nil, oversized, fmtErrorf("server error %s", args)
+		case "MSG", "HMSG":
+			ack, err := s.readAck(strings.ToUpper(op) == "HMSG", strings.Fields(args))
+			if err != nil {
+				return nil, oversized, err
+			}
+			if ack.index < 0 || ack.index >= len(sent) || answered[ack.index] {
+				continue
+			}
+			answered[ack.index] = true
+			pending--
+			if ack.err != nil {
+				failed = append(failed, sent[ack.index])
+				if rejection == nil {
+					rejection = ack.err
+				}
+			}
+		}
+	}
+	return failed, oversized, rejection
+}
+
+// natsAck is a JetStream acknowledgment, index is -1 when the message answers no record of the current batch
+type natsAck struct {
+	index int
+	err   error // Rejection by the stream, or a no responders status when no stream captures the subject
+}
+
+// readAck reads the payload of a message received on the acknowledgment inbox
+func (s *NATSSink) readAck(headers bool, args []string) (natsAck, error) {
+	ack := natsAck{index: -1}
+	// MSG <subject> <sid> [reply] <size>, HMSG <subject> <sid> [reply] <header size> <total size>
+	if len(args) < 3 {
+		return ack, fmtErrorf("malformed message '%s'", strings.Join(args, " "))
+	}
+	total, err := strconv.Atoi(args[len(args)-1])
+	if err != nil || total < 0 {
+		return ack, fmtErrorf("malformed message size '%s'", args[len(args)-1])
+	}
+	headerSize := 0
+	if headers {
+		if headerSize, err = strconv.Atoi(args[len(args)-2]); err != nil || headerSize < 0 || headerSize > total {
+			return ack, fmtErrorf("malformed message header size '%s'", args[len(args)-2])
+		}
+	}
+	payload := make([]byte, total+2)
+	if _, err := io.ReadFull(s.reader, payload); err != nil {
+		return ack, err
+	}
+
+	if suffix, ok := strings.CutPrefix(args[0], s.inbox); ok {
+		if i, err := strconv.Atoi(suffix); err == nil {
+			ack.index = i
+		}
+	}
+
+	// Header blocks start with a status line, "NATS/1.0 503" when no stream captured the record
+	statusLine, _, _ := bytes.Cut(payload[:headerSize], []byte("\r\n"))
+	if fields := strings.Fields(string(statusLine)); len(fields) > 1 && fields[1] == "503" {
+		ack.err = fmtErrorf("no jetstream stream captures subject '%s'", s.opts.Subject)
+		return ack, nil
+	}
+	var response struct {
+		Error *struct {
+			Code        int    `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(payload[headerSize:total], &response); err != nil {
+		ack.err = fmtErrorf("malformed jetstream acknowledgment: %w", err)
+	} else if response.Error != nil {
+		ack.err = fmtErrorf("jetstream rejected record: %d %s", response.Error.Code, response.Error.Description)
+	}
+	return ack, nil
+}
+
+// connect dials the server, upgrades to TLS when configured or required, authenticates, and subscribes to the
+// acknowledgment inbox when JetStream is used
+func (s *NATSSink) connect() error {
+	dialer := net.Dialer{Timeout: s.opts.Timeout}
+	conn, err := dialer.DialContext(s.ctx, "tcp", s.opts.Address)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	s.reader = bufio.NewReader(conn)
+	_ = conn.SetDeadline(time.Now().Add(s.opts.Timeout))
+
+	line, err := s.readLine()
+	if err != nil {
+		s.disconnect()
+		return err
+	}
+	op, payload, _ := strings.Cut(line, " ")
+	var info natsInfo
+	if strings.ToUpper(op) != "INFO" || json.Unmarshal([]byte(payload), &info) != nil {
+		s.disconnect()
+		return fmtErrorf("unexpected server greeting '%s'", line)
+	}
+	s.maxSize = info.MaxPayload
+
+	if s.opts.TLSConfig != nil || info.TLSRequired {
+		config := &tls.Config{}
+		if s.opts.TLSConfig != nil {
+			config = s.opts.TLSConfig.Clone()
+		}
+		if config.ServerName == "" {
+			config.ServerName, _, _ = net.SplitHostPort(s.opts.Address)
+		}
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.HandshakeContext(s.ctx); err != nil {
+			s.disconnect()
+			return fmtErrorf("tls handshake failed: %w", err)
+		}
+		s.conn = tlsConn
+		s.reader = bufio.NewReader(tlsConn)
+	}
+
+	options := map[string]any{
+		"verbose":  false,
+		"pedantic": false,
+		"lang":     "go",
+		"version":  "lixenwraith/log",
+		"protocol": 1,
+	}
+	if s.opts.Token != "" {
+		options["auth_token"] = s.opts.Token
+	}
+	if s.opts.User != "" {
+		options["user"] = s.opts.User
+		options["pass"] = s.opts.Password
+	}
+	// No responders statuses report a missing stream at once instead of an acknowledgment timeout
+	if s.opts.JetStream && info.Headers {
+		options["headers"] = true
+		options["no_responders"] = true
+	}
+	connectJSON, _ := json.Marshal(options)
+
+	handshake := "CONNECT " + string(connectJSON) + "\r\n"
+	s.inbox = ""
+	if s.opts.JetStream {
+		id := make([]byte, 8)
+		_, _ = rand.Read(id)
+		s.inbox = "_INBOX." + hex.EncodeToString(id) + "."
+		handshake += "SUB " + s.inbox + "* 1\r\n"
+	}
+	handshake += "PING\r\n"
+	if _, err := io.WriteString(s.conn, handshake); err != nil {
+		s.disconnect()
+		return err
+	}
+
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			s.disconnect()
+			return err
+		}
+		op, args, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(op) {
+		case "PONG":
+			return nil
+		case "-ERR":
+			s.disconnect()
+			return fmtErrorf("server refused connection: %s", args)
+		}
+	}
+}
+
+// readLine reads one protocol line without its CRLF
+func (s *NATSSink) readLine() (string, error) {
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// disconnect closes the connection, the next batch reconnects
+func (s *NATSSink) disconnect() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		s.reader = nil
+	}
+}
\ No newline at end of file
@@ -0,0 +1,182 @@
+package log
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// natsServer is a test NATS server storing published messages
+// With jetStream, publishes with a reply subject are acknowledged; messages containing "full" are rejected once
+type natsServer struct {
+	ln        net.Listener
+	jetStream bool
+	mu        sync.Mutex
+	connect   map[string]any
+	subjects  []string
+	messages  []string
+	rejected  map[string]bool
+}
+
+func newNATSServer(t *testing.T, jetStream bool) *natsServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := &natsServer{ln: ln, jetStream: jetStream, rejected: make(map[string]bool)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return srv
+}
+
+func (srv *natsServer) serve(conn net.Conn) {
+	defer conn.Close()
+	_, _ = io.WriteString(conn, `INFO {"server_id":"test","max_payload":1048576,"headers":true}`+"\r\n")
+	r := bufio.NewReader(conn)
+	seq := 0
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		op, args, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		switch op {
+		case "CONNECT":
+			srv.mu.Lock()
+			_ = json.Unmarshal([]byte(args), &srv.connect)
+			srv.mu.Unlock()
+		case "PING":
+			_, _ = io.WriteString(conn, "PONG\r\n")
+		case "PUB":
+			fields := strings.Fields(args)
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			message := string(payload[:size])
+
+			srv.mu.Lock()
+			reject := srv.jetStream && strings.Contains(message, "full") && !srv.rejected[message]
+			if reject {
+				srv.rejected[message] = true
+			} else {
+				srv.subjects = append(srv.subjects, fields[0])
+				srv.messages = append(srv.messages, message)
+			}
+			srv.mu.Unlock()
+
+			if srv.jetStream && len(fields) == 3 {
+				ack := `{"error":{"code":503,"description":"stream full"}}`
+				if !reject {
+					seq++
+					ack = fmt.Sprintf(`{"stream":"LOGS","seq":%d}`, seq)
+				}
+				_, _ = fmt.Fprintf(conn, "MSG %s 1 %d\r\n%s\r\n", fields[1], len(ack), ack)
+			}
+		}
+	}
+}
+
+func (srv *natsServer) snapshot() ([]string, []string, map[string]any) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return append([]string(nil), srv.subjects...), append([]string(nil), srv.messages...), srv.connect
+}
+
+// TestNATSSink verifies records are published as JSON to the subject with the configured credentials
+func TestNATSSink(t *testing.T) {
+	srv := newNATSServer(t, false)
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	sink, err := NewNATSSink(NATSSinkOptions{
+		Address:       srv.ln.Addr().String(),
+		Subject:       "logs.app",
+		User:          "app",
+		Password:      "secret",
+		FlushInterval: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, logger.AddSink("nats", sink))
+
+	logger.Info("first", "n", 1)
+	logger.Warn("second")
+	require.NoError(t, logger.Flush(time.Second))
+
+	require.Eventually(t, func() bool {
+		_, messages, _ := srv.snapshot()
+		return len(messages) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	subjects, messages, connect := srv.snapshot()
+	assert.Equal(t, []string{"logs.app", "logs.app"}, subjects)
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(messages[0]), &entry))
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "app", connect["user"])
+	assert.Equal(t, "secret", connect["pass"])
+	require.NoError(t, logger.RemoveSink("nats"))
+
+	_, err = NewNATSSink(NATSSinkOptions{Address: srv.ln.Addr().String(), Subject: "bad subject"})
+	assert.Error(t, err)
+	_, err = NewNATSSink(NATSSinkOptions{Address: "nats", Subject: "logs"})
+	assert.Error(t, err)
+}
+
+// TestNATSSinkJetStream verifies JetStream acknowledgments are awaited and rejected records are retried
+func TestNATSSinkJetStream(t *testing.T) {
+	srv := newNATSServer(t, true)
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	sink, err := NewNATSSink(NATSSinkOptions{
+		Address:       srv.ln.Addr().String(),
+		Subject:       "logs.app",
+		Format:        "txt",
+		JetStream:     true,
+		FlushInterval: 20 * time.Millisecond,
+		RetryBackoff:  10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, logger.AddSink("nats", sink))
+
+	logger.Info("stored")
+	logger.Error("disk full")
+	require.NoError(t, logger.Flush(time.Second))
+
+	require.Eventually(t, func() bool {
+		_, messages, _ := srv.snapshot()
+		return len(messages) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_, messages, _ := srv.snapshot()
+	assert.Contains(t, messages[0], "INFO stored")
+	assert.Contains(t, messages[1], `ERROR "disk full"`)
+
+	// The rejected record was published again on its own
+	srv.mu.Lock()
+	assert.Len(t, srv.rejected, 1)
+	srv.mu.Unlock()
+	for _, h := range logger.Stats().Sinks {
+		if h.Name == "nats" {
+			assert.Equal(t, SinkStatusOK, h.Status)
+		}
+	}
+}
\ No newline at end of file
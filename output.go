@@ -0,0 +1,339 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Record describes a processed record handed to a Sink along with its formatted bytes
+type Record struct {
+	Time  time.Time // Record timestamp
+	Level int64     // Record level, heartbeats use LevelProc and above
+	Flags int64     // Formatting flags the record was logged with, 0 for the configured defaults
+	Trace string    // Function trace, empty unless requested
+	Args  []any     // Record arguments after bound fields are applied, must not be modified
+
+	Labels map[string]string // Labels of the logging logger, nil when it has none; must not be modified
+
+	consoleTag string // Console-only component tag
+	audit      bool   // Audit record awaiting write confirmation
+}
+
+// Sink is a destination for formatted records, registered with Logger.AddSink
+// Write receives every record processed by the logger, formatted with its configuration, and must not retain data
+// Calls are serialized per sink; Flush runs on explicit flushes and periodic syncs, Close on removal and shutdown
+type Sink interface {
+	Write(data []byte, record Record) error
+	Flush() error
+	Close() error
+}
+
+// Built-in outputs implement Sink
+var (
+	_ Sink = (*consoleSink)(nil)
+	_ Sink = (*fileSink)(nil)
+)
+
+// reservedSinkNames are health names of built-in outputs, unavailable to registered sinks
+var reservedSinkNames = map[string]bool{
+	"console":  true,
+	"file":     true,
+	"syslog":   true,
+	"journald": true,
+	"gelf":     true,
+	"s3":       true,
+}
+
+// namedSink is a registered sink with its delivery health
+type namedSink struct {
+	name   string
+	sink   Sink
+	mu     sync.Mutex // Serializes calls, shard processors write to the parent's sinks concurrently
+	closed bool
+	status healthTracker
+}
+
+// newRecord exposes a queued record to sinks
+func newRecord(record logRecord) Record {
+	return Record{
+		Time:       record.TimeStamp,
+		Level:      record.Level,
+		Flags:      record.Flags,
+		Trace:      record.Trace,
+		Args:       record.Args,
+		Labels:     record.Labels,
+		consoleTag: record.ConsoleTag,
+		audit:      record.ack != nil,
+	}
+}
+
+// AddSink registers a destination receiving every record processed by this logger, including heartbeats
+// Records reach the sink regardless of file output health; sink errors are reported under name in Stats().Sinks
+// The sink is shared by all loggers derived from the same core and is flushed and closed on Shutdown
+// Returns an error if name is empty, contains ':', is a built-in output name, or is already registered
+func (l *Logger) AddSink(name string, s Sink) error {
+	if s == nil {
+		return fmtErrorf("sink cannot be nil")
+	}
+	if name == "" || reservedSinkNames[name] || strings.IndexByte(name, ':') >= 0 {
+		return fmtErrorf("invalid sink name '%s'", name)
+	}
+
+	l.sinkMu.Lock()
+	defer l.sinkMu.Unlock()
+
+	current := l.getOutputs()
+	for _, ns := range current {
+		if ns.name == name {
+			return fmtErrorf("sink '%s' already registered", name)
+		}
+	}
+	updated := make([]*namedSink, 0, len(current)+1)
+	updated = append(updated, current...)
+	updated = append(updated, &namedSink{name: name, sink: s})
+	l.outputs.Store(updated)
+	return nil
+}
+
+// RemoveSink unregisters the named sink, then flushes and closes it
+// Returns an error if no sink is registered under name, or the flush and close errors
+func (l *Logger) RemoveSink(name string) error {
+	l.sinkMu.Lock()
+	current := l.getOutputs()
+	var removed *namedSink
+	updated := make([]*namedSink, 0, len(current))
+	for _, ns := range current {
+		if ns.name == name {
+			removed = ns
+			continue
+		}
+		updated = append(updated, ns)
+	}
+	if removed != nil {
+		l.outputs.Store(updated)
+	}
+	l.sinkMu.Unlock()
+
+	if removed == nil {
+		return fmtErrorf("sink '%s' not registered", name)
+	}
+	// A processor that loaded the old list may still write, close waits for it and rejects later writes
+	return removed.close()
+}
+
+// getOutputs returns the registered sinks, which must not be modified
+func (c *loggerCore) getOutputs() []*namedSink {
+	outputs, _ := c.outputs.Load().([]*namedSink)
+	return outputs
+}
+
+// hasOutputs reports whether records of this logger reach any registered sink
+func (l *Logger) hasOutputs() bool {
+	return len(l.getOutputs()) > 0 || (l.parent != nil && len(l.parent.getOutputs()) > 0)
+}
+
+// writeOutputs hands a formatted record to every registered sink, shard loggers also feed their parent's sinks
+func (l *Logger) writeOutputs(data []byte, record Record) {
+	for _, ns := range l.getOutputs() {
+		ns.write(data, record)
+	}
+	if l.parent != nil {
+		for _, ns := range l.parent.getOutputs() {
+			ns.write(data, record)
+		}
+	}
+}
+
+// flushOutputs flushes every registered sink of this logger
+func (l *Logger) flushOutputs() {
+	for _, ns := range l.getOutputs() {
+		ns.flush()
+	}
+}
+
+// closeOutputs flushes and closes every registered sink of this logger
+func (l *Logger) closeOutputs() error {
+	var err error
+	for _, ns := range l.getOutputs() {
+		err = errors.Join(err, ns.close())
+	}
+	return err
+}
+
+// write passes a record to the sink and records the outcome
+func (ns *namedSink) write(data []byte, record Record) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	if ns.closed {
+		return
+	}
+	if err := ns.sink.Write(data, record); err != nil {
+		ns.status.failure(err)
+		return
+	}
+	ns.status.success()
+}
+
+// flush flushes the sink, failures count against its health
+func (ns *namedSink) flush() {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	if ns.closed {
+		return
+	}
+	if err := ns.sink.Flush(); err != nil {
+		ns.status.failure(err)
+	}
+}
+
+// close flushes and closes the sink once
+func (ns *namedSink) close() error {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	if ns.closed {
+		return nil
+	}
+	ns.closed = true
+	err := errors.Join(ns.sink.Flush(), ns.sink.Close())
+	if err != nil {
+		return fmtErrorf("sink '%s': %w", ns.name, err)
+	}
+	return nil
+}
+
+// health reports delivery health under the registered name, taken from the sink when it tracks its own
+func (ns *namedSink) health() SinkHealth {
+	if reporter, ok := ns.sink.(healthReporter); ok {
+		health := reporter.health()
+		health.Name = ns.name
+		return health
+	}
+	return ns.status.snapshot(ns.name)
+}
+
+// consoleSink writes records to the configured console target
+type consoleSink struct {
+	l           *Logger
+	target      string // "stdout", "stderr", or "split"
+	colorStdout bool   // Records written to stdout are colored, see console_color
+	colorStderr bool   // Records written to stderr are colored
+	colorLine   bool   // Whole lines are colored instead of the level token
+}
+
+// fileSink writes records to the active log file, rotating it when it would exceed the size limit
+// It is only used by the processor, under batchMu
+type fileSink struct {
+	l       *Logger
+	cfg     *Config
+	special bool // Character device, pipe, or socket such as /dev/stdout, never rotated, synced, or read back
+}
+
+// Write appends a record to the active log file
+func (s *fileSink) Write(data []byte, record Record) error {
+	_, err := s.write(data, record)
+	return err
+}
+
+// write appends a record to the active log file, rotating first if needed, and updates counters and health
+// Returns bytes written, which are counted even when audit verification fails
+func (s *fileSink) write(data []byte, record Record) (int64, error) {
+	l := s.l
+	dataLen := int64(len(data))
+
+	// File rotation check, compressed files grow by an unknown amount and rotate once the limit is reached
+	growth := dataLen
+	if s.cfg.GzipActive {
+		growth = 1
+	}
+	if maxSizeKB := s.cfg.MaxSizeKB; maxSizeKB > 0 && !s.special && l.state.CurrentSize.Load()+growth > maxSizeKB*sizeMultiplier {
+		if err := l.rotateLogFile(rotationTriggerSize); err != nil {
+			l.internalLog("failed to rotate log file: %v\n", err)
+			l.state.fileHealth.failure(err)
+			// Account for the dropped log that triggered the failed rotation
+			l.state.DroppedLogs.Add(1)
+			l.reportWriteError(data, record, err)
+			return 0, err
+		}
+	}
+
+	currentLogFile, isFile := l.state.CurrentFile.Load().(*os.File)
+	if !isFile || currentLogFile == nil {
+		l.state.fileHealth.failure(errNoLogFile)
+		l.state.DroppedLogs.Add(1)
+		l.reportWriteError(data, record, errNoLogFile)
+		return 0, errNoLogFile
+	}
+
+	stream := l.getGzipStream(currentLogFile)
+	var n int
+	var err error
+	if stream != nil {
+		n, err = stream.write(data)
+	} else {
+		n, err = currentLogFile.Write(data)
+	}
+	if err != nil {
+		l.internalLog("failed to write to log file: %v\n", err)
+		l.state.fileHealth.failure(err)
+		l.state.DroppedLogs.Add(1)
+		l.reportWriteError(data, record, err)
+		l.performDiskCheck(true)
+		return 0, err
+	}
+
+	// Audit records are read back before they are confirmed, compressed ones are flushed and synced instead
+	if record.audit && s.cfg.AuditVerify && !s.special {
+		var err error
+		if stream != nil {
+			if err = stream.flush(); err == nil {
+				err = currentLogFile.Sync()
+			}
+		} else {
+			err = verifyWrite(currentLogFile, data[:n])
+		}
+		if err != nil {
+			l.internalLog("audit write verification failed: %v\n", err)
+			l.state.fileHealth.failure(err)
+			l.addFileSize(stream, n)
+			return int64(n), err
+		}
+	}
+	l.state.fileHealth.success()
+	l.addFileSize(stream, n)
+	l.state.TotalLogsProcessed.Add(1)
+	l.state.recordsSinceFlush.Add(1)
+	l.state.bytesSinceFlush.Add(uint64(n))
+	l.state.TotalBytesWritten.Add(uint64(n))
+	return int64(n), nil
+}
+
+// Flush syncs the active log file, through the sync group when joined
+func (s *fileSink) Flush() error {
+	_, err := s.l.syncCurrentFile()
+	return err
+}
+
+// Close syncs and closes the active log file
+func (s *fileSink) Close() error {
+	l := s.l
+	currentLogFile, ok := l.state.CurrentFile.Load().(*os.File)
+	if !ok || currentLogFile == nil {
+		return nil
+	}
+
+	err := l.closeGzipStream(currentLogFile)
+	// Special files cannot be synced, closing releases only this logger's descriptor
+	if !s.special {
+		if syncErr := currentLogFile.Sync(); syncErr != nil {
+			err = errors.Join(err, fmtErrorf("failed to sync log file '%s' during shutdown: %w", currentLogFile.Name(), syncErr))
+		}
+	}
+	if closeErr := currentLogFile.Close(); closeErr != nil {
+		err = errors.Join(err, fmtErrorf("failed to close log file '%s' during shutdown: %w", currentLogFile.Name(), closeErr))
+	}
+	l.state.CurrentFile.Store((*os.File)(nil))
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,154 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySink collects formatted records in memory
+type memorySink struct {
+	mu      sync.Mutex
+	data    []byte
+	records []Record
+	flushes int
+	closed  bool
+	err     error // Returned by Write when set
+}
+
+func (s *memorySink) Write(data []byte, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.data = append(s.data, data...)
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *memorySink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushes++
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// snapshot returns the collected data, record count, flush count, and closed state
+func (s *memorySink) snapshot() (string, int, int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return string(s.data), len(s.records), s.flushes, s.closed
+}
+
+// TestAddSink verifies registered sinks receive the bytes written to the file and are flushed and closed
+func TestAddSink(t *testing.T) {
+	logger, dir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	mem := &memorySink{}
+	require.NoError(t, logger.AddSink("memory", mem))
+
+	logger.Info("to sink", "key", "value")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	data, count, flushes, _ := mem.snapshot()
+	assert.Equal(t, string(content), data)
+	assert.Equal(t, 1, count)
+	assert.GreaterOrEqual(t, flushes, 1)
+
+	mem.mu.Lock()
+	record := mem.records[0]
+	mem.mu.Unlock()
+	assert.Equal(t, LevelInfo, record.Level)
+	assert.Equal(t, []any{"to sink", "key", "value"}, record.Args)
+
+	require.NoError(t, logger.RemoveSink("memory"))
+	_, _, _, closed := mem.snapshot()
+	assert.True(t, closed)
+
+	logger.Info("after removal")
+	require.NoError(t, logger.Flush(time.Second))
+	_, count, _, _ = mem.snapshot()
+	assert.Equal(t, 1, count)
+
+	assert.Error(t, logger.RemoveSink("memory"))
+}
+
+// TestAddSinkValidation verifies sink names are checked
+func TestAddSinkValidation(t *testing.T) {
+	logger := NewLogger()
+
+	assert.Error(t, logger.AddSink("", &memorySink{}))
+	assert.Error(t, logger.AddSink("file", &memorySink{}))
+	assert.Error(t, logger.AddSink("a:b", &memorySink{}))
+	assert.Error(t, logger.AddSink("nil", nil))
+
+	require.NoError(t, logger.AddSink("memory", &memorySink{}))
+	assert.Error(t, logger.AddSink("memory", &memorySink{}))
+}
+
+// TestSinkHealth verifies a failing sink is reported without affecting file output, and closed on shutdown
+func TestSinkHealth(t *testing.T) {
+	logger, dir := createTestLogger(t)
+
+	failing := &memorySink{err: errors.New("destination down")}
+	require.NoError(t, logger.AddSink("remote", failing))
+
+	logger.Info("still written")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "still written")
+
+	var found bool
+	for _, s := range logger.Stats().Sinks {
+		if s.Name == "remote" {
+			found = true
+			assert.Equal(t, SinkStatusDegraded, s.Status)
+			assert.Equal(t, "destination down", s.LastError)
+		}
+	}
+	assert.True(t, found)
+
+	require.NoError(t, logger.Shutdown())
+	_, _, _, closed := failing.snapshot()
+	assert.True(t, closed)
+}
+
+// TestSinkShards verifies sinks registered on a sharded logger receive the records of every shard
+func TestSinkShards(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+
+	cfg := logger.GetConfig()
+	cfg.Shards = 2
+	require.NoError(t, logger.ApplyConfig(cfg))
+
+	mem := &memorySink{}
+	require.NoError(t, logger.AddSink("memory", mem))
+
+	for i := 0; i < 20; i++ {
+		logger.Info("sharded", i)
+	}
+	require.NoError(t, logger.Flush(time.Second))
+
+	_, count, _, _ := mem.snapshot()
+	assert.Equal(t, 20, count)
+}
\ No newline at end of file
@@ -0,0 +1,113 @@
+package log
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// defaultPressureThresholds are used by OnPressure when no thresholds are given
+var defaultPressureThresholds = []float64{0.5, 0.8, 0.95}
+
+// PressureFunc is called when queue pressure crosses a threshold registered with OnPressure
+// level is the highest threshold now reached, 0 once pressure falls below all thresholds; pressure is the
+// occupancy that caused the crossing
+type PressureFunc func(level, pressure float64)
+
+// pressureWatch holds the thresholds of an OnPressure registration and the band last reported
+type pressureWatch struct {
+	thresholds []float64 // Ascending, each in (0, 1]
+	fn         PressureFunc
+	band       atomic.Int64 // Number of thresholds reached at the last check
+}
+
+// Pressure returns the occupancy of the record queue, 0 when empty and 1 when full and dropping records
+// Sharded loggers report their fullest shard. Returns 0 when the logger is not running
+func (l *Logger) Pressure() float64 {
+	return l.queuePressure()
+}
+
+// OnPressure calls fn whenever Pressure crosses one of the thresholds, rising or falling, so producers can
+// reduce logging detail before records are dropped. Without thresholds, 0.5, 0.8, and 0.95 are used
+// fn runs synchronously on the logging or processor goroutine that observed the crossing and must return quickly
+// A nil fn removes the callback. Returns an error if a threshold is outside (0, 1]
+func (l *Logger) OnPressure(fn PressureFunc, thresholds ...float64) error {
+	if fn == nil {
+		l.pressureWatch.Store((*pressureWatch)(nil))
+		return nil
+	}
+	if len(thresholds) == 0 {
+		thresholds = defaultPressureThresholds
+	}
+	for _, t := range thresholds {
+		if t <= 0 || t > 1 {
+			return fmtErrorf("pressure threshold must be in (0, 1]: %v", t)
+		}
+	}
+
+	sorted := make([]float64, len(thresholds))
+	copy(sorted, thresholds)
+	slices.Sort(sorted)
+	w := &pressureWatch{thresholds: sorted, fn: fn}
+	w.band.Store(int64(w.bandOf(l.Pressure())))
+	l.pressureWatch.Store(w)
+	return nil
+}
+
+// checkPressure reports a threshold crossing to the OnPressure callback, shards report through their parent
+func (l *Logger) checkPressure() {
+	owner := l.loggerCore
+	if owner.parent != nil {
+		owner = owner.parent
+	}
+	w, _ := owner.pressureWatch.Load().(*pressureWatch)
+	if w == nil {
+		return
+	}
+
+	pressure := owner.queuePressure()
+	band := int64(w.bandOf(pressure))
+	old := w.band.Load()
+	// Concurrent observers of the same crossing report it once
+	if band == old || !w.band.CompareAndSwap(old, band) {
+		return
+	}
+	level := 0.0
+	if band > 0 {
+		level = w.thresholds[band-1]
+	}
+	w.fn(level, pressure)
+}
+
+// bandOf returns the number of thresholds reached at the given pressure
+func (w *pressureWatch) bandOf(pressure float64) int {
+	band := 0
+	for band < len(w.thresholds) && pressure >= w.thresholds[band] {
+		band++
+	}
+	return band
+}
+
+// queuePressure returns the occupancy of the core's queue, or of its fullest shard queue
+func (c *loggerCore) queuePressure() float64 {
+	if set, _ := c.shards.Load().(*shardSet); set != nil {
+		var highest float64
+		for _, shard := range set.loggers {
+			highest = max(highest, shard.queuePressure())
+		}
+		return highest
+	}
+	q, _ := c.state.ActiveQueue.Load().(*logQueue)
+	if q == nil {
+		return 0
+	}
+	return q.pressure()
+}
+
+// pressure returns the queue occupancy in [0, 1]
+func (q *logQueue) pressure() float64 {
+	size := cap(q.records)
+	if size == 0 {
+		return 0
+	}
+	return float64(len(q.records)) / float64(size)
+}
\ No newline at end of file
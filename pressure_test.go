@@ -0,0 +1,54 @@
+package log
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPressure verifies queue occupancy is reported and threshold crossings invoke the callback both ways
+func TestPressure(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+
+	cfg := logger.GetConfig()
+	cfg.BufferSize = 10
+	require.NoError(t, logger.ApplyConfig(cfg))
+	assert.Equal(t, 0.0, logger.Pressure())
+
+	var mu sync.Mutex
+	var levels []float64
+	require.NoError(t, logger.OnPressure(func(level, pressure float64) {
+		mu.Lock()
+		levels = append(levels, level)
+		mu.Unlock()
+	}, 0.8, 0.5))
+
+	// Stall the processor so records accumulate in the queue
+	logger.batchMu.Lock()
+	logger.Info("taken by the processor")
+	time.Sleep(50 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		logger.Info("queued", i)
+	}
+	assert.Equal(t, 1.0, logger.Pressure())
+	logger.batchMu.Unlock()
+
+	require.NoError(t, logger.Flush(time.Second))
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(levels) > 0 && levels[len(levels)-1] == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0.0, logger.Pressure())
+
+	mu.Lock()
+	assert.Equal(t, []float64{0.5, 0.8, 0}, levels)
+	mu.Unlock()
+
+	assert.Error(t, logger.OnPressure(func(float64, float64) {}, 1.5))
+	require.NoError(t, logger.OnPressure(nil))
+}
\ No newline at end of file
@@ -3,15 +3,18 @@ package log
 import (
 	"os"
 	"time"
-
-	"github.com/lixenwraith/log/formatter"
 )
 
+// errFlushStopped answers the flush requests waiting behind a record on_disk_full=block held when the processor stops
+var errFlushStopped = fmtErrorf("logger stopped while a record was held for disk limits, flush not completed")
+
 // processLogs is the main log processing loop running in a separate goroutine
-func (l *Logger) processLogs(ch <-chan logRecord) {
+func (l *Logger) processLogs(q *logQueue) {
 	l.state.ProcessorExited.Store(false)
 	defer l.state.ProcessorExited.Store(true)
 
+	ch := q.records
+
 	// Set up timers and state variables
 	timers := l.setupProcessingTimers()
 	defer l.stopProcessingTimers(timers)
@@ -19,8 +22,8 @@ func (l *Logger) processLogs(ch <-chan logRecord) {
 	c := l.getConfig()
 
 	// Perform an initial disk check on startup (skip if file output is disabled)
-	if c.EnableFile {
-		l.performDiskCheck(true)
+	if c.fileOutput() {
+		l.lockedDiskCheck(true)
 	}
 
 	// Send initial heartbeats immediately instead of waiting for first tick
@@ -42,25 +45,68 @@ func (l *Logger) processLogs(ch <-chan logRecord) {
 	var lastCheckTime = time.Now()
 	var logsSinceLastCheck int64 = 0
 
+	// With on_disk_full=block a record the file cannot take is held here, and later records wait behind it, while
+	// the loop keeps serving timers; flush requests are answered once it is written
+	var held *logRecord
+	var heldRetry <-chan time.Time
+	var heldFlushes []chan FlushResult
+
 	// --- Main Loop ---
 	for {
+		records := ch
+		if held != nil {
+			records = nil
+		}
+
 		select {
-		case record, ok := <-ch:
-			if !ok {
-				l.performSync()
-				return
+		case <-q.done:
+			// No sender can queue anymore, write what is left and exit
+			// Started is already cleared, a held record is written or dropped like any other
+			stoppedHeld := held != nil
+			if held != nil {
+				l.processBatch(*held, ch, 1)
 			}
+			l.drainQueued(ch)
+			l.batchMu.Lock()
+			synced := l.performSync()
+			l.flushOutputs()
+			l.batchMu.Unlock()
+
+			// Flush requests still waiting are answered, failed when they waited behind a held record
+			select {
+			case resultChan := <-l.state.flushRequestChan:
+				heldFlushes = append(heldFlushes, resultChan)
+			default:
+			}
+			for _, resultChan := range heldFlushes {
+				if stoppedHeld {
+					resultChan <- FlushResult{err: errFlushStopped}
+					continue
+				}
+				resultChan <- FlushResult{
+					Records: l.state.recordsSinceFlush.Swap(0),
+					Bytes:   l.state.bytesSinceFlush.Swap(0),
+					Synced:  synced,
+				}
+			}
+			return
 
-			// Process the received log record
-			bytesWritten := l.processLogRecord(record)
+		case record := <-records:
+			// Process the received log record along with any already queued behind it
+			bytesWritten, logsWritten, blocked := l.processBatch(record, ch, maxBatchSize)
+			if blocked != nil {
+				held, heldRetry = blocked, l.heldRetryTimer()
+			}
+			// Falling pressure is observed here when producers are idle
+			l.checkPressure()
 			if bytesWritten > 0 {
 				// Update adaptive check counters
 				bytesSinceLastCheck += bytesWritten
-				logsSinceLastCheck++
+				logsSinceLastCheck += logsWritten
 
 				// Reactive Check Trigger
 				if bytesSinceLastCheck > reactiveCheckThresholdBytes {
-					if l.performDiskCheck(false) {
+					if l.lockedDiskCheck(false) {
 						bytesSinceLastCheck = 0
 						logsSinceLastCheck = 0
 						lastCheckTime = time.Now()
@@ -70,132 +116,370 @@ func (l *Logger) processLogs(ch <-chan logRecord) {
 
 		case <-timers.flushTicker.C:
 			l.handleFlushTick()
+			l.watchHeartbeatIncident(timers)
+			l.mirrorInternalErrors()
 
 		case <-timers.diskCheckTicker.C:
 			// Periodic disk check
-			if l.performDiskCheck(true) {
+			if l.lockedDiskCheck(true) {
 				l.adjustDiskCheckInterval(timers, lastCheckTime, logsSinceLastCheck)
 				bytesSinceLastCheck = 0
 				logsSinceLastCheck = 0
 				lastCheckTime = time.Now()
 			}
 
-		case confirmChan := <-l.state.flushRequestChan:
-			l.handleFlushRequest(confirmChan)
+		case <-heldRetry:
+			// Retried after a disk check, or under the current policy if ApplyConfig changed it
+			l.lockedDiskCheck(true)
+			stopping := !l.state.Started.Load() || l.state.LoggerDisabled.Load()
+			if _, _, held = l.processBatch(*held, ch, 1); held != nil {
+				heldRetry = l.heldRetryTimer()
+				break
+			}
+			heldRetry = nil
+			if stopping && !l.state.DiskStatusOK.Load() {
+				// The held record was dropped for the stopping logger, the flushes waiting behind it fail
+				for _, resultChan := range heldFlushes {
+					resultChan <- FlushResult{err: errFlushStopped}
+				}
+				heldFlushes = nil
+			}
+			for len(heldFlushes) > 0 && held == nil {
+				held = l.handleFlushRequest(heldFlushes[0], ch)
+				if held == nil {
+					heldFlushes = heldFlushes[1:]
+				} else {
+					heldRetry = l.heldRetryTimer()
+				}
+			}
+
+		case resultChan := <-l.state.flushRequestChan:
+			if held != nil {
+				heldFlushes = append(heldFlushes, resultChan)
+				break
+			}
+			if held = l.handleFlushRequest(resultChan, ch); held != nil {
+				heldRetry = l.heldRetryTimer()
+				heldFlushes = append(heldFlushes, resultChan)
+			}
 
 		case <-timers.retentionChan:
 			l.handleRetentionCheck()
 
 		case <-timers.heartbeatChan:
+			l.watchHeartbeatIncident(timers)
 			l.handleHeartbeat()
+			l.settleHeartbeatIncident(timers)
+
+		case <-timers.suppressionChan:
+			l.logSuppressionSummary(timers.suppressionInterval)
 		}
 	}
 }
 
-// processLogRecord handles individual log records and returns bytes written
-func (l *Logger) processLogRecord(record logRecord) int64 {
-	c := l.getConfig()
-	enableFile := c.EnableFile
-	if enableFile && !l.state.DiskStatusOK.Load() {
-		// Simple increment of both counters
-		l.state.DroppedLogs.Add(1)
-		l.state.TotalDroppedLogs.Add(1)
-		return 0
-	}
-
-	// Atomically load formatter instance
-	formatterPtr := l.formatter.Load()
-	if formatterPtr == nil {
-		// Defensive: Should never happen after initialization
-		return 0
-	}
-	f := formatterPtr.(*formatter.Formatter)
-
-	// Format the log entry using atomically-loaded formatter
-	formattedData := f.Format(
-		record.Flags,
-		record.TimeStamp,
-		record.Level,
-		record.Trace,
-		record.Args,
-	)
-	formattedDataLen := int64(len(formattedData))
+// processBatch writes the first record and up to limit-1 records already queued behind it
+// The batch runs under one configuration epoch while holding batchMu, so ApplyConfig cannot swap
+// the formatter, console writer, or log file between records of the batch
+// Returns total bytes written, the number of records written, and the record on_disk_full=block holds, if any
+func (l *Logger) processBatch(first logRecord, ch <-chan logRecord, limit int) (int64, int64, *logRecord) {
+	l.batchMu.Lock()
+	defer l.batchMu.Unlock()
+
+	epoch := l.getEpoch()
+	var bytesWritten, logsWritten int64
+
+	record := first
+	for i := 0; ; i++ {
+		if l.blocksOnDisk(epoch, record) {
+			return bytesWritten, logsWritten, &record
+		}
+		if n := l.processLogRecord(epoch, record); n > 0 {
+			bytesWritten += n
+			logsWritten++
+		}
+		if i+1 >= limit {
+			return bytesWritten, logsWritten, nil
+		}
 
-	// Write to console if enabled
-	enableConsole := c.EnableConsole
-	if enableConsole {
-		if s := l.state.StdoutWriter.Load(); s != nil {
-			if sinkWrapper, ok := s.(*sink); ok && sinkWrapper != nil {
-				// Handle split mode
-				if c.ConsoleTarget == "split" {
-					if record.Level >= LevelWarn {
-						// Write WARN and ERROR to stderr
-						_, _ = os.Stderr.Write(formattedData)
-					} else {
-						// Write INFO and DEBUG to stdout
-						_, _ = sinkWrapper.w.Write(formattedData)
-					}
-				} else {
-					// Write to the configured target (stdout or stderr)
-					_, _ = sinkWrapper.w.Write(formattedData)
-				}
+		select {
+		case record = <-ch:
+		default:
+			return bytesWritten, logsWritten, nil
+		}
+	}
+}
+
+// drainQueued writes the records queued at call time in batches, so a flush covers everything logged before it
+// The processor is the only receiver, the queued records stay available until taken here
+// Returns the record on_disk_full=block holds, the records behind it stay queued
+func (l *Logger) drainQueued(ch <-chan logRecord) *logRecord {
+	defer l.checkPressure()
+	for pending := len(ch); pending > 0; {
+		select {
+		case record := <-ch:
+			batch := min(pending, maxBatchSize)
+			if _, _, held := l.processBatch(record, ch, batch); held != nil {
+				return held
 			}
+			pending -= batch
+		default:
+			return nil
 		}
 	}
+	return nil
+}
 
-	// Skip file operations if file output is disabled
-	if !enableFile {
-		l.state.TotalLogsProcessed.Add(1)
-		return formattedDataLen // Return data length for adaptive interval calculations
+// blocksOnDisk reports whether on_disk_full=block holds the record until disk limits are met
+// A stopping or disabled logger does not hold records, they are dropped as with on_disk_full=drop
+func (l *Logger) blocksOnDisk(epoch *configEpoch, record logRecord) bool {
+	c := epoch.config
+	if c.OnDiskFull != "block" || epoch.file == nil || l.state.DiskStatusOK.Load() {
+		return false
+	}
+	if record.Level < c.FileLevel && record.ack == nil {
+		return false
+	}
+	return l.state.Started.Load() && !l.state.LoggerDisabled.Load()
+}
+
+// heldRetryTimer schedules the next attempt to write the record on_disk_full=block holds
+func (l *Logger) heldRetryTimer() <-chan time.Time {
+	return time.After(time.Duration(l.getConfig().MinCheckIntervalMs) * time.Millisecond)
+}
+
+// processLogRecord handles an individual log record under the given configuration epoch and returns bytes written
+// Audit records receive the write outcome on their acknowledgment channel
+func (l *Logger) processLogRecord(epoch *configEpoch, record logRecord) int64 {
+	n, err := l.writeLogRecord(epoch, record)
+	if record.ack != nil {
+		record.ack <- err
 	}
+	return n
+}
 
-	// File rotation check
-	currentFileSize := l.state.CurrentSize.Load()
-	estimatedSize := currentFileSize + formattedDataLen
+// writeLogRecord formats and writes a record to the console, file, and registered sink outputs
+// Returns bytes written and the file output error that caused the record to be dropped
+func (l *Logger) writeLogRecord(epoch *configEpoch, record logRecord) (int64, error) {
+	record = epoch.enrich(record)
 
-	maxSizeKB := c.MaxSizeKB
-	if maxSizeKB > 0 && estimatedSize > maxSizeKB*sizeMultiplier {
-		if err := l.rotateLogFile(); err != nil {
-			l.internalLog("failed to rotate log file: %v\n", err)
-			// Account for the dropped log that triggered the failed rotation
+	// Sinks, syslog, journald, and GELF are independent of file output health
+	l.dispatchSinks(record)
+	l.writeNetworkOutputs(record)
+	l.triggerExecHook(record)
+
+	l.forwardToErrorFile(record)
+
+	c := epoch.config
+	// Audit records are confirmed by the file output, its level does not apply to them
+	toFile := epoch.file != nil && (record.Level >= c.FileLevel || record.ack != nil)
+	toConsole := epoch.console != nil && record.Level >= c.ConsoleLevel
+	if toFile {
+		l.forwardToMirror(record)
+	}
+
+	// Records on_disk_full=block holds do not reach here until limits are met or the logger stops
+	fileBlocked := toFile && !l.state.DiskStatusOK.Load()
+	// With on_disk_full=stderr the record is written to stderr instead of being dropped
+	fallback := fileBlocked && c.OnDiskFull == "stderr"
+	// Dropped WARN and above stay visible on the console when console_file_drops is enabled
+	mirrorDrop := false
+	if fileBlocked {
+		l.state.fileHealth.failure(errDiskLimit)
+		if fallback {
+			l.state.StderrFallback.Add(1)
+		} else {
+			// Simple increment of both counters
 			l.state.DroppedLogs.Add(1)
-			return 0
+			l.state.TotalDroppedLogs.Add(1)
+			mirrorDrop = toConsole && c.ConsoleFileDrops && record.Level >= LevelWarn
+			// Registered sinks, recent records, mirrored drops, and OnWriteError still receive the record, nothing else
+			// needs it formatted
+			if !l.hasOutputs() && l.getRecent() == nil && !mirrorDrop && l.getWriteError() == nil {
+				return 0, errDiskLimit
+			}
 		}
 	}
 
-	// Write to file
-	cfPtr := l.state.CurrentFile.Load()
-	if currentLogFile, isFile := cfPtr.(*os.File); isFile && currentLogFile != nil {
-		n, err := currentLogFile.Write(formattedData)
-		if err != nil {
-			l.internalLog("failed to write to log file: %v\n", err)
-			l.state.DroppedLogs.Add(1)
-			l.performDiskCheck(true)
-			return 0
+	// Format the log entry using the epoch's formatter, in the file format or heartbeat_format for heartbeats
+	// A custom formatter replaces the file format, heartbeat_format still applies to heartbeats
+	pub := newRecord(record)
+	var formattedData []byte
+	if heartbeat := record.Level >= LevelProc && epoch.heartbeatFormatter != nil; c.CustomFormatter != nil && !heartbeat {
+		formattedData = c.CustomFormatter.FormatRecord(pub)
+	} else {
+		f := epoch.formatter
+		if heartbeat {
+			f = epoch.heartbeatFormatter
+		}
+		formattedData = f.FormatLabeled(
+			record.Flags,
+			record.TimeStamp,
+			record.Level,
+			record.Trace,
+			record.Labels,
+			record.Args,
+		)
+	}
+	formattedDataLen := int64(len(formattedData))
+
+	if recent := l.getRecent(); recent != nil {
+		recent.add(formattedData)
+	}
+	l.writeOutputs(formattedData, pub)
+	if fileBlocked {
+		if fallback {
+			l.writeStderrFallback(epoch, record, formattedData, pub, toConsole)
 		} else {
-			l.state.CurrentSize.Add(int64(n))
-			l.state.TotalLogsProcessed.Add(1)
-			return int64(n)
+			if mirrorDrop {
+				l.writeConsole(epoch, record, formattedData, pub, true)
+			}
+			l.reportWriteError(formattedData, pub, errDiskLimit)
 		}
+		return 0, errDiskLimit
+	}
+
+	// Heartbeats report the distribution and are left out of it
+	if record.Level < LevelProc {
+		l.state.recordSizes.observe(formattedDataLen, record.Args)
+	}
+
+	// Write to console if enabled
+	if toConsole {
+		l.writeConsole(epoch, record, formattedData, pub, false)
+	}
+
+	// Skip file operations if file output is disabled or below its level
+	if !toFile {
+		l.state.TotalLogsProcessed.Add(1)
+		l.state.recordsSinceFlush.Add(1)
+		l.state.bytesSinceFlush.Add(uint64(formattedDataLen))
+		l.state.TotalBytesWritten.Add(uint64(formattedDataLen))
+		return formattedDataLen, nil // Return data length for adaptive interval calculations
+	}
+
+	return epoch.file.write(formattedData, pub)
+}
+
+// writeNetworkOutputs sends a record to the syslog, journald, and GELF outputs, shard processors use their parent's
+// The outputs are read under networkMu, which ApplyConfig holds while closing retired ones
+func (l *Logger) writeNetworkOutputs(record logRecord) {
+	owner := l.loggerCore
+	if owner.parent != nil {
+		owner = owner.parent
+	}
+	epoch := owner.epoch.Load().(*configEpoch)
+	if epoch.syslog == nil && epoch.journald == nil && epoch.gelf == nil {
+		return
+	}
+
+	owner.networkMu.Lock()
+	defer owner.networkMu.Unlock()
+	epoch = owner.epoch.Load().(*configEpoch)
+	if epoch.syslog != nil {
+		epoch.syslog.write(record)
+	}
+	if epoch.journald != nil {
+		epoch.journald.write(record)
+	}
+	if epoch.gelf != nil {
+		epoch.gelf.write(record)
+	}
+}
+
+// writeConsole writes a record to the console, reformatting formattedData when the console uses another format,
+// glyphs, or colored levels; records dropped from the file are tagged "[file-drop]"
+func (l *Logger) writeConsole(epoch *configEpoch, record logRecord, formattedData []byte, pub Record, dropped bool) {
+	c := epoch.config
+	consoleData := formattedData
+	if epoch.consoleFormatter != nil {
+		// Mirrors the formatter: records without flags fall back to the configured ShowLevel
+		showsLevel := record.Flags&FlagShowLevel != 0 || (record.Flags == 0 && c.ShowLevel)
+		glyph := c.ConsoleGlyphs && showsLevel && record.Flags&FlagRaw == 0
+		if glyph || epoch.console.colorsLevel(c) || c.consoleFormat() != c.fileFormat() {
+			flags := record.Flags
+			if glyph {
+				flags &^= FlagShowLevel
+			}
+			consoleData = epoch.consoleFormatter.FormatLabeled(
+				flags,
+				record.TimeStamp,
+				record.Level,
+				record.Trace,
+				record.Labels,
+				record.Args,
+			)
+			if glyph {
+				color := epoch.console.color(record.Level)
+				if epoch.console.colorLine {
+					color = "" // The whole line is colored below
+				}
+				consoleData = appendLevelGlyph(make([]byte, 0, len(consoleData)+16), record.Level, color, consoleData)
+			}
+		}
+	}
+	if color := epoch.console.color(record.Level); color != "" && epoch.console.colorLine && record.Flags&FlagRaw == 0 {
+		consoleData = appendColorLine(make([]byte, 0, len(consoleData)+16), color, consoleData)
+	}
+	if dropped {
+		consoleData = appendConsoleTag(make([]byte, 0, len(consoleData)+24), fileDropTag, epoch.console.color(record.Level), consoleData)
+	}
+	if err := epoch.console.Write(consoleData, pub); err != nil {
+		l.state.consoleHealth.failure(err)
 	} else {
-		l.state.DroppedLogs.Add(1)
-		return 0
+		l.state.consoleHealth.success()
 	}
 }
 
+// writeStderrFallback writes a record the file cannot take to stderr as formatted for the file, tagged "[file-drop]"
+// The console copy is skipped when the console writes the record to stderr too
+func (l *Logger) writeStderrFallback(epoch *configEpoch, record logRecord, formattedData []byte, pub Record, toConsole bool) {
+	color := ""
+	if colorEnabled(epoch.config.ConsoleColor, os.Stderr) {
+		color = levelColor(record.Level)
+	}
+	data := appendConsoleTag(make([]byte, 0, len(formattedData)+24), fileDropTag, color, formattedData)
+	_, _ = os.Stderr.Write(data)
+	if toConsole && !epoch.console.writesStderr(record.Level) {
+		l.writeConsole(epoch, record, formattedData, pub, false)
+	}
+}
+
+// lockedDiskCheck runs a disk check from the main loop, excluded from ApplyConfig output swaps like a batch
+func (l *Logger) lockedDiskCheck(forceCleanup bool) bool {
+	l.batchMu.Lock()
+	defer l.batchMu.Unlock()
+	return l.performDiskCheck(forceCleanup)
+}
+
 // handleFlushTick handles the periodic flush timer tick
 func (l *Logger) handleFlushTick() {
 	c := l.getConfig()
 	enableSync := c.EnablePeriodicSync
 	if enableSync {
+		l.batchMu.Lock()
 		l.performSync()
+		l.flushOutputs()
+		l.batchMu.Unlock()
 	}
 }
 
-// handleFlushRequest handles an explicit flush request
-func (l *Logger) handleFlushRequest(confirmChan chan struct{}) {
-	l.performSync()
-	close(confirmChan)
+// handleFlushRequest handles an explicit flush request and reports write counters since the previous one
+// Records queued before the request are written first; when on_disk_full=block holds one of them the request
+// is left unanswered and the held record returned
+func (l *Logger) handleFlushRequest(resultChan chan FlushResult, ch <-chan logRecord) *logRecord {
+	if held := l.drainQueued(ch); held != nil {
+		return held
+	}
+	l.batchMu.Lock()
+	synced := l.performSync()
+	l.flushOutputs()
+	l.batchMu.Unlock()
+	resultChan <- FlushResult{
+		Records: l.state.recordsSinceFlush.Swap(0),
+		Bytes:   l.state.bytesSinceFlush.Swap(0),
+		Synced:  synced,
+	}
+	return nil
 }
 
 // handleRetentionCheck performs file retention check and cleanup
@@ -212,6 +496,7 @@ func (l *Logger) handleRetentionCheck() {
 					l.updateEarliestFileTime()
 				} else {
 					l.internalLog("failed to clean expired logs: %v\n", err)
+					l.noteInternalError("retention", err)
 				}
 			}
 		} else if !ok || earliest.IsZero() {
@@ -2,9 +2,11 @@ package log
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -37,6 +39,127 @@ func TestLoggerHeartbeat(t *testing.T) {
 	assert.Contains(t, string(content), "uptime_hours")
 	assert.Contains(t, string(content), "processed_logs")
 	assert.Contains(t, string(content), "num_goroutine")
+	// Rates need a previous PROC heartbeat, the startup heartbeat provides it
+	assert.Contains(t, string(content), "logs_per_sec")
+	assert.Contains(t, string(content), "bytes_per_sec")
+
+	assert.Equal(t, 5.0, ratePerSec(30, 20, 2))
+	assert.Equal(t, 0.0, ratePerSec(10, 20, 2), "Counters going back report no rate")
+}
+
+// TestHeartbeatSequences verifies each heartbeat type numbers its records independently and reports the interval
+func TestHeartbeatSequences(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString("format=json", "heartbeat_interval_s=3600"))
+
+	logger.logProcHeartbeat()
+	logger.logProcHeartbeat()
+	logger.logDiskHeartbeat()
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+
+	sequences := make(map[string][]float64)
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		fields, _ := entry["fields"].([]any)
+		values := make(map[string]any)
+		for i := 0; i+1 < len(fields); i += 2 {
+			values[fmt.Sprint(fields[i])] = fields[i+1]
+		}
+		heartbeatType, ok := values["type"].(string)
+		if !ok {
+			continue
+		}
+		assert.Equal(t, 3600.0, values["interval_s"])
+		sequences[heartbeatType] = append(sequences[heartbeatType], values["sequence"].(float64))
+	}
+	assert.Equal(t, []float64{1, 2}, sequences["proc"])
+	assert.Equal(t, []float64{1}, sequences["disk"])
+}
+
+// TestHeartbeatFormat verifies heartbeats are structured per heartbeat_format while other records keep the file format
+func TestHeartbeatFormat(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString("format=txt", "heartbeat_format=json", "heartbeat_interval_s=3600"))
+
+	logger.Info("regular", "user", "alice")
+	logger.logProcHeartbeat()
+	require.NoError(t, logger.Flush(time.Second))
+	require.NoError(t, logger.ApplyConfigString("heartbeat_format=logfmt"))
+	logger.logDiskHeartbeat()
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "INFO regular user alice")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry), "PROC heartbeat should be JSON: %s", lines[1])
+	assert.Equal(t, "PROC", entry["level"])
+	assert.Equal(t, "proc", entry["type"], "Heartbeat fields are flattened")
+	assert.Equal(t, 3600.0, entry["interval_s"])
+
+	assert.Contains(t, lines[2], " level=DISK type=disk sequence=1 ")
+	assert.True(t, strings.HasPrefix(lines[2], "time="))
+
+	assert.Error(t, logger.ApplyConfigString("heartbeat_format=xml"))
+	assert.Error(t, logger.ApplyConfigString("format=binary"), "Binary files cannot interleave structured heartbeats")
+	require.NoError(t, logger.ApplyConfigString("heartbeat_format=", "format=binary"))
+}
+
+// TestHeartbeatIncidentInterval verifies heartbeats speed up while records drop or disk status is not OK and
+// return to their interval after a quiet incident interval
+func TestHeartbeatIncidentInterval(t *testing.T) {
+	logger := NewLogger()
+	defer logger.Shutdown()
+	cfg := DefaultConfig()
+	cfg.EnableConsole = false
+	cfg.HeartbeatLevel = 1
+	cfg.HeartbeatIntervalS = 60
+	cfg.HeartbeatIncidentIntervalS = 5
+	require.NoError(t, logger.ApplyConfig(cfg))
+
+	// The processor is not started, the test drives the timers itself
+	timers := &TimerSet{}
+	logger.setupHeartbeatTimer(timers)
+	defer timers.heartbeatTicker.Stop()
+	assert.Equal(t, 5*time.Second, timers.incidentInterval)
+
+	logger.watchHeartbeatIncident(timers)
+	assert.False(t, logger.state.HeartbeatIncident.Load())
+
+	// A drop starts the incident, the interval it was seen in keeps heartbeats fast
+	logger.state.DroppedLogs.Add(1)
+	logger.watchHeartbeatIncident(timers)
+	assert.True(t, logger.state.HeartbeatIncident.Load())
+	assert.Equal(t, int64(5), logger.heartbeatIntervalS(cfg))
+	logger.state.DroppedLogs.Store(0)
+	logger.settleHeartbeatIncident(timers)
+	assert.True(t, logger.state.HeartbeatIncident.Load())
+
+	// Disk limits extend it
+	logger.state.DiskStatusOK.Store(false)
+	logger.watchHeartbeatIncident(timers)
+	logger.settleHeartbeatIncident(timers)
+	assert.True(t, logger.state.HeartbeatIncident.Load())
+
+	// A quiet interval ends it
+	logger.state.DiskStatusOK.Store(true)
+	logger.watchHeartbeatIncident(timers)
+	logger.settleHeartbeatIncident(timers)
+	assert.False(t, logger.state.HeartbeatIncident.Load())
+	assert.Equal(t, int64(60), logger.heartbeatIntervalS(cfg))
+
+	// An incident interval at or above the regular one leaves heartbeats unchanged
+	cfg.HeartbeatIncidentIntervalS = 60
+	assert.Zero(t, incidentHeartbeatInterval(cfg))
 }
 
 // TestDroppedLogs confirms that the logger correctly tracks dropped logs when the buffer is full
@@ -219,4 +342,86 @@ func TestDroppedLogRecoveryOnDroppedHeartbeat(t *testing.T) {
 
 	// The 'total_dropped_logs' counter should be accurate, reflecting the initial flood (~50) + the one dropped heartbeat
 	assert.True(t, totalDropCount >= float64(floodCount), "Total drop count should be at least the number of flooded logs plus the dropped heartbeat.")
+}
+
+// TestApplyConfigDuringWrites verifies that every record is fully formatted under a single configuration
+// while formats are switched concurrently with logging
+func TestApplyConfigDuringWrites(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+
+	cfg := logger.GetConfig()
+	cfg.Format = "txt"
+	cfg.ShowTimestamp = false
+	cfg.BufferSize = 10000
+	require.NoError(t, logger.ApplyConfig(cfg))
+
+	const writers, perWriter = 4, 500
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			c := logger.GetConfig()
+			if c.Format == "txt" {
+				c.Format = "json"
+			} else {
+				c.Format = "txt"
+			}
+			assert.NoError(t, logger.ApplyConfig(c))
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				logger.Info("concurrent", id, i)
+			}
+		}(w)
+	}
+	wg.Wait()
+	<-done
+	require.NoError(t, logger.Shutdown(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, writers*perWriter, "No record should be lost or split")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "{") {
+			var entry map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(line), &entry), "JSON record should be complete: %s", line)
+			assert.Equal(t, "INFO", entry["level"])
+		} else {
+			assert.True(t, strings.HasPrefix(line, "INFO concurrent "), "Text record should be complete: %s", line)
+		}
+	}
+}
+
+// TestDrainQueuedBatches verifies a drain writes everything queued at call time across several batches
+func TestDrainQueuedBatches(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString("format=txt", "show_timestamp=false"))
+	require.NoError(t, logger.Stop())
+
+	const queued = 2*maxBatchSize + 10
+	q := newLogQueue(queued)
+	for i := range queued {
+		require.True(t, q.send(logRecord{Flags: FlagShowLevel, TimeStamp: time.Now(), Level: LevelInfo, Args: []any{"queued", i}}))
+	}
+	logger.drainQueued(q.records)
+	assert.Empty(t, q.records)
+
+	logger.batchMu.Lock()
+	logger.flushOutputs()
+	logger.batchMu.Unlock()
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, queued)
+	assert.Equal(t, "INFO queued 0", lines[0])
+	assert.Equal(t, fmt.Sprintf("INFO queued %d", queued-1), lines[queued-1])
 }
\ No newline at end of file
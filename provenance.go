@@ -0,0 +1,117 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Configuration sources reported by Config.Provenance
+const (
+	SourceDefault  = "default"  // Default value, never changed
+	SourceFile     = "file"     // Set from a configuration file through Config.Set
+	SourceEnv      = "env"      // Set from environment variables through Config.Set
+	SourceFlag     = "flag"     // Set from a command-line flag registered by Config.RegisterFlags
+	SourceOverride = "override" // Set by ApplyConfigString
+	SourceBuilder  = "builder"  // Set by a Builder method
+	SourceCode     = "code"     // Assigned directly on the Config struct
+)
+
+// provenanceEntry records the source that set a field and the value it set
+type provenanceEntry struct {
+	source string
+	value  string
+}
+
+// configField is a Config field named by its toml key
+type configField struct {
+	key   string
+	value reflect.Value
+}
+
+// fields returns the configuration fields of c in declaration order
+func (c *Config) fields() []configField {
+	rv := reflect.ValueOf(c).Elem()
+	rt := rv.Type()
+	fields := make([]configField, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		key := rt.Field(i).Tag.Get("toml")
+		if key == "" || key == "-" {
+			continue
+		}
+		fields = append(fields, configField{key: key, value: rv.Field(i)})
+	}
+	return fields
+}
+
+// Set applies a single "key" = "value" setting like ApplyConfigString and records source as its origin
+// Configuration loaders use it with SourceFile or SourceEnv so Provenance reports where each value came from
+func (c *Config) Set(key, value, source string) error {
+	if err := applyConfigField(c, key, value); err != nil {
+		return err
+	}
+	for _, field := range c.fields() {
+		if field.key == key {
+			c.recordSource(key, source, field.value)
+			break
+		}
+	}
+	return nil
+}
+
+// recordSource notes source as the origin of the field key currently holding value
+func (c *Config) recordSource(key, source string, value reflect.Value) {
+	if c.provenance == nil {
+		c.provenance = make(map[string]provenanceEntry)
+	}
+	c.provenance[key] = provenanceEntry{source: source, value: fmt.Sprint(value.Interface())}
+}
+
+// recordChanged attributes to source every field differing from its default without a recorded origin
+func (c *Config) recordChanged(source string) {
+	defaults := defaultConfig.fields()
+	for i, field := range c.fields() {
+		if _, ok := c.provenance[field.key]; ok {
+			continue
+		}
+		if !reflect.DeepEqual(field.value.Interface(), defaults[i].value.Interface()) {
+			c.recordSource(field.key, source, field.value)
+		}
+	}
+}
+
+// Provenance reports the source of each configuration field, keyed by toml name
+// Fields without a recorded source are "default" while they hold their default value and "code" otherwise;
+// a recorded field whose value has since been assigned directly is also reported as "code"
+func (c *Config) Provenance() map[string]string {
+	defaults := defaultConfig.fields()
+	provenance := make(map[string]string, len(defaults))
+	for i, field := range c.fields() {
+		value := field.value.Interface()
+		if entry, ok := c.provenance[field.key]; ok && entry.value == fmt.Sprint(value) {
+			provenance[field.key] = entry.source
+		} else if !ok && reflect.DeepEqual(value, defaults[i].value.Interface()) {
+			provenance[field.key] = SourceDefault
+		} else {
+			provenance[field.key] = SourceCode
+		}
+	}
+	return provenance
+}
+
+// ExplainConfig describes the effective configuration, one "key = value (source)" line per field in declaration
+// order, for tracing which layer of configuration set a value
+func (l *Logger) ExplainConfig() string {
+	cfg := l.getConfig()
+	provenance := cfg.Provenance()
+
+	var sb strings.Builder
+	for _, field := range cfg.fields() {
+		value := field.value.Interface()
+		if field.value.Kind() == reflect.String {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&sb, "%s = %v (%s)\n", field.key, value, provenance[field.key])
+	}
+	return sb.String()
+}
\ No newline at end of file
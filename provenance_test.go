@@ -0,0 +1,55 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProvenance verifies each configuration layer is reported as the source of the fields it set
+func TestProvenance(t *testing.T) {
+	cfg := DefaultConfig()
+	require.NoError(t, cfg.Set("name", "fromfile", SourceFile))
+	require.NoError(t, cfg.Set("level", "warn", SourceEnv))
+	cfg.Format = "json"
+	assert.Error(t, cfg.Set("no_such_key", "1", SourceFile))
+
+	provenance := cfg.Provenance()
+	assert.Equal(t, SourceFile, provenance["name"])
+	assert.Equal(t, SourceEnv, provenance["level"])
+	assert.Equal(t, SourceCode, provenance["format"])
+	assert.Equal(t, SourceDefault, provenance["directory"])
+	assert.NotContains(t, provenance, "no_such_key")
+
+	logger := NewLogger()
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfig(cfg))
+	require.NoError(t, logger.ApplyConfigString("level=error"))
+	provenance = logger.GetConfig().Provenance()
+	assert.Equal(t, SourceOverride, provenance["level"])
+	assert.Equal(t, SourceFile, provenance["name"])
+
+	// Assigning another value over a recorded one moves the field to code
+	cfg = logger.GetConfig()
+	cfg.Name = "changed"
+	assert.Equal(t, SourceCode, cfg.Provenance()["name"])
+
+	explain := logger.ExplainConfig()
+	assert.Contains(t, explain, "level = 8 (override)\n")
+	assert.Contains(t, explain, "name = \"fromfile\" (file)\n")
+	assert.Contains(t, explain, "format = \"json\" (code)\n")
+	assert.Contains(t, explain, "extension = \"log\" (default)\n")
+}
+
+// TestBuilderProvenance verifies values set through the builder are attributed to it
+func TestBuilderProvenance(t *testing.T) {
+	logger, err := NewBuilder().Level(LevelDebug).Format("json").Build()
+	require.NoError(t, err)
+	defer logger.Shutdown()
+
+	provenance := logger.GetConfig().Provenance()
+	assert.Equal(t, SourceBuilder, provenance["level"])
+	assert.Equal(t, SourceBuilder, provenance["format"])
+	assert.Equal(t, SourceDefault, provenance["name"])
+}
\ No newline at end of file
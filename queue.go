@@ -0,0 +1,83 @@
+package log
+
+import (
+	"context"
+	"sync"
+)
+
+// errQueueStopped is returned to blocking senders when the queue stops accepting records
+var errQueueStopped = fmtErrorf("log queue stopped")
+
+// logQueue carries records from callers to the processor goroutine
+// The record channel is never closed: senders hold mu for reading while they check stopped and send, so once
+// stop holds the write lock no further record can be queued and the processor drains a final set of records
+type logQueue struct {
+	records  chan logRecord
+	stopping chan struct{} // Closed first on stop, releases senders blocked on a full queue
+	done     chan struct{} // Closed once no sender can queue, the processor drains and exits
+	mu       sync.RWMutex
+	stopped  bool
+	stopOnce sync.Once
+}
+
+// newLogQueue creates a running queue with the given buffer size
+func newLogQueue(size int64) *logQueue {
+	return &logQueue{
+		records:  make(chan logRecord, size),
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// newStoppedQueue returns a queue rejecting every record, used while the logger is not running
+func newStoppedQueue() *logQueue {
+	q := newLogQueue(0)
+	q.stop()
+	return q
+}
+
+// send queues a record without blocking, returns false if the queue is full or stopped
+func (q *logQueue) send(record logRecord) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.stopped {
+		return false
+	}
+	select {
+	case q.records <- record:
+		return true
+	default:
+		return false
+	}
+}
+
+// sendWait queues a record, waiting for buffer space until ctx is done or the queue stops
+func (q *logQueue) sendWait(ctx context.Context, record logRecord) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.stopped {
+		return errQueueStopped
+	}
+	select {
+	case q.records <- record:
+		return nil
+	case <-q.stopping:
+		return errQueueStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop rejects further records and signals the processor once in-flight sends have completed
+// Safe to call more than once
+func (q *logQueue) stop() {
+	q.stopOnce.Do(func() {
+		close(q.stopping)
+		q.mu.Lock()
+		q.stopped = true
+		q.mu.Unlock()
+		close(q.done)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,99 @@
+package log
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// recentBuffer keeps the formatted bytes of the last records in a fixed ring of slots
+// Processors publish through an atomic cursor and per-slot pointers, adding never blocks or takes a lock
+type recentBuffer struct {
+	slots []atomic.Pointer[recentEntry]
+	next  atomic.Uint64 // Records added so far, the next one goes to slot next % len(slots)
+}
+
+// recentEntry is a record kept by recentBuffer, seq detects slots overwritten while reading
+type recentEntry struct {
+	seq  uint64
+	data []byte
+}
+
+// newRecentBuffer creates a buffer keeping size records
+func newRecentBuffer(size int) *recentBuffer {
+	return &recentBuffer{slots: make([]atomic.Pointer[recentEntry], size)}
+}
+
+// add stores a copy of a formatted record, replacing the oldest one when full
+func (b *recentBuffer) add(data []byte) {
+	seq := b.next.Add(1) - 1
+	entry := &recentEntry{seq: seq, data: append([]byte(nil), data...)}
+	b.slots[seq%uint64(len(b.slots))].Store(entry)
+}
+
+// snapshot returns up to n of the newest records, oldest first, or all kept records when n <= 0
+// Records overwritten by concurrent adds while reading are skipped
+func (b *recentBuffer) snapshot(n int) [][]byte {
+	end := b.next.Load()
+	count := uint64(len(b.slots))
+	if n > 0 && uint64(n) < count {
+		count = uint64(n)
+	}
+	count = min(count, end)
+
+	records := make([][]byte, 0, count)
+	for seq := end - count; seq < end; seq++ {
+		entry := b.slots[seq%uint64(len(b.slots))].Load()
+		if entry != nil && entry.seq == seq {
+			records = append(records, entry.data)
+		}
+	}
+	return records
+}
+
+// getRecent returns the buffer of recent records, shard processors use their parent's; nil when disabled
+func (l *Logger) getRecent() *recentBuffer {
+	owner := l.loggerCore
+	if owner.parent != nil {
+		owner = owner.parent
+	}
+	recent, _ := owner.recent.Load().(*recentBuffer)
+	return recent
+}
+
+// configureRecent sizes the recent record buffer to cfg, the newest records are kept across resizes
+func (l *Logger) configureRecent(cfg *Config) {
+	current, _ := l.recent.Load().(*recentBuffer)
+	size := int(cfg.RecentRecords)
+	if size <= 0 {
+		l.recent.Store((*recentBuffer)(nil))
+		return
+	}
+	if current != nil && len(current.slots) == size {
+		return
+	}
+
+	recent := newRecentBuffer(size)
+	if current != nil {
+		for _, data := range current.snapshot(size) {
+			recent.add(data)
+		}
+	}
+	l.recent.Store(recent)
+}
+
+// DumpRecent writes the last n records processed by the logger to w, oldest first, or all kept records when
+// n <= 0, so crash handlers and debug endpoints can show recent context even when file logging is disabled
+// Records are kept as formatted for the log file, up to recent_records; records still queued are not included
+// Returns an error if recent_records is 0 or writing to w fails
+func (l *Logger) DumpRecent(w io.Writer, n int) error {
+	recent := l.getRecent()
+	if recent == nil {
+		return fmtErrorf("recent records are not kept, set recent_records to enable DumpRecent")
+	}
+	for _, data := range recent.snapshot(n) {
+		if _, err := w.Write(data); err != nil {
+			return fmtErrorf("failed to write recent records: %w", err)
+		}
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDumpRecent verifies the newest records are kept without file output and survive a resize
+func TestDumpRecent(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+
+	var buf bytes.Buffer
+	assert.Error(t, logger.DumpRecent(&buf, 0), "Disabled by default")
+
+	require.NoError(t, logger.ApplyConfigString("enable_file=false", "recent_records=3", "format=txt"))
+	for i := 0; i < 5; i++ {
+		logger.Info("record", i)
+	}
+	require.NoError(t, logger.Flush(time.Second))
+
+	require.NoError(t, logger.DumpRecent(&buf, 0))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "record 2")
+	assert.Contains(t, lines[2], "record 4")
+
+	buf.Reset()
+	require.NoError(t, logger.DumpRecent(&buf, 1))
+	assert.Contains(t, buf.String(), "record 4")
+	assert.NotContains(t, buf.String(), "record 3")
+
+	// Growing the buffer keeps what was already collected
+	require.NoError(t, logger.ApplyConfigString("recent_records=10"))
+	buf.Reset()
+	require.NoError(t, logger.DumpRecent(&buf, 0))
+	assert.Equal(t, 3, strings.Count(buf.String(), "record"))
+}
+
+// TestRecentBufferConcurrent verifies concurrent adds never return torn or misordered records
+func TestRecentBufferConcurrent(t *testing.T) {
+	b := newRecentBuffer(8)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				b.add([]byte(fmt.Sprintf("w%d-%d", w, i)))
+			}
+		}(w)
+	}
+	for i := 0; i < 100; i++ {
+		for _, data := range b.snapshot(0) {
+			assert.True(t, strings.HasPrefix(string(data), "w"))
+		}
+	}
+	wg.Wait()
+	assert.Len(t, b.snapshot(0), 8)
+	assert.Len(t, b.snapshot(3), 3)
+}
\ No newline at end of file
@@ -3,15 +3,17 @@ package log
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"time"
+
+	"github.com/lixenwraith/log/formatter"
 )
 
-// getCurrentLogChannel safely retrieves the current log channel
-func (l *Logger) getCurrentLogChannel() chan logRecord {
-	chVal := l.state.ActiveLogChannel.Load()
-	// No defensive nil check required in correct use of initialized logger
-	return chVal.(chan logRecord)
+// getQueue retrieves the current log queue
+func (l *Logger) getQueue() *logQueue {
+	// No defensive nil check required, NewLogger stores a stopped queue
+	return l.state.ActiveQueue.Load().(*logQueue)
 }
 
 // getFlags from config
@@ -30,19 +32,6 @@ func (l *Logger) getFlags() int64 {
 
 // sendLogRecord handles safe sending to the active channel
 func (l *Logger) sendLogRecord(record logRecord) {
-	defer func() {
-		if r := recover(); r != nil {
-			// A panic is only expected when a race condition occurs during shutdown
-			if err, ok := r.(error); ok && err.Error() == "send on closed channel" {
-				// Expected race condition between logging and shutdown
-				l.handleFailedSend()
-			} else {
-				// Unexpected panic, re-throw to surface
-				panic(r)
-			}
-		}
-	}()
-
 	if l.state.ShutdownCalled.Load() ||
 		l.state.LoggerDisabled.Load() ||
 		!l.state.Started.Load() {
@@ -51,15 +40,17 @@ func (l *Logger) sendLogRecord(record logRecord) {
 		return
 	}
 
-	ch := l.getCurrentLogChannel()
+	// Sharded loggers spread records across shard processors
+	if set := l.getShards(); set != nil {
+		set.pick().sendLogRecord(record)
+		return
+	}
 
-	// Non-blocking send
-	select {
-	case ch <- record:
-		// Success
-	default:
+	// Non-blocking send, a queue stopped concurrently rejects the record instead of panicking
+	if !l.getQueue().send(record) {
 		l.handleFailedSend()
 	}
+	l.checkPressure()
 }
 
 // handleFailedSend increments drop counters
@@ -70,6 +61,11 @@ func (l *Logger) handleFailedSend() {
 
 // log handles the core logging logic
 func (l *Logger) log(flags int64, level int64, depth int64, args ...any) {
+	l.logTagged(flags, level, depth, "", args...)
+}
+
+// logTagged handles the core logging logic with an optional console-only component tag
+func (l *Logger) logTagged(flags int64, level int64, depth int64, consoleTag string, args ...any) {
 	// State checks
 	if !l.state.IsInitialized.Load() {
 		return
@@ -85,29 +81,177 @@ func (l *Logger) log(flags int64, level int64, depth int64, args ...any) {
 	}
 
 	// Discard or proceed based on level
-	cfg := l.getConfig()
-	if level < cfg.Level {
+	cfg, ok := l.admits(level, l.labels, args)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	if cfg.measuresCallLatency() {
+		defer l.observeCallLatency(cfg, now)
+	}
+	if l.shedsCall(level) {
+		if cfg.SuppressionSummaryIntervalS > 0 {
+			l.state.CallsShed.Add(1)
+		}
+		l.handleFailedSend()
 		return
 	}
 
-	// Get trace info from runtime
+	// Get trace info from runtime, skipped once the call path exceeded its latency budget
 	// Depth filter hard-coded based on call stack of current package design
 	var trace string
-	if depth > 0 {
-		const skipTrace = 3 // log.Info -> log -> getTrace (Adjust if call stack changes)
+	if depth > 0 && !l.state.CallLatencyDegraded.Load() {
+		const skipTrace = 4 // log.Info -> log -> logTagged -> getTrace (Adjust if call stack changes)
 		trace = getTrace(depth, skipTrace)
 	}
 
+	l.enqueue(cfg, flags, now, level, trace, consoleTag, l.labels, args)
+}
+
+// Ingest queues a record produced elsewhere, such as by another process, keeping its time, level, flags, trace, and
+// labels. Level filtering, bound fields and labels, and field limits apply as for records logged directly; a zero Time
+// is replaced by now
+func (l *Logger) Ingest(record Record) {
+	if !l.state.IsInitialized.Load() || !l.state.Started.Load() {
+		return
+	}
+	labels := mergeLabels(l.labels, record.Labels)
+	cfg, ok := l.admits(record.Level, labels, record.Args)
+	if !ok {
+		return
+	}
+	timestamp := record.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	l.enqueue(cfg, record.Flags, timestamp, record.Level, record.Trace, "", labels, record.Args)
+}
+
+// Enabled reports whether a record at level can pass the level filter, for callers skipping costly argument
+// preparation. With level_overrides_by_field, a level is enabled when some override could admit it
+func (l *Logger) Enabled(level int64) bool {
+	epoch := l.getEpoch()
+	if epoch.levelRoutes != nil {
+		return level >= epoch.levelRoutes.minLevel
+	}
+	return level >= epoch.config.Level
+}
+
+// admits reports whether a record at level passes the level filter, returning the configuration it was checked against
+// Field-based overrides see the labels, the bound fields, and the call arguments
+func (l *Logger) admits(level int64, labels map[string]string, args []any) (*Config, bool) {
+	epoch := l.getEpoch()
+	cfg := epoch.config
+	if epoch.levelRoutes != nil {
+		return cfg, epoch.levelRoutes.allows(level, cfg.Level, labels, l.fields, args)
+	}
+	return cfg, level >= cfg.Level
+}
+
+// enqueue applies bound fields and field limits to an admitted record and sends it to the processor
+func (l *Logger) enqueue(cfg *Config, flags int64, timestamp time.Time, level int64, trace, consoleTag string, labels map[string]string, args []any) {
+	// Qualify the call's keys with the logger's group, bound fields were qualified by With
+	flags, args = l.applyGroup(flags, args)
+
+	// Insert fields bound to this logger after the message
+	if len(l.fields) > 0 {
+		args = l.bindFields(flags, args)
+	}
+
+	// Enforce field limits before the record is queued
+	var keep bool
+	if args, keep = l.applyFieldLimits(cfg, flags, args); !keep {
+		return
+	}
+
+	// Snapshot mutable objects on the caller goroutine before handing off to the processor
+	if cfg.EagerStringify {
+		args = stringifyArgs(args)
+	}
+
 	record := logRecord{
-		Flags:     flags,
-		TimeStamp: time.Now(),
-		Level:     level,
-		Trace:     trace,
-		Args:      args,
+		Flags:      flags,
+		TimeStamp:  timestamp,
+		Level:      level,
+		Trace:      trace,
+		Args:       args,
+		ConsoleTag: consoleTag,
+		Labels:     labels,
 	}
 	l.sendLogRecord(record)
 }
 
+// bindFields returns args with the logger's bound fields inserted after the message arguments, see formatter.MessageLen
+// Structured records get the fields merged into a copy of their field map, raw records are left untouched
+func (l *Logger) bindFields(flags int64, args []any) []any {
+	if flags&FlagRaw != 0 {
+		return args
+	}
+
+	if flags&FlagStructuredJSON != 0 && len(args) == 2 {
+		if fields, ok := args[1].(map[string]any); ok {
+			merged := make(map[string]any, len(fields)+len(l.fields)/2)
+			for i := 0; i+1 < len(l.fields); i += 2 {
+				merged[fmt.Sprint(l.fields[i])] = l.fields[i+1]
+			}
+			// Call-site fields take precedence over bound fields
+			for k, v := range fields {
+				merged[k] = v
+			}
+			return []any{args[0], merged}
+		}
+	}
+
+	// Bound pairs follow the message so formats telling the message apart from the pairs still find it
+	at := formatter.MessageLen(args)
+	bound := make([]any, 0, len(l.fields)+len(args))
+	bound = append(bound, args[:at]...)
+	bound = append(bound, l.fields...)
+	return append(bound, args[at:]...)
+}
+
+// stringifyArgs replaces error and fmt.Stringer arguments with their string values
+// The args slice is copied only if a replacement is made, leaving the caller's slice untouched
+func stringifyArgs(args []any) []any {
+	var out []any
+	for i, arg := range args {
+		var str string
+		switch v := arg.(type) {
+		case time.Time, time.Duration:
+			// Formatted by time_value_format and duration_format, not their String methods
+			continue
+		case error:
+			if isNilPointer(v) {
+				continue
+			}
+			str = v.Error()
+		case fmt.Stringer:
+			if isNilPointer(v) {
+				continue
+			}
+			str = v.String()
+		default:
+			continue
+		}
+		if out == nil {
+			out = make([]any, len(args))
+			copy(out, args)
+		}
+		out[i] = str
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
+
+// isNilPointer reports whether v holds a typed nil pointer, whose methods may panic when called
+func isNilPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
 // internalLog handles writing internal logger diagnostics to stderr if enabled
 func (l *Logger) internalLog(format string, args ...any) {
 	// Check if internal error reporting is enabled
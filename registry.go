@@ -0,0 +1,96 @@
+package log
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Registry maps names to logger instances for multi-tenant applications
+// Static fields attached at registration identify every record logged through the registered logger
+type Registry struct {
+	mu      sync.RWMutex
+	loggers map[string]*Logger
+}
+
+// NewRegistry creates an empty logger registry
+func NewRegistry() *Registry {
+	return &Registry{
+		loggers: make(map[string]*Logger),
+	}
+}
+
+// Register adds a logger under the given name with optional static key-value fields
+// The returned logger shares the provided logger's output and carries the fields on every record
+func (r *Registry) Register(name string, logger *Logger, fields ...any) (*Logger, error) {
+	if name == "" {
+		return nil, fmtErrorf("registry name cannot be empty")
+	}
+	if logger == nil {
+		return nil, fmtErrorf("cannot register nil logger '%s'", name)
+	}
+	if len(fields)%2 != 0 {
+		return nil, fmtErrorf("static fields for '%s' must be key-value pairs, got %d values", name, len(fields))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.loggers[name]; exists {
+		return nil, fmtErrorf("logger '%s' already registered", name)
+	}
+
+	registered := logger
+	if len(fields) > 0 {
+		registered = logger.withFields(fields...)
+	}
+	r.loggers[name] = registered
+	return registered, nil
+}
+
+// Get returns the logger registered under name
+func (r *Registry) Get(name string) (*Logger, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	logger, ok := r.loggers[name]
+	return logger, ok
+}
+
+// Unregister removes a logger from the registry without shutting it down
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.loggers, name)
+}
+
+// Names returns the sorted names of all registered loggers
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.loggers))
+	for name := range r.loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ShutdownAll shuts down every registered logger, loggers sharing an instance are shut down once
+func (r *Registry) ShutdownAll(timeout ...time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs error
+	seen := make(map[*loggerCore]bool, len(r.loggers))
+	for name, logger := range r.loggers {
+		if seen[logger.loggerCore] {
+			continue
+		}
+		seen[logger.loggerCore] = true
+		if err := logger.Shutdown(timeout...); err != nil {
+			errs = errors.Join(errs, fmtErrorf("failed to shut down logger '%s': %w", name, err))
+		}
+	}
+	return errs
+}
\ No newline at end of file
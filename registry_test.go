@@ -0,0 +1,72 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegistryStaticFields verifies that registered loggers carry their static fields on every record
+func TestRegistryStaticFields(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	require.NoError(t, logger.ApplyConfigString("format=json"))
+
+	registry := NewRegistry()
+	defer registry.ShutdownAll()
+
+	tenantA, err := registry.Register("tenant-a", logger, "tenant_id", "a", "shard", 1)
+	require.NoError(t, err)
+	tenantB, err := registry.Register("tenant-b", logger, "tenant_id", "b")
+	require.NoError(t, err)
+
+	tenantA.Info("request handled")
+	tenantB.LogStructured(LevelInfo, "structured", map[string]any{"path": "/"})
+	logger.Info("untagged")
+
+	require.NoError(t, logger.Flush(time.Second))
+
+	var content string
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+		content = string(data)
+		return err == nil && strings.Count(content, "\n") >= 3
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Contains(t, content, `"fields":["request handled","tenant_id","a","shard",1]`)
+	assert.Contains(t, content, `"fields":{"path":"/","tenant_id":"b"}`)
+	assert.Contains(t, content, `"fields":["untagged"]`)
+
+	got, ok := registry.Get("tenant-a")
+	assert.True(t, ok)
+	assert.Same(t, tenantA, got)
+	assert.Equal(t, []string{"tenant-a", "tenant-b"}, registry.Names())
+}
+
+// TestRegistryValidation verifies registration errors
+func TestRegistryValidation(t *testing.T) {
+	logger := NewLogger()
+	registry := NewRegistry()
+
+	_, err := registry.Register("", logger)
+	assert.Error(t, err)
+
+	_, err = registry.Register("nil", nil)
+	assert.Error(t, err)
+
+	_, err = registry.Register("odd", logger, "tenant_id")
+	assert.ErrorContains(t, err, "key-value pairs")
+
+	_, err = registry.Register("dup", logger)
+	require.NoError(t, err)
+	_, err = registry.Register("dup", logger)
+	assert.ErrorContains(t, err, "already registered")
+
+	registry.Unregister("dup")
+	_, ok := registry.Get("dup")
+	assert.False(t, ok)
+}
\ No newline at end of file
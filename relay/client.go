@@ -0,0 +1,428 @@
+package relay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lixenwraith/log"
+	"github.com/lixenwraith/log/formatter"
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// Client defaults
+const (
+	clientQueueSize       = 10000
+	clientBatchSize       = 100
+	clientRetryBackoff    = 500 * time.Millisecond
+	clientMaxRetryBackoff = 30 * time.Second
+	clientTimeout         = 10 * time.Second
+)
+
+// ClientOptions configures a Client
+// Zero values select the defaults noted on each field
+type ClientOptions struct {
+	Network      string        // "tcp" or "unix"; default "tcp"
+	Address      string        // Server address, "host:port" or a socket path; required
+	Formats      []string      // Record formats offered in order of preference; default FormatBinary
+	Filter       log.Filter    // Selects the records sent, nil sends all records except heartbeats
+	QueueSize    int           // Records buffered for delivery, new records are dropped when full; default 10000
+	BatchSize    int           // Largest number of records per batch; default 100
+	RetryBackoff time.Duration // Delay before the first reconnection, doubled up to 30s for each further one; default 500ms
+	Timeout      time.Duration // Dial, negotiation, and write timeout, and the longest Close waits for acks; default 10s
+}
+
+// ClientStats reports the delivery counters of a Client
+type ClientStats struct {
+	Sent      uint64 // Records written to a connection, including resends after reconnecting
+	Acked     uint64 // Records acknowledged by the server
+	Dropped   uint64 // Records dropped because the queue was full or the client closed before delivering them
+	Reconnect uint64 // Connections established after the first
+	LastError error  // Last connection or protocol error, nil if none occurred
+}
+
+// Client is a log.Sink sending records to a relay Server
+// Records are queued by Write without blocking and delivered by the client's own goroutine; records not acknowledged
+// when a connection fails are sent again on the next one, so the server may receive a record more than once
+type Client struct {
+	opts      ClientOptions
+	queue     chan entry
+	formatter *formatter.Formatter // Used by Write only, sink calls are serialized
+	pending   atomic.Int64         // Records queued or awaiting acknowledgment
+	closing   chan struct{}
+	closeOnce sync.Once
+	exited    chan struct{}
+
+	sent      atomic.Uint64
+	acked     atomic.Uint64
+	dropped   atomic.Uint64
+	reconnect atomic.Uint64
+	errMu     sync.Mutex
+	lastErr   error
+}
+
+// entry is a queued record encoded in each offered format
+type entry struct {
+	binary []byte // Binary journal record, nil unless FormatBinary is offered
+	line   []byte // Level varint followed by the formatted line, nil unless FormatLine is offered
+}
+
+// errQueueFull is returned by Write for records dropped because the queue is full
+var errQueueFull = errors.New("relay: client queue full, record dropped")
+
+// errClosed is returned by Write after Close
+var errClosed = errors.New("relay: client closed")
+
+// errSilent reports a connection without frames for heartbeatMisses intervals
+var errSilent = errors.New("relay: no frame from server within the heartbeat timeout")
+
+// NewClient starts a client delivering records to the server at opts.Address, register it with Logger.AddSink
+// Returns an error if the address or a format is invalid
+func NewClient(opts ClientOptions) (*Client, error) {
+	if opts.Network == "" {
+		opts.Network = "tcp"
+	}
+	if opts.Network != "tcp" && opts.Network != "unix" {
+		return nil, fmt.Errorf("relay: invalid network '%s' (use tcp or unix)", opts.Network)
+	}
+	if opts.Address == "" {
+		return nil, fmt.Errorf("relay: address is required")
+	}
+	if len(opts.Formats) == 0 {
+		opts.Formats = []string{FormatBinary}
+	}
+	for _, format := range opts.Formats {
+		if !validFormat(format) {
+			return nil, fmt.Errorf("relay: invalid format '%s' (use %s or %s)", format, FormatBinary, FormatLine)
+		}
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = clientQueueSize
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = clientBatchSize
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = clientRetryBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = clientTimeout
+	}
+
+	c := &Client{
+		opts:      opts,
+		queue:     make(chan entry, opts.QueueSize),
+		formatter: formatter.New(sanitizer.New()).Type("binary"),
+		closing:   make(chan struct{}),
+		exited:    make(chan struct{}),
+	}
+	go c.run()
+	return c, nil
+}
+
+// Write queues a record for delivery, dropping it when the queue is full
+func (c *Client) Write(data []byte, record log.Record) error {
+	if record.Level >= log.LevelProc {
+		return nil
+	}
+	if c.opts.Filter != nil && !c.opts.Filter(record.Level, record.Args) {
+		return nil
+	}
+	select {
+	case <-c.closing:
+		return errClosed
+	default:
+	}
+
+	var e entry
+	if slices.Contains(c.opts.Formats, FormatBinary) {
+		encoded := c.formatter.Format(record.Flags, record.Time, record.Level, record.Trace, record.Args)
+		e.binary = append([]byte(nil), encoded...)
+	}
+	if slices.Contains(c.opts.Formats, FormatLine) {
+		e.line = append(binary.AppendVarint(nil, record.Level), data...)
+	}
+
+	c.pending.Add(1)
+	select {
+	case c.queue <- e:
+		return nil
+	default:
+		c.pending.Add(-1)
+		c.dropped.Add(1)
+		return errQueueFull
+	}
+}
+
+// Flush returns immediately, records are sent as soon as the server's window allows
+// The logger calls Flush from its processor, use Wait to block until records are acknowledged
+func (c *Client) Flush() error {
+	return nil
+}
+
+// Wait blocks until every queued record has been acknowledged by the server or timeout passes
+func (c *Client) Wait(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for c.pending.Load() > 0 {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("relay: %d records not acknowledged within %v", c.pending.Load(), timeout)
+		}
+		select {
+		case <-c.exited:
+			return errClosed
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	return nil
+}
+
+// Close stops the client once queued records are acknowledged or the timeout passes, remaining records are dropped
+func (c *Client) Close() error {
+	err := c.Wait(c.opts.Timeout)
+	if errors.Is(err, errClosed) {
+		err = nil
+	}
+	c.closeOnce.Do(func() { close(c.closing) })
+	<-c.exited
+	return err
+}
+
+// Stats returns the client's delivery counters
+func (c *Client) Stats() ClientStats {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+	return ClientStats{
+		Sent:      c.sent.Load(),
+		Acked:     c.acked.Load(),
+		Dropped:   c.dropped.Load(),
+		Reconnect: c.reconnect.Load(),
+		LastError: c.lastErr,
+	}
+}
+
+// setError records a connection or protocol error
+func (c *Client) setError(err error) {
+	c.errMu.Lock()
+	c.lastErr = err
+	c.errMu.Unlock()
+}
+
+// run connects to the server and delivers records until the client is closed, reconnecting with backoff
+func (c *Client) run() {
+	defer close(c.exited)
+
+	var inflight []entry // Taken from the queue and not yet acknowledged, resent on the next connection
+	backoff := c.opts.RetryBackoff
+	connected := false
+	for {
+		conn, w, err := c.connect()
+		if err == nil {
+			if connected {
+				c.reconnect.Add(1)
+			}
+			connected = true
+			backoff = c.opts.RetryBackoff
+			inflight, err = c.session(conn, w, inflight)
+			conn.Close()
+			if err == nil {
+				break
+			}
+		}
+		c.setError(err)
+		if !c.sleep(backoff) {
+			break
+		}
+		backoff = min(backoff*2, clientMaxRetryBackoff)
+	}
+
+	// Records still held when closing are lost
+	dropped := len(inflight) + len(c.queue)
+	c.dropped.Add(uint64(dropped))
+	c.pending.Add(-int64(dropped))
+}
+
+// sleep waits for d, returning false if the client closes first
+func (c *Client) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-c.closing:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// connect dials the server and negotiates the protocol version and record format
+func (c *Client) connect() (net.Conn, welcome, error) {
+	conn, err := net.DialTimeout(c.opts.Network, c.opts.Address, c.opts.Timeout)
+	if err != nil {
+		return nil, welcome{}, fmt.Errorf("relay: failed to connect to '%s': %w", c.opts.Address, err)
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(c.opts.Timeout))
+	h := hello{minVersion: MinVersion, maxVersion: MaxVersion, formats: c.opts.Formats}
+	if err := writeFrame(conn, frameHello, h.encode()); err != nil {
+		conn.Close()
+		return nil, welcome{}, fmt.Errorf("relay: failed to send hello: %w", err)
+	}
+	typ, payload, err := readFrame(bufio.NewReaderSize(conn, 64), nil)
+	if err != nil {
+		conn.Close()
+		return nil, welcome{}, fmt.Errorf("relay: failed to read welcome: %w", err)
+	}
+	switch typ {
+	case frameWelcome:
+	case frameReject:
+		conn.Close()
+		return nil, welcome{}, fmt.Errorf("%w: %s", ErrRejected, payload)
+	default:
+		conn.Close()
+		return nil, welcome{}, fmt.Errorf("%w: unexpected frame %d during negotiation", errProtocol, typ)
+	}
+	w, err := decodeWelcome(payload)
+	if err == nil && (w.version < MinVersion || w.version > MaxVersion || !slices.Contains(c.opts.Formats, w.format)) {
+		err = fmt.Errorf("%w: server chose version %d and format '%s'", errProtocol, w.version, w.format)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, welcome{}, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, w, nil
+}
+
+// session sends records over a negotiated connection until it fails or the client closes
+// Returns the records still awaiting acknowledgment, and a nil error only when closing
+func (c *Client) session(conn net.Conn, w welcome, inflight []entry) ([]entry, error) {
+	var writeMu sync.Mutex // The reader answers pings while the session writes batches
+	write := func(typ byte, payload []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_ = conn.SetWriteDeadline(time.Now().Add(c.opts.Timeout))
+		return writeFrame(conn, typ, payload)
+	}
+
+	acks := make(chan uint64, 16)
+	failed := make(chan error, 1)
+	done := make(chan struct{}) // Releases the reader blocked on acks once the session ends
+	defer close(done)
+	var lastRecv atomic.Int64
+	lastRecv.Store(time.Now().UnixNano())
+	go func() {
+		r := bufio.NewReader(conn)
+		var buf []byte
+		for {
+			typ, payload, err := readFrame(r, buf)
+			if err != nil {
+				failed <- err
+				return
+			}
+			buf = payload[:0]
+			lastRecv.Store(time.Now().UnixNano())
+			switch typ {
+			case frameAck:
+				p := &payloadReader{data: payload}
+				seq := p.uvarint()
+				if p.err != nil {
+					failed <- p.err
+					return
+				}
+				select {
+				case acks <- seq:
+				case <-done:
+					return
+				}
+			case framePing:
+				if err := write(framePong, nil); err != nil {
+					failed <- err
+					return
+				}
+			}
+			// Pongs only refresh lastRecv, unknown frames are skipped
+		}
+	}()
+
+	window := int(min(w.window, uint64(1<<30)))
+	var acked uint64 // Sequence acknowledged on this connection, inflight[0] is acked+1
+	sent := 0        // Entries of inflight written to this connection
+	lastSend := time.Now()
+	ticker := time.NewTicker(w.heartbeat / 2)
+	defer ticker.Stop()
+
+	for {
+		// Send whatever the window allows
+		for sent < len(inflight) && sent < window {
+			n := min(len(inflight)-sent, c.opts.BatchSize, window-sent)
+			if err := write(frameBatch, encodeBatch(acked+uint64(sent)+1, inflight[sent:sent+n], w.format)); err != nil {
+				return inflight, fmt.Errorf("relay: failed to send batch: %w", err)
+			}
+			sent += n
+			c.sent.Add(uint64(n))
+			lastSend = time.Now()
+		}
+
+		// New records are taken only while the window has room
+		var queue chan entry
+		if len(inflight) < window {
+			queue = c.queue
+		}
+		select {
+		case e := <-queue:
+			inflight = append(inflight, e)
+			for len(inflight)-sent < c.opts.BatchSize && len(inflight) < window && len(c.queue) > 0 {
+				inflight = append(inflight, <-c.queue)
+			}
+
+		case seq := <-acks:
+			if seq <= acked || seq > acked+uint64(sent) {
+				continue
+			}
+			n := int(seq - acked)
+			clear(inflight[:n])
+			inflight = inflight[n:]
+			sent -= n
+			acked = seq
+			c.acked.Add(uint64(n))
+			c.pending.Add(-int64(n))
+
+		case now := <-ticker.C:
+			if now.Sub(time.Unix(0, lastRecv.Load())) > heartbeatMisses*w.heartbeat {
+				return inflight, errSilent
+			}
+			if now.Sub(lastSend) >= w.heartbeat {
+				if err := write(framePing, nil); err != nil {
+					return inflight, fmt.Errorf("relay: failed to send ping: %w", err)
+				}
+				lastSend = now
+			}
+
+		case err := <-failed:
+			return inflight, fmt.Errorf("relay: connection lost: %w", err)
+
+		case <-c.closing:
+			return inflight, nil
+		}
+	}
+}
+
+// encodeBatch serializes records numbered from seq in the negotiated format
+func encodeBatch(seq uint64, entries []entry, format string) []byte {
+	buf := binary.AppendUvarint(nil, seq)
+	buf = binary.AppendUvarint(buf, uint64(len(entries)))
+	for _, e := range entries {
+		data := e.binary
+		if format == FormatLine {
+			data = e.line
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf
+}
\ No newline at end of file
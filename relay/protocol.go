@@ -0,0 +1,240 @@
+// Package relay forwards records between processes over a small versioned wire protocol
+//
+// A Client is a log.Sink that sends the records of one logger to a Server, which writes them into another logger.
+// Every frame is length-prefixed:
+//
+//	uint32  big-endian length of the remainder of the frame
+//	uint8   frame type
+//	        payload
+//
+// A connection starts with the client's Hello, listing the protocol versions and record formats it supports.
+// The server answers with Welcome, carrying the chosen version and format, the window of records the client may
+// send before an acknowledgment, and the heartbeat interval; or with Reject and a reason before closing.
+// The client then sends Batch frames, numbering records from 1 per connection, and the server acknowledges each
+// batch with Ack once its records have been handed to the logger. Acknowledgments are withheld while the server's
+// logger is under queue pressure, so a slow destination slows its clients instead of dropping records.
+// The client sends Ping when it has sent nothing for a heartbeat interval, and each side answers Ping with Pong;
+// either side closes a connection that receives no frame for three intervals.
+//
+// Frame types unknown to a peer are skipped, so later versions can add frames without breaking older peers.
+package relay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Protocol versions
+const (
+	Version1 = 1 // Length-prefixed frames, Hello/Welcome negotiation, batches, cumulative acks, heartbeats
+
+	MinVersion = Version1 // Oldest version this package speaks
+	MaxVersion = Version1 // Newest version this package speaks
+)
+
+// Record formats
+const (
+	FormatBinary = "binary" // Binary journal records, lossless: time, level, flags, trace, and typed arguments
+	FormatLine   = "line"   // Level followed by the line formatted by the client's logger, written verbatim
+)
+
+// Frame types
+const (
+	frameHello   byte = 1
+	frameWelcome byte = 2
+	frameReject  byte = 3
+	frameBatch   byte = 4
+	frameAck     byte = 5
+	framePing    byte = 6
+	framePong    byte = 7
+)
+
+// magic opens every Hello payload
+var magic = [4]byte{'L', 'G', 'R', 'L'}
+
+// maxFrameSize bounds a single frame to detect corrupt length prefixes
+const maxFrameSize = 16 << 20
+
+// heartbeatMisses is the number of heartbeat intervals without a frame after which a connection is closed
+const heartbeatMisses = 3
+
+// ErrRejected is returned when the server refuses a connection during negotiation
+var ErrRejected = errors.New("relay: connection rejected")
+
+// errProtocol reports a malformed frame
+var errProtocol = errors.New("relay: protocol error")
+
+// hello is the client's opening frame
+type hello struct {
+	minVersion byte
+	maxVersion byte
+	formats    []string // In order of preference
+}
+
+// welcome is the server's answer to an accepted hello
+type welcome struct {
+	version   byte
+	format    string
+	window    uint64        // Records the client may send ahead of acknowledgments
+	heartbeat time.Duration // Idle interval after which a Ping is sent
+}
+
+// writeFrame writes a frame of type typ with payload
+func writeFrame(w io.Writer, typ byte, payload []byte) error {
+	if len(payload)+1 > maxFrameSize {
+		return fmt.Errorf("relay: frame of %d bytes exceeds the limit of %d", len(payload)+1, maxFrameSize)
+	}
+	frame := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)+1))
+	frame[4] = typ
+	_, err := w.Write(append(frame, payload...))
+	return err
+}
+
+// readFrame reads the next frame, the payload is valid until buf is reused
+func readFrame(r *bufio.Reader, buf []byte) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:4])
+	if size < 1 || size > maxFrameSize {
+		return 0, nil, fmt.Errorf("%w: invalid frame length %d", errProtocol, size)
+	}
+	if cap(buf) < int(size-1) {
+		buf = make([]byte, size-1)
+	}
+	payload := buf[:size-1]
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	return header[4], payload, nil
+}
+
+// appendString appends a string prefixed by its uvarint length
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// payloadReader decodes the fields of a frame payload, the first malformed field sets err
+type payloadReader struct {
+	data []byte
+	err  error
+}
+
+// byte reads a single byte
+func (p *payloadReader) byte() byte {
+	if p.err != nil || len(p.data) < 1 {
+		p.fail()
+		return 0
+	}
+	b := p.data[0]
+	p.data = p.data[1:]
+	return b
+}
+
+// uvarint reads an unsigned varint
+func (p *payloadReader) uvarint() uint64 {
+	if p.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(p.data)
+	if n <= 0 {
+		p.fail()
+		return 0
+	}
+	p.data = p.data[n:]
+	return v
+}
+
+// bytes reads a uvarint length-prefixed byte string, aliasing the payload
+func (p *payloadReader) bytes() []byte {
+	size := p.uvarint()
+	if p.err != nil || uint64(len(p.data)) < size {
+		p.fail()
+		return nil
+	}
+	b := p.data[:size]
+	p.data = p.data[size:]
+	return b
+}
+
+// fail records a malformed payload
+func (p *payloadReader) fail() {
+	if p.err == nil {
+		p.err = fmt.Errorf("%w: truncated payload", errProtocol)
+	}
+}
+
+// encode serializes a hello payload
+func (h hello) encode() []byte {
+	buf := append([]byte(nil), magic[:]...)
+	buf = append(buf, h.minVersion, h.maxVersion, byte(len(h.formats)))
+	for _, format := range h.formats {
+		buf = appendString(buf, format)
+	}
+	return buf
+}
+
+// decodeHello parses a hello payload
+func decodeHello(payload []byte) (hello, error) {
+	if len(payload) < len(magic) || [4]byte(payload[:4]) != magic {
+		return hello{}, fmt.Errorf("%w: not a relay client", errProtocol)
+	}
+	p := &payloadReader{data: payload[len(magic):]}
+	h := hello{minVersion: p.byte(), maxVersion: p.byte()}
+	count := int(p.byte())
+	for range count {
+		h.formats = append(h.formats, string(p.bytes()))
+	}
+	return h, p.err
+}
+
+// encode serializes a welcome payload
+func (w welcome) encode() []byte {
+	buf := []byte{w.version}
+	buf = appendString(buf, w.format)
+	buf = binary.AppendUvarint(buf, w.window)
+	return binary.AppendUvarint(buf, uint64(w.heartbeat/time.Millisecond))
+}
+
+// decodeWelcome parses a welcome payload
+func decodeWelcome(payload []byte) (welcome, error) {
+	p := &payloadReader{data: payload}
+	w := welcome{version: p.byte(), format: string(p.bytes()), window: p.uvarint()}
+	w.heartbeat = time.Duration(p.uvarint()) * time.Millisecond
+	if p.err == nil && (w.window == 0 || w.heartbeat <= 0) {
+		return w, fmt.Errorf("%w: invalid welcome", errProtocol)
+	}
+	return w, p.err
+}
+
+// negotiate picks the newest common version and the client's most preferred supported format
+// Returns a rejection reason when the peers have no version or format in common
+func negotiate(h hello, formats []string) (welcome, string) {
+	version := min(h.maxVersion, MaxVersion)
+	if version < max(h.minVersion, MinVersion) {
+		return welcome{}, fmt.Sprintf("no common protocol version, server speaks %d-%d", MinVersion, MaxVersion)
+	}
+	for _, format := range h.formats {
+		for _, supported := range formats {
+			if format == supported {
+				return welcome{version: version, format: format}, ""
+			}
+		}
+	}
+	return welcome{}, fmt.Sprintf("no common record format, server accepts %v", formats)
+}
+
+// validFormat reports whether format is a record format of this package
+func validFormat(format string) bool {
+	return format == FormatBinary || format == FormatLine
+}
\ No newline at end of file
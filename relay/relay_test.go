@@ -0,0 +1,181 @@
+package relay
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lixenwraith/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServerLogger creates a started json file logger, returning it with its directory
+func newServerLogger(t *testing.T) (*log.Logger, string) {
+	t.Helper()
+	dir := t.TempDir()
+	logger, err := log.NewBuilder().
+		Directory(dir).
+		Format("json").
+		EnableFile(true).
+		EnableConsole(false).
+		FlushIntervalMs(10).
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, logger.Start())
+	t.Cleanup(func() { logger.Shutdown() })
+	return logger, dir
+}
+
+// startServer runs a server writing into a file logger, returning the logger, its directory, and the server address
+func startServer(t *testing.T, opts ServerOptions) (*log.Logger, string, *Server, string) {
+	t.Helper()
+	logger, dir := newServerLogger(t)
+	server, err := NewServer(logger, opts)
+	require.NoError(t, err)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(ln)
+	t.Cleanup(func() { server.Close() })
+	return logger, dir, server, ln.Addr().String()
+}
+
+// newClientLogger creates a logger without outputs of its own, sending its records through client
+func newClientLogger(t *testing.T, format string, client *Client) *log.Logger {
+	t.Helper()
+	logger, err := log.NewBuilder().
+		Directory(t.TempDir()).
+		Format(format).
+		EnableFile(false).
+		EnableConsole(false).
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, logger.Start())
+	require.NoError(t, logger.AddSink("relay", client))
+	t.Cleanup(func() { logger.Shutdown() })
+	return logger
+}
+
+// TestRelay verifies records keep their level and typed arguments from client logger to server logger
+func TestRelay(t *testing.T) {
+	serverLogger, dir, server, addr := startServer(t, ServerOptions{})
+	client, err := NewClient(ClientOptions{Address: addr})
+	require.NoError(t, err)
+	logger := newClientLogger(t, "txt", client)
+
+	logger.Info("started", "workers", 4)
+	logger.Error("failed", "retry", true)
+	require.NoError(t, logger.Flush(time.Second))
+	require.NoError(t, client.Wait(5*time.Second))
+	require.NoError(t, serverLogger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"level":"INFO","fields":["started","workers",4]`)
+	assert.Contains(t, string(content), `"level":"ERROR","fields":["failed","retry",true]`)
+
+	stats := client.Stats()
+	assert.Equal(t, uint64(2), stats.Acked)
+	assert.Zero(t, stats.Dropped)
+	assert.Equal(t, uint64(2), server.Stats().Records)
+	assert.Equal(t, uint64(1), server.Stats().Connections)
+}
+
+// TestRelayLineFormat verifies lines formatted by the client logger are written verbatim when negotiated
+func TestRelayLineFormat(t *testing.T) {
+	serverLogger, dir, _, addr := startServer(t, ServerOptions{Formats: []string{FormatLine}})
+	client, err := NewClient(ClientOptions{Address: addr, Formats: []string{FormatBinary, FormatLine}})
+	require.NoError(t, err)
+	logger := newClientLogger(t, "txt", client)
+
+	logger.Warn("disk slow", "latency_ms", 250)
+	require.NoError(t, logger.Flush(time.Second))
+	require.NoError(t, client.Wait(5*time.Second))
+	require.NoError(t, serverLogger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `WARN "disk slow" latency_ms 250`)
+}
+
+// TestRelayRejected verifies a client offering no format the server accepts is refused during negotiation
+func TestRelayRejected(t *testing.T) {
+	_, _, server, addr := startServer(t, ServerOptions{Formats: []string{FormatBinary}})
+	client, err := NewClient(ClientOptions{Address: addr, Formats: []string{FormatLine}, RetryBackoff: time.Hour})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.Eventually(t, func() bool {
+		return errors.Is(client.Stats().LastError, ErrRejected)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, client.Stats().LastError.Error(), "no common record format")
+	assert.Equal(t, uint64(1), server.Stats().Rejected)
+}
+
+// TestRelayReconnect verifies records written while the server is unreachable are delivered once it is up
+func TestRelayReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	client, err := NewClient(ClientOptions{Address: addr, RetryBackoff: 20 * time.Millisecond})
+	require.NoError(t, err)
+	logger := newClientLogger(t, "txt", client)
+	logger.Info("queued while down")
+	require.NoError(t, logger.Flush(time.Second))
+	assert.Error(t, client.Wait(50*time.Millisecond))
+
+	serverLogger, dir := newServerLogger(t)
+	server, err := NewServer(serverLogger, ServerOptions{})
+	require.NoError(t, err)
+	ln, err = net.Listen("tcp", addr)
+	require.NoError(t, err)
+	go server.Serve(ln)
+	defer server.Close()
+
+	require.NoError(t, client.Wait(5*time.Second))
+	require.NoError(t, serverLogger.Flush(time.Second))
+	content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "queued while down")
+}
+
+// TestRelayProtocol verifies negotiation, heartbeats, and that unknown frame types are skipped
+func TestRelayProtocol(t *testing.T) {
+	_, _, _, addr := startServer(t, ServerOptions{Window: 50, Heartbeat: time.Second})
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	// A future client offering versions 1 through 3 is answered with the newest common one
+	h := hello{minVersion: 1, maxVersion: 3, formats: []string{"future", FormatBinary}}
+	require.NoError(t, writeFrame(conn, frameHello, h.encode()))
+	typ, payload, err := readFrame(r, nil)
+	require.NoError(t, err)
+	require.Equal(t, frameWelcome, typ)
+	w, err := decodeWelcome(payload)
+	require.NoError(t, err)
+	assert.Equal(t, welcome{version: Version1, format: FormatBinary, window: 50, heartbeat: time.Second}, w)
+
+	require.NoError(t, writeFrame(conn, 99, []byte("from a later version")))
+	require.NoError(t, writeFrame(conn, framePing, nil))
+	typ, _, err = readFrame(r, nil)
+	require.NoError(t, err)
+	assert.Equal(t, framePong, typ)
+
+	// Versions older than the server speaks are refused
+	old, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer old.Close()
+	require.NoError(t, writeFrame(old, frameHello, hello{minVersion: 0, maxVersion: 0, formats: []string{FormatBinary}}.encode()))
+	typ, payload, err = readFrame(bufio.NewReader(old), nil)
+	require.NoError(t, err)
+	assert.Equal(t, frameReject, typ)
+	assert.Contains(t, string(payload), "no common protocol version")
+}
\ No newline at end of file
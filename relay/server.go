@@ -0,0 +1,334 @@
+package relay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lixenwraith/log"
+	"github.com/lixenwraith/log/logreader"
+)
+
+// Server defaults
+const (
+	serverWindow        = 1000
+	serverHeartbeat     = 5 * time.Second
+	serverPressureLimit = 0.8
+	serverTimeout       = 10 * time.Second
+)
+
+// ServerOptions configures a Server
+// Zero values select the defaults noted on each field
+type ServerOptions struct {
+	Formats       []string      // Record formats accepted; default FormatBinary and FormatLine
+	Window        int           // Records a client may send ahead of acknowledgments; default 1000
+	Heartbeat     time.Duration // Idle interval after which clients send a ping; default 5s
+	PressureLimit float64       // Logger queue pressure at which acknowledgments are withheld; default 0.8, 1 disables
+	Timeout       time.Duration // Negotiation and write timeout; default 10s
+}
+
+// ServerStats reports the counters of a Server
+type ServerStats struct {
+	Connections uint64 // Connections currently open
+	Accepted    uint64 // Connections accepted after negotiation
+	Rejected    uint64 // Connections refused during negotiation
+	Records     uint64 // Records received and handed to the logger
+	Invalid     uint64 // Records that could not be decoded and were skipped
+}
+
+// Server receives records from relay clients and writes them into a logger with their original time and level
+type Server struct {
+	logger *log.Logger
+	opts   ServerOptions
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+	done      chan struct{}
+
+	accepted atomic.Uint64
+	rejected atomic.Uint64
+	records  atomic.Uint64
+	invalid  atomic.Uint64
+}
+
+// ErrServerClosed is returned by Serve and ListenAndServe after Close
+var ErrServerClosed = errors.New("relay: server closed")
+
+// NewServer creates a server writing received records into logger
+// Returns an error if logger is nil or a format is invalid
+func NewServer(logger *log.Logger, opts ServerOptions) (*Server, error) {
+	if logger == nil {
+		return nil, fmt.Errorf("relay: logger cannot be nil")
+	}
+	if len(opts.Formats) == 0 {
+		opts.Formats = []string{FormatBinary, FormatLine}
+	}
+	for _, format := range opts.Formats {
+		if !validFormat(format) {
+			return nil, fmt.Errorf("relay: invalid format '%s' (use %s or %s)", format, FormatBinary, FormatLine)
+		}
+	}
+	if opts.Window <= 0 {
+		opts.Window = serverWindow
+	}
+	if opts.Heartbeat <= 0 {
+		opts.Heartbeat = serverHeartbeat
+	}
+	if opts.PressureLimit <= 0 {
+		opts.PressureLimit = serverPressureLimit
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = serverTimeout
+	}
+
+	return &Server{
+		logger:    logger,
+		opts:      opts,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// ListenAndServe listens on the network address and serves clients until Close
+func (s *Server) ListenAndServe(network, address string) error {
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return fmt.Errorf("relay: failed to listen on '%s': %w", address, err)
+	}
+	return s.Serve(ln)
+}
+
+// Serve accepts clients on ln until Close, which also closes ln
+// Always returns a non-nil error, ErrServerClosed after Close
+func (s *Server) Serve(ln net.Listener) error {
+	if !s.track(ln, nil) {
+		ln.Close()
+		return ErrServerClosed
+	}
+	defer s.untrack(ln, nil)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			select {
+			case <-s.done:
+				return ErrServerClosed
+			default:
+			}
+			return fmt.Errorf("relay: failed to accept: %w", err)
+		}
+		if !s.track(nil, conn) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.untrack(nil, conn)
+			s.serveConn(conn)
+		}()
+	}
+}
+
+// Close stops accepting clients, closes open connections, and waits for their records to be handed to the logger
+// Unacknowledged records are sent again by clients when they reconnect
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	var err error
+	for ln := range s.listeners {
+		err = errors.Join(err, ln.Close())
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// Stats returns the server's counters
+func (s *Server) Stats() ServerStats {
+	s.mu.Lock()
+	open := len(s.conns)
+	s.mu.Unlock()
+	return ServerStats{
+		Connections: uint64(open),
+		Accepted:    s.accepted.Load(),
+		Rejected:    s.rejected.Load(),
+		Records:     s.records.Load(),
+		Invalid:     s.invalid.Load(),
+	}
+}
+
+// track registers a listener or connection to be closed by Close, false if the server is already closed
+func (s *Server) track(ln net.Listener, conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if ln != nil {
+		s.listeners[ln] = struct{}{}
+	}
+	if conn != nil {
+		s.conns[conn] = struct{}{}
+	}
+	return true
+}
+
+// untrack removes a listener or connection closed on its own
+func (s *Server) untrack(ln net.Listener, conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ln != nil {
+		delete(s.listeners, ln)
+	}
+	if conn != nil {
+		delete(s.conns, conn)
+		conn.Close()
+	}
+}
+
+// serveConn negotiates with a client, then ingests its batches until the connection ends
+func (s *Server) serveConn(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	write := func(typ byte, payload []byte) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(s.opts.Timeout))
+		return writeFrame(conn, typ, payload)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(s.opts.Timeout))
+	typ, payload, err := readFrame(r, nil)
+	if err != nil || typ != frameHello {
+		s.rejected.Add(1)
+		return
+	}
+	h, err := decodeHello(payload)
+	if err != nil {
+		s.rejected.Add(1)
+		_ = write(frameReject, []byte(err.Error()))
+		return
+	}
+	w, reason := negotiate(h, s.opts.Formats)
+	if reason != "" {
+		s.rejected.Add(1)
+		_ = write(frameReject, []byte(reason))
+		return
+	}
+	w.window = uint64(s.opts.Window)
+	w.heartbeat = s.opts.Heartbeat
+	if err := write(frameWelcome, w.encode()); err != nil {
+		return
+	}
+	s.accepted.Add(1)
+
+	var buf []byte
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(heartbeatMisses * s.opts.Heartbeat))
+		typ, payload, err := readFrame(r, buf)
+		if err != nil {
+			return
+		}
+		buf = payload[:0]
+
+		switch typ {
+		case frameBatch:
+			last, err := s.ingestBatch(payload, w.format)
+			if err != nil {
+				_ = write(frameReject, []byte(err.Error()))
+				return
+			}
+			if !s.awaitPressure(write) {
+				return
+			}
+			if err := write(frameAck, binary.AppendUvarint(nil, last)); err != nil {
+				return
+			}
+		case framePing:
+			if err := write(framePong, nil); err != nil {
+				return
+			}
+		}
+		// Pongs and unknown frames are skipped
+	}
+}
+
+// ingestBatch hands the records of a batch to the logger, returning the sequence number of its last record
+func (s *Server) ingestBatch(payload []byte, format string) (uint64, error) {
+	p := &payloadReader{data: payload}
+	seq := p.uvarint()
+	count := p.uvarint()
+	if p.err != nil || count == 0 {
+		return 0, fmt.Errorf("%w: invalid batch", errProtocol)
+	}
+	for range count {
+		data := p.bytes()
+		if p.err != nil {
+			return 0, p.err
+		}
+		record, ok := decodeRecord(data, format)
+		if !ok {
+			s.invalid.Add(1)
+			continue
+		}
+		s.logger.Ingest(record)
+		s.records.Add(1)
+	}
+	return seq + count - 1, nil
+}
+
+// awaitPressure holds the acknowledgment while the logger's queue is above the pressure limit
+// Pings sent every heartbeat interval meanwhile keep the client from taking the connection for dead
+// Returns false if the server closes or the connection fails while waiting
+func (s *Server) awaitPressure(write func(typ byte, payload []byte) error) bool {
+	lastPing := time.Now()
+	for s.opts.PressureLimit < 1 && s.logger.Pressure() >= s.opts.PressureLimit {
+		select {
+		case <-s.done:
+			return false
+		case now := <-time.After(10 * time.Millisecond):
+			if now.Sub(lastPing) >= s.opts.Heartbeat {
+				if err := write(framePing, nil); err != nil {
+					return false
+				}
+				lastPing = now
+			}
+		}
+	}
+	return true
+}
+
+// decodeRecord converts a received record to a log.Record
+func decodeRecord(data []byte, format string) (log.Record, bool) {
+	if format == FormatLine {
+		level, n := binary.Varint(data)
+		if n <= 0 {
+			return log.Record{}, false
+		}
+		// The line is written as the client formatted it, stamped with its arrival time
+		return log.Record{Level: level, Flags: log.FlagRaw, Args: []any{string(data[n:])}}, true
+	}
+
+	rec, err := logreader.NewBinaryReader(bytes.NewReader(data)).Next()
+	if err != nil {
+		return log.Record{}, false
+	}
+	return log.Record{Time: rec.Time, Level: rec.Level, Flags: rec.Flags, Trace: rec.Trace, Args: rec.Args}, true
+}
\ No newline at end of file
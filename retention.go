@@ -0,0 +1,260 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Reasons an archive is selected for deletion
+const (
+	RetentionReasonAge      = "age"       // Older than retention_period_hrs
+	RetentionReasonSize     = "size"      // Log files exceed max_total_size_kb
+	RetentionReasonDiskFree = "disk_free" // Free disk space is below min_disk_free_kb
+)
+
+// FileInfo describes an archived log file selected for deletion
+type FileInfo struct {
+	Name       string    // File name
+	Path       string    // Full path
+	Size       int64     // Size in bytes
+	ModTime    time.Time // Last modification time
+	Generation uint64    // Rotation generation from the archive name, 0 for archives named without one
+	Reason     string    // RetentionReasonAge, RetentionReasonSize, or RetentionReasonDiskFree
+}
+
+// RetentionPreview lists the archives the next cleanup passes would delete and why, without deleting anything
+// Expired archives are listed first; when disk limits require more space, the oldest remaining archives follow
+// Archives of shard loggers and the error file are included. Returns nil when file output is disabled, a read_only
+// logger previews the directory it inspects
+func (l *Logger) RetentionPreview() ([]FileInfo, error) {
+	var preview []FileInfo
+	var shardErr error
+	l.forEachShard(func(shard *Logger) {
+		files, err := shard.RetentionPreview()
+		if err != nil && shardErr == nil {
+			shardErr = err
+		}
+		preview = append(preview, files...)
+	})
+	if errorFile := l.getErrorFile(); errorFile != nil {
+		files, err := errorFile.RetentionPreview()
+		if err != nil && shardErr == nil {
+			shardErr = err
+		}
+		preview = append(preview, files...)
+	}
+	if mirror := l.getMirror(); mirror != nil {
+		files, err := mirror.RetentionPreview()
+		if err != nil && shardErr == nil {
+			shardErr = err
+		}
+		preview = append(preview, files...)
+	}
+	if shardErr != nil {
+		return nil, shardErr
+	}
+
+	c := l.getConfig()
+	if !c.ReadOnly && (!c.fileOutput() || l.specialFileOutput()) {
+		return preview, nil
+	}
+
+	archives, err := l.listArchives(c.Directory)
+	if os.IsNotExist(err) {
+		return preview, nil
+	}
+	if err != nil {
+		return nil, fmtErrorf("failed to read log directory '%s': %w", c.Directory, err)
+	}
+
+	expired, _ := l.selectExpired(c, archives)
+	preview = append(preview, expired...)
+
+	required, reason, err := l.diskLimitExcess(c)
+	if err != nil {
+		return nil, err
+	}
+	// Expired archives free space too, the size pass only needs the remainder
+	for _, f := range expired {
+		required -= f.Size
+	}
+	if required > 0 {
+		remaining := make([]FileInfo, 0, len(archives))
+		for _, f := range archives {
+			if !containsFile(expired, f.Path) {
+				remaining = append(remaining, f)
+			}
+		}
+		oldest, _ := selectOldest(remaining, required, reason)
+		preview = append(preview, oldest...)
+	}
+	return preview, nil
+}
+
+// listArchives returns the archived log files in dir, oldest first by rotation generation, or the error reading dir
+// Archives named without a generation predate those with one and are ordered by modification time
+func (l *Logger) listArchives(dir string) ([]FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The active log file is never eligible for deletion
+	matcher := l.getFileMatcher()
+	var archives []FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !matcher.isArchive(entry.Name()) {
+			continue
+		}
+		info, errInfo := entry.Info()
+		if errInfo != nil {
+			continue
+		}
+		generation, _ := matcher.archiveGeneration(entry.Name())
+		archives = append(archives, FileInfo{
+			Name:       entry.Name(),
+			Path:       filepath.Join(dir, entry.Name()),
+			Size:       info.Size(),
+			ModTime:    info.ModTime(),
+			Generation: generation,
+		})
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		if archives[i].Generation != archives[j].Generation {
+			return archives[i].Generation < archives[j].Generation
+		}
+		return archives[i].ModTime.Before(archives[j].ModTime)
+	})
+	return archives, nil
+}
+
+// selectExpired returns the archives older than the retention period, and the number skipped because their
+// modification time is implausibly far before the logger started
+func (l *Logger) selectExpired(c *Config, archives []FileInfo) ([]FileInfo, int) {
+	rpDuration := time.Duration(c.RetentionPeriodHrs * float64(time.Hour))
+	if rpDuration <= 0 {
+		return nil, 0
+	}
+	cutoffTime := l.retentionNow().Add(-rpDuration - retentionClockMargin)
+
+	// Modification times far before the logger started indicate a broken clock, not an old file
+	var implausibleBefore time.Time
+	if start, ok := l.state.LoggerStartTime.Load().(time.Time); ok && !start.IsZero() {
+		implausibleBefore = start.Add(-retentionSanityBound)
+	}
+
+	var expired []FileInfo
+	skipped := 0
+	for _, f := range archives {
+		if f.ModTime.Before(implausibleBefore) {
+			skipped++
+			continue
+		}
+		if f.ModTime.Before(cutoffTime) {
+			f.Reason = RetentionReasonAge
+			expired = append(expired, f)
+		}
+	}
+	return expired, skipped
+}
+
+// selectOldest returns the oldest archives freeing at least required bytes, or all of them when required is 0
+// Returns the selection and the bytes it frees
+func selectOldest(archives []FileInfo, required int64, reason string) ([]FileInfo, int64) {
+	var selected []FileInfo
+	var freed int64
+	for _, f := range archives {
+		if required > 0 && freed >= required {
+			break
+		}
+		f.Reason = reason
+		selected = append(selected, f)
+		freed += f.Size
+	}
+	return selected, freed
+}
+
+// diskLimitExcess returns the bytes the disk limits of c require freeing, zero when they are met, and the limit
+// requiring the most
+func (l *Logger) diskLimitExcess(c *Config) (int64, string, error) {
+	maxTotal := c.MaxTotalSizeKB * sizeMultiplier
+	minFreeRequired := c.MinDiskFreeKB * sizeMultiplier
+	if maxTotal <= 0 && minFreeRequired <= 0 {
+		return 0, "", nil
+	}
+
+	// Checked with either limit set, an unusable directory fails the disk check
+	freeSpace, err := l.getDiskFreeSpace(c.Directory)
+	if err != nil {
+		return 0, "", fmtErrorf("failed to check free disk space for '%s': %w", c.Directory, err)
+	}
+	var required int64
+	var reason string
+	if minFreeRequired > 0 && freeSpace < minFreeRequired {
+		required, reason = minFreeRequired-freeSpace, RetentionReasonDiskFree
+	}
+
+	if maxTotal > 0 {
+		dirSize, err := l.getLogDirSize(c.Directory)
+		if err != nil {
+			return 0, "", fmtErrorf("failed to check log directory size for '%s': %w", c.Directory, err)
+		}
+		if amountOver := dirSize - maxTotal; amountOver > 0 && amountOver > required {
+			required, reason = amountOver, RetentionReasonSize
+		}
+	}
+	return required, reason, nil
+}
+
+// removeArchives deletes the given archives, or only reports them when retention_dry_run is enabled
+// Returns the bytes freed, counting reported archives in dry-run mode
+func (l *Logger) removeArchives(c *Config, archives []FileInfo) int64 {
+	var freed int64
+	for _, f := range archives {
+		if c.RetentionDryRun {
+			l.reportDryRun(f)
+			freed += f.Size
+			continue
+		}
+		if err := os.Remove(f.Path); err != nil {
+			l.internalLog("failed to remove log file '%s': %v\n", f.Path, err)
+			l.noteInternalError("retention", err)
+			continue
+		}
+		freed += f.Size
+		l.state.TotalDeletions.Add(1)
+		l.state.dirSize.removed(f.Size)
+	}
+	return freed
+}
+
+// reportDryRun logs an archive retention_dry_run kept, once per archive and reason
+func (l *Logger) reportDryRun(f FileInfo) {
+	if _, reported := l.state.dryRunReported.LoadOrStore(f.Reason+":"+f.Path, true); reported {
+		return
+	}
+	l.sendLogRecord(logRecord{
+		Flags:     FlagDefault,
+		TimeStamp: time.Now(),
+		Level:     LevelWarn,
+		Args: []any{"Retention dry run, log file would be deleted",
+			"file", f.Name,
+			"reason", f.Reason,
+			"size", f.Size,
+			"modified", f.ModTime.UTC().Format(time.RFC3339),
+		},
+	})
+}
+
+// containsFile reports whether files includes path
+func containsFile(files []FileInfo, path string) bool {
+	for _, f := range files {
+		if f.Path == path {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
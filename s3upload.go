@@ -0,0 +1,428 @@
+package log
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errS3QueueFull is recorded for archives left local because the upload queue is full
+var errS3QueueFull = fmtErrorf("s3 upload queue full, archive kept local")
+
+// s3Settings are the configuration values an archive uploader is built from
+type s3Settings struct {
+	endpoint  string
+	bucket    string
+	region    string
+	prefix    string
+	keepLocal bool
+	name      string // Logger name, substituted for {name} in the prefix
+}
+
+// s3Upload is an archive waiting for upload, rotated is the time the key prefix is expanded with
+type s3Upload struct {
+	path    string
+	rotated time.Time
+}
+
+// s3Uploader uploads rotated archives to an S3-compatible bucket on its own goroutine
+// Archives are queued by rotation without blocking; failed uploads are retried with backoff, then kept local
+type s3Uploader struct {
+	settings s3Settings
+	host     string
+	client   *http.Client
+	queue    chan s3Upload
+	ctx      context.Context // Cancelled on close, aborting the running upload and its retries
+	cancel   context.CancelFunc
+	draining chan struct{} // Closed by drain, the goroutine exits once the queue is empty
+	exited   chan struct{}
+	drained  sync.Once
+	closed   sync.Once
+	uploaded atomic.Uint64
+	failed   atomic.Uint64
+	pending  atomic.Int64
+	status   healthTracker
+}
+
+// s3Credentials are the keys requests are signed with
+type s3Credentials struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// configureS3Upload returns the archive uploader for cfg, reusing current when its settings are unchanged
+// Returns nil when archive upload is disabled
+func configureS3Upload(cfg *Config, current *s3Uploader) *s3Uploader {
+	if !cfg.S3Upload || cfg.ReadOnly {
+		return nil
+	}
+
+	settings := s3Settings{
+		endpoint:  strings.TrimRight(cfg.S3Endpoint, "/"),
+		bucket:    cfg.S3Bucket,
+		region:    cfg.S3Region,
+		prefix:    cfg.S3Prefix,
+		keepLocal: cfg.S3KeepLocal,
+		name:      cfg.Name,
+	}
+	if current != nil && current.settings == settings {
+		return current
+	}
+
+	host, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+	u := &s3Uploader{
+		settings: settings,
+		host:     host,
+		client:   &http.Client{},
+		queue:    make(chan s3Upload, s3UploadQueueSize),
+		ctx:      ctx,
+		cancel:   cancel,
+		draining: make(chan struct{}),
+		exited:   make(chan struct{}),
+	}
+	go u.run()
+	return u
+}
+
+// validateS3Upload checks the archive upload settings of cfg
+func validateS3Upload(c *Config) error {
+	if !c.S3Upload {
+		return nil
+	}
+	endpoint, err := url.Parse(c.S3Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return fmtErrorf("invalid s3_endpoint '%s': use an http or https URL", c.S3Endpoint)
+	}
+	if strings.TrimSpace(c.S3Bucket) == "" || strings.Contains(c.S3Bucket, "/") {
+		return fmtErrorf("invalid s3_bucket '%s'", c.S3Bucket)
+	}
+	if strings.TrimSpace(c.S3Region) == "" {
+		return fmtErrorf("s3_region cannot be empty")
+	}
+	return nil
+}
+
+// enqueue queues a rotated archive for upload without blocking rotation
+func (u *s3Uploader) enqueue(path string, rotated time.Time) {
+	u.pending.Add(1)
+	select {
+	case u.queue <- s3Upload{path: path, rotated: rotated}:
+	default:
+		u.pending.Add(-1)
+		u.failed.Add(1)
+		u.status.failure(errS3QueueFull)
+	}
+}
+
+// enqueueLeftover queues archives a previous run left local, oldest first, as far as the queue has room
+// The rest stay local until upload starts again
+func (u *s3Uploader) enqueueLeftover(archives []FileInfo) {
+	for _, archive := range archives {
+		u.pending.Add(1)
+		select {
+		case u.queue <- s3Upload{path: archive.Path, rotated: archive.ModTime}:
+		default:
+			u.pending.Add(-1)
+			return
+		}
+	}
+}
+
+// drain uploads the queued archives until the queue is empty or timeout passes, then closes the uploader
+// Returns the archives not uploaded, they stay local
+func (u *s3Uploader) drain(timeout time.Duration) []s3Upload {
+	u.drained.Do(func() { close(u.draining) })
+	select {
+	case <-u.exited:
+	case <-time.After(timeout):
+	}
+	return u.close()
+}
+
+// close stops the upload goroutine, aborting a running upload
+// Returns the archives not uploaded, they stay local and can be handed to a replacing uploader
+func (u *s3Uploader) close() []s3Upload {
+	var left []s3Upload
+	u.closed.Do(func() {
+		u.cancel()
+		<-u.exited
+		for {
+			select {
+			case job := <-u.queue:
+				left = append(left, job)
+			default:
+				u.pending.Store(0)
+				return
+			}
+		}
+	})
+	return left
+}
+
+// health reports upload health of the uploader
+func (u *s3Uploader) health(name string) SinkHealth {
+	return u.status.snapshot(name)
+}
+
+// run uploads queued archives one at a time until closed, or until the queue is empty once draining
+func (u *s3Uploader) run() {
+	defer close(u.exited)
+	for {
+		select {
+		case <-u.ctx.Done():
+			return
+		case job := <-u.queue:
+			if !u.process(job) {
+				return
+			}
+		case <-u.draining:
+			select {
+			case job := <-u.queue:
+				if !u.process(job) {
+					return
+				}
+			default:
+				return
+			}
+		}
+	}
+}
+
+// process uploads one archive and records the outcome, returns false when close aborted it
+func (u *s3Uploader) process(job s3Upload) bool {
+	err := u.uploadWithRetry(job)
+	if u.ctx.Err() != nil {
+		// Aborted, hand the archive back with the rest of the queue
+		select {
+		case u.queue <- job:
+		default:
+		}
+		return false
+	}
+	u.pending.Add(-1)
+	if err != nil {
+		u.failed.Add(1)
+		u.status.failure(err)
+		return true
+	}
+	u.uploaded.Add(1)
+	u.status.success()
+	return true
+}
+
+// uploadWithRetry uploads an archive, retrying transport errors, 429, and 5xx responses with exponential backoff
+// The local copy is removed after a successful upload unless keep-local is set
+func (u *s3Uploader) uploadWithRetry(job s3Upload) error {
+	key := u.objectKey(job)
+	backoff := s3UploadRetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := u.upload(job.path, key)
+		if err == nil {
+			break
+		}
+		err = fmtErrorf("failed to upload '%s' to s3: %w", filepath.Base(job.path), err)
+		if !retry || attempt >= s3UploadMaxRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff = min(backoff*2, s3UploadMaxBackoff)
+		case <-u.ctx.Done():
+			return err
+		}
+	}
+
+	if !u.settings.keepLocal {
+		if err := os.Remove(job.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmtErrorf("uploaded '%s' but failed to remove local copy: %w", filepath.Base(job.path), err)
+		}
+	}
+	return nil
+}
+
+// objectKey expands the prefix template for an archive and appends its file name
+func (u *s3Uploader) objectKey(job s3Upload) string {
+	t := job.rotated.UTC()
+	prefix := strings.NewReplacer(
+		"{name}", u.settings.name,
+		"{host}", u.host,
+		"{yyyy}", t.Format("2006"),
+		"{mm}", t.Format("01"),
+		"{dd}", t.Format("02"),
+		"{hh}", t.Format("15"),
+	).Replace(u.settings.prefix)
+	return strings.TrimLeft(prefix, "/") + filepath.Base(job.path)
+}
+
+// upload sends one signed PUT request with the archive as body, reporting whether a failure is worth retrying
+func (u *s3Uploader) upload(path, key string) (bool, error) {
+	creds := s3Credentials{
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.accessKey == "" || creds.secretKey == "" {
+		return true, fmtErrorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are not set")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		// A removed archive cannot succeed on retry
+		return !errors.Is(err, os.ErrNotExist), err
+	}
+	defer file.Close()
+
+	// The payload hash is part of the signature, hash the file before sending it
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return true, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return true, err
+	}
+
+	ctx, cancel := context.WithTimeout(u.ctx, s3UploadTimeout)
+	defer cancel()
+
+	target := u.settings.endpoint + "/" + s3EscapePath(u.settings.bucket+"/"+key)
+	// An empty archive needs NoBody, a zero length reader would be sent chunked
+	var body io.Reader = http.NoBody
+	if size > 0 {
+		body = io.NopCloser(file)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, body)
+	if err != nil {
+		return false, err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	signS3Request(req, hex.EncodeToString(hash.Sum(nil)), creds, u.settings.region, time.Now())
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return u.ctx.Err() == nil, err
+	}
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmtErrorf("endpoint responded %s", resp.Status)
+	default:
+		return false, fmtErrorf("endpoint rejected upload: %s", resp.Status)
+	}
+}
+
+// signS3Request adds AWS Signature Version 4 headers to req, signing the host and every header already set
+// payloadHash is the hex SHA-256 of the body; the request path must already be escaped with s3EscapePath
+func signS3Request(req *http.Request, payloadHash string, creds s3Credentials, region string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	day := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+
+	var canonical strings.Builder
+	canonical.WriteString(req.Method + "\n")
+	canonical.WriteString(req.URL.EscapedPath() + "\n")
+	canonical.WriteString(req.URL.RawQuery + "\n")
+	for _, k := range names {
+		canonical.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonical.WriteString("\n" + signedHeaders + "\n" + payloadHash)
+
+	scope := day + "/" + region + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonical.String()))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data under key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3EscapePath percent-encodes an object path as S3 signs it: everything except unreserved characters and '/'
+func s3EscapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte("0123456789ABCDEF"[c>>4])
+		b.WriteByte("0123456789ABCDEF"[c&15])
+	}
+	return b.String()
+}
+
+// s3UploadArgs returns DISK heartbeat fields counting archive uploads of the logger and its shards
+// Returns nil when archive upload is disabled
+func (l *Logger) s3UploadArgs() []any {
+	var uploaders []*s3Uploader
+	if u := l.getEpoch().s3; u != nil {
+		uploaders = append(uploaders, u)
+	}
+	l.forEachShard(func(shard *Logger) {
+		if u := shard.getEpoch().s3; u != nil {
+			uploaders = append(uploaders, u)
+		}
+	})
+	if len(uploaders) == 0 {
+		return nil
+	}
+
+	var uploaded, failed uint64
+	var pending int64
+	for _, u := range uploaders {
+		uploaded += u.uploaded.Load()
+		failed += u.failed.Load()
+		pending += u.pending.Load()
+	}
+	return []any{"s3_uploaded", uploaded, "s3_upload_failed", failed, "s3_upload_pending", pending}
+}
\ No newline at end of file
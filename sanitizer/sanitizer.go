@@ -5,6 +5,7 @@ package sanitizer
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"unicode"
@@ -239,6 +240,24 @@ func (se *Serializer) WriteString(buf *[]byte, s string) {
 	}
 }
 
+// WriteRawJSON writes serialized JSON: verbatim in json, compacted when it spans several lines, and as a string in
+// every other format or when data is not valid JSON
+func (se *Serializer) WriteRawJSON(buf *[]byte, data []byte) {
+	if se.format == "json" {
+		if bytes.ContainsAny(data, "\n\r") {
+			var compact bytes.Buffer
+			if json.Compact(&compact, data) == nil {
+				*buf = append(*buf, compact.Bytes()...)
+				return
+			}
+		} else if json.Valid(data) {
+			*buf = append(*buf, data...)
+			return
+		}
+	}
+	se.WriteString(buf, string(data))
+}
+
 // WriteNumber writes a number value
 func (se *Serializer) WriteNumber(buf *[]byte, n string) {
 	*buf = append(*buf, n...)
@@ -249,14 +268,30 @@ func (se *Serializer) WriteBool(buf *[]byte, b bool) {
 	*buf = strconv.AppendBool(*buf, b)
 }
 
-// WriteNil writes a nil value
+// WriteNil writes a nil value: null in json, nil in every other format
 func (se *Serializer) WriteNil(buf *[]byte) {
 	switch se.format {
-	case "raw":
-		*buf = append(*buf, "nil"...)
-	default:
+	case "json":
 		*buf = append(*buf, "null"...)
+	default:
+		*buf = append(*buf, "nil"...)
+	}
+}
+
+// WriteArray writes n items as an array: comma separated in json, space separated in every other format
+func (se *Serializer) WriteArray(buf *[]byte, n int, writeItem func(i int)) {
+	sep := byte(' ')
+	if se.format == "json" {
+		sep = ','
+	}
+	*buf = append(*buf, '[')
+	for i := range n {
+		if i > 0 {
+			*buf = append(*buf, sep)
+		}
+		writeItem(i)
 	}
+	*buf = append(*buf, ']')
 }
 
 // WriteComplex writes complex types
@@ -188,6 +188,11 @@ func TestSerializer(t *testing.T) {
 		buf = nil
 		jsonHandler.WriteNil(&buf)
 		assert.Equal(t, "null", string(buf))
+
+		txtHandler := NewSerializer("txt", san)
+		buf = nil
+		txtHandler.WriteNil(&buf)
+		assert.Equal(t, "nil", string(buf))
 	})
 }
 
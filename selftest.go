@@ -0,0 +1,192 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SelfTestReport is the outcome of SelfTest, listing each exercised path in order
+type SelfTestReport struct {
+	OK        bool           // All steps succeeded
+	Directory string         // Isolated directory the test ran in, removed afterwards
+	Duration  time.Duration  // Total run time
+	Steps     []SelfTestStep // Steps up to and including the first failure, followed by teardown
+}
+
+// SelfTestStep reports one exercised path
+type SelfTestStep struct {
+	Name     string        // "setup", "disk_check", "write", "sync", "rotate", "cleanup", or "teardown"
+	OK       bool          // Step succeeded
+	Duration time.Duration // Step run time
+	Detail   string        // What was observed, e.g. bytes written or free space
+	Error    string        // Failure reason, empty on success
+}
+
+// SelfTest exercises disk check, write, sync, rotation, and cleanup on a temporary logger in an isolated
+// subdirectory of the configured directory, then removes it. The logger itself and its files are not touched
+// Intended for deployment preflight checks before a host takes traffic
+func (l *Logger) SelfTest() SelfTestReport {
+	start := time.Now()
+	cfg := l.getConfig()
+	report := SelfTestReport{OK: true}
+
+	step := func(name string, fn func() (string, error)) bool {
+		stepStart := time.Now()
+		detail, err := fn()
+		s := SelfTestStep{Name: name, OK: err == nil, Duration: time.Since(stepStart), Detail: detail}
+		if err != nil {
+			s.Error = err.Error()
+			report.OK = false
+		}
+		report.Steps = append(report.Steps, s)
+		return err == nil
+	}
+
+	var probe *Logger
+	marker := fmt.Sprintf("selftest %d %d", os.Getpid(), start.UnixNano())
+
+	ok := step("setup", func() (string, error) {
+		if err := os.MkdirAll(cfg.Directory, 0755); err != nil {
+			return "", fmtErrorf("failed to create log directory '%s': %w", cfg.Directory, err)
+		}
+		dir, err := os.MkdirTemp(cfg.Directory, selfTestDirPattern)
+		if err != nil {
+			return "", fmtErrorf("failed to create self-test directory in '%s': %w", cfg.Directory, err)
+		}
+		report.Directory = dir
+
+		probe = NewLogger()
+		if err := probe.ApplyConfig(selfTestConfig(cfg, dir)); err != nil {
+			return "", err
+		}
+		if err := probe.Start(); err != nil {
+			return "", err
+		}
+		return dir, nil
+	})
+
+	// Checked before writing, a failing disk check makes the logger drop records
+	ok = ok && step("disk_check", func() (string, error) {
+		free, err := probe.getDiskFreeSpace(report.Directory)
+		if err != nil {
+			return "", fmtErrorf("failed to check free disk space: %w", err)
+		}
+		detail := strconv.FormatInt(free/sizeMultiplier, 10) + " KB free"
+		if !probe.lockedDiskCheck(false) {
+			return detail, fmtErrorf("disk check failed, min_disk_free_kb is %d", cfg.MinDiskFreeKB)
+		}
+		return detail, nil
+	})
+
+	ok = ok && step("write", func() (string, error) {
+		probe.Info(marker)
+		result, err := probe.FlushStats(selfTestTimeout)
+		if err != nil {
+			return "", err
+		}
+		if result.Records != 1 {
+			return "", fmtErrorf("expected 1 record written, got %d", result.Records)
+		}
+		content, err := os.ReadFile(probe.getStaticLogFilePath())
+		if err != nil {
+			return "", fmtErrorf("failed to read back log file: %w", err)
+		}
+		if !bytes.Contains(content, []byte(marker)) {
+			return "", fmtErrorf("written record not found in '%s'", probe.getStaticLogFilePath())
+		}
+		return strconv.FormatUint(result.Bytes, 10) + " bytes", nil
+	})
+
+	ok = ok && step("sync", func() (string, error) {
+		result, err := probe.FlushStats(selfTestTimeout)
+		if err != nil {
+			return "", err
+		}
+		if !result.Synced {
+			return "", fmtErrorf("log file was not synced")
+		}
+		return "", nil
+	})
+
+	ok = ok && step("rotate", func() (string, error) {
+		probe.batchMu.Lock()
+		err := probe.rotateLogFile(rotationTriggerSelfTest)
+		probe.batchMu.Unlock()
+		if err != nil {
+			return "", err
+		}
+		count, err := probe.getLogFileCount(report.Directory)
+		if err != nil {
+			return "", err
+		}
+		if count != 2 {
+			return "", fmtErrorf("expected active and archived log files after rotation, found %d", count)
+		}
+		return "", nil
+	})
+
+	_ = ok && step("cleanup", func() (string, error) {
+		probe.batchMu.Lock()
+		err := probe.cleanOldLogs(0, "")
+		probe.batchMu.Unlock()
+		if err != nil {
+			return "", err
+		}
+		count, err := probe.getLogFileCount(report.Directory)
+		if err != nil {
+			return "", err
+		}
+		if count != 1 {
+			return "", fmtErrorf("expected only the active log file after cleanup, found %d", count)
+		}
+		return "", nil
+	})
+
+	if report.Directory != "" {
+		step("teardown", func() (string, error) {
+			var shutdownErr error
+			if probe != nil {
+				shutdownErr = probe.Shutdown(selfTestTimeout)
+			}
+			if err := os.RemoveAll(report.Directory); err != nil {
+				return "", fmtErrorf("failed to remove self-test directory: %w", err)
+			}
+			return "", shutdownErr
+		})
+	}
+
+	report.Duration = time.Since(start)
+	return report
+}
+
+// selfTestConfig derives the probe configuration: file output only, in dir, keeping the format and disk limits
+func selfTestConfig(cfg *Config, dir string) *Config {
+	probeCfg := cfg.Clone()
+	probeCfg.Directory = dir
+	probeCfg.Name = "selftest"
+	probeCfg.Level = LevelInfo
+	probeCfg.LevelOverridesByField = nil
+	probeCfg.EnableFile = true
+	probeCfg.EnableConsole = false
+	probeCfg.EnableSyslog = false
+	probeCfg.EnableGELF = false
+	probeCfg.EnableJournal = false
+	probeCfg.S3Upload = false
+	probeCfg.Shards = 0
+	probeCfg.SplitErrorFile = false
+	probeCfg.MirrorDirectory = ""
+	probeCfg.RecentRecords = 0
+	probeCfg.OnErrorExec = ""
+	probeCfg.HeartbeatLevel = 0
+	probeCfg.MaxTotalSizeKB = 0
+	probeCfg.RetentionPeriodHrs = 0
+	probeCfg.RetentionDryRun = false
+	probeCfg.InternalErrorsToStderr = false
+	probeCfg.InternalErrorsToLog = false
+	probeCfg.FlushOnExit = false
+	probeCfg.OnDiskFull = "drop"
+	return probeCfg
+}
\ No newline at end of file
@@ -0,0 +1,59 @@
+package log
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSelfTest verifies every step passes on a healthy directory and the isolated directory is removed
+func TestSelfTest(t *testing.T) {
+	logger, dir := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString("min_disk_free_kb=1"))
+
+	report := logger.SelfTest()
+	require.True(t, report.OK, "%+v", report.Steps)
+
+	var names []string
+	for _, step := range report.Steps {
+		names = append(names, step.Name)
+		assert.True(t, step.OK, step.Name)
+		assert.Empty(t, step.Error, step.Name)
+	}
+	assert.Equal(t, []string{"setup", "disk_check", "write", "sync", "rotate", "cleanup", "teardown"}, names)
+	assert.Contains(t, report.Steps[1].Detail, "KB free")
+
+	_, err := os.Stat(report.Directory)
+	assert.True(t, os.IsNotExist(err), "Self-test directory should be removed")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.False(t, entry.IsDir(), "No self-test directory should remain: %s", entry.Name())
+	}
+	assert.Equal(t, uint64(0), logger.state.TotalRotations.Load(), "The logger's own files are not rotated")
+}
+
+// TestSelfTestDiskCheckFailure verifies a failing step stops the run and teardown still cleans up
+func TestSelfTestDiskCheckFailure(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString("min_disk_free_kb=9223372036854775"))
+
+	report := logger.SelfTest()
+	assert.False(t, report.OK)
+
+	last := report.Steps[len(report.Steps)-1]
+	failed := report.Steps[len(report.Steps)-2]
+	assert.Equal(t, "teardown", last.Name)
+	assert.True(t, last.OK)
+	assert.Equal(t, "disk_check", failed.Name)
+	assert.False(t, failed.OK)
+	assert.Contains(t, failed.Error, "min_disk_free_kb")
+
+	_, err := os.Stat(report.Directory)
+	assert.True(t, os.IsNotExist(err))
+}
\ No newline at end of file
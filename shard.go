@@ -0,0 +1,134 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// shardSet holds the loggers writing the shard files when Config.Shards > 1
+// Each shard logger has its own channel, processor goroutine, formatter, and file
+type shardSet struct {
+	loggers []*Logger
+	next    atomic.Uint64 // Round-robin cursor
+}
+
+// pick returns the next shard logger in round-robin order
+func (s *shardSet) pick() *Logger {
+	n := s.next.Add(1)
+	return s.loggers[n%uint64(len(s.loggers))]
+}
+
+// getShards returns the active shard set, or nil if the logger writes a single file
+func (l *Logger) getShards() *shardSet {
+	set, _ := l.shards.Load().(*shardSet)
+	return set
+}
+
+// forEachShard calls fn for every shard logger, no-op when not sharded
+func (l *Logger) forEachShard(fn func(shard *Logger)) {
+	if set := l.getShards(); set != nil {
+		for _, shard := range set.loggers {
+			fn(shard)
+		}
+	}
+}
+
+// shardConfig derives the configuration of shard i, writing {name}_{i}.{ext} in the same directory
+// The total size limit is divided between shards; heartbeats and the syslog, journald, and GELF outputs stay
+// with the parent logger, which sends every record to them before handing it to a shard
+func shardConfig(cfg *Config, i int) *Config {
+	shardCfg := cfg.Clone()
+	shardCfg.Name = fmt.Sprintf("%s_%d", cfg.Name, i)
+	shardCfg.EnableSyslog = false
+	shardCfg.EnableJournal = false
+	shardCfg.EnableGELF = false
+	shardCfg.Shards = 0
+	shardCfg.HeartbeatLevel = 0
+	shardCfg.SuppressionSummaryIntervalS = 0
+	shardCfg.SplitErrorFile = false
+	shardCfg.MirrorDirectory = ""
+	shardCfg.RecentRecords = 0
+	shardCfg.OnErrorExec = ""
+	shardCfg.FlushOnExit = false
+	if cfg.MaxTotalSizeKB > 0 {
+		shardCfg.MaxTotalSizeKB = max(cfg.MaxTotalSizeKB/cfg.Shards, 1)
+	}
+	return shardCfg
+}
+
+// configureShards creates or reconfigures shard loggers to match cfg, assuming initMu is held
+// New shards are set up before existing ones are touched; on error they are shut down and reconfigured shards
+// get their previous configuration back, so the current shard set stays as it was
+// Returns the shard set to publish with the new configuration, nil when not sharded, and the shard loggers no
+// longer in use, to be shut down after the new configuration is committed
+func (l *Logger) configureShards(cfg *Config) (*shardSet, []*Logger, error) {
+	var current []*Logger
+	if set := l.getShards(); set != nil {
+		current = set.loggers
+	}
+
+	want := 0
+	if cfg.EnableFile && cfg.Shards > 1 && !cfg.ReadOnly {
+		want = int(cfg.Shards)
+	}
+	if want == 0 {
+		return nil, current, nil
+	}
+
+	loggers := make([]*Logger, want)
+	var created []*Logger
+	var previous []*Config
+	fail := func(err error) (*shardSet, []*Logger, error) {
+		for i, prev := range previous {
+			_ = loggers[i].ApplyConfig(prev)
+		}
+		_ = shutdownShards(created)
+		return nil, nil, err
+	}
+	setup := func(i int, shard *Logger) error {
+		if err := shard.ApplyConfig(shardConfig(cfg, i)); err != nil {
+			return fmtErrorf("failed to configure shard %d: %w", i, err)
+		}
+		if l.state.Started.Load() {
+			if err := shard.Start(); err != nil {
+				return fmtErrorf("failed to start shard %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+
+	for i := len(current); i < want; i++ {
+		shard := NewLogger()
+		shard.parent = l.loggerCore
+		created = append(created, shard)
+		if err := setup(i, shard); err != nil {
+			return fail(err)
+		}
+		loggers[i] = shard
+	}
+	for i := 0; i < min(len(current), want); i++ {
+		shard := current[i]
+		loggers[i] = shard
+		previous = append(previous, shard.GetConfig())
+		if err := setup(i, shard); err != nil {
+			return fail(err)
+		}
+	}
+
+	set := &shardSet{loggers: loggers}
+	if len(current) > want {
+		return set, current[want:], nil
+	}
+	return set, nil, nil
+}
+
+// shutdownShards shuts down shard loggers, joining their errors
+func shutdownShards(shards []*Logger, timeout ...time.Duration) error {
+	var err error
+	for _, shard := range shards {
+		err = errors.Join(err, shard.Shutdown(timeout...))
+	}
+	return err
+}
\ No newline at end of file
@@ -0,0 +1,144 @@
+package log
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lixenwraith/log/logreader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestShardedOutput verifies records are spread over shard files and can be merged back in order
+func TestShardedOutput(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	require.NoError(t, logger.ApplyConfigString("shards=3", "format=binary"))
+
+	const total = 300
+	for i := 0; i < total; i++ {
+		logger.Info(i)
+	}
+
+	result, err := logger.FlushStats(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(total), result.Records)
+	assert.Equal(t, uint64(total), logger.Stats().ProcessedLogs)
+	require.NoError(t, logger.Shutdown(time.Second))
+
+	var files []*os.File
+	for i := 0; i < 3; i++ {
+		info, err := os.Stat(filepath.Join(tmpDir, shardConfig(logger.GetConfig(), i).Name+".log"))
+		require.NoError(t, err, "Shard %d file should exist", i)
+		assert.Positive(t, info.Size(), "Shard %d should receive records", i)
+
+		f, err := os.Open(filepath.Join(tmpDir, info.Name()))
+		require.NoError(t, err)
+		defer f.Close()
+		files = append(files, f)
+	}
+
+	merged := logreader.NewMergeReader(files[0], files[1], files[2])
+	var last time.Time
+	count := 0
+	for {
+		rec, err := merged.Next()
+		if err != nil {
+			break
+		}
+		assert.False(t, rec.Time.Before(last), "Merged records should be chronological")
+		last = rec.Time
+		count++
+	}
+	assert.Equal(t, total, count)
+}
+
+// TestShardReconfigure verifies shards can be resized and removed at runtime
+func TestShardReconfigure(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("shards=4"))
+	require.Len(t, logger.getShards().loggers, 4)
+	retired := logger.getShards().loggers[3]
+
+	require.NoError(t, logger.ApplyConfigString("shards=2"))
+	require.Len(t, logger.getShards().loggers, 2)
+	assert.True(t, retired.state.ShutdownCalled.Load(), "Removed shards should be shut down")
+
+	require.NoError(t, logger.ApplyConfigString("shards=0"))
+	assert.Nil(t, logger.getShards())
+
+	logger.Info("single file again")
+	_, err := logger.FlushStats(time.Second)
+	assert.NoError(t, err)
+	assert.Error(t, logger.ApplyConfigString("shards=-1"))
+}
+
+// TestShardReconfigureFailure verifies a shard that fails to configure leaves the current shards as they were
+func TestShardReconfigureFailure(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	require.NoError(t, logger.ApplyConfigString("shards=2", "max_total_size_kb=3000"))
+	shards := logger.getShards().loggers
+	require.Len(t, shards, 2)
+
+	// The file of the added shard cannot be opened
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, "log_2.log"), 0755))
+	assert.Error(t, logger.ApplyConfigString("shards=3"))
+	assert.Equal(t, shards, logger.getShards().loggers)
+	for _, shard := range shards {
+		assert.Equal(t, int64(1500), shard.GetConfig().MaxTotalSizeKB)
+	}
+
+	logger.Info("still sharded")
+	_, err := logger.FlushStats(time.Second)
+	assert.NoError(t, err)
+
+	// Outputs the parent sends records to are not repeated by shards
+	cfg := logger.GetConfig()
+	cfg.EnableSyslog, cfg.EnableJournal, cfg.EnableGELF = true, true, true
+	shardCfg := shardConfig(cfg, 0)
+	assert.False(t, shardCfg.EnableSyslog)
+	assert.False(t, shardCfg.EnableJournal)
+	assert.False(t, shardCfg.EnableGELF)
+}
+
+// TestShardNetworkOutputs verifies shard records reach the parent's GELF output, each once
+func TestShardNetworkOutputs(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString("shards=3", "enable_gelf=true", "gelf_address="+conn.LocalAddr().String()))
+
+	const total = 9
+	for i := 0; i < total; i++ {
+		logger.Info("sharded", "i", i)
+	}
+	require.NoError(t, logger.Flush(time.Second))
+
+	buf := make([]byte, 65536)
+	for i := 0; i < total; i++ {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		_, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err, "message %d", i)
+	}
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	_, _, err = conn.ReadFrom(buf)
+	assert.Error(t, err, "no record should be sent twice")
+
+	var gelfSinks []string
+	for _, sink := range logger.Stats().Sinks {
+		if strings.HasPrefix(sink.Name, "gelf") {
+			gelfSinks = append(gelfSinks, sink.Name)
+		}
+	}
+	assert.Equal(t, []string{"gelf"}, gelfSinks)
+}
\ No newline at end of file
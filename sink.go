@@ -0,0 +1,176 @@
+package log
+
+import (
+	"sync"
+)
+
+// Filter selects the records forwarded to another logger
+// args are the record's arguments after bound fields are applied
+type Filter func(level int64, args []any) bool
+
+// LevelFilter returns a Filter matching records at or above the given level
+func LevelFilter(minLevel int64) Filter {
+	return func(level int64, args []any) bool {
+		return level >= minLevel
+	}
+}
+
+// recordSink receives every processed record in addition to the file and console outputs
+// Sinks are invoked by the processor under the batch configuration snapshot and must not block
+type recordSink interface {
+	handleRecord(record logRecord)
+}
+
+// errForwardTargetStopped is recorded when a forwarded record reaches a target that is not running
+var errForwardTargetStopped = fmtErrorf("forward target logger is not running")
+
+// forwardMu serializes forwarding changes across loggers so cycle detection sees a consistent graph
+var forwardMu sync.Mutex
+
+// forwardSink passes matching records to another logger, which formats and writes them with its own configuration
+type forwardSink struct {
+	target *Logger
+	filter Filter
+	status healthTracker // Delivery to the target, failing while the target is not running
+}
+
+// handleRecord re-queues a matching record on the target logger
+func (s *forwardSink) handleRecord(record logRecord) {
+	// Heartbeats describe the source logger, the target produces its own
+	if record.Level >= LevelProc {
+		return
+	}
+	if s.filter != nil && !s.filter(record.Level, record.Args) {
+		return
+	}
+
+	target := s.target
+	if record.Level < target.getConfig().Level {
+		return
+	}
+	// The record is still sent so the target accounts for it as dropped
+	if !target.state.Started.Load() || target.state.LoggerDisabled.Load() {
+		s.status.failure(errForwardTargetStopped)
+	} else {
+		s.status.success()
+	}
+	if len(target.fields) > 0 {
+		record.Args = target.bindFields(record.Flags, record.Args)
+	}
+	record.Labels = mergeLabels(target.labels, record.Labels)
+	// The source logger confirms audit records, forwarded copies are fire-and-forget
+	record.ack = nil
+	target.sendLogRecord(record)
+}
+
+// health reports delivery health under the target logger's name
+func (s *forwardSink) health() SinkHealth {
+	return s.status.snapshot("forward:" + s.target.getConfig().Name)
+}
+
+// ForwardTo passes records processed by this logger to another logger, e.g. ERROR-only records into an audit logger
+// with its own directory and retention. A nil filter forwards every record; heartbeats are never forwarded
+// Forwarding is shared by all loggers derived from the same core; the target's bound fields are applied
+// Returns an error on nil target, forwarding to itself, or a forwarding cycle
+func (l *Logger) ForwardTo(other *Logger, filter Filter) error {
+	if other == nil {
+		return fmtErrorf("forward target cannot be nil")
+	}
+	if other.loggerCore == l.loggerCore {
+		return fmtErrorf("logger cannot forward to itself")
+	}
+
+	forwardMu.Lock()
+	defer forwardMu.Unlock()
+
+	if other.forwardsTo(l.loggerCore, make(map[*loggerCore]bool)) {
+		return fmtErrorf("forwarding would create a cycle")
+	}
+
+	l.addSink(&forwardSink{target: other, filter: filter})
+	return nil
+}
+
+// StopForwarding removes all forwarding from this logger to the other logger's core
+// Returns true if any forwarding was removed
+func (l *Logger) StopForwarding(other *Logger) bool {
+	if other == nil {
+		return false
+	}
+
+	forwardMu.Lock()
+	defer forwardMu.Unlock()
+
+	return l.removeSinks(func(s recordSink) bool {
+		fs, ok := s.(*forwardSink)
+		return ok && fs.target.loggerCore == other.loggerCore
+	})
+}
+
+// forwardsTo reports whether records of this logger reach the given core through forwarding
+func (l *Logger) forwardsTo(core *loggerCore, visited map[*loggerCore]bool) bool {
+	if visited[l.loggerCore] {
+		return false
+	}
+	visited[l.loggerCore] = true
+
+	for _, s := range l.getSinks() {
+		fs, ok := s.(*forwardSink)
+		if !ok {
+			continue
+		}
+		if fs.target.loggerCore == core || fs.target.forwardsTo(core, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// getSinks returns the current sink list, which must not be modified
+func (c *loggerCore) getSinks() []recordSink {
+	sinks, _ := c.sinks.Load().([]recordSink)
+	return sinks
+}
+
+// addSink appends a sink using copy-on-write so the processor never observes a partial list
+func (l *Logger) addSink(s recordSink) {
+	l.sinkMu.Lock()
+	defer l.sinkMu.Unlock()
+
+	current := l.getSinks()
+	updated := make([]recordSink, 0, len(current)+1)
+	updated = append(updated, current...)
+	updated = append(updated, s)
+	l.sinks.Store(updated)
+}
+
+// removeSinks drops every sink matching the predicate, returns true if any was removed
+func (l *Logger) removeSinks(match func(recordSink) bool) bool {
+	l.sinkMu.Lock()
+	defer l.sinkMu.Unlock()
+
+	current := l.getSinks()
+	updated := make([]recordSink, 0, len(current))
+	for _, s := range current {
+		if !match(s) {
+			updated = append(updated, s)
+		}
+	}
+	if len(updated) == len(current) {
+		return false
+	}
+	l.sinks.Store(updated)
+	return true
+}
+
+// dispatchSinks hands a record to every registered sink, shard loggers also feed their parent's sinks
+func (l *Logger) dispatchSinks(record logRecord) {
+	for _, s := range l.getSinks() {
+		s.handleRecord(record)
+	}
+	if l.parent != nil {
+		for _, s := range l.parent.getSinks() {
+			s.handleRecord(record)
+		}
+	}
+}
\ No newline at end of file
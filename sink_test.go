@@ -0,0 +1,73 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestForwardTo verifies that only filtered records reach the target logger's own output
+func TestForwardTo(t *testing.T) {
+	app, appDir := createTestLogger(t)
+	defer app.Shutdown()
+	audit, auditDir := createTestLogger(t)
+	defer audit.Shutdown()
+
+	require.NoError(t, app.ForwardTo(audit.withFields("source", "app"), LevelFilter(LevelError)))
+
+	app.Info("routine event")
+	app.Error("payment failed")
+	require.NoError(t, app.Flush(time.Second))
+
+	var auditContent string
+	require.Eventually(t, func() bool {
+		_ = audit.Flush(time.Second)
+		data, err := os.ReadFile(filepath.Join(auditDir, "log.log"))
+		auditContent = string(data)
+		return err == nil && strings.Contains(auditContent, "payment failed")
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Contains(t, auditContent, "payment failed source app", "Target bound fields should be applied")
+	assert.NotContains(t, auditContent, "routine event")
+
+	appContent, err := os.ReadFile(filepath.Join(appDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(appContent), "routine event")
+	assert.Contains(t, string(appContent), "payment failed")
+
+	// Forwarding stops after removal
+	assert.True(t, app.StopForwarding(audit))
+	assert.False(t, app.StopForwarding(audit))
+	app.Error("after stop")
+	require.NoError(t, app.Flush(time.Second))
+	require.NoError(t, audit.Flush(time.Second))
+
+	data, err := os.ReadFile(filepath.Join(auditDir, "log.log"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "after stop")
+}
+
+// TestForwardToCycle verifies that self-forwarding and forwarding cycles are rejected
+func TestForwardToCycle(t *testing.T) {
+	a, _ := createTestLogger(t)
+	defer a.Shutdown()
+	b, _ := createTestLogger(t)
+	defer b.Shutdown()
+	c, _ := createTestLogger(t)
+	defer c.Shutdown()
+
+	assert.Error(t, a.ForwardTo(nil, nil))
+	assert.Error(t, a.ForwardTo(a.withFields("k", "v"), nil), "Derived logger shares the core")
+
+	require.NoError(t, a.ForwardTo(b, nil))
+	require.NoError(t, b.ForwardTo(c, nil))
+
+	err := c.ForwardTo(a, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+}
\ No newline at end of file
@@ -0,0 +1,147 @@
+package log
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Errors returned by Write of the queued sinks
+var (
+	errSinkQueueFull = fmtErrorf("sink queue full, record dropped")
+	errSinkClosed    = fmtErrorf("sink closed")
+)
+
+// sinkQueue is the record queue shared by the network sinks: Write queues records without blocking the processor
+// and the sink's own goroutine delivers them in batches; delivery outcomes are kept in status, reported under the
+// name the sink is registered with
+type sinkQueue struct {
+	kind      string // Sink type used in errors, e.g. "http sink"
+	filter    Filter
+	timeout   time.Duration // Longest Close waits for queued records
+	records   chan Record
+	closing   chan struct{}
+	closeOnce sync.Once
+	ctx       context.Context // Cancelled when closing times out, aborting deliveries and retries
+	cancel    context.CancelFunc
+	exited    chan struct{} // Closed when the delivery goroutine has sent its final batch
+	status    healthTracker
+}
+
+// newSinkQueue returns a queue holding up to size records, start runs its delivery goroutine
+func newSinkQueue(kind string, size int, filter Filter, timeout time.Duration) *sinkQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &sinkQueue{
+		kind:    kind,
+		filter:  filter,
+		timeout: timeout,
+		records: make(chan Record, size),
+		closing: make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+		exited:  make(chan struct{}),
+	}
+}
+
+// start runs the sink's delivery loop on its own goroutine
+func (q *sinkQueue) start(run func()) {
+	go func() {
+		defer close(q.exited)
+		defer q.cancel()
+		run()
+	}()
+}
+
+// Write queues a matching record for delivery, dropping it when the queue is full; heartbeats are not sent
+func (q *sinkQueue) Write(data []byte, record Record) error {
+	if record.Level >= LevelProc {
+		return nil
+	}
+	if q.filter != nil && !q.filter(record.Level, record.Args) {
+		return nil
+	}
+	select {
+	case <-q.closing:
+		return errSinkClosed
+	default:
+	}
+
+	select {
+	case q.records <- record:
+		return nil
+	default:
+		q.status.failure(errSinkQueueFull)
+		return errSinkQueueFull
+	}
+}
+
+// Flush returns immediately, batches are sent when full or once their oldest record is the flush interval old
+func (q *sinkQueue) Flush() error {
+	return nil
+}
+
+// Close stops accepting records and waits up to the sink's timeout for the queued ones to be sent
+// A delivery still running at the timeout is cancelled and its batch dropped
+func (q *sinkQueue) Close() error {
+	q.closeOnce.Do(func() { close(q.closing) })
+
+	timer := time.NewTimer(q.timeout)
+	defer timer.Stop()
+	select {
+	case <-q.exited:
+		return nil
+	case <-timer.C:
+		q.cancel()
+		<-q.exited
+		return fmtErrorf("%s: timed out sending queued records", q.kind)
+	}
+}
+
+// health reports delivery health, the registered name replaces the one given here
+func (q *sinkQueue) health() SinkHealth {
+	return q.status.snapshot(q.kind)
+}
+
+// batch passes queued records to add until the sink closes, calling flush when add reports a full batch or the
+// oldest record of the batch is interval old; on close the records still queued are added and flushed
+func (q *sinkQueue) batch(interval time.Duration, add func(record Record) bool, flush func()) {
+	timer := time.NewTimer(interval)
+	timer.Stop()
+	pending := false
+
+	deliver := func() {
+		if pending {
+			timer.Stop()
+			flush()
+			pending = false
+		}
+	}
+	push := func(record Record) {
+		if !pending {
+			timer.Reset(interval)
+			pending = true
+		}
+		if add(record) {
+			deliver()
+		}
+	}
+
+	for {
+		select {
+		case record := <-q.records:
+			push(record)
+		case <-timer.C:
+			deliver()
+		case <-q.closing:
+			for {
+				select {
+				case record := <-q.records:
+					push(record)
+				default:
+					deliver()
+					return
+				}
+			}
+		}
+	}
+}
\ No newline at end of file
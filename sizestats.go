@@ -0,0 +1,121 @@
+package log
+
+import (
+	"math/bits"
+	"strings"
+	"sync"
+)
+
+// RecordSizeStats describes the formatted size in bytes of records written since the last PROC heartbeat,
+// or since the logger started when heartbeats are disabled
+// Percentiles are upper bounds of power-of-two buckets, capped at Max
+type RecordSizeStats struct {
+	Count      uint64 // Records measured
+	Max        int64  // Largest record
+	P50        int64  // Median record size
+	P99        int64  // 99th percentile record size
+	MaxMessage string // Leading text of the largest record's message, to locate its call site
+}
+
+// sizeHistogram is the record size distribution of one interval
+type sizeHistogram struct {
+	buckets [recordSizeBuckets]uint64 // Bucket i counts sizes in (2^(i-1), 2^i]
+	count   uint64
+	max     int64
+	maxMsg  string
+}
+
+// sizeTracker accumulates record sizes observed by the processor, reset by the PROC heartbeat
+type sizeTracker struct {
+	mu      sync.Mutex
+	current sizeHistogram
+}
+
+// observe adds a formatted record size, remembering the message of the largest record
+func (t *sizeTracker) observe(size int64, args []any) {
+	idx := 0
+	if size > 1 {
+		idx = min(bits.Len64(uint64(size-1)), recordSizeBuckets-1)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := &t.current
+	h.buckets[idx]++
+	h.count++
+	if size > h.max {
+		h.max = size
+		h.maxMsg = recordMessage(args)
+	}
+}
+
+// snapshot returns the current distribution, starting a new interval if reset is set
+func (t *sizeTracker) snapshot(reset bool) sizeHistogram {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := t.current
+	if reset {
+		t.current = sizeHistogram{}
+	}
+	return h
+}
+
+// merge adds another distribution, used to combine shard intervals
+func (h *sizeHistogram) merge(other sizeHistogram) {
+	for i, n := range other.buckets {
+		h.buckets[i] += n
+	}
+	h.count += other.count
+	if other.max > h.max {
+		h.max = other.max
+		h.maxMsg = other.maxMsg
+	}
+}
+
+// stats computes the public summary of the distribution
+func (h *sizeHistogram) stats() RecordSizeStats {
+	return RecordSizeStats{
+		Count:      h.count,
+		Max:        h.max,
+		P50:        h.percentile(0.50),
+		P99:        h.percentile(0.99),
+		MaxMessage: h.maxMsg,
+	}
+}
+
+// percentile returns the upper bound of the bucket holding the p-th fraction of records
+func (h *sizeHistogram) percentile(p float64) int64 {
+	if h.count == 0 {
+		return 0
+	}
+	rank := uint64(p*float64(h.count-1)) + 1
+	var seen uint64
+	for i, n := range h.buckets {
+		seen += n
+		if seen >= rank {
+			return min(int64(1)<<i, h.max)
+		}
+	}
+	return h.max
+}
+
+// recordMessage returns the leading text of a record's message, empty if it is not a string
+// The message is the first argument, or the value of a leading "msg" key as written by the compat adapters
+func recordMessage(args []any) string {
+	if len(args) == 0 {
+		return ""
+	}
+	if key, ok := args[0].(string); ok && key == "msg" && len(args) > 1 {
+		args = args[1:]
+	}
+	msg, ok := args[0].(string)
+	if !ok {
+		return ""
+	}
+	if len(msg) > recordSizeMessageLen {
+		msg = strings.ToValidUTF8(msg[:recordSizeMessageLen], "")
+	}
+	return msg
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSizeHistogram verifies bucketed percentiles, the maximum, and merging
+func TestSizeHistogram(t *testing.T) {
+	var tracker sizeTracker
+	for range 98 {
+		tracker.observe(100, []any{"small"})
+	}
+	tracker.observe(3000, []any{"msg", "medium", "source", "fiber"})
+	tracker.observe(5000, []any{strings.Repeat("x", 100)})
+
+	h := tracker.snapshot(false)
+	stats := h.stats()
+	assert.Equal(t, uint64(100), stats.Count)
+	assert.Equal(t, int64(128), stats.P50, "Upper bound of the (64, 128] bucket")
+	assert.Equal(t, int64(4096), stats.P99)
+	assert.Equal(t, int64(5000), stats.Max)
+	assert.Equal(t, strings.Repeat("x", recordSizeMessageLen), stats.MaxMessage)
+
+	var other sizeTracker
+	other.observe(9000, []any{"msg", "huge"})
+	h.merge(other.snapshot(true))
+	assert.Equal(t, uint64(101), h.count)
+	assert.Equal(t, "huge", h.stats().MaxMessage)
+	assert.Equal(t, uint64(0), other.snapshot(false).count, "Reset starts a new interval")
+
+	var single sizeTracker
+	single.observe(1, nil)
+	assert.Equal(t, int64(1), single.current.stats().P99, "Percentiles are capped at the maximum")
+}
+
+// TestRecordSizeStats verifies Stats reports sizes and the PROC heartbeat starts a new interval
+func TestRecordSizeStats(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+
+	logger.Info("short")
+	logger.Info("oversized", strings.Repeat("y", 10000))
+	require.NoError(t, logger.Flush(time.Second))
+
+	sizes := logger.Stats().RecordSizes
+	assert.Equal(t, uint64(2), sizes.Count)
+	assert.Greater(t, sizes.Max, int64(10000))
+	assert.Equal(t, "oversized", sizes.MaxMessage)
+
+	logger.logProcHeartbeat()
+	require.NoError(t, logger.Flush(time.Second))
+	assert.Equal(t, uint64(0), logger.Stats().RecordSizes.Count)
+}
\ No newline at end of file
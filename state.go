@@ -3,6 +3,7 @@ package log
 import (
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // State encapsulates the runtime state of the logger
@@ -17,8 +18,10 @@ type State struct {
 	ProcessorExited atomic.Bool // Tracks if the processor goroutine is running or has exited
 
 	// Flushing state
-	flushRequestChan chan chan struct{} // Channel to request a flush
-	flushMutex       sync.Mutex         // Protect concurrent Flush calls
+	flushRequestChan  chan chan FlushResult // Channel to request a flush
+	flushMutex        sync.Mutex            // Protect concurrent Flush calls
+	recordsSinceFlush atomic.Uint64         // Records written since the last explicit flush
+	bytesSinceFlush   atomic.Uint64         // Bytes written since the last explicit flush
 
 	// Outputs
 	CurrentFile  atomic.Value // stores *os.File
@@ -27,16 +30,56 @@ type State struct {
 	// File State
 	CurrentSize      atomic.Int64 // Size of the current log file
 	EarliestFileTime atomic.Value // stores time.Time for retention
+	dryRunReported   sync.Map     // "reason:path" of archives already logged by retention_dry_run
+	gzipStreams      sync.Map     // *os.File -> *gzipStream of open log files when gzip_active is enabled
+	dirSize          dirSizeAccount
 
 	// Log state
-	ActiveLogChannel atomic.Value  // stores chan logRecord
+	ActiveQueue      atomic.Value  // stores *logQueue
 	DroppedLogs      atomic.Uint64 // Counter for logs dropped since last heartbeat
 	TotalDroppedLogs atomic.Uint64 // Counter for total logs dropped since logger start
+	StderrFallback   atomic.Uint64 // Records written to stderr instead of the blocked file, see on_disk_full
+
+	// Output health
+	fileHealth    healthTracker // Active log file writes and rotation
+	consoleHealth healthTracker // Console writes
+
+	// Output and retention errors awaiting internal_errors_to_log records
+	internalErrors internalErrorMirror
+
+	// Record size distribution since the last PROC heartbeat
+	recordSizes sizeTracker
+
+	// Logging call durations since the last PROC heartbeat
+	callLatency         latencyTracker
+	CallLatencyDegraded atomic.Bool   // A call exceeded call_latency_budget_us, reset by ApplyConfig
+	CallsShed           atomic.Uint64 // Records the degraded call path dropped since the last suppression summary
+
+	// Context-aware logging
+	SuppressedCancelled atomic.Uint64 // Records skipped because their context was already cancelled
+
+	// Field limit statistics
+	FieldLimitViolations atomic.Uint64 // Records exceeding field count or key length limits
+	RejectedRecords      atomic.Uint64 // Records dropped by the "reject" field limit policy
+	DroppedReservedKeys  atomic.Uint64 // Fields left out by reserved_key_policy=drop
 
 	// Heartbeat statistics
-	HeartbeatSequence  atomic.Uint64 // Counter for heartbeat sequence numbers
+	ProcSequence       atomic.Uint64 // Sequence number of the last PROC heartbeat
+	DiskSequence       atomic.Uint64 // Sequence number of the last DISK heartbeat
+	SysSequence        atomic.Uint64 // Sequence number of the last SYS heartbeat
 	LoggerStartTime    atomic.Value  // Stores time.Time for uptime calculation
 	TotalLogsProcessed atomic.Uint64 // Counter for non-heartbeat logs successfully processed
+	TotalBytesWritten  atomic.Uint64 // Counter for formatted bytes of processed logs
 	TotalRotations     atomic.Uint64 // Counter for successful log rotations
+	RotationGeneration atomic.Uint64 // Generation of the last archive, 0 until seeded by the first rotation
 	TotalDeletions     atomic.Uint64 // Counter for successful log deletions (cleanup/retention)
+	procRateMark       rateMark      // Counters at the previous PROC heartbeat, used only by the processor
+	HeartbeatIncident  atomic.Bool   // Heartbeats run at heartbeat_incident_interval_s until conditions normalize
+}
+
+// rateMark records counter values at a point in time for rate computation
+type rateMark struct {
+	time  time.Time
+	logs  uint64
+	bytes uint64
 }
\ No newline at end of file
@@ -0,0 +1,62 @@
+package log
+
+import "os"
+
+// Stats is a point-in-time snapshot of the logger's counters
+type Stats struct {
+	ProcessedLogs        uint64           // Records successfully written since logger creation
+	DroppedLogs          uint64           // Records dropped since logger creation
+	StderrFallback       uint64           // Records written to stderr instead of the file by on_disk_full=stderr
+	Rotations            uint64           // Successful log file rotations
+	Deletions            uint64           // Log files deleted by cleanup or retention
+	CurrentFileSize      int64            // Size of the active log file in bytes
+	FieldLimitViolations uint64           // Records exceeding MaxFieldsPerRecord or MaxFieldKeyLen
+	RejectedRecords      uint64           // Records dropped by the "reject" field limit policy
+	DroppedReservedKeys  uint64           // Fields left out by reserved_key_policy=drop
+	SuppressedCancelled  uint64           // Records skipped by the *Context methods because the context was done
+	RecordSizes          RecordSizeStats  // Record size distribution since the last PROC heartbeat
+	CallLatency          CallLatencyStats // Time spent in logging calls since the last PROC heartbeat
+	Sinks                []SinkHealth     // Health of each output in use: console, file or shard files, forwarding, registered sinks
+}
+
+// Stats returns a snapshot of the logger's counters, safe to call at any time
+// Counters of sharded loggers include all shards
+func (l *Logger) Stats() Stats {
+	stats := Stats{
+		ProcessedLogs:        l.state.TotalLogsProcessed.Load(),
+		DroppedLogs:          l.state.TotalDroppedLogs.Load(),
+		StderrFallback:       l.state.StderrFallback.Load(),
+		Rotations:            l.state.TotalRotations.Load(),
+		Deletions:            l.state.TotalDeletions.Load(),
+		CurrentFileSize:      l.state.CurrentSize.Load(),
+		FieldLimitViolations: l.state.FieldLimitViolations.Load(),
+		RejectedRecords:      l.state.RejectedRecords.Load(),
+		DroppedReservedKeys:  l.state.DroppedReservedKeys.Load(),
+		SuppressedCancelled:  l.state.SuppressedCancelled.Load(),
+		CallLatency:          l.callLatency(false),
+		Sinks:                l.sinkHealth(),
+	}
+
+	// A read-only logger reports the active file as found on disk
+	if cfg := l.getConfig(); cfg.ReadOnly {
+		if info, err := os.Stat(logFilePath(cfg)); err == nil {
+			stats.CurrentFileSize = info.Size()
+		}
+	}
+
+	sizes := l.state.recordSizes.snapshot(false)
+
+	l.forEachShard(func(shard *Logger) {
+		shardStats := shard.Stats()
+		stats.ProcessedLogs += shardStats.ProcessedLogs
+		stats.DroppedLogs += shardStats.DroppedLogs
+		stats.StderrFallback += shardStats.StderrFallback
+		stats.Rotations += shardStats.Rotations
+		stats.Deletions += shardStats.Deletions
+		stats.DroppedReservedKeys += shardStats.DroppedReservedKeys
+		stats.CurrentFileSize += shardStats.CurrentFileSize
+		sizes.merge(shard.state.recordSizes.snapshot(false))
+	})
+	stats.RecordSizes = sizes.stats()
+	return stats
+}
\ No newline at end of file
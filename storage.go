@@ -1,39 +1,116 @@
 package log
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 )
 
-// performSync syncs the current log file
-func (l *Logger) performSync() {
+// logFileMatcher identifies the active and archived files of a logger, honoring empty extensions
+type logFileMatcher struct {
+	name       string
+	ext        string
+	activeName string          // Active log file name, e.g. "log.log" or "log"
+	pattern    *regexp.Regexp  // Matches the active file and "<name>_<suffix>[.ext]" archives
+	excluded   *logFileMatcher // Files of the error file logger, which share the name prefix; nil when not split
+}
+
+// newLogFileMatcher compiles a matcher for the given base name and extension
+func newLogFileMatcher(name, ext string) *logFileMatcher {
+	suffix := ""
+	if ext != "" {
+		suffix = "." + ext
+	}
+	return &logFileMatcher{
+		name:       name,
+		ext:        ext,
+		activeName: name + suffix,
+		// Archive suffix excludes dots so that "name_x.other" never matches an empty extension
+		pattern: regexp.MustCompile("^" + regexp.QuoteMeta(name) + "(_[^.]+)?" + regexp.QuoteMeta(suffix) + "$"),
+	}
+}
+
+// isActive reports whether fname is the active log file
+func (m *logFileMatcher) isActive(fname string) bool {
+	return fname == m.activeName
+}
+
+// isArchive reports whether fname is an archived log file eligible for cleanup and retention
+func (m *logFileMatcher) isArchive(fname string) bool {
+	return fname != m.activeName && m.matches(fname)
+}
+
+// matches reports whether fname is the active or an archived log file
+func (m *logFileMatcher) matches(fname string) bool {
+	return m.pattern.MatchString(fname) && (m.excluded == nil || !m.excluded.matches(fname))
+}
+
+// getFileMatcher returns the file matcher for the current configuration, recompiling on name or extension change
+func (l *Logger) getFileMatcher() *logFileMatcher {
 	c := l.getConfig()
+	if m, ok := l.fileMatcher.Load().(*logFileMatcher); ok && m.name == c.Name && m.ext == c.fileExtension() &&
+		(m.excluded != nil) == c.SplitErrorFile {
+		return m
+	}
+	m := newLogFileMatcher(c.Name, c.fileExtension())
+	if c.SplitErrorFile {
+		m.excluded = newLogFileMatcher(c.Name+errorFileSuffix, c.fileExtension())
+	}
+	l.fileMatcher.Store(m)
+	return m
+}
+
+// performSync syncs the current log file, returns true if a sync was performed successfully
+func (l *Logger) performSync() bool {
 	// Skip sync if file output is disabled
-	enableFile := c.EnableFile
-	if !enableFile {
-		return
+	if !l.getConfig().fileOutput() {
+		return false
 	}
 
-	cfPtr := l.state.CurrentFile.Load()
-	if cfPtr != nil {
-		if currentLogFile, isFile := cfPtr.(*os.File); isFile && currentLogFile != nil {
-			if err := currentLogFile.Sync(); err != nil {
-				// Log sync error
-				syncErrRecord := logRecord{
-					Flags:     FlagDefault,
-					TimeStamp: time.Now(),
-					Level:     LevelWarn,
-					Args:      []any{"Log file sync failed", "file", currentLogFile.Name(), "error", err.Error()},
-				}
-				l.sendLogRecord(syncErrRecord)
-			}
+	synced, err := l.syncCurrentFile()
+	if err != nil {
+		// Log sync error
+		syncErrRecord := logRecord{
+			Flags:     FlagDefault,
+			TimeStamp: time.Now(),
+			Level:     LevelWarn,
+			Args:      []any{"Log file sync failed", "file", l.getStaticLogFilePath(), "error", err.Error()},
 		}
+		l.sendLogRecord(syncErrRecord)
 	}
+	return synced
+}
+
+// syncCurrentFile syncs the active log file through the sync group when joined
+// Returns false without error when no file is open or the file is a special file, which cannot be synced
+func (l *Logger) syncCurrentFile() (bool, error) {
+	currentLogFile, isFile := l.state.CurrentFile.Load().(*os.File)
+	if !isFile || currentLogFile == nil || l.specialFileOutput() {
+		return false, nil
+	}
+
+	// Compressed data only reaches the file at a flush point
+	if stream := l.getGzipStream(currentLogFile); stream != nil {
+		if err := stream.flush(); err != nil {
+			return false, err
+		}
+		l.state.CurrentSize.Store(stream.size.Load())
+	}
+
+	var err error
+	if group := l.getSyncGroup(); group != nil {
+		err = group.sync(currentLogFile)
+	} else {
+		err = currentLogFile.Sync()
+	}
+	return err == nil, err
 }
 
 // performDiskCheck checks disk space, triggers cleanup if needed, and updates status
@@ -41,9 +118,10 @@ func (l *Logger) performSync() {
 func (l *Logger) performDiskCheck(forceCleanup bool) bool {
 	c := l.getConfig()
 	// Skip all disk checks if file output is disabled
-	enableFile := c.EnableFile
+	// Special files such as /dev/stdout occupy no space in the directory
+	enableFile := c.fileOutput() && !l.specialFileOutput()
 	if !enableFile {
-		// Always return OK status when file output is disabled
+		// Always return OK status when file output is disabled or writes to a special file
 		if !l.state.DiskStatusOK.Load() {
 			l.state.DiskStatusOK.Store(true)
 			l.state.DiskFullLogged.Store(false)
@@ -51,59 +129,20 @@ func (l *Logger) performDiskCheck(forceCleanup bool) bool {
 		return true
 	}
 
-	dir := c.Directory
-	ext := c.Extension
-	maxTotalKB := c.MaxTotalSizeKB
-	minDiskFreeKB := c.MinDiskFreeKB
-	maxTotal := maxTotalKB * sizeMultiplier
-	minFreeRequired := minDiskFreeKB * sizeMultiplier
-
-	// If no limits are set, the disk is considered OK
-	if maxTotal <= 0 && minFreeRequired <= 0 {
-		if !l.state.DiskStatusOK.Load() {
-			l.state.DiskStatusOK.Store(true)
-			l.state.DiskFullLogged.Store(false)
-		}
-		return true
-	}
+	// Restore directory and active file if removed externally
+	l.recreateLogFileIfMissing()
 
-	// Check available disk space
-	freeSpace, err := l.getDiskFreeSpace(dir)
+	spaceToFree, reason, err := l.diskLimitExcess(c)
 	if err != nil {
-		l.internalLog("warning - failed to check free disk space for '%s': %v\n", dir, err)
+		l.internalLog("warning - %v\n", err)
 		l.state.DiskStatusOK.Store(false)
 		return false
 	}
-
-	// Determine if cleanup is needed based on disk space and total log size
-	needsCleanupCheck := false
-	spaceToFree := int64(0)
-	if minFreeRequired > 0 && freeSpace < minFreeRequired {
-		needsCleanupCheck = true
-		spaceToFree = minFreeRequired - freeSpace
-	}
-
-	if maxTotal > 0 {
-		dirSize, err := l.getLogDirSize(dir, ext)
-		if err != nil {
-			l.internalLog("warning - failed to check log directory size for '%s': %v\n", dir, err)
-			if l.state.DiskStatusOK.Load() {
-				l.state.DiskStatusOK.Store(false)
-			}
-			return false
-		}
-		if dirSize > maxTotal {
-			needsCleanupCheck = true
-			amountOver := dirSize - maxTotal
-			if amountOver > spaceToFree {
-				spaceToFree = amountOver
-			}
-		}
-	}
+	needsCleanupCheck := spaceToFree > 0
 
 	// Trigger cleanup if needed and allowed by the 'forceCleanup' flag
 	if needsCleanupCheck && forceCleanup {
-		if err := l.cleanOldLogs(spaceToFree); err != nil {
+		if err := l.cleanOldLogs(spaceToFree, reason); err != nil {
 			if !l.state.DiskFullLogged.Swap(true) {
 				diskFullRecord := logRecord{
 					Flags: FlagDefault, TimeStamp: time.Now(), Level: LevelError,
@@ -156,96 +195,38 @@ func (l *Logger) getDiskFreeSpace(path string) (int64, error) {
 	return availableBytes, nil
 }
 
-// getLogDirSize calculates total size of log files matching the current extension
-func (l *Logger) getLogDirSize(dir, ext string) (int64, error) {
-	var size int64
-	entries, err := os.ReadDir(dir)
+// getLogDirSize returns the total size of the active and archived log files
+// Archives are counted from the running totals, the directory is read only when they are due for reconciliation
+func (l *Logger) getLogDirSize(dir string) (int64, error) {
+	size, _, err := l.archiveTotals(dir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return 0, nil
-		}
-		return 0, fmtErrorf("failed to read log directory '%s': %w", dir, err)
+		return 0, err
 	}
-
-	targetExt := "." + ext
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		if filepath.Ext(entry.Name()) == targetExt {
-			info, errInfo := entry.Info()
-			if errInfo != nil {
-				continue
-			}
-			size += info.Size()
-		}
-	}
-	return size, nil
+	active, _ := l.activeFileSize(dir)
+	return size + active, nil
 }
 
-// cleanOldLogs removes oldest log files until required space is freed
-func (l *Logger) cleanOldLogs(required int64) error {
+// cleanOldLogs removes oldest log files until required space is freed, all archives when required is 0
+// reason is reported for each file when retention_dry_run keeps them
+func (l *Logger) cleanOldLogs(required int64, reason string) error {
 	c := l.getConfig()
 	dir := c.Directory
-	ext := c.Extension
-	name := c.Name
 
-	entries, err := os.ReadDir(dir)
+	archives, err := l.listArchives(dir)
 	if err != nil {
 		return fmtErrorf("failed to read log directory '%s' for cleanup: %w", dir, err)
 	}
 
-	// Build a list of log files eligible for deletion, excluding the active log file
-	staticLogName := name
-	if ext != "" {
-		staticLogName = name + "." + ext
-	}
-
-	type logFileMeta struct {
-		name    string
-		modTime time.Time
-		size    int64
-	}
-	var logs []logFileMeta
-	targetExt := "." + ext
-	for _, entry := range entries {
-		if entry.IsDir() || entry.Name() == staticLogName {
-			continue
-		}
-		if ext != "" && filepath.Ext(entry.Name()) != targetExt {
-			continue
-		}
-		info, errInfo := entry.Info()
-		if errInfo != nil {
-			continue
-		}
-		logs = append(logs, logFileMeta{name: entry.Name(), modTime: info.ModTime(), size: info.Size()})
-	}
-
-	if len(logs) == 0 {
+	if len(archives) == 0 {
 		if required > 0 {
 			return fmtErrorf("no old logs available to delete in '%s', needed %d bytes", dir, required)
 		}
 		return nil
 	}
 
-	// Sort logs by modification time to delete the oldest ones first
-	sort.Slice(logs, func(i, j int) bool { return logs[i].modTime.Before(logs[j].modTime) })
-
-	// Iterate and remove files until enough space has been freed
-	var freedSpace int64
-	for _, log := range logs {
-		if required > 0 && freedSpace >= required {
-			break
-		}
-		filePath := filepath.Join(dir, log.name)
-		if err := os.Remove(filePath); err != nil {
-			l.internalLog("failed to remove old log file '%s': %v\n", filePath, err)
-			continue
-		}
-		freedSpace += log.size
-		l.state.TotalDeletions.Add(1)
-	}
+	// Delete the oldest files until enough space has been freed
+	selected, _ := selectOldest(archives, required, reason)
+	freedSpace := l.removeArchives(c, selected)
 
 	if required > 0 && freedSpace < required {
 		return fmtErrorf("could not free enough space in '%s': freed %d bytes, needed %d bytes", dir, freedSpace, required)
@@ -257,8 +238,6 @@ func (l *Logger) cleanOldLogs(required int64) error {
 func (l *Logger) updateEarliestFileTime() {
 	c := l.getConfig()
 	dir := c.Directory
-	ext := c.Extension
-	name := c.Name
 
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -267,24 +246,10 @@ func (l *Logger) updateEarliestFileTime() {
 	}
 
 	var earliest time.Time
-	// Get the active log filename to exclude from timestamp tracking
-	staticLogName := name
-	if ext != "" {
-		staticLogName = name + "." + ext
-	}
-
-	targetExt := "." + ext
-	prefix := name + "_"
+	// Only archives are tracked, the active log file is excluded
+	matcher := l.getFileMatcher()
 	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		fname := entry.Name()
-		// Skip the active log file
-		if fname == staticLogName {
-			continue
-		}
-		if !strings.HasPrefix(fname, prefix) || (ext != "" && filepath.Ext(fname) != targetExt) {
+		if entry.IsDir() || !matcher.isArchive(entry.Name()) {
 			continue
 		}
 		info, errInfo := entry.Info()
@@ -300,106 +265,354 @@ func (l *Logger) updateEarliestFileTime() {
 
 // cleanExpiredLogs removes log files older than the retention period
 func (l *Logger) cleanExpiredLogs(oldest time.Time) error {
+	if l.specialFileOutput() {
+		return nil
+	}
 	c := l.getConfig()
 	dir := c.Directory
-	ext := c.Extension
-	name := c.Name
 	retentionPeriodHrs := c.RetentionPeriodHrs
 	rpDuration := time.Duration(retentionPeriodHrs * float64(time.Hour))
 
 	if rpDuration <= 0 {
 		return nil
 	}
-	cutoffTime := time.Now().Add(-rpDuration)
+	cutoffTime := l.retentionNow().Add(-rpDuration - retentionClockMargin)
 	if oldest.IsZero() || !oldest.Before(cutoffTime) {
 		return nil
 	}
 
-	entries, err := os.ReadDir(dir)
+	archives, err := l.listArchives(dir)
 	if err != nil {
 		return fmtErrorf("failed to read log directory '%s' for retention cleanup: %w", dir, err)
 	}
 
-	// Get the active log filename to exclude from deletion
-	staticLogName := name
-	if ext != "" {
-		staticLogName = name + "." + ext
+	expired, skippedCount := l.selectExpired(c, archives)
+	l.removeArchives(c, expired)
+
+	if skippedCount > 0 {
+		l.internalLog("retention skipped %d log files with implausible modification times in '%s'\n", skippedCount, dir)
 	}
 
-	targetExt := "." + ext
-	var deletedCount int
-	for _, entry := range entries {
-		if entry.IsDir() || entry.Name() == staticLogName {
-			continue
+	return nil
+}
+
+// retentionNow returns the reference time for retention, the earlier of the wall clock and an anchor, initially the
+// logger's start time, advanced by elapsed monotonic time
+// A forward wall clock step, e.g. an NTP correction, therefore never makes files appear older than they are on the
+// pass that sees it, while after a backward step files only appear younger and are kept longer
+func (l *Logger) retentionNow() time.Time {
+	start, _ := l.state.LoggerStartTime.Load().(time.Time)
+	return l.retentionClock.now(time.Now(), start)
+}
+
+// retentionClock holds the anchor retentionNow advances by monotonic time
+type retentionClock struct {
+	mu   sync.Mutex
+	wall time.Time     // Wall time of the anchor
+	mono time.Time     // Monotonic reading of the anchor, zero until first use
+	step time.Duration // Forward wall clock step seen by the previous pass, zero when none
+}
+
+// now returns the earlier of now's wall time and the anchor advanced to now, re-anchoring to the wall clock when two
+// consecutive passes see the same forward step, so a clock stepped forward after starting wrong (a device without an
+// RTC booting at 1970) is trusted from the second pass on instead of pinning retention to the start time
+func (c *retentionClock) now(now, start time.Time) time.Time {
+	wall := now.Round(0)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mono.IsZero() {
+		if start.IsZero() {
+			return wall
+		}
+		// Round(0) strips the monotonic reading, leaving the start wall time
+		c.wall, c.mono = start.Round(0), start
+	}
+
+	monotonicNow := c.wall.Add(now.Sub(c.mono))
+	step := wall.Sub(monotonicNow)
+	switch {
+	case step <= 0:
+		c.step = 0
+		return wall
+	case step <= retentionClockMargin:
+		// Drift between the clocks, not a step
+		c.step = 0
+		return monotonicNow
+	case c.step > 0 && (step-c.step).Abs() <= retentionClockMargin:
+		// The step persisted for a full pass, the wall clock was corrected rather than glitched
+		c.wall, c.mono, c.step = wall, now, 0
+		return wall
+	default:
+		c.step = step
+		return monotonicNow
+	}
+}
+
+// getStaticLogFilePath returns the full path to the active log file
+func (l *Logger) getStaticLogFilePath() string {
+	return logFilePath(l.getConfig())
+}
+
+// checkLogDirectory reports a descriptive error if dir cannot serve as the log directory
+// Catches a regular file or dangling symlink in the path, symlink loops, and directories inside the
+// temporary build directory of the running binary (e.g. go run), which is removed when the process exits
+func checkLogDirectory(dir string) error {
+	// Find the deepest existing path element, missing elements are created by MkdirAll
+	existing := dir
+	for {
+		info, err := os.Lstat(existing)
+		if err == nil {
+			if info.Mode()&os.ModeSymlink != 0 {
+				if err := checkSymlinkTarget(existing); err != nil {
+					return err
+				}
+			} else if !info.IsDir() {
+				if existing == dir {
+					return fmtErrorf("log directory '%s' exists but is not a directory", dir)
+				}
+				return fmtErrorf("log directory '%s' cannot be created, '%s' is not a directory", dir, existing)
+			}
+			break
 		}
-		// Only consider files with correct extension
-		if ext != "" && filepath.Ext(entry.Name()) != targetExt {
-			continue
+		// A file or symlink loop further up the path surfaces as ENOTDIR or ELOOP, keep walking to report it
+		if !os.IsNotExist(err) && !errors.Is(err, syscall.ENOTDIR) && !errors.Is(err, syscall.ELOOP) {
+			return fmtErrorf("failed to inspect log directory '%s': %w", dir, err)
 		}
-		info, errInfo := entry.Info()
-		if errInfo != nil {
-			continue
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
 		}
-		if info.ModTime().Before(cutoffTime) {
-			filePath := filepath.Join(dir, entry.Name())
-			if err := os.Remove(filePath); err != nil {
-				l.internalLog("failed to remove expired log file '%s': %v\n", filePath, err)
-			} else {
-				deletedCount++
-				l.state.TotalDeletions.Add(1)
-			}
+		existing = parent
+	}
+
+	return checkTempBuildDirectory(dir)
+}
+
+// checkSymlinkTarget verifies that a symlink in the log directory path resolves to a directory
+func checkSymlinkTarget(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, syscall.ELOOP) {
+			return fmtErrorf("log directory path '%s' is a symlink loop", path)
+		}
+		if os.IsNotExist(err) {
+			return fmtErrorf("log directory path '%s' is a dangling symlink", path)
 		}
+		return fmtErrorf("failed to resolve symlink '%s': %w", path, err)
+	}
+	if !info.IsDir() {
+		return fmtErrorf("log directory path '%s' is a symlink to a non-directory", path)
+	}
+	return nil
+}
+
+// checkTempBuildDirectory rejects log directories inside the temporary directory holding the running binary
+// Only applies when the binary itself lives under the system temp directory
+func checkTempBuildDirectory(dir string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil
+	}
+	exeDir := filepath.Dir(resolvePath(exe))
+	if !isWithinDir(exeDir, resolvePath(os.TempDir())) {
+		return nil
 	}
 
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	if isWithinDir(resolvePath(absDir), exeDir) {
+		return fmtErrorf("log directory '%s' is inside the temporary build directory '%s' of the running binary, logs would be lost on exit", dir, exeDir)
+	}
 	return nil
 }
 
-// getStaticLogFilePath returns the full path to the active log file
-func (l *Logger) getStaticLogFilePath() string {
-	c := l.getConfig()
-	dir := c.Directory
-	ext := c.Extension
-	name := c.Name
+// resolvePath resolves symlinks in the longest existing prefix of path, keeping the missing remainder
+func resolvePath(path string) string {
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path
+	}
+	return filepath.Join(resolvePath(parent), filepath.Base(path))
+}
+
+// isWithinDir reports whether path equals dir or is located below it
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
 
+// logFilePath returns the full path to the active log file for the given configuration
+func logFilePath(c *Config) string {
 	// Handle extension with or without dot
-	filename := name
-	if ext != "" {
-		filename = name + "." + ext
+	filename := c.Name
+	if ext := c.fileExtension(); ext != "" {
+		filename = c.Name + "." + ext
 	}
 
-	return filepath.Join(dir, filename)
+	return filepath.Join(c.Directory, filename)
 }
 
-// generateArchiveLogFileName creates a timestamped filename for archived logs during rotation
-func (l *Logger) generateArchiveLogFileName(timestamp time.Time) string {
+// generateArchiveLogFileName creates the filename for an archive during rotation, {name}_{generation}_{timestamp}
+// The zero-padded generation orders archives even when the clock steps back or rotations share a timestamp
+func (l *Logger) generateArchiveLogFileName(timestamp time.Time, generation uint64) string {
 	c := l.getConfig()
-	ext := c.Extension
+	ext := c.fileExtension()
 	name := c.Name
 
 	tsFormat := timestamp.Format("060102_150405")
 	nano := timestamp.Nanosecond()
 
 	if ext != "" {
-		return fmt.Sprintf("%s_%s_%d.%s", name, tsFormat, nano, ext)
+		return fmt.Sprintf("%s_%0*d_%s_%d.%s", name, archiveGenerationDigits, generation, tsFormat, nano, ext)
+	}
+	return fmt.Sprintf("%s_%0*d_%s_%d", name, archiveGenerationDigits, generation, tsFormat, nano)
+}
+
+// archiveGeneration returns the rotation generation in an archive name, false for archives named without one
+func (m *logFileMatcher) archiveGeneration(fname string) (uint64, bool) {
+	if !m.isArchive(fname) {
+		return 0, false
+	}
+	suffix, _, _ := strings.Cut(fname[len(m.name)+1:], ".")
+	parts := strings.Split(suffix, "_")
+	// {generation}_{date}_{time}_{nano}, archives of earlier versions lack the generation
+	if len(parts) != 4 || len(parts[0]) < archiveGenerationDigits {
+		return 0, false
+	}
+	generation, err := strconv.ParseUint(parts[0], 10, 64)
+	return generation, err == nil
+}
+
+// nextRotationGeneration returns the generation of the next archive
+// The first rotation continues from the highest generation among existing archives and the rotation journal
+func (l *Logger) nextRotationGeneration(c *Config) uint64 {
+	if l.state.RotationGeneration.Load() == 0 {
+		l.state.RotationGeneration.Store(l.lastRotationGeneration(c))
+	}
+	return l.state.RotationGeneration.Add(1)
+}
+
+// lastRotationGeneration returns the highest generation among the archives in the log directory and the rotation
+// journal, which keeps it after archives are deleted; 0 when neither has one
+func (l *Logger) lastRotationGeneration(c *Config) uint64 {
+	var last uint64
+	if entries, err := os.ReadDir(c.Directory); err == nil {
+		matcher := l.getFileMatcher()
+		for _, entry := range entries {
+			if generation, ok := matcher.archiveGeneration(entry.Name()); ok {
+				last = max(last, generation)
+			}
+		}
+	}
+	return max(last, lastJournalGeneration(rotationJournalPath(c)))
+}
+
+// dropPageCache flushes a file about to be archived and advises the kernel to evict its cached pages
+// Archived logs are rarely read back, keeping them cached only displaces the application's working set
+func (l *Logger) dropPageCache(file *os.File) {
+	// Only clean pages are evicted, dirty pages must be written first
+	if err := file.Sync(); err != nil {
+		l.internalLog("failed to sync log file before dropping page cache: %v\n", err)
+		return
+	}
+	if err := fadviseDontNeed(file); err != nil {
+		l.internalLog("failed to drop page cache of log file: %v\n", err)
 	}
-	return fmt.Sprintf("%s_%s_%d", name, tsFormat, nano)
 }
 
 // createNewLogFile generates a unique name and opens a new log file
 func (l *Logger) createNewLogFile() (*os.File, error) {
-	fullPath := l.getStaticLogFilePath()
+	return openLogFile(l.getStaticLogFilePath(), l.getConfig().OpenDSync)
+}
 
-	file, err := os.OpenFile(fullPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// openLogFile opens or creates the log file at fullPath in append mode, with synchronized data writes if dsync is set
+func openLogFile(fullPath string, dsync bool) (*os.File, error) {
+	flags := os.O_APPEND | os.O_CREATE | os.O_WRONLY
+	if dsync {
+		flags |= openDSyncFlag
+	}
+	file, err := os.OpenFile(fullPath, flags, 0644)
 	if err != nil {
 		return nil, fmtErrorf("failed to open/create log file '%s': %w", fullPath, err)
 	}
 	return file, nil
 }
 
+// isSpecialFile reports whether path is an existing character device, pipe, or socket, e.g. /dev/stdout
+// or /proc/self/fd/1, which cannot be rotated, synced, or measured like a regular log file
+func isSpecialFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.Mode().IsRegular() && !info.IsDir()
+}
+
+// specialFileOutput reports whether the active log file is a special file, see isSpecialFile
+func (l *Logger) specialFileOutput() bool {
+	out := l.getEpoch().file
+	return out != nil && out.special
+}
+
+// recreateLogFileIfMissing recreates the log directory and active file if they were removed at runtime
+// Returns true if the file was recreated, only active when AutoRecreateDir is enabled
+func (l *Logger) recreateLogFileIfMissing() bool {
+	c := l.getConfig()
+	if !c.AutoRecreateDir || !c.fileOutput() {
+		return false
+	}
+
+	currentPath := l.getStaticLogFilePath()
+	if _, err := os.Stat(currentPath); err == nil || !os.IsNotExist(err) {
+		return false
+	}
+
+	if err := os.MkdirAll(c.Directory, 0755); err != nil {
+		l.internalLog("failed to recreate log directory '%s': %v\n", c.Directory, err)
+		return false
+	}
+
+	newFile, err := l.createNewLogFile()
+	if err != nil {
+		l.internalLog("failed to recreate log file '%s': %v\n", currentPath, err)
+		return false
+	}
+
+	// Release the handle to the unlinked file
+	if cfPtr := l.state.CurrentFile.Load(); cfPtr != nil {
+		if oldFile, ok := cfPtr.(*os.File); ok && oldFile != nil {
+			_ = l.closeGzipStream(oldFile)
+			_ = oldFile.Close()
+		}
+	}
+
+	l.activateLogFile(c, newFile)
+
+	recreatedRecord := logRecord{
+		Flags:     FlagDefault,
+		TimeStamp: time.Now(),
+		Level:     LevelWarn,
+		Args:      []any{"Log file or directory was removed, recreated", "file", currentPath},
+	}
+	l.sendLogRecord(recreatedRecord)
+	return true
+}
+
 // rotateLogFile implements the rename-on-rotate strategy
 // Closes current file, renames it with timestamp, creates new static file
-func (l *Logger) rotateLogFile() error {
+func (l *Logger) rotateLogFile(trigger string) error {
+	// A special file cannot be renamed, writes continue to it
+	if l.specialFileOutput() {
+		return nil
+	}
 	c := l.getConfig()
+	start := time.Now()
 
 	// Get current file handle
 	cfPtr := l.state.CurrentFile.Load()
@@ -410,8 +623,7 @@ func (l *Logger) rotateLogFile() error {
 		if err != nil {
 			return fmtErrorf("failed to create log file during rotation: %w", err)
 		}
-		l.state.CurrentFile.Store(newFile)
-		l.state.CurrentSize.Store(0)
+		l.activateLogFile(c, newFile)
 		l.state.TotalRotations.Add(1)
 		return nil
 	}
@@ -423,12 +635,18 @@ func (l *Logger) rotateLogFile() error {
 		if err != nil {
 			return fmtErrorf("failed to create log file during rotation: %w", err)
 		}
-		l.state.CurrentFile.Store(newFile)
-		l.state.CurrentSize.Store(0)
+		l.activateLogFile(c, newFile)
 		l.state.TotalRotations.Add(1)
 		return nil
 	}
 
+	if err := l.closeGzipStream(currentFile); err != nil {
+		l.internalLog("%v\n", err)
+	}
+	if c.FadviseDontNeed {
+		l.dropPageCache(currentFile)
+	}
+
 	// Close current file before renaming
 	if err := currentFile.Close(); err != nil {
 		l.internalLog("failed to close log file before rotation: %v\n", err)
@@ -437,12 +655,20 @@ func (l *Logger) rotateLogFile() error {
 
 	// Generate a new unique name with current timestamp for the old log file
 	dir := c.Directory
-	archiveName := l.generateArchiveLogFileName(time.Now())
+	generation := l.nextRotationGeneration(c)
+	archiveName := l.generateArchiveLogFileName(time.Now(), generation)
 	archivePath := filepath.Join(dir, archiveName)
 
 	// Rename current file to archive name
 	currentPath := l.getStaticLogFilePath()
 	if err := os.Rename(currentPath, archivePath); err != nil {
+		// The file or directory was removed externally, nothing to archive
+		if os.IsNotExist(err) && c.AutoRecreateDir {
+			l.state.CurrentFile.Store((*os.File)(nil))
+			if l.recreateLogFileIfMissing() {
+				return nil
+			}
+		}
 		// Critical failure: the original file is closed and couldn't be renamed
 		// This is a terminal state for file logging
 		l.internalLog("failed to rename log file from '%s' to '%s': %v. file logging disabled.",
@@ -458,9 +684,28 @@ func (l *Logger) rotateLogFile() error {
 	}
 
 	// Update state
-	l.state.CurrentFile.Store(newFile)
-	l.state.CurrentSize.Store(0)
+	archivedSize := l.state.CurrentSize.Load()
+	l.activateLogFile(c, newFile)
 	l.state.TotalRotations.Add(1)
+	if info, err := os.Stat(archivePath); err == nil {
+		l.state.dirSize.archived(info.Size())
+	} else {
+		l.state.dirSize.invalidate()
+	}
+
+	l.recordRotation(c, rotationEvent{
+		Time:       start,
+		Trigger:    trigger,
+		File:       filepath.Base(currentPath),
+		Archive:    archiveName,
+		Generation: generation,
+		Size:       archivedSize,
+		Duration:   time.Since(start),
+	})
+
+	if u := l.getEpoch().s3; u != nil {
+		u.enqueue(archivePath, start)
+	}
 
 	// Update earliest file time after successful rotation
 	l.updateEarliestFileTime()
@@ -468,26 +713,14 @@ func (l *Logger) rotateLogFile() error {
 	return nil
 }
 
-// getLogFileCount calculates the number of log files matching the current extension
-func (l *Logger) getLogFileCount(dir, ext string) (int, error) {
-	count := 0
-	entries, err := os.ReadDir(dir)
+// getLogFileCount returns the number of active and archived log files, counted like getLogDirSize
+func (l *Logger) getLogFileCount(dir string) (int, error) {
+	_, count, err := l.archiveTotals(dir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return 0, nil
-		}
-		return -1, fmtErrorf("failed to read log directory '%s': %w", dir, err)
+		return -1, err
 	}
-
-	targetExt := "." + ext
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		// Count all files matching the extension, including the current one if present
-		if filepath.Ext(entry.Name()) == targetExt {
-			count++
-		}
+	if _, ok := l.activeFileSize(dir); ok {
+		count++
 	}
 	return count, nil
 }
\ No newline at end of file
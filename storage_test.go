@@ -1,13 +1,19 @@
 package log
 
 import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/lixenwraith/log/logreader"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -131,4 +137,667 @@ func TestRetentionPolicy(t *testing.T) {
 	// Verify old file was deleted
 	_, err = os.Stat(oldFile)
 	assert.True(t, os.IsNotExist(err))
+}
+
+// TestEmptyExtensionCleanup verifies that without an extension, cleanup and retention remove name_<ts> archives while
+// the active file, error files, and unrelated files in the directory are kept
+func TestEmptyExtensionCleanup(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	// Archives created below are seen by the next scan
+	require.NoError(t, logger.ApplyConfigString("extension=", "split_error_file=true", "min_disk_free_kb=0",
+		"size_reconcile_interval_s=0"))
+	oldTime := time.Now().Add(-2 * time.Hour)
+	archives := []string{"log_2024-01-15_10-30-00_000000001", "log_2024-01-16_10-30-00_000000002"}
+	kept := []string{"log", "log_error", "log_error_2024-01-15_10-30-00_000000001", "log.txt", "log_notes.txt", "logbook", "other"}
+	create := func() {
+		for _, name := range append(archives, kept...) {
+			path := filepath.Join(tmpDir, name)
+			switch {
+			case name == "log" || name == "log_error":
+			case strings.HasPrefix(name, "log_error_"):
+				// Small enough that the error file logger's own size limit keeps it
+				require.NoError(t, os.WriteFile(path, []byte("a"), 0644))
+			default:
+				require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("a", 2000)), 0644))
+			}
+			require.NoError(t, os.Chtimes(path, oldTime, oldTime))
+		}
+	}
+	verify := func(step string) {
+		for _, name := range archives {
+			assert.NoFileExists(t, filepath.Join(tmpDir, name), step)
+		}
+		for _, name := range kept {
+			assert.FileExists(t, filepath.Join(tmpDir, name), step)
+		}
+	}
+
+	// Retention removes expired archives
+	require.NoError(t, logger.ApplyConfigString("retention_period_hrs=1"))
+	create()
+	require.NoError(t, logger.cleanExpiredLogs(oldTime))
+	verify("retention")
+
+	// The total size limit removes archives
+	require.NoError(t, logger.ApplyConfigString("retention_period_hrs=0", "max_total_size_kb=1"))
+	create()
+	logger.performDiskCheck(true)
+	verify("cleanup")
+}
+
+// TestRetentionPreview verifies the preview lists expired archives and the oldest archives over the size limit
+// without deleting them, and that dry-run cleanups keep files and log each one once
+func TestRetentionPreview(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	files := map[string]time.Duration{
+		"log_a.log": 3 * time.Hour,
+		"log_b.log": 2 * time.Hour,
+		"log_c.log": 30 * time.Minute,
+		"log_d.log": 20 * time.Minute,
+	}
+	for name, age := range files {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("a", 2000)), 0644))
+		modTime := time.Now().Add(-age)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	cfg := logger.GetConfig()
+	cfg.RetentionPeriodHrs = 1.0
+	cfg.MaxTotalSizeKB = 3 // Over by 5 KB: expired a and b free 4 KB, c is also needed
+	cfg.RetentionDryRun = true
+	require.NoError(t, logger.ApplyConfig(cfg))
+
+	preview, err := logger.RetentionPreview()
+	require.NoError(t, err)
+	var got []string
+	for _, f := range preview {
+		got = append(got, f.Name+"="+f.Reason)
+		assert.Equal(t, int64(2000), f.Size)
+	}
+	assert.Equal(t, []string{"log_a.log=age", "log_b.log=age", "log_c.log=size"}, got)
+
+	// Dry run: the passes report instead of deleting
+	require.NoError(t, logger.cleanExpiredLogs(time.Now().Add(-3*time.Hour)))
+	require.NoError(t, logger.cleanOldLogs(2000, RetentionReasonSize))
+	require.NoError(t, logger.cleanOldLogs(2000, RetentionReasonSize))
+	require.NoError(t, logger.Flush(time.Second))
+	for name := range files {
+		assert.FileExists(t, filepath.Join(tmpDir, name))
+	}
+	assert.Equal(t, uint64(0), logger.state.TotalDeletions.Load())
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "log.log"))
+	require.NoError(t, err)
+	// The processor's disk check reports too, but each archive only once per reason
+	for _, entry := range []string{"log_a.log reason age", "log_b.log reason age", "log_a.log reason size"} {
+		assert.Equal(t, 1, strings.Count(string(content), "file "+entry), entry)
+	}
+
+	cfg.RetentionDryRun = false
+	require.NoError(t, logger.ApplyConfig(cfg))
+	require.NoError(t, logger.cleanExpiredLogs(time.Now().Add(-3*time.Hour)))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "log_a.log"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "log_b.log"))
+	assert.FileExists(t, filepath.Join(tmpDir, "log_c.log"))
+}
+
+// TestRetentionClockSafety verifies that retention keeps files whose age depends on an unreliable clock
+func TestRetentionClockSafety(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	cfg := logger.GetConfig()
+	cfg.RetentionPeriodHrs = 1.0
+	require.NoError(t, logger.ApplyConfig(cfg))
+
+	files := map[string]time.Time{
+		"log_expired.log": time.Now().Add(-2 * time.Hour),
+		"log_margin.log":  time.Now().Add(-time.Hour - retentionClockMargin/2), // Within the safety margin
+		"log_epoch.log":   time.Unix(0, 0),                                     // Clock reset at boot
+		"log_future.log":  time.Now().Add(24 * time.Hour),                      // Written before a backward step
+	}
+	for name, modTime := range files {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(path, []byte("data"), 0644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	require.NoError(t, logger.cleanExpiredLogs(time.Unix(0, 0)))
+
+	for name := range files {
+		_, err := os.Stat(filepath.Join(tmpDir, name))
+		if name == "log_expired.log" {
+			assert.True(t, os.IsNotExist(err), "%s should be deleted", name)
+		} else {
+			assert.NoError(t, err, "%s should be kept", name)
+		}
+	}
+
+	// The reference time never runs ahead of the wall clock
+	assert.False(t, logger.retentionNow().After(time.Now()))
+	assert.WithinDuration(t, time.Now(), logger.retentionNow(), time.Second)
+}
+
+// TestRetentionClockForwardStep verifies a forward wall clock step is trusted once two consecutive passes see it
+func TestRetentionClockForwardStep(t *testing.T) {
+	// Started with the clock at the epoch, then NTP stepped it to the current time
+	var clock retentionClock
+	clock.wall, clock.mono = time.Unix(0, 0), time.Now()
+
+	first := clock.now(time.Now(), time.Time{})
+	assert.True(t, first.Before(time.Unix(3600, 0)), "a step seen once is not trusted")
+
+	second := clock.now(time.Now(), time.Time{})
+	assert.WithinDuration(t, time.Now(), second, time.Second)
+
+	// Later passes advance from the new anchor
+	assert.WithinDuration(t, time.Now(), clock.now(time.Now(), time.Time{}), time.Second)
+	assert.Zero(t, clock.step)
+}
+
+// TestAutoRecreateDir verifies that a removed log directory is recreated and logging resumes
+func TestAutoRecreateDir(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	logDir := filepath.Join(tmpDir, "logs")
+	err := logger.ApplyConfigString(
+		"directory="+logDir,
+		"auto_recreate_dir=true",
+		"disk_check_interval_ms=50",
+		"min_check_interval_ms=50",
+		"enable_adaptive_interval=false",
+	)
+	require.NoError(t, err)
+
+	logger.Info("before removal")
+	require.NoError(t, logger.Flush(time.Second))
+
+	require.NoError(t, os.RemoveAll(logDir))
+
+	// Wait for the periodic disk check to restore the directory
+	logPath := filepath.Join(logDir, "log.log")
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(logPath)
+		return err == nil
+	}, 2*time.Second, 20*time.Millisecond, "log file should be recreated")
+
+	logger.Info("after removal")
+	require.NoError(t, logger.Flush(time.Second))
+
+	require.Eventually(t, func() bool {
+		content, err := os.ReadFile(logPath)
+		return err == nil && strings.Contains(string(content), "after removal")
+	}, time.Second, 10*time.Millisecond)
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "recreated")
+	assert.NotContains(t, string(content), "before removal")
+}
+
+// TestFileHeader verifies that every new log file, including rotated ones, starts with a metadata header
+func TestFileHeader(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := NewLogger()
+
+	cfg := DefaultConfig()
+	cfg.EnableConsole = false
+	cfg.EnableFile = true
+	cfg.Directory = tmpDir
+	cfg.Format = "json"
+	cfg.FileHeader = true
+	cfg.MaxSizeKB = 10
+	require.NoError(t, logger.ApplyConfig(cfg))
+	require.NoError(t, logger.Start())
+
+	largeData := strings.Repeat("x", 1000)
+	for i := 0; i < 30; i++ {
+		logger.Info(largeData)
+	}
+	require.NoError(t, logger.Shutdown(time.Second))
+
+	files, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(files), 2, "Expected rotation to create archives")
+
+	for _, f := range files {
+		content, err := os.ReadFile(filepath.Join(tmpDir, f.Name()))
+		require.NoError(t, err)
+		firstLine, _, _ := strings.Cut(string(content), "\n")
+
+		var header map[string]fileHeader
+		require.NoError(t, json.Unmarshal([]byte(firstLine), &header), "File %s should start with a header", f.Name())
+		assert.Equal(t, fileHeaderSchemaVersion, header["log_header"].SchemaVersion)
+		assert.Equal(t, os.Getpid(), header["log_header"].PID)
+		assert.Equal(t, "json", header["log_header"].Format)
+		assert.Equal(t, 1, strings.Count(string(content), "log_header"), "Header must be written once per file")
+	}
+}
+
+func TestFileIOTuning(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := NewLogger()
+
+	cfg := DefaultConfig()
+	cfg.EnableConsole = false
+	cfg.EnableFile = true
+	cfg.Directory = tmpDir
+	cfg.MaxSizeKB = 10
+	cfg.FadviseDontNeed = true
+	cfg.OpenDSync = true
+	require.NoError(t, logger.ApplyConfig(cfg))
+	require.NoError(t, logger.Start())
+
+	largeData := strings.Repeat("x", 1000)
+	const records = 30
+	for i := 0; i < records; i++ {
+		logger.Info(largeData)
+	}
+	require.NoError(t, logger.Shutdown(time.Second))
+
+	files, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(files), 2, "Expected rotation to create archives")
+
+	// Dropping cached pages must not lose archived content
+	total := 0
+	for _, f := range files {
+		content, err := os.ReadFile(filepath.Join(tmpDir, f.Name()))
+		require.NoError(t, err)
+		total += strings.Count(string(content), largeData)
+	}
+	assert.Equal(t, records, total)
+}
+
+func TestCheckLogDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	regularFile := filepath.Join(tmpDir, "file")
+	require.NoError(t, os.WriteFile(regularFile, []byte("x"), 0644))
+	validDir := filepath.Join(tmpDir, "dir")
+	require.NoError(t, os.Mkdir(validDir, 0755))
+
+	dangling := filepath.Join(tmpDir, "dangling")
+	require.NoError(t, os.Symlink(filepath.Join(tmpDir, "missing"), dangling))
+	loopA := filepath.Join(tmpDir, "loop_a")
+	loopB := filepath.Join(tmpDir, "loop_b")
+	require.NoError(t, os.Symlink(loopB, loopA))
+	require.NoError(t, os.Symlink(loopA, loopB))
+	dirLink := filepath.Join(tmpDir, "dir_link")
+	require.NoError(t, os.Symlink(validDir, dirLink))
+
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr string
+	}{
+		{"missing directory", filepath.Join(tmpDir, "new", "logs"), ""},
+		{"existing directory", validDir, ""},
+		{"symlink to directory", dirLink, ""},
+		{"regular file", regularFile, "is not a directory"},
+		{"file in path", filepath.Join(regularFile, "logs"), "cannot be created"},
+		{"dangling symlink", dangling, "dangling symlink"},
+		{"symlink loop", filepath.Join(loopA, "logs"), "symlink loop"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkLogDirectory(tt.dir)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("temporary build directory", func(t *testing.T) {
+		exe, err := os.Executable()
+		require.NoError(t, err)
+		exeDir := filepath.Dir(resolvePath(exe))
+		if !isWithinDir(exeDir, resolvePath(os.TempDir())) {
+			t.Skip("test binary is not in the temp directory")
+		}
+		err = checkLogDirectory(filepath.Join(exeDir, "logs"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "temporary build directory")
+	})
+
+	t.Run("apply config keeps current configuration", func(t *testing.T) {
+		logger, logDir := createTestLogger(t)
+		defer logger.Shutdown()
+
+		cfg := logger.GetConfig()
+		cfg.Directory = regularFile
+		err := logger.ApplyConfig(cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a directory")
+		assert.Equal(t, logDir, logger.GetConfig().Directory)
+	})
+}
+
+// TestRotationJournal verifies that each rotation is recorded and the journal stays bounded
+func TestRotationJournal(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := NewLogger()
+
+	cfg := DefaultConfig()
+	cfg.EnableConsole = false
+	cfg.EnableFile = true
+	cfg.Directory = tmpDir
+	cfg.MaxSizeKB = 10
+	cfg.RotationJournal = true
+	require.NoError(t, logger.ApplyConfig(cfg))
+	require.NoError(t, logger.Start())
+
+	largeData := strings.Repeat("x", 1000)
+	for i := 0; i < 30; i++ {
+		logger.Info(largeData)
+	}
+	require.NoError(t, logger.Shutdown(time.Second))
+
+	journal, err := os.Open(rotationJournalPath(cfg))
+	require.NoError(t, err)
+	defer journal.Close()
+	events, err := logreader.ReadRotationJournal(journal)
+	require.NoError(t, err)
+
+	require.Len(t, events, int(logger.Stats().Rotations))
+	require.NotEmpty(t, events)
+	for i, event := range events {
+		assert.Equal(t, uint64(i+1), event.Generation)
+		info, ok := logreader.ParseArchiveName(event.Archive, "log")
+		require.True(t, ok, event.Archive)
+		assert.Equal(t, event.Generation, info.Generation)
+		assert.Equal(t, rotationTriggerSize, event.Trigger)
+		assert.Equal(t, "log.log", event.File)
+		assert.FileExists(t, filepath.Join(tmpDir, event.Archive))
+		assert.Greater(t, event.Size, int64(9000))
+	}
+
+	t.Run("trim", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log.rotations")
+		line := strings.Repeat("x", 99) + "\n"
+		require.NoError(t, os.WriteFile(path, []byte(strings.Repeat(line, rotationJournalMaxBytes/100+10)), 0644))
+
+		require.NoError(t, trimRotationJournal(path))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(data), rotationJournalMaxBytes/2)
+		assert.Zero(t, len(data)%len(line), "Only whole lines are kept")
+	})
+}
+
+// TestRotationGeneration verifies archive generations continue from existing archives and the rotation journal,
+// and order retention independent of modification times
+func TestRotationGeneration(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	// An archive from an earlier run, modified after the legacy one despite its older name
+	legacy := filepath.Join(tmpDir, "log_240115_103000_0.log")
+	previous := filepath.Join(tmpDir, "log_00000041_240115_090000_0.log")
+	require.NoError(t, os.WriteFile(legacy, []byte("legacy"), 0644))
+	require.NoError(t, os.WriteFile(previous, []byte("previous"), 0644))
+	require.NoError(t, os.Chtimes(legacy, time.Now(), time.Now()))
+	require.NoError(t, os.Chtimes(previous, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)))
+
+	require.NoError(t, logger.rotateLogFile(rotationTriggerSize))
+	require.NoError(t, logger.rotateLogFile(rotationTriggerSize))
+	assert.Equal(t, uint64(43), logger.state.RotationGeneration.Load())
+
+	archives, err := logger.listArchives(tmpDir)
+	require.NoError(t, err)
+	var generations []uint64
+	for _, archive := range archives {
+		generations = append(generations, archive.Generation)
+	}
+	assert.Equal(t, []uint64{0, 41, 42, 43}, generations)
+	assert.Equal(t, "log_240115_103000_0.log", archives[0].Name)
+
+	// After archives are deleted, the journal keeps the sequence going
+	cfg := logger.GetConfig()
+	cfg.RotationJournal = true
+	require.NoError(t, os.WriteFile(rotationJournalPath(cfg), []byte(`{"generation":99}`+"\n"), 0644))
+	for _, archive := range archives {
+		require.NoError(t, os.Remove(archive.Path))
+	}
+	cfg.Name = "other"
+	require.NoError(t, logger.ApplyConfig(cfg))
+	cfg.Name = "log"
+	require.NoError(t, logger.ApplyConfig(cfg))
+	require.NoError(t, logger.rotateLogFile(rotationTriggerSize))
+	assert.Equal(t, uint64(100), logger.state.RotationGeneration.Load())
+}
+
+// TestDirSizeAccounting verifies archive totals follow rotations and deletions, and files added by others
+// are counted from the next reconciliation scan
+func TestDirSizeAccounting(t *testing.T) {
+	logger, tmpDir := createTestLogger(t)
+	defer logger.Shutdown()
+
+	logger.Info("first file")
+	require.NoError(t, logger.Flush(time.Second))
+	require.NoError(t, logger.rotateLogFile(rotationTriggerSize))
+	logger.Info("second file")
+	require.NoError(t, logger.Flush(time.Second))
+	require.NoError(t, logger.rotateLogFile(rotationTriggerSize))
+
+	scanned := func() int64 {
+		var size int64
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		for _, entry := range entries {
+			if logger.getFileMatcher().matches(entry.Name()) {
+				info, err := entry.Info()
+				require.NoError(t, err)
+				size += info.Size()
+			}
+		}
+		return size
+	}
+	size, err := logger.getLogDirSize(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, scanned(), size)
+	count, err := logger.getLogFileCount(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// A file written by another process is not seen until the totals are reconciled
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "log_00000099_240115_090000_0.log"), []byte("external"), 0644))
+	size, err = logger.getLogDirSize(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, scanned()-int64(len("external")), size)
+
+	require.NoError(t, logger.ApplyConfigString("size_reconcile_interval_s=0"))
+	size, err = logger.getLogDirSize(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, scanned(), size)
+
+	// Deletions by the logger update the totals without a scan
+	require.NoError(t, logger.ApplyConfigString("size_reconcile_interval_s=3600"))
+	require.NoError(t, logger.cleanOldLogs(0, ""))
+	count, err = logger.getLogFileCount(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	size, err = logger.getLogDirSize(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, scanned(), size)
+}
+
+// TestSplitErrorFile verifies WARN and ERROR records are duplicated into the error file, kept apart from the main file's archives
+func TestSplitErrorFile(t *testing.T) {
+	logger, dir := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString("split_error_file=true"))
+
+	logger.Info("info record")
+	logger.Warn("warn record")
+	logger.Error("error record")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "info record")
+	assert.Contains(t, string(content), "error record")
+
+	errContent, err := os.ReadFile(filepath.Join(dir, "log_error.log"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(errContent), "info record")
+	assert.Contains(t, string(errContent), "warn record")
+	assert.Contains(t, string(errContent), "error record")
+
+	matcher := logger.getFileMatcher()
+	assert.False(t, matcher.isArchive("log_error.log"))
+	assert.False(t, matcher.isArchive("log_error_20240101_120000_1.log"))
+	assert.True(t, matcher.isArchive("log_20240101_120000_1.log"))
+
+	var found bool
+	for _, s := range logger.Stats().Sinks {
+		found = found || s.Name == "file:log_error"
+	}
+	assert.True(t, found)
+
+	require.NoError(t, logger.ApplyConfigString("split_error_file=false"))
+	assert.Nil(t, logger.getErrorFile())
+	assert.True(t, logger.getFileMatcher().isArchive("log_error.log"))
+}
+
+// TestMirrorDirectory verifies file records are copied to the mirror directory without a stalled mirror holding back the file
+func TestMirrorDirectory(t *testing.T) {
+	logger, dir := createTestLogger(t)
+	defer logger.Shutdown()
+	mirrorDir := t.TempDir()
+	require.NoError(t, logger.ApplyConfigString("mirror_directory="+mirrorDir))
+	mirror := logger.getMirror()
+	require.NotNil(t, mirror)
+
+	logger.Info("first record")
+	logger.Debug("below level")
+	require.NoError(t, logger.Flush(time.Second))
+
+	content, err := os.ReadFile(filepath.Join(mirrorDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "first record")
+	assert.NotContains(t, string(content), "below level")
+
+	// A stalled mirror delays its own copy only
+	mirror.batchMu.Lock()
+	logger.Info("second record")
+	assert.Error(t, logger.Flush(100*time.Millisecond))
+	content, err = os.ReadFile(filepath.Join(dir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "second record")
+	mirror.batchMu.Unlock()
+
+	require.NoError(t, logger.Flush(time.Second))
+	content, err = os.ReadFile(filepath.Join(mirrorDir, "log.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "second record")
+
+	var found bool
+	for _, s := range logger.Stats().Sinks {
+		found = found || s.Name == "mirror:"+mirrorDir
+	}
+	assert.True(t, found)
+
+	// Unavailable storage disables the mirror, the primary configuration still applies
+	blocker := filepath.Join(mirrorDir, "file")
+	require.NoError(t, os.WriteFile(blocker, nil, 0644))
+	require.NoError(t, logger.ApplyConfigString("mirror_directory="+filepath.Join(blocker, "mirror")))
+	assert.Nil(t, logger.getMirror())
+
+	assert.Error(t, logger.ApplyConfigString("mirror_directory="+dir))
+}
+
+// TestSpecialFileOutput verifies file output to a character device skips rotation, syncing, and disk checks
+func TestSpecialFileOutput(t *testing.T) {
+	logger := NewLogger()
+	cfg := DefaultConfig()
+	cfg.EnableConsole = false
+	cfg.EnableFile = true
+	cfg.Directory = "/dev"
+	cfg.Name = "null"
+	cfg.Extension = ""
+	cfg.MaxSizeKB = 1
+	cfg.MaxTotalSizeKB = 1
+	cfg.RetentionPeriodHrs = 1
+	cfg.AuditVerify = true
+	require.NoError(t, logger.ApplyConfig(cfg))
+	require.NoError(t, logger.Start())
+
+	assert.True(t, logger.specialFileOutput())
+	for i := 0; i < 20; i++ {
+		logger.Info("special", strings.Repeat("x", 100))
+	}
+	require.NoError(t, logger.Audit(context.Background(), LevelInfo, "confirmed without read-back"))
+	require.NoError(t, logger.Flush(time.Second))
+
+	assert.True(t, logger.performDiskCheck(true))
+	assert.Equal(t, uint64(0), logger.state.TotalRotations.Load())
+	assert.Equal(t, uint64(0), logger.state.DroppedLogs.Load())
+	preview, err := logger.RetentionPreview()
+	require.NoError(t, err)
+	assert.Empty(t, preview)
+
+	require.NoError(t, logger.Shutdown())
+	_, err = os.Stat("/dev/null")
+	assert.NoError(t, err, "Shutdown must not remove or rename the device")
+}
+
+// TestGzipActive verifies the active file is written compressed, readable at flush points, and rotated by compressed size
+func TestGzipActive(t *testing.T) {
+	logger, dir := createTestLogger(t)
+	require.NoError(t, logger.ApplyConfigString("gzip_active=true", "format=txt", "file_header=true"))
+
+	logger.Info("compressed record")
+	require.NoError(t, logger.Flush(time.Second))
+
+	readAll := func(path string) string {
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		r, err := logreader.Decompress(file)
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return string(data)
+	}
+	activePath := filepath.Join(dir, "log.log.gz")
+	content := readAll(activePath)
+	assert.Contains(t, content, "# log_header")
+	assert.Contains(t, content, "compressed record")
+
+	// Incompressible records reach the size limit and rotate into complete gzip archives
+	require.NoError(t, logger.ApplyConfigString("max_size_kb=16"))
+	for i := 0; i < 200; i++ {
+		var random strings.Builder
+		for j := 0; j < 32; j++ {
+			fmt.Fprintf(&random, "%016x", rand.Uint64())
+		}
+		logger.Info("filler", random.String())
+	}
+	require.NoError(t, logger.Flush(time.Second))
+	require.NoError(t, logger.Shutdown())
+
+	archives, err := filepath.Glob(filepath.Join(dir, "log_*.log.gz"))
+	require.NoError(t, err)
+	require.NotEmpty(t, archives)
+	for _, archive := range archives {
+		file, err := os.Open(archive)
+		require.NoError(t, err)
+		zr, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		_, err = io.ReadAll(zr)
+		assert.NoError(t, err, "Archives hold complete gzip members")
+		file.Close()
+	}
+	assert.Contains(t, readAll(activePath)+readAll(archives[0]), "filler")
 }
\ No newline at end of file
@@ -0,0 +1,133 @@
+package log
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SyncGroup batches the file syncs of loggers writing to the same disks into group commits
+// A sync requested while a commit is in progress joins the next commit, which syncs all requested files at once
+// On Linux, files on the same filesystem are flushed with a single syncfs call instead of one fsync each
+type SyncGroup struct {
+	window     time.Duration // Time a commit waits for more requests before syncing
+	mu         sync.Mutex
+	next       *syncBatch // Batch collecting requests for the next commit, nil if none are pending
+	committing bool       // A member is committing a batch
+	requests   atomic.Uint64
+	commits    atomic.Uint64
+	syncs      atomic.Uint64
+}
+
+// SyncGroupStats reports the syncs requested by member loggers and the work performed to serve them
+type SyncGroupStats struct {
+	Requests uint64 // File syncs requested by member loggers
+	Commits  uint64 // Group commits performed
+	Syncs    uint64 // fsync and syncfs calls issued
+}
+
+// syncBatch collects the files of one group commit
+type syncBatch struct {
+	files   map[*os.File]error // Requested files, set to their sync result by the committing member
+	ready   chan struct{}      // Closed when no other commit is in progress and a member should commit this batch
+	done    chan struct{}      // Closed when the batch is committed
+	claimed bool               // A member is committing this batch
+}
+
+// NewSyncGroup creates a sync coordinator, window delays each commit to collect requests from more loggers
+// A zero window only batches requests that arrive while another commit is running
+func NewSyncGroup(window time.Duration) *SyncGroup {
+	return &SyncGroup{window: max(window, 0)}
+}
+
+// Stats returns the group's request and commit counters
+func (g *SyncGroup) Stats() SyncGroupStats {
+	return SyncGroupStats{
+		Requests: g.requests.Load(),
+		Commits:  g.commits.Load(),
+		Syncs:    g.syncs.Load(),
+	}
+}
+
+// sync durably writes file as part of the next group commit, blocking until that commit completes
+// The first member to find the batch ready commits it on its own goroutine, the others wait for the result
+func (g *SyncGroup) sync(file *os.File) error {
+	g.requests.Add(1)
+
+	g.mu.Lock()
+	if g.next == nil {
+		g.next = &syncBatch{
+			files: make(map[*os.File]error),
+			ready: make(chan struct{}),
+			done:  make(chan struct{}),
+		}
+		if !g.committing {
+			close(g.next.ready)
+		}
+	}
+	batch := g.next
+	batch.files[file] = nil
+	g.mu.Unlock()
+
+	select {
+	case <-batch.done:
+		return batch.files[file]
+	case <-batch.ready:
+	}
+
+	g.mu.Lock()
+	if batch.claimed {
+		g.mu.Unlock()
+		<-batch.done
+		return batch.files[file]
+	}
+	batch.claimed = true
+	g.committing = true
+	g.mu.Unlock()
+
+	if g.window > 0 {
+		// Requests arriving meanwhile still join this batch
+		time.Sleep(g.window)
+	}
+
+	g.mu.Lock()
+	g.next = nil
+	g.mu.Unlock()
+
+	// The batch is detached, only this goroutine touches its files until done is closed
+	g.syncs.Add(uint64(syncFiles(batch.files)))
+	g.commits.Add(1)
+	close(batch.done)
+
+	g.mu.Lock()
+	g.committing = false
+	if g.next != nil {
+		close(g.next.ready)
+	}
+	g.mu.Unlock()
+
+	return batch.files[file]
+}
+
+// fsyncFiles syncs each file individually, storing the results in files, and returns the number of calls
+func fsyncFiles(files map[*os.File]error) int {
+	for f := range files {
+		files[f] = f.Sync()
+	}
+	return len(files)
+}
+
+// JoinSyncGroup makes the logger and its shards sync files through g, nil restores independent syncs
+func (l *Logger) JoinSyncGroup(g *SyncGroup) {
+	l.syncGroup.Store(g)
+}
+
+// getSyncGroup returns the sync group of the logger, shards use the group of their parent
+func (c *loggerCore) getSyncGroup() *SyncGroup {
+	if c.parent != nil {
+		return c.parent.getSyncGroup()
+	}
+	g, _ := c.syncGroup.Load().(*SyncGroup)
+	return g
+}
\ No newline at end of file
@@ -0,0 +1,72 @@
+//go:build linux && (amd64 || arm64 || riscv64 || loong64 || ppc64 || ppc64le || s390x || mips64 || mips64le)
+
+package log
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// sysSyncfs is the syncfs system call number, missing from the syscall package on amd64
+var sysSyncfs = map[string]uintptr{
+	"amd64": 306, "arm64": 267, "riscv64": 267, "loong64": 267,
+	"ppc64": 348, "ppc64le": 348, "s390x": 338, "mips64": 5301, "mips64le": 5301,
+}[runtime.GOARCH]
+
+// syncFiles flushes files with one syncfs call per filesystem holding more than one of them, storing the results
+// in files, and returns the number of calls; single files and failed lookups fall back to fsync
+func syncFiles(files map[*os.File]error) int {
+	if len(files) < 2 {
+		return fsyncFiles(files)
+	}
+
+	byDevice := make(map[uint64][]*os.File)
+	calls := 0
+	for f := range files {
+		var stat *syscall.Stat_t
+		if info, err := f.Stat(); err == nil {
+			stat, _ = info.Sys().(*syscall.Stat_t)
+		}
+		if stat == nil {
+			files[f] = f.Sync()
+			calls++
+			continue
+		}
+		dev := uint64(stat.Dev)
+		byDevice[dev] = append(byDevice[dev], f)
+	}
+
+	for _, group := range byDevice {
+		calls++
+		if len(group) == 1 {
+			files[group[0]] = group[0].Sync()
+			continue
+		}
+		err := syncfs(group[0])
+		for _, f := range group {
+			files[f] = err
+		}
+	}
+	return calls
+}
+
+// syncfs commits all dirty data of the filesystem containing file
+func syncfs(file *os.File) error {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var errno syscall.Errno
+	ctrlErr := conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(sysSyncfs, fd, 0, 0)
+	})
+	if ctrlErr != nil {
+		return ctrlErr
+	}
+	if errno != 0 {
+		return &os.PathError{Op: "syncfs", Path: file.Name(), Err: errno}
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,12 @@
+//go:build !linux || !(amd64 || arm64 || riscv64 || loong64 || ppc64 || ppc64le || s390x || mips64 || mips64le)
+
+package log
+
+import (
+	"os"
+)
+
+// syncFiles syncs each file individually on platforms without syncfs support
+func syncFiles(files map[*os.File]error) int {
+	return fsyncFiles(files)
+}
\ No newline at end of file
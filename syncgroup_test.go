@@ -0,0 +1,80 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSyncGroupBatches verifies concurrent sync requests are served by fewer commits, each reporting its result
+func TestSyncGroupBatches(t *testing.T) {
+	dir := t.TempDir()
+	group := NewSyncGroup(20 * time.Millisecond)
+
+	const members = 8
+	var wg sync.WaitGroup
+	errs := make([]error, members)
+	for i := range members {
+		file, err := os.Create(filepath.Join(dir, "member"+string(rune('a'+i))))
+		require.NoError(t, err)
+		defer file.Close()
+		_, err = file.WriteString("data")
+		require.NoError(t, err)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = group.sync(file)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	stats := group.Stats()
+	assert.Equal(t, uint64(members), stats.Requests)
+	assert.Less(t, stats.Commits, stats.Requests, "Concurrent requests should share commits")
+	assert.Less(t, stats.Syncs, stats.Requests, "Files on one filesystem should share sync calls")
+}
+
+// TestSyncGroupLoggers verifies loggers joined to a group sync their files through it
+func TestSyncGroupLoggers(t *testing.T) {
+	group := NewSyncGroup(10 * time.Millisecond)
+
+	var loggers []*Logger
+	for range 3 {
+		logger, _ := createTestLogger(t)
+		defer logger.Shutdown()
+		// Only explicit flushes sync, keeping the request count deterministic
+		require.NoError(t, logger.ApplyConfigString("enable_periodic_sync=false"))
+		logger.JoinSyncGroup(group)
+		loggers = append(loggers, logger)
+	}
+
+	var wg sync.WaitGroup
+	for _, logger := range loggers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info("tenant record")
+			result, err := logger.FlushStats(time.Second)
+			assert.NoError(t, err)
+			assert.True(t, result.Synced)
+		}()
+	}
+	wg.Wait()
+
+	stats := group.Stats()
+	assert.Equal(t, uint64(len(loggers)), stats.Requests)
+	assert.LessOrEqual(t, stats.Commits, stats.Requests)
+
+	loggers[0].JoinSyncGroup(nil)
+	require.NoError(t, loggers[0].Flush(time.Second))
+	assert.Equal(t, stats.Requests, group.Stats().Requests, "A logger that left the group syncs independently")
+}
\ No newline at end of file
@@ -0,0 +1,337 @@
+package log
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lixenwraith/log/formatter"
+	"github.com/lixenwraith/log/sanitizer"
+)
+
+// Syslog message formats
+const (
+	SyslogRFC3164 = "rfc3164"
+	SyslogRFC5424 = "rfc5424"
+)
+
+// syslogFacilities maps facility names to their RFC 5424 codes
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogLocalPaths are the usual local syslog daemon sockets, tried in order when no network is configured
+var syslogLocalPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// errSyslogUnavailable is recorded for records written while reconnection is backing off
+var errSyslogUnavailable = fmtErrorf("syslog daemon unavailable, waiting to reconnect")
+
+// syslogSettings are the configuration values a syslog output is built from
+type syslogSettings struct {
+	network         string
+	address         string
+	format          string
+	facility        string
+	tag             string
+	bodyFormat      string
+	bytesEncoding   string
+	durationFormat  string
+	timeValueFormat string
+	sanitization    sanitizer.PolicyPreset
+	cef             cefSettings // Header fields and extension keys of CEF bodies
+}
+
+// syslogOutput forwards records to a local or remote syslog daemon
+// The connection is established on the first write and re-established after failures, all on the processor goroutine
+type syslogOutput struct {
+	settings  syslogSettings
+	facility  int
+	hostname  string
+	pid       string
+	formatter *formatter.Formatter // Formats the message body without timestamp and level, carried by the header
+	conn      net.Conn
+	stream    bool      // Stream connections need framing, datagrams carry one message each
+	retryAt   time.Time // No reconnect attempts before this time after a failed dial
+	status    healthTracker
+	buf       []byte // Framed message, reused across records
+	msgBuf    []byte // Unframed message, reused across records
+}
+
+// newSyslogSettings extracts syslog settings from cfg, the tag defaults to the log name
+func newSyslogSettings(cfg *Config) syslogSettings {
+	tag := cfg.SyslogTag
+	if tag == "" {
+		tag = cfg.Name
+	}
+	// Binary and MessagePack records are not text, GELF carries its own envelope, CSV rows need their header, and
+	// RFC 5424 lines would repeat the syslog header, syslog bodies fall back to txt; CEF lines are sent as they are,
+	// syslog being their usual transport
+	bodyFormat := cfg.bodyFormat()
+	if bodyFormat == "binary" || bodyFormat == "msgpack" || bodyFormat == "gelf" || bodyFormat == "csv" || bodyFormat == "rfc5424" {
+		bodyFormat = "txt"
+	}
+	settings := syslogSettings{
+		network:         cfg.SyslogNetwork,
+		address:         cfg.SyslogAddress,
+		format:          cfg.SyslogFormat,
+		facility:        cfg.SyslogFacility,
+		tag:             tag,
+		bodyFormat:      bodyFormat,
+		bytesEncoding:   cfg.BytesEncoding,
+		durationFormat:  cfg.DurationFormat,
+		timeValueFormat: cfg.TimeValueFormat,
+		sanitization:    cfg.sanitization(cfg.NetworkSanitization, bodyFormat),
+	}
+	if bodyFormat == "cef" {
+		settings.cef = newCEFSettings(cfg)
+	}
+	return settings
+}
+
+// newSyslogOutput creates an unconnected syslog output
+func newSyslogOutput(settings syslogSettings) *syslogOutput {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	f := formatter.New(sanitizer.New().Policy(settings.sanitization)).
+		Type(settings.bodyFormat).
+		ShowTimestamp(false).
+		ShowLevel(false).
+		BytesEncoding(settings.bytesEncoding).
+		DurationFormat(settings.durationFormat).
+		TimeValueFormat(settings.timeValueFormat)
+	settings.cef.apply(f)
+	return &syslogOutput{
+		settings:  settings,
+		facility:  syslogFacilities[settings.facility],
+		hostname:  hostname,
+		pid:       strconv.Itoa(os.Getpid()),
+		formatter: f,
+	}
+}
+
+// configureSyslog returns the syslog output for cfg, reusing current when its settings are unchanged
+// Returns nil when syslog is disabled
+func configureSyslog(cfg *Config, current *syslogOutput) *syslogOutput {
+	if !cfg.EnableSyslog {
+		return nil
+	}
+	settings := newSyslogSettings(cfg)
+	if current != nil && current.settings == settings {
+		return current
+	}
+	return newSyslogOutput(settings)
+}
+
+// write sends a record, connecting first if needed; a failed write drops the connection for the next record
+func (o *syslogOutput) write(record logRecord) {
+	if o.conn == nil {
+		if time.Now().Before(o.retryAt) {
+			o.status.failure(errSyslogUnavailable)
+			return
+		}
+		if err := o.connect(); err != nil {
+			o.retryAt = time.Now().Add(syslogRetryInterval)
+			o.status.failure(err)
+			return
+		}
+	}
+
+	o.buf = o.appendMessage(o.buf[:0], record)
+	_ = o.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+	if _, err := o.conn.Write(o.buf); err != nil {
+		o.status.failure(fmtErrorf("failed to write to syslog: %w", err))
+		o.close()
+		return
+	}
+	o.status.success()
+}
+
+// connect dials the configured daemon, or the first reachable local socket when no network is set
+func (o *syslogOutput) connect() error {
+	if o.settings.network != "" {
+		conn, err := net.DialTimeout(o.settings.network, o.settings.address, syslogTimeout)
+		if err != nil {
+			return fmtErrorf("failed to connect to syslog at %s://%s: %w", o.settings.network, o.settings.address, err)
+		}
+		o.conn = conn
+		o.stream = o.settings.network != "udp" && o.settings.network != "unixgram"
+		return nil
+	}
+
+	for _, path := range syslogLocalPaths {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err := net.DialTimeout(network, path, syslogTimeout); err == nil {
+				o.conn = conn
+				o.stream = network == "unix"
+				return nil
+			}
+		}
+	}
+	return fmtErrorf("no local syslog socket found (tried %s)", strings.Join(syslogLocalPaths, ", "))
+}
+
+// close releases the connection, the next write reconnects
+func (o *syslogOutput) close() {
+	if o.conn != nil {
+		_ = o.conn.Close()
+		o.conn = nil
+	}
+}
+
+// appendMessage appends the framed syslog message for a record
+func (o *syslogOutput) appendMessage(buf []byte, record logRecord) []byte {
+	body := o.formatter.Format(
+		record.Flags&^(FlagShowTimestamp|FlagShowLevel),
+		record.TimeStamp,
+		record.Level,
+		record.Trace,
+		record.Args,
+	)
+	body = bytes.TrimRight(body, "\n")
+	pri := o.facility*8 + syslogSeverity(record.Level)
+
+	msg := o.msgBuf[:0]
+	if o.settings.format == SyslogRFC3164 {
+		// <PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG, local daemons add the hostname themselves
+		msg = append(msg, '<')
+		msg = strconv.AppendInt(msg, int64(pri), 10)
+		msg = append(msg, '>')
+		msg = record.TimeStamp.AppendFormat(msg, time.Stamp)
+		msg = append(msg, ' ')
+		if o.settings.network != "" {
+			msg = append(msg, o.hostname...)
+			msg = append(msg, ' ')
+		}
+		msg = append(msg, o.settings.tag...)
+		msg = append(msg, '[')
+		msg = append(msg, o.pid...)
+		msg = append(msg, "]: "...)
+	} else {
+		// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+		msg = append(msg, '<')
+		msg = strconv.AppendInt(msg, int64(pri), 10)
+		msg = append(msg, ">1 "...)
+		msg = record.TimeStamp.AppendFormat(msg, "2006-01-02T15:04:05.000000Z07:00")
+		msg = append(msg, ' ')
+		msg = append(msg, o.hostname...)
+		msg = append(msg, ' ')
+		msg = append(msg, o.settings.tag...)
+		msg = append(msg, ' ')
+		msg = append(msg, o.pid...)
+		msg = append(msg, " - - "...)
+	}
+	msg = append(msg, body...)
+	o.msgBuf = msg
+
+	switch {
+	case !o.stream:
+		return append(buf, msg...)
+	case o.settings.format == SyslogRFC5424 && o.settings.network != "unix" && o.settings.network != "":
+		// RFC 6587 octet counting for remote RFC 5424 streams
+		buf = strconv.AppendInt(buf, int64(len(msg)), 10)
+		buf = append(buf, ' ')
+		return append(buf, msg...)
+	default:
+		// Newline framing for RFC 3164 streams and local stream sockets
+		buf = append(buf, msg...)
+		return append(buf, '\n')
+	}
+}
+
+// health reports delivery health of the syslog output
+func (o *syslogOutput) health(name string) SinkHealth {
+	return o.status.snapshot(name)
+}
+
+// syslogSeverity maps log levels to syslog severities, heartbeats are notices
+func syslogSeverity(level int64) int {
+	switch {
+	case level >= LevelProc:
+		return 5 // notice
+	case level >= LevelError:
+		return 3 // err
+	case level >= LevelWarn:
+		return 4 // warning
+	case level >= LevelInfo:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// validateSyslog checks the syslog settings of cfg
+func validateSyslog(c *Config) error {
+	switch c.SyslogNetwork {
+	case "":
+		// local daemon socket
+	case "udp", "tcp", "unix", "unixgram":
+		if strings.TrimSpace(c.SyslogAddress) == "" {
+			return fmtErrorf("syslog_address is required for syslog_network '%s'", c.SyslogNetwork)
+		}
+	default:
+		return fmtErrorf("invalid syslog_network: '%s' (use udp, tcp, unix, unixgram, or empty for the local daemon)", c.SyslogNetwork)
+	}
+
+	if c.SyslogFormat != SyslogRFC3164 && c.SyslogFormat != SyslogRFC5424 {
+		return fmtErrorf("invalid syslog_format: '%s' (use rfc3164 or rfc5424)", c.SyslogFormat)
+	}
+
+	if _, ok := syslogFacilities[c.SyslogFacility]; !ok {
+		return fmtErrorf("invalid syslog_facility: '%s' (use kern, user, daemon, auth, local0-local7, ...)", c.SyslogFacility)
+	}
+
+	if strings.ContainsAny(c.SyslogTag, " \t\n") {
+		return fmtErrorf("syslog_tag cannot contain whitespace: '%s'", c.SyslogTag)
+	}
+
+	// SD-IDs without an enterprise number are reserved for IANA registered names
+	name, enterprise, _ := strings.Cut(c.SyslogSDID, "@")
+	if name == "" || len(c.SyslogSDID) > 32 || strings.ContainsFunc(name, func(r rune) bool {
+		return r <= ' ' || r >= 0x7f || strings.ContainsRune(`="]@`, r)
+	}) || enterprise == "" || strings.Trim(enterprise, "0123456789.") != "" {
+		return fmtErrorf("invalid syslog_sd_id: '%s' (use name@enterprise-number of at most 32 characters, e.g. fields@32473)", c.SyslogSDID)
+	}
+	return nil
+}
+
+// rfc5424Settings holds the header fields of rfc5424 records in comparable form
+type rfc5424Settings struct {
+	facility int
+	hostname string
+	appName  string
+	procID   string
+	sdID     string
+}
+
+// newRFC5424Settings extracts the rfc5424 header fields from the syslog settings of cfg, the APP-NAME defaults to
+// the log name
+func newRFC5424Settings(cfg *Config) rfc5424Settings {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = ""
+	}
+	appName := cfg.SyslogTag
+	if appName == "" {
+		appName = cfg.Name
+	}
+	return rfc5424Settings{
+		facility: syslogFacilities[cfg.SyslogFacility],
+		hostname: hostname,
+		appName:  appName,
+		procID:   strconv.Itoa(os.Getpid()),
+		sdID:     cfg.SyslogSDID,
+	}
+}
+
+// apply sets the rfc5424 header fields of f
+func (s rfc5424Settings) apply(f *formatter.Formatter) *formatter.Formatter {
+	return f.SyslogHeader(s.facility, s.hostname, s.appName, s.procID, s.sdID)
+}
\ No newline at end of file
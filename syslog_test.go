@@ -0,0 +1,152 @@
+package log
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSyslogUDP verifies RFC 5424 datagrams carry the facility, mapped severity, tag, and formatted body
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString(
+		"enable_syslog=true",
+		"syslog_network=udp",
+		"syslog_address="+conn.LocalAddr().String(),
+		"syslog_facility=local0",
+		"syslog_tag=api",
+		"format=txt",
+	))
+
+	logger.Warn("disk slow", "latency_ms", 250)
+	require.NoError(t, logger.Flush(time.Second))
+
+	buf := make([]byte, 2048)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	msg := string(buf[:n])
+
+	assert.True(t, strings.HasPrefix(msg, "<132>1 "), "local0 (16) * 8 + warning (4): %s", msg)
+	assert.Contains(t, msg, " api "+strconv.Itoa(os.Getpid())+" - - ")
+	assert.True(t, strings.HasSuffix(msg, `"disk slow" latency_ms 250`), msg)
+	assert.NotContains(t, msg, "WARN", "Level is carried by the header only")
+
+	for _, sink := range logger.Stats().Sinks {
+		if sink.Name == "syslog" {
+			assert.Equal(t, SinkStatusOK, sink.Status)
+		}
+	}
+}
+
+// TestSyslogTCP verifies RFC 3164 stream messages are newline framed with the remote hostname and tag
+func TestSyslogTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString(
+		"enable_syslog=true",
+		"syslog_network=tcp",
+		"syslog_address="+ln.Addr().String(),
+		"syslog_format=rfc3164",
+		"syslog_facility=daemon",
+	))
+
+	logger.Error("first")
+	logger.Info("second")
+	require.NoError(t, logger.Flush(time.Second))
+
+	for _, want := range []struct{ pri, body string }{{"<27>", "first"}, {"<30>", "second"}} {
+		select {
+		case line := <-lines:
+			assert.True(t, strings.HasPrefix(line, want.pri), line)
+			assert.Contains(t, line, " log["+strconv.Itoa(os.Getpid())+"]: ")
+			assert.True(t, strings.HasSuffix(line, want.body), line)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("syslog message %q not received", want.body)
+		}
+	}
+}
+
+// TestSyslogUnavailable verifies an unreachable daemon degrades the syslog output without affecting the file
+func TestSyslogUnavailable(t *testing.T) {
+	logger, _ := createTestLogger(t)
+	defer logger.Shutdown()
+	require.NoError(t, logger.ApplyConfigString(
+		"enable_syslog=true",
+		"syslog_network=unix",
+		"syslog_address="+t.TempDir()+"/missing.sock",
+	))
+
+	logger.Info("kept in file")
+	require.NoError(t, logger.Flush(time.Second))
+
+	stats := logger.Stats()
+	assert.Equal(t, uint64(1), stats.ProcessedLogs)
+	var found bool
+	for _, sink := range stats.Sinks {
+		if sink.Name == "syslog" {
+			found = true
+			assert.Equal(t, SinkStatusDegraded, sink.Status)
+			assert.Contains(t, sink.LastError, "failed to connect to syslog")
+		}
+	}
+	assert.True(t, found)
+}
+
+// TestSyslogConfigValidation verifies syslog settings are validated
+func TestSyslogConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"unknown network", func(c *Config) { c.SyslogNetwork = "sctp" }},
+		{"missing address", func(c *Config) { c.SyslogNetwork = "udp" }},
+		{"unknown format", func(c *Config) { c.SyslogFormat = "rfc9999" }},
+		{"unknown facility", func(c *Config) { c.SyslogFacility = "local9" }},
+		{"tag with space", func(c *Config) { c.SyslogTag = "my app" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(cfg)
+			assert.Error(t, cfg.Validate())
+		})
+	}
+
+	assert.Equal(t, 7, syslogSeverity(LevelDebug))
+	assert.Equal(t, 3, syslogSeverity(LevelError))
+	assert.Equal(t, 5, syslogSeverity(LevelProc))
+}
\ No newline at end of file
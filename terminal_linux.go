@@ -0,0 +1,24 @@
+//go:build linux
+
+package log
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// isTerminal reports whether file is a terminal, by querying its terminal attributes
+func isTerminal(file *os.File) bool {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return false
+	}
+
+	var errno syscall.Errno
+	var termios syscall.Termios
+	ctrlErr := conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&termios)))
+	})
+	return ctrlErr == nil && errno == 0
+}
\ No newline at end of file
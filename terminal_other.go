@@ -0,0 +1,13 @@
+//go:build !linux
+
+package log
+
+import (
+	"os"
+)
+
+// isTerminal reports whether file is a character device, the closest check without terminal ioctls
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
\ No newline at end of file
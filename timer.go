@@ -24,6 +24,9 @@ func (l *Logger) setupProcessingTimers() *TimerSet {
 	// Set up heartbeat timer
 	timers.heartbeatChan = l.setupHeartbeatTimer(timers)
 
+	// Set up suppression summary timer
+	timers.suppressionChan = l.setupSuppressionTimer(timers)
+
 	return timers
 }
 
@@ -78,12 +81,26 @@ func (l *Logger) setupHeartbeatTimer(timers *TimerSet) <-chan time.Time {
 		if intervalS <= 0 {
 			intervalS = DefaultConfig().HeartbeatIntervalS
 		}
-		timers.heartbeatTicker = time.NewTicker(time.Duration(intervalS) * time.Second)
+		timers.heartbeatInterval = time.Duration(intervalS) * time.Second
+		timers.incidentInterval = incidentHeartbeatInterval(c)
+		l.state.HeartbeatIncident.Store(false)
+		timers.heartbeatTicker = time.NewTicker(timers.heartbeatInterval)
 		return timers.heartbeatTicker.C
 	}
 	return nil
 }
 
+// setupSuppressionTimer configures the suppression summary timer if enabled
+func (l *Logger) setupSuppressionTimer(timers *TimerSet) <-chan time.Time {
+	c := l.getConfig()
+	if c.SuppressionSummaryIntervalS > 0 {
+		timers.suppressionInterval = time.Duration(c.SuppressionSummaryIntervalS) * time.Second
+		timers.suppressionTicker = time.NewTicker(timers.suppressionInterval)
+		return timers.suppressionTicker.C
+	}
+	return nil
+}
+
 // stopProcessingTimers stops all active timers
 func (l *Logger) stopProcessingTimers(timers *TimerSet) {
 	timers.flushTicker.Stop()
@@ -96,4 +113,7 @@ func (l *Logger) stopProcessingTimers(timers *TimerSet) {
 	if timers.heartbeatTicker != nil {
 		timers.heartbeatTicker.Stop()
 	}
+	if timers.suppressionTicker != nil {
+		timers.suppressionTicker.Stop()
+	}
 }
\ No newline at end of file
@@ -3,15 +3,51 @@ package log
 import (
 	"io"
 	"time"
+
+	"github.com/lixenwraith/log/formatter"
 )
 
+// RawJSON is implemented by values holding already serialized JSON, embedded verbatim in json output
+// json.RawMessage arguments are embedded the same way
+type RawJSON = formatter.RawJSON
+
 // logRecord represents a single log entry
 type logRecord struct {
-	Flags     int64
-	TimeStamp time.Time
-	Level     int64
-	Trace     string
-	Args      []any
+	Flags      int64
+	TimeStamp  time.Time
+	Level      int64
+	Trace      string
+	Args       []any
+	ConsoleTag string            // Component tag prefixed to console output only
+	Labels     map[string]string // Labels of the logging logger, shared read-only
+	ack        chan error        // Receives the write outcome of an audit record, nil for regular records
+}
+
+// configEpoch is an immutable snapshot of the configuration and its formatter, published as a single unit
+// The processor loads one epoch per record batch so a batch never mixes configurations
+type configEpoch struct {
+	seq                uint64
+	config             *Config
+	formatter          *formatter.Formatter
+	consoleFormatter   *formatter.Formatter // Formats console output in console_format or for glyphs, nil when formatter serves it
+	heartbeatFormatter *formatter.Formatter // Formats heartbeats in heartbeat_format, nil when formatter serves them
+	levelRoutes        *levelRoutes         // Compiled field-based level overrides, nil when none are configured
+	enrichment         []any                // Key-value pairs appended to every record, nil when enrichment is disabled
+	syslog             *syslogOutput        // Syslog output, nil when disabled; shared by epochs with unchanged settings
+	journald           *journaldOutput      // Journald output, nil when disabled; shared like syslog
+	gelf               *gelfOutput          // GELF output, nil when disabled; shared like syslog
+	s3                 *s3Uploader          // Archive uploader, nil when disabled; shared like syslog
+	exec               *execHook            // on_error_exec hook, nil when disabled; shared like syslog
+	console            *consoleSink         // Console output, nil when disabled
+	file               *fileSink            // File output, nil when disabled or delegated to shards
+}
+
+// FlushResult reports the outcome of an explicit flush
+type FlushResult struct {
+	Records uint64 // Records written since the previous explicit flush
+	Bytes   uint64 // Bytes written since the previous explicit flush
+	Synced  bool   // True if the log file was synced to disk
+	err     error  // Set by the processor when it stopped before completing the flush
 }
 
 // TimerSet holds all timers used in processLogs
@@ -22,6 +58,16 @@ type TimerSet struct {
 	heartbeatTicker *time.Ticker
 	retentionChan   <-chan time.Time
 	heartbeatChan   <-chan time.Time
+
+	// Suppression summaries, nil when suppression_summary_interval_s is 0
+	suppressionTicker   *time.Ticker
+	suppressionChan     <-chan time.Time
+	suppressionInterval time.Duration
+
+	// Heartbeat spacing, switched to incidentInterval while drops or disk limit violations occur
+	heartbeatInterval time.Duration
+	incidentInterval  time.Duration // 0 when heartbeats keep their interval during incidents
+	incidentSeen      bool          // An incident condition was observed since the previous heartbeat
 }
 
 // sink is a wrapper around an io.Writer, atomic value type change workaround
@@ -0,0 +1,30 @@
+package log
+
+// WriteErrorFunc receives a record the file output dropped, with its formatted bytes and the cause
+// data is only valid during the call, copy it to keep it
+type WriteErrorFunc func(data []byte, record Record, err error)
+
+// OnWriteError calls fn for every record dropped because the active log file could not be written: failed writes,
+// failed rotations, a missing file, and disk limits with on_disk_full=drop, so applications can spool the bytes
+// elsewhere. Records of shard loggers are reported too, in the format of the file they were bound for
+// fn runs synchronously on the processor goroutine and must return quickly. A nil fn removes the callback
+func (l *Logger) OnWriteError(fn WriteErrorFunc) {
+	l.writeError.Store(fn)
+}
+
+// getWriteError returns the OnWriteError callback, shards report through their parent
+func (c *loggerCore) getWriteError() WriteErrorFunc {
+	owner := c
+	if owner.parent != nil {
+		owner = owner.parent
+	}
+	fn, _ := owner.writeError.Load().(WriteErrorFunc)
+	return fn
+}
+
+// reportWriteError passes a record dropped by the file output to the OnWriteError callback
+func (l *Logger) reportWriteError(data []byte, record Record, err error) {
+	if fn := l.getWriteError(); fn != nil {
+		fn(data, record, err)
+	}
+}
\ No newline at end of file
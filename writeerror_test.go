@@ -0,0 +1,62 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOnWriteError verifies records dropped by the file output reach the callback with their formatted bytes
+func TestOnWriteError(t *testing.T) {
+	logger, err := NewBuilder().
+		Directory(t.TempDir()).
+		Format("txt").
+		EnableFile(true).
+		EnableConsole(false).
+		MinDiskFreeKB(9999999999).
+		Build()
+	require.NoError(t, err)
+	require.NoError(t, logger.Start())
+	defer logger.Shutdown()
+
+	// The logger's own disk and sync warnings are dropped too, records are keyed by message
+	var mu sync.Mutex
+	dropped := make(map[any]string)
+	causes := make(map[any]error)
+	logger.OnWriteError(func(data []byte, record Record, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dropped[record.Args[0]] = string(bytes.Clone(data))
+		causes[record.Args[0]] = err
+	})
+
+	// Disk limits hold back the record
+	require.False(t, logger.performDiskCheck(true))
+	logger.Warn("held back", "id", 1)
+	require.NoError(t, logger.Flush(time.Second))
+
+	// A failed write drops the record
+	require.NoError(t, logger.ApplyConfigString("min_disk_free_kb=0"))
+	require.True(t, logger.performDiskCheck(true))
+	file, ok := logger.state.CurrentFile.Load().(*os.File)
+	require.True(t, ok)
+	require.NoError(t, file.Close())
+	logger.Error("lost write")
+	require.NoError(t, logger.Flush(time.Second))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, dropped["held back"], `WARN "held back" id 1`)
+	assert.ErrorIs(t, causes["held back"], errDiskLimit)
+	assert.Contains(t, dropped["lost write"], `ERROR "lost write"`)
+	assert.True(t, errors.Is(causes["lost write"], os.ErrClosed))
+
+	logger.OnWriteError(nil)
+	assert.Nil(t, logger.getWriteError())
+}
\ No newline at end of file